@@ -22,6 +22,17 @@ type App struct {
 	termMgr       *TerminalManager
 	openCode      *OpenCodeManager
 	fileMgr       *FileManager
+	taskMgr       *TaskManager
+	debugMgr      *DebugManager
+	searchMgr     *SearchManager
+	replaceMgr    *ReplaceManager
+	historyMgr    *HistoryManager
+	trashMgr      *TrashManager
+	gitJobMgr     *GitJobManager
+	turnMgr       *SessionChangeTracker
+	worktreeMgr   *WorktreeManager
+	mcpMgr        *MCPManager
+	mcpSecrets    *MCPSecretManager
 	sseCancel     context.CancelFunc // 用于取消 SSE 订阅
 	sseSubscribed bool
 	accountMgr    *AccountManager // Kiro Account Manager
@@ -46,6 +57,18 @@ func NewApp() *App {
 	app.termMgr = NewTerminalManager(app)
 	app.openCode = NewOpenCodeManager(app)
 	app.fileMgr = NewFileManager(app)
+	app.taskMgr = NewTaskManager(app)
+	app.debugMgr = NewDebugManager(app)
+	app.searchMgr = NewSearchManager(app)
+	app.replaceMgr = NewReplaceManager(app)
+	app.historyMgr = NewHistoryManager(app)
+	app.trashMgr = NewTrashManager(app)
+	app.gitJobMgr = NewGitJobManager(app)
+	app.turnMgr = NewSessionChangeTracker(app)
+	app.worktreeMgr = NewWorktreeManager(app)
+	app.mcpMgr = NewMCPManager(app)
+	app.mcpSecrets = NewMCPSecretManager(app)
+	app.mcpSecrets.onRefresh = app.resyncMCPServer
 
 	// Initialize Kiro Account Manager
 	app.initAccountManager()
@@ -116,6 +139,44 @@ func (a *App) startup(ctx context.Context) {
 	}()
 }
 
+// shutdown 应用退出时清理后台进程
+func (a *App) shutdown(ctx context.Context) {
+	// 结束调试会话、任务和 Git 操作，避免调试适配器、被调试程序、构建进程和凭据服务残留
+	a.debugMgr.StopAll()
+	a.taskMgr.StopAll()
+	a.gitJobMgr.StopAll()
+}
+
+// emitEvent 推送事件到前端和远程控制客户端
+func (a *App) emitEvent(name string, data interface{}) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, name, data)
+	}
+	if a.httpServer != nil {
+		a.httpServer.BroadcastEvent(name, data)
+	}
+}
+
+// getDataDir 返回应用数据目录下的子目录（不存在时创建）
+func (a *App) getDataDir(sub string) (string, error) {
+	var base string
+	if a.configMgr != nil {
+		base = a.configMgr.GetDataDirectory()
+	} else {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("获取配置目录失败: %v", err)
+		}
+		base = filepath.Join(configDir, "opencode-desktop", "data")
+	}
+
+	dir := filepath.Join(base, sub)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建数据目录失败: %v", err)
+	}
+	return dir, nil
+}
+
 func (a *App) SetServerURL(url string) {
 	a.serverURL = strings.TrimSuffix(url, "/")
 }
@@ -224,159 +285,123 @@ func (a *App) UnwatchFile(path string) {
 	a.fileMgr.UnwatchFile(path)
 }
 
-// RunFile 运行文件
+// RunFile 返回运行文件的命令行，由前端粘贴到终端执行
 func (a *App) RunFile(filePath string) (string, error) {
 	if filePath == "" {
 		return "", fmt.Errorf("文件路径为空")
 	}
 
-	// 获取文件扩展名和目录
 	ext := strings.ToLower(filepath.Ext(filePath))
-	dir := filepath.Dir(filePath)
-	fileName := filepath.Base(filePath)
-	fileNameNoExt := strings.TrimSuffix(fileName, ext)
-
-	var cmd string
-	var args []string
-
-	switch ext {
-	case ".py":
-		cmd = "python3"
-		args = []string{filePath}
-	case ".go":
-		cmd = "go"
-		args = []string{"run", filePath}
-	case ".js":
-		cmd = "node"
-		args = []string{filePath}
-	case ".ts":
-		cmd = "npx"
-		args = []string{"ts-node", filePath}
-	case ".java":
-		// 检测是否是 Maven 项目
-		if a.isMavenProject(dir) {
-			cmd = "mvn"
-			args = []string{"-f", a.findPomXml(dir), "compile", "exec:java", "-Dexec.mainClass=" + a.getJavaMainClass(filePath)}
-		} else if a.isGradleProject(dir) {
-			cmd = "gradle"
-			args = []string{"-p", a.findGradleProject(dir), "run"}
-		} else {
-			// 单文件编译运行
-			cmd = "sh"
-			args = []string{"-c", fmt.Sprintf("cd %s && javac %s && java %s", dir, fileName, fileNameNoExt)}
-		}
-	case ".rs":
-		cmd = "cargo"
-		args = []string{"run", "--manifest-path", a.findCargoToml(dir)}
-	case ".rb":
-		cmd = "ruby"
-		args = []string{filePath}
-	case ".php":
-		cmd = "php"
-		args = []string{filePath}
-	case ".sh":
-		cmd = "bash"
-		args = []string{filePath}
-	case ".html", ".htm":
+	if ext == ".html" || ext == ".htm" {
 		// 返回特殊标记，前端处理打开浏览器
 		return "OPEN_BROWSER:" + filePath, nil
-	default:
-		return "", fmt.Errorf("不支持运行 %s 文件", ext)
 	}
 
-	// 返回命令让前端在终端中执行
-	fullCmd := cmd + " " + strings.Join(args, " ")
-	return fullCmd, nil
+	task, err := FileTask(filePath)
+	if err != nil {
+		return "", err
+	}
+	return terminalCommandInDir(task.Cwd, task.CommandLine), nil
 }
 
-// 检测是否是 Maven 项目
-func (a *App) isMavenProject(dir string) bool {
-	return a.findPomXml(dir) != ""
+// RunFileTask 在后台执行文件对应的任务，返回执行记录 ID
+func (a *App) RunFileTask(filePath string) (string, error) {
+	if filePath == "" {
+		return "", fmt.Errorf("文件路径为空")
+	}
+	task, err := FileTask(filePath)
+	if err != nil {
+		return "", err
+	}
+	return a.taskMgr.Run(*task)
 }
 
-// 查找 pom.xml
-func (a *App) findPomXml(dir string) string {
-	// 向上查找 pom.xml
-	for {
-		pomPath := filepath.Join(dir, "pom.xml")
-		if _, err := os.Stat(pomPath); err == nil {
-			return pomPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-	return ""
+// --- 任务 ---
+
+// GetTasks 获取当前工作区检测到的任务和自定义任务
+func (a *App) GetTasks() ([]Task, error) {
+	return a.taskMgr.DetectTasks(a.fileMgr.GetRootDir())
 }
 
-// 获取 Java 主类名 (package.ClassName)
-func (a *App) getJavaMainClass(filePath string) string {
-	content, err := os.ReadFile(filePath)
+// RunTask 执行任务，返回执行记录 ID
+func (a *App) RunTask(taskID string) (string, error) {
+	task, err := a.taskMgr.FindTask(a.fileMgr.GetRootDir(), taskID)
 	if err != nil {
-		return ""
+		return "", err
 	}
+	return a.taskMgr.Run(*task)
+}
 
-	code := string(content)
+// StopTask 取消正在运行的任务
+func (a *App) StopTask(runID string) error {
+	return a.taskMgr.Stop(runID)
+}
 
-	// 查找 package 声明
-	packageName := ""
-	lines := strings.Split(code, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "package ") && strings.HasSuffix(line, ";") {
-			packageName = strings.TrimSuffix(strings.TrimPrefix(line, "package "), ";")
-			break
-		}
-	}
+// GetTaskRun 获取任务执行记录（含输出和诊断）
+func (a *App) GetTaskRun(runID string) (*TaskRun, error) {
+	return a.taskMgr.GetRun(runID)
+}
+
+// GetTaskRuns 获取任务执行历史
+func (a *App) GetTaskRuns() []TaskRun {
+	return a.taskMgr.ListRuns()
+}
 
-	// 获取文件名作为类名 (Java 规范)
-	className := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+// --- 调试 ---
 
-	if packageName != "" {
-		return packageName + "." + className
-	}
-	return className
+// StartDebug 启动调试会话，language 为空时按文件类型推断（go / python / node）
+func (a *App) StartDebug(language, program string, args []string) (*DebugSessionInfo, error) {
+	return a.debugMgr.Start(language, program, args, a.fileMgr.GetRootDir())
 }
 
-// 检测是否是 Gradle 项目
-func (a *App) isGradleProject(dir string) bool {
-	return a.findGradleProject(dir) != ""
+// StopDebug 结束调试会话
+func (a *App) StopDebug(sessionID string) error {
+	return a.debugMgr.Stop(sessionID)
 }
 
-// 查找 Gradle 项目目录
-func (a *App) findGradleProject(dir string) string {
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "build.gradle")); err == nil {
-			return dir
-		}
-		if _, err := os.Stat(filepath.Join(dir, "build.gradle.kts")); err == nil {
-			return dir
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-	return ""
+// GetDebugSessions 获取所有调试会话
+func (a *App) GetDebugSessions() []DebugSessionInfo {
+	return a.debugMgr.ListSessions()
 }
 
-// 查找 Cargo.toml
-func (a *App) findCargoToml(dir string) string {
-	for {
-		cargoPath := filepath.Join(dir, "Cargo.toml")
-		if _, err := os.Stat(cargoPath); err == nil {
-			return cargoPath
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-	return ""
+// SetBreakpoints 设置文件断点（覆盖该文件原有断点）
+func (a *App) SetBreakpoints(file string, breakpoints []DebugBreakpoint) ([]DebugBreakpoint, error) {
+	return a.debugMgr.SetBreakpoints(file, breakpoints)
+}
+
+// GetBreakpoints 获取所有断点
+func (a *App) GetBreakpoints() map[string][]DebugBreakpoint {
+	return a.debugMgr.GetBreakpoints()
+}
+
+// DebugStep 执行 continue / next / stepIn / stepOut / pause
+func (a *App) DebugStep(sessionID, command string, threadID int) error {
+	return a.debugMgr.Step(sessionID, command, threadID)
+}
+
+// GetDebugThreads 获取线程列表
+func (a *App) GetDebugThreads(sessionID string) ([]DebugThread, error) {
+	return a.debugMgr.Threads(sessionID)
+}
+
+// GetDebugStackTrace 获取调用栈
+func (a *App) GetDebugStackTrace(sessionID string, threadID int) ([]DebugStackFrame, error) {
+	return a.debugMgr.StackTrace(sessionID, threadID)
+}
+
+// GetDebugScopes 获取栈帧作用域
+func (a *App) GetDebugScopes(sessionID string, frameID int) ([]DebugScope, error) {
+	return a.debugMgr.Scopes(sessionID, frameID)
+}
+
+// GetDebugVariables 获取变量
+func (a *App) GetDebugVariables(sessionID string, variablesReference int) ([]DebugVariable, error) {
+	return a.debugMgr.Variables(sessionID, variablesReference)
+}
+
+// DebugEvaluate 求值表达式
+func (a *App) DebugEvaluate(sessionID, expression string, frameID int) (*DebugVariable, error) {
+	return a.debugMgr.Evaluate(sessionID, expression, frameID)
 }
 
 // OpenFolder 打开文件夹选择对话框并设置为工作目录
@@ -446,13 +471,16 @@ func (a *App) CopyToClipboard(text string) error {
 
 // --- Kiro Account Manager Initialization ---
 
+// defaultMasterKey 本地加密存储使用的主密钥
+const defaultMasterKey = "opencode-kiro-master-key-v1"
+
 // initAccountManager initializes the Kiro Account Manager
 func (a *App) initAccountManager() {
 	fmt.Println("=== 初始化 Kiro 账号管理器 ===")
 
 	// Initialize crypto service with a default master key
 	// TODO: In production, this should be derived from user credentials or system keychain
-	crypto := NewCryptoService("opencode-kiro-master-key-v1")
+	crypto := NewCryptoService(defaultMasterKey)
 	fmt.Println("✓ 加密服务初始化完成")
 
 	// Initialize configuration manager
@@ -1276,7 +1304,13 @@ func (a *App) GetSkills() ([]SkillInfo, error) {
 		fmt.Printf("✗ 获取技能失败: %v\n", err)
 		return nil, err
 	}
-	fmt.Printf("✓ 获取到 %d 个技能\n", len(skills))
+	invalid := 0
+	for _, skill := range skills {
+		if hasSkillErrors(skill.Issues) {
+			invalid++
+		}
+	}
+	fmt.Printf("✓ 获取到 %d 个技能，%d 个存在错误\n", len(skills), invalid)
 	return skills, nil
 }
 
@@ -1341,11 +1375,11 @@ func (a *App) GetSkillTemplates() []SkillTemplate {
 	return templates
 }
 
-// CreateSkillFromTemplate 从模板创建技能
-func (a *App) CreateSkillFromTemplate(templateID, customName string, global bool) error {
+// CreateSkillFromTemplate 从模板创建技能，values 为模板变量的取值
+func (a *App) CreateSkillFromTemplate(templateID, customName string, global bool, values map[string]string) error {
 	fmt.Printf("=== API 调用: CreateSkillFromTemplate (template=%s, name=%s, global=%v) ===\n", templateID, customName, global)
 	sm := a.getSkillsManager()
-	err := sm.CreateSkillFromTemplate(templateID, customName, global)
+	err := sm.CreateSkillFromTemplate(templateID, customName, global, values)
 	if err != nil {
 		fmt.Printf("✗ 从模板创建技能失败: %v\n", err)
 		return err
@@ -1354,6 +1388,141 @@ func (a *App) CreateSkillFromTemplate(templateID, customName string, global bool
 	return nil
 }
 
+// SaveSkillAsTemplate 将技能保存为模板，templateID 为空时使用技能名称
+func (a *App) SaveSkillAsTemplate(name, templateID, category string, global bool) error {
+	fmt.Printf("=== API 调用: SaveSkillAsTemplate (name=%s, template=%s, global=%v) ===\n", name, templateID, global)
+	sm := a.getSkillsManager()
+	if err := sm.SaveSkillAsTemplate(name, templateID, category, global); err != nil {
+		fmt.Printf("✗ 保存模板失败: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ 模板已保存")
+	return nil
+}
+
+// DeleteSkillTemplate 删除用户模板
+func (a *App) DeleteSkillTemplate(templateID string) error {
+	fmt.Printf("=== API 调用: DeleteSkillTemplate (template=%s) ===\n", templateID)
+	sm := a.getSkillsManager()
+	if err := sm.DeleteSkillTemplate(templateID); err != nil {
+		fmt.Printf("✗ 删除模板失败: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ 模板已删除")
+	return nil
+}
+
+// SetSkillEnabled 在指定配置层（global/project/local，为空时为本机层）启用或禁用技能
+func (a *App) SetSkillEnabled(name string, enabled bool, layer string) error {
+	fmt.Printf("=== API 调用: SetSkillEnabled (name=%s, enabled=%v, layer=%s) ===\n", name, enabled, layer)
+	sm := a.getSkillsManager()
+	written, err := sm.SetSkillEnabled(name, enabled, layer)
+	if err != nil {
+		fmt.Printf("✗ 切换技能状态失败: %v\n", err)
+		return err
+	}
+	state := "禁用"
+	if enabled {
+		state = "启用"
+	}
+	a.emitEvent("output-log", fmt.Sprintf("技能 %s 已在%s层%s，重启 OpenCode 后生效", name, written, state))
+	fmt.Println("✓ 技能状态已更新")
+	return nil
+}
+
+// SetSkillMetadata 修改技能版本和作者
+func (a *App) SetSkillMetadata(name, version, author string) error {
+	fmt.Printf("=== API 调用: SetSkillMetadata (name=%s, version=%s) ===\n", name, version)
+	sm := a.getSkillsManager()
+	if err := sm.SetSkillMetadata(name, version, author); err != nil {
+		fmt.Printf("✗ 更新技能元数据失败: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ 技能元数据已更新")
+	return nil
+}
+
+// ReadSkillFile 读取技能附带的文件
+func (a *App) ReadSkillFile(name, path string) (string, error) {
+	return a.getSkillsManager().ReadSkillFile(name, path)
+}
+
+// WriteSkillFile 添加或修改技能附带的文件
+func (a *App) WriteSkillFile(name, path, content string) error {
+	fmt.Printf("=== API 调用: WriteSkillFile (name=%s, path=%s) ===\n", name, path)
+	if err := a.getSkillsManager().WriteSkillFile(name, path, content); err != nil {
+		fmt.Printf("✗ 写入技能文件失败: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ 技能文件已保存")
+	return nil
+}
+
+// DeleteSkillFile 删除技能附带的文件
+func (a *App) DeleteSkillFile(name, path string) error {
+	fmt.Printf("=== API 调用: DeleteSkillFile (name=%s, path=%s) ===\n", name, path)
+	if err := a.getSkillsManager().DeleteSkillFile(name, path); err != nil {
+		fmt.Printf("✗ 删除技能文件失败: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ 技能文件已删除")
+	return nil
+}
+
+// ExportSkill 导出技能包，path 为空时弹出保存对话框；返回写入的路径，取消时为空
+func (a *App) ExportSkill(name, path string) (string, error) {
+	fmt.Printf("=== API 调用: ExportSkill (name=%s) ===\n", name)
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "导出技能包",
+			DefaultFilename: name + ".zip",
+			Filters:         []runtime.FileFilter{{DisplayName: "技能包 (*.zip)", Pattern: "*.zip"}},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+	if err := a.getSkillsManager().ExportSkill(name, path); err != nil {
+		fmt.Printf("✗ 导出技能失败: %v\n", err)
+		return "", err
+	}
+	fmt.Printf("✓ 技能已导出到 %s\n", path)
+	return path, nil
+}
+
+// SelectSkillPackage 选择要导入的技能包
+func (a *App) SelectSkillPackage() (string, error) {
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "导入技能包",
+		Filters: []runtime.FileFilter{{DisplayName: "技能包 (*.zip)", Pattern: "*.zip"}},
+	})
+}
+
+// PreviewSkillImport 校验技能包并预览与已安装技能的差异
+func (a *App) PreviewSkillImport(path string, global bool) (*SkillImportPreview, error) {
+	fmt.Printf("=== API 调用: PreviewSkillImport (path=%s, global=%v) ===\n", path, global)
+	preview, err := a.getSkillsManager().PreviewSkillImport(path, global)
+	if err != nil {
+		fmt.Printf("✗ 读取技能包失败: %v\n", err)
+		return nil, err
+	}
+	fmt.Printf("✓ 技能包 %s@%s，%d 处变化\n", preview.Name, preview.Version, len(preview.Changes))
+	return preview, nil
+}
+
+// ImportSkill 安装技能包，replace 为 true 时替换已安装的同名技能
+func (a *App) ImportSkill(path string, global, replace bool) (*SkillInfo, error) {
+	fmt.Printf("=== API 调用: ImportSkill (path=%s, global=%v, replace=%v) ===\n", path, global, replace)
+	skill, err := a.getSkillsManager().ImportSkill(path, global, replace)
+	if err != nil {
+		fmt.Printf("✗ 导入技能失败: %v\n", err)
+		return nil, err
+	}
+	fmt.Printf("✓ 技能 %s 已导入\n", skill.Name)
+	return skill, nil
+}
+
 // --- Remote Control API ---
 
 // StartRemoteControl 启动远程控制服务器
@@ -19,6 +19,7 @@ type AuthService struct {
 	kiroClient    KiroDesktopAPI
 	pendingLogins map[string]*PendingLogin // state -> PendingLogin
 	onAuthSuccess func(*KiroAccount) error // Callback when authentication succeeds
+	callbackMux   *http.ServeMux           // Routes served by the local callback server
 }
 
 type KiroDesktopAPI interface {
@@ -48,6 +49,7 @@ func NewAuthService() *AuthService {
 		config:        config,
 		kiroClient:    NewKiroDesktopClient(""),
 		pendingLogins: make(map[string]*PendingLogin),
+		callbackMux:   http.NewServeMux(),
 	}
 
 	// Start the local callback server
@@ -63,12 +65,14 @@ func (as *AuthService) SetAuthSuccessCallback(callback func(*KiroAccount) error)
 
 // StartCallbackServer starts the local HTTP server for OAuth callbacks
 func (as *AuthService) StartCallbackServer() {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/oauth/callback", as.handleCallbackHTTP)
+	if as.callbackMux == nil {
+		as.callbackMux = http.NewServeMux()
+	}
+	as.callbackMux.HandleFunc("/oauth/callback", as.handleCallbackHTTP)
 
 	server := &http.Server{
-		Addr:    "127.0.0.1:54321",
-		Handler: mux,
+		Addr:    authCallbackAddr,
+		Handler: as.callbackMux,
 	}
 
 	fmt.Println("Starting OAuth callback server on 127.0.0.1:54321 (CORS enabled)")
@@ -77,6 +81,18 @@ func (as *AuthService) StartCallbackServer() {
 	}
 }
 
+// authCallbackAddr is the address of the local OAuth callback server
+const authCallbackAddr = "127.0.0.1:54321"
+
+// HandleCallbackPath registers an extra handler on the local callback server and returns its URL
+func (as *AuthService) HandleCallbackPath(path string, handler http.HandlerFunc) (string, error) {
+	if as.callbackMux == nil {
+		return "", fmt.Errorf("callback server not started")
+	}
+	as.callbackMux.HandleFunc(path, handler)
+	return "http://" + authCallbackAddr + path, nil
+}
+
 // handleCallbackHTTP handles the HTTP request for the OAuth callback
 func (as *AuthService) handleCallbackHTTP(w http.ResponseWriter, r *http.Request) {
 	// 添加 CORS 支持
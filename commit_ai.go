@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CommitMessageSettings AI 生成提交信息的设置
+type CommitMessageSettings struct {
+	Model          string `json:"model"`          // provider/modelID，为空时使用 OpenCode 默认模型
+	PromptTemplate string `json:"promptTemplate"` // 支持 {{branch}} {{recent}} {{files}} {{diff}} {{language}}
+	ReviewTemplate string `json:"reviewTemplate"` // 需要审查时追加到提示末尾
+	Language       string `json:"language"`       // 提交信息使用的语言
+	MaxDiffChars   int    `json:"maxDiffChars"`   // 发送给模型的 diff 总字符数上限
+	MaxFileChars   int    `json:"maxFileChars"`   // diff 超出上限时，超过该长度的文件先单独总结
+}
+
+// CommitMessageOptions 生成提交信息的选项
+type CommitMessageOptions struct {
+	Model  string `json:"model"`  // 覆盖设置中的模型
+	Review bool   `json:"review"` // 同时审查有风险的更改
+}
+
+// CommitMessageResult 生成的提交信息
+type CommitMessageResult struct {
+	Message         string   `json:"message"`
+	Subject         string   `json:"subject"`
+	Body            string   `json:"body"`
+	Conventional    bool     `json:"conventional"` // 标题是否符合 Conventional Commits
+	Review          string   `json:"review,omitempty"`
+	Files           []string `json:"files"`
+	SummarizedFiles []string `json:"summarizedFiles"` // 因 diff 过大而先单独总结的文件
+	Truncated       bool     `json:"truncated"`       // 部分文件只发送了统计信息
+	Model           string   `json:"model"`
+}
+
+// commitReviewMarker 分隔提交信息和审查意见
+const commitReviewMarker = "---REVIEW---"
+
+// commitPromptTimeout 单次模型请求的超时时间
+const commitPromptTimeout = 3 * time.Minute
+
+const defaultCommitPromptTemplate = `You are writing a git commit message for the staged changes below.
+
+Use the Conventional Commits format: "<type>(<optional scope>): <subject>", where type is one of feat, fix, docs, style, refactor, perf, test, build, ci, chore or revert. Keep the subject under 72 characters, in the imperative mood and without a trailing period. If the change needs explanation, add a blank line and a short body wrapped at 72 characters. Write the message in {{language}}.
+
+Branch: {{branch}}
+
+Recent commits (for style reference):
+{{recent}}
+
+Changed files:
+{{files}}
+
+Diff:
+{{diff}}
+
+Reply with the commit message without code fences or commentary.`
+
+const defaultCommitReviewTemplate = `After the commit message, add a line containing exactly ` + commitReviewMarker + ` followed by a short review of risky changes in the diff: likely bugs, security problems, leaked secrets, leftover debug code or missing tests. Write one "- " bullet per finding, or "- No issues found." if there is nothing notable.`
+
+const commitSummaryPrompt = `Summarize the following diff of %s in at most three short bullet points describing what changed and why it matters. Reply with the bullet points only.
+
+%s`
+
+// conventionalCommitRe Conventional Commits 标题格式
+var conventionalCommitRe = regexp.MustCompile(`^(feat|fix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([^)]+\))?!?: \S`)
+
+// generatedFilePatterns 只发送统计信息的生成文件
+var generatedFilePatterns = []string{
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock", "poetry.lock", "composer.lock", "Gemfile.lock",
+	"*.min.js", "*.min.css", "*.map", "*.snap",
+}
+
+// defaultCommitMessageSettings 默认设置
+func defaultCommitMessageSettings() CommitMessageSettings {
+	return CommitMessageSettings{
+		PromptTemplate: defaultCommitPromptTemplate,
+		ReviewTemplate: defaultCommitReviewTemplate,
+		Language:       "English",
+		MaxDiffChars:   60000,
+		MaxFileChars:   8000,
+	}
+}
+
+// loadCommitMessageSettings 读取设置，缺失的字段使用默认值
+func loadCommitMessageSettings(dir string) CommitMessageSettings {
+	settings := defaultCommitMessageSettings()
+	if data, err := os.ReadFile(filepath.Join(dir, "commit-message.json")); err == nil {
+		json.Unmarshal(data, &settings)
+	}
+	defaults := defaultCommitMessageSettings()
+	if strings.TrimSpace(settings.PromptTemplate) == "" {
+		settings.PromptTemplate = defaults.PromptTemplate
+	}
+	if strings.TrimSpace(settings.ReviewTemplate) == "" {
+		settings.ReviewTemplate = defaults.ReviewTemplate
+	}
+	if settings.Language == "" {
+		settings.Language = defaults.Language
+	}
+	if settings.MaxDiffChars <= 0 {
+		settings.MaxDiffChars = defaults.MaxDiffChars
+	}
+	if settings.MaxFileChars <= 0 {
+		settings.MaxFileChars = defaults.MaxFileChars
+	}
+	return settings
+}
+
+// saveCommitMessageSettings 保存设置
+func saveCommitMessageSettings(dir string, settings CommitMessageSettings) error {
+	if !strings.Contains(settings.PromptTemplate, "{{diff}}") && strings.TrimSpace(settings.PromptTemplate) != "" {
+		return fmt.Errorf("提示模板必须包含 {{diff}}")
+	}
+	if settings.MaxDiffChars < 0 || settings.MaxFileChars < 0 {
+		return fmt.Errorf("字符数上限不能为负数")
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, "commit-message.json"), data, 0600)
+}
+
+// isGeneratedFile 判断是否为锁文件、压缩文件等生成文件
+func isGeneratedFile(p string) bool {
+	base := path.Base(p)
+	for _, pattern := range generatedFilePatterns {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// commitFileLine 文件列表中的一行，如 "M src/app.go (+3 -1)"
+func commitFileLine(d *GitFileDiff) string {
+	name := d.Path
+	if d.OldPath != "" {
+		name = d.OldPath + " -> " + d.Path
+	}
+	if d.Binary {
+		return fmt.Sprintf("%s %s (binary)", d.Status, name)
+	}
+	added, deleted := d.stat()
+	return fmt.Sprintf("%s %s (+%d -%d)", d.Status, name, added, deleted)
+}
+
+// buildCommitDiffContext 生成发送给模型的 diff；超出上限时对大文件单独总结，仍超出则只保留统计信息
+func buildCommitDiffContext(diffs []GitFileDiff, settings CommitMessageSettings, summarize func(path, patch string) (string, error)) (string, []string, bool) {
+	patches := make([]string, len(diffs))
+	total := 0
+	for i := range diffs {
+		patches[i] = diffs[i].text()
+		total += len(patches[i])
+	}
+	if total <= settings.MaxDiffChars {
+		return strings.Join(patches, ""), []string{}, false
+	}
+
+	summarized := []string{}
+	entries := make([]string, len(diffs))
+	for i := range diffs {
+		d := &diffs[i]
+		switch {
+		case d.Binary || isGeneratedFile(d.Path):
+			entries[i] = fmt.Sprintf("### %s (diff omitted)\n", commitFileLine(d))
+		case len(patches[i]) > settings.MaxFileChars && summarize != nil:
+			patch := patches[i]
+			if len(patch) > settings.MaxDiffChars {
+				patch = patch[:settings.MaxDiffChars] + "\n... (truncated)\n"
+			}
+			summary, err := summarize(d.Path, patch)
+			if err != nil || summary == "" {
+				entries[i] = patches[i]
+				continue
+			}
+			entries[i] = fmt.Sprintf("### %s (summarized)\n%s\n", commitFileLine(d), strings.TrimSpace(summary))
+			summarized = append(summarized, d.Path)
+		default:
+			entries[i] = patches[i]
+		}
+	}
+
+	// 仍超出上限时，超出部分的文件只保留统计信息
+	var sb strings.Builder
+	truncated := false
+	for i, entry := range entries {
+		if sb.Len()+len(entry) > settings.MaxDiffChars {
+			entry = fmt.Sprintf("### %s (diff omitted to fit the context limit)\n", commitFileLine(&diffs[i]))
+			truncated = true
+		}
+		sb.WriteString(entry)
+	}
+	return sb.String(), summarized, truncated
+}
+
+// renderCommitTemplate 替换模板中的占位符
+func renderCommitTemplate(tmpl string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for k, v := range values {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// parseCommitResponse 拆分模型回复中的提交信息和审查意见
+func parseCommitResponse(text string) (string, string) {
+	message, review, _ := strings.Cut(text, commitReviewMarker)
+	message = strings.TrimSpace(message)
+	if strings.HasPrefix(message, "```") {
+		lines := strings.Split(message, "\n")
+		lines = lines[1:]
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "```" {
+			lines = lines[:len(lines)-1]
+		}
+		message = strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+	return message, strings.TrimSpace(review)
+}
+
+// newCommitMessageResult 根据模型回复生成结果
+func newCommitMessageResult(text string) *CommitMessageResult {
+	message, review := parseCommitResponse(text)
+	subject, body, _ := strings.Cut(message, "\n")
+	subject = strings.TrimSpace(subject)
+	return &CommitMessageResult{
+		Message:      message,
+		Subject:      subject,
+		Body:         strings.TrimSpace(body),
+		Conventional: conventionalCommitRe.MatchString(subject),
+		Review:       review,
+	}
+}
+
+// oneShotPrompt 在临时会话中发送提示，结束后删除会话（避免上下文互相影响）
+func (a *App) oneShotPrompt(text, model string) (string, error) {
+	session, err := a.CreateSession()
+	if err != nil {
+		return "", err
+	}
+	defer a.deleteSession(session.ID)
+	return a.promptSession(session.ID, text, model, commitPromptTimeout)
+}
+
+// --- App API ---
+
+// GetCommitMessageSettings 读取 AI 提交信息设置
+func (a *App) GetCommitMessageSettings() CommitMessageSettings {
+	dir, err := a.getDataDir("git")
+	if err != nil {
+		return defaultCommitMessageSettings()
+	}
+	return loadCommitMessageSettings(dir)
+}
+
+// SaveCommitMessageSettings 保存 AI 提交信息设置
+func (a *App) SaveCommitMessageSettings(settings CommitMessageSettings) error {
+	dir, err := a.getDataDir("git")
+	if err != nil {
+		return err
+	}
+	return saveCommitMessageSettings(dir, settings)
+}
+
+// GenerateCommitMessage 根据已暂存的更改生成提交信息，可选同时审查有风险的更改
+func (a *App) GenerateCommitMessage(dir string, opts CommitMessageOptions) (*CommitMessageResult, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runGit(root, "-c", "core.quotePath=false", "diff", "--cached", "--no-color", "--no-ext-diff", "-M", "-U3")
+	if err != nil {
+		return nil, err
+	}
+	diffs := parseGitDiff(out)
+	if len(diffs) == 0 {
+		return nil, fmt.Errorf("没有已暂存的更改")
+	}
+
+	settings := a.GetCommitMessageSettings()
+	model := opts.Model
+	if model == "" {
+		model = settings.Model
+	}
+
+	files := make([]string, len(diffs))
+	fileLines := make([]string, len(diffs))
+	for i := range diffs {
+		files[i] = diffs[i].Path
+		fileLines[i] = commitFileLine(&diffs[i])
+	}
+
+	a.emitEvent("commit-message-progress", map[string]interface{}{"stage": "diff", "files": len(diffs)})
+	summarize := func(path, patch string) (string, error) {
+		a.emitEvent("commit-message-progress", map[string]interface{}{"stage": "summarize", "file": path})
+		return a.oneShotPrompt(fmt.Sprintf(commitSummaryPrompt, path, patch), model)
+	}
+	diffText, summarized, truncated := buildCommitDiffContext(diffs, settings, summarize)
+
+	branch := "(detached)"
+	if b, err := runGit(root, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		branch = strings.TrimSpace(b)
+	}
+	recent, _ := runGit(root, "log", "-n", "10", "--format=%s")
+	if strings.TrimSpace(recent) == "" {
+		recent = "(none)"
+	}
+
+	prompt := renderCommitTemplate(settings.PromptTemplate, map[string]string{
+		"branch":   branch,
+		"recent":   strings.TrimSpace(recent),
+		"files":    strings.Join(fileLines, "\n"),
+		"diff":     diffText,
+		"language": settings.Language,
+	})
+	if opts.Review {
+		prompt += "\n\n" + settings.ReviewTemplate
+	}
+
+	a.emitEvent("commit-message-progress", map[string]interface{}{"stage": "generate"})
+	text, err := a.oneShotPrompt(prompt, model)
+	if err != nil {
+		return nil, fmt.Errorf("生成提交信息失败: %v", err)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("模型没有返回提交信息")
+	}
+
+	result := newCommitMessageResult(text)
+	result.Files = files
+	result.SummarizedFiles = summarized
+	result.Truncated = truncated
+	result.Model = model
+	if !opts.Review {
+		result.Review = ""
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestBuildCommitDiffContext tests per-file summarization and truncation of large staged diffs
+func TestBuildCommitDiffContext(t *testing.T) {
+	big := func(path string, n int) GitFileDiff {
+		h := DiffHunk{OldStart: 1, NewStart: 1, NewLines: n}
+		for i := 0; i < n; i++ {
+			h.Lines = append(h.Lines, fmt.Sprintf("+line %d of %s", i, path))
+		}
+		return GitFileDiff{Path: path, Status: "M", Hunks: []DiffHunk{h}, header: []string{"diff --git a/" + path + " b/" + path}}
+	}
+	settings := defaultCommitMessageSettings()
+	settings.MaxDiffChars = 2000
+	settings.MaxFileChars = 500
+
+	diffs := []GitFileDiff{big("small.go", 3), big("large.go", 200), big("go.sum", 100)}
+	var asked []string
+	summarize := func(path, patch string) (string, error) {
+		asked = append(asked, path)
+		if len(patch) > settings.MaxDiffChars+100 {
+			t.Errorf("patch for %s not truncated: %d chars", path, len(patch))
+		}
+		return "- adds many lines", nil
+	}
+
+	text, summarized, truncated := buildCommitDiffContext(diffs, settings, summarize)
+	if strings.Join(asked, ",") != "large.go" || strings.Join(summarized, ",") != "large.go" || truncated {
+		t.Errorf("asked = %v, summarized = %v, truncated = %v", asked, summarized, truncated)
+	}
+	if !strings.Contains(text, "+line 2 of small.go") || !strings.Contains(text, "### M large.go (+200 -0) (summarized)\n- adds many lines") ||
+		!strings.Contains(text, "### M go.sum (+100 -0) (diff omitted)") || len(text) > settings.MaxDiffChars {
+		t.Errorf("context = %q", text)
+	}
+
+	// Small diffs are sent unchanged
+	text, summarized, truncated = buildCommitDiffContext(diffs[:1], settings, nil)
+	if text != diffs[0].text() || len(summarized) != 0 || truncated {
+		t.Errorf("small context = %q", text)
+	}
+
+	// Without a summarizer the oversized file falls back to stats only
+	settings.MaxFileChars = 100000
+	_, _, truncated = buildCommitDiffContext(diffs, settings, nil)
+	if !truncated {
+		t.Error("expected truncation when the diff cannot fit")
+	}
+}
+
+// TestNewCommitMessageResult tests parsing of the model reply
+func TestNewCommitMessageResult(t *testing.T) {
+	tests := []struct {
+		reply        string
+		subject      string
+		body         string
+		review       string
+		conventional bool
+	}{
+		{"feat(git): add hunk staging\n\nAllows staging single hunks.\n---REVIEW---\n- No issues found.", "feat(git): add hunk staging", "Allows staging single hunks.", "- No issues found.", true},
+		{"```\nfix!: handle empty repo\n```", "fix!: handle empty repo", "", "", true},
+		{"Update readme", "Update readme", "", "", false},
+	}
+	for _, tt := range tests {
+		r := newCommitMessageResult(tt.reply)
+		if r.Subject != tt.subject || r.Body != tt.body || r.Review != tt.review || r.Conventional != tt.conventional {
+			t.Errorf("newCommitMessageResult(%q) = %+v", tt.reply, r)
+		}
+	}
+}
+
+// TestCommitMessageSettings tests settings defaults, validation and template rendering
+func TestCommitMessageSettings(t *testing.T) {
+	dir := t.TempDir()
+	settings := loadCommitMessageSettings(dir)
+	if settings.PromptTemplate != defaultCommitPromptTemplate || settings.MaxDiffChars != 60000 {
+		t.Errorf("defaults = %+v", settings)
+	}
+
+	if err := saveCommitMessageSettings(dir, CommitMessageSettings{PromptTemplate: "no diff here"}); err == nil {
+		t.Error("saveCommitMessageSettings() accepted a template without {{diff}}")
+	}
+	if err := saveCommitMessageSettings(dir, CommitMessageSettings{PromptTemplate: "Diff for {{branch}}:\n{{diff}}", Model: "anthropic/claude"}); err != nil {
+		t.Fatalf("saveCommitMessageSettings() error = %v", err)
+	}
+	settings = loadCommitMessageSettings(dir)
+	if settings.Model != "anthropic/claude" || settings.ReviewTemplate != defaultCommitReviewTemplate || settings.Language != "English" {
+		t.Errorf("loaded = %+v", settings)
+	}
+
+	got := renderCommitTemplate(settings.PromptTemplate, map[string]string{"branch": "main", "diff": "+x"})
+	if got != "Diff for main:\n+x" {
+		t.Errorf("renderCommitTemplate() = %q", got)
+	}
+}
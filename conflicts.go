@@ -0,0 +1,364 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConflictHunk 文件中的一个冲突区块
+type ConflictHunk struct {
+	Index       int      `json:"index"`
+	StartLine   int      `json:"startLine"` // "<<<<<<<" 所在行（从 1 开始）
+	EndLine     int      `json:"endLine"`   // ">>>>>>>" 所在行
+	Ours        []string `json:"ours"`
+	Base        []string `json:"base"` // 仅 diff3/zdiff3 风格的标记才有
+	Theirs      []string `json:"theirs"`
+	OursLabel   string   `json:"oursLabel"`
+	BaseLabel   string   `json:"baseLabel,omitempty"`
+	TheirsLabel string   `json:"theirsLabel"`
+	HasBase     bool     `json:"hasBase"`
+
+	// 各部分在文件行中的范围 [from, to)
+	oursFrom, oursTo     int
+	baseFrom, baseTo     int
+	theirsFrom, theirsTo int
+}
+
+// GitConflictFile 冲突文件的三方内容
+type GitConflictFile struct {
+	Path      string         `json:"path"`
+	Kind      string         `json:"kind"`      // UU, AA, DU, UD, AU, UA, DD
+	MergeHead string         `json:"mergeHead"` // merging, rebasing, cherry-picking, reverting
+	Base      string         `json:"base"`      // 暂存区 stage 1
+	Ours      string         `json:"ours"`      // 暂存区 stage 2
+	Theirs    string         `json:"theirs"`    // 暂存区 stage 3
+	HasBase   bool           `json:"hasBase"`
+	HasOurs   bool           `json:"hasOurs"`
+	HasTheirs bool           `json:"hasTheirs"`
+	Merged    string         `json:"merged"` // 工作区中带冲突标记的内容
+	Exists    bool           `json:"exists"` // 工作区文件是否存在
+	Binary    bool           `json:"binary"`
+	Hunks     []ConflictHunk `json:"hunks"`
+}
+
+// conflictMarkerLen 冲突标记的长度
+const conflictMarkerLen = 7
+
+// conflictMarker 判断行是否为指定字符的冲突标记，返回标记后的标签
+func conflictMarker(line string, ch byte) (string, bool) {
+	line = strings.TrimRight(line, "\r")
+	if len(line) < conflictMarkerLen || line[:conflictMarkerLen] != strings.Repeat(string(ch), conflictMarkerLen) {
+		return "", false
+	}
+	rest := line[conflictMarkerLen:]
+	if rest == "" {
+		return "", true
+	}
+	if rest[0] != ' ' {
+		return "", false
+	}
+	return rest[1:], true
+}
+
+// parseConflictHunks 解析文件中的冲突标记（支持 merge 和 diff3 风格）
+func parseConflictHunks(lines []string) []ConflictHunk {
+	hunks := []ConflictHunk{}
+	for i := 0; i < len(lines); i++ {
+		label, ok := conflictMarker(lines[i], '<')
+		if !ok {
+			continue
+		}
+		h := ConflictHunk{StartLine: i + 1, OursLabel: label, oursFrom: i + 1}
+		section := "ours"
+		closed := false
+		for j := i + 1; j < len(lines) && !closed; j++ {
+			line := lines[j]
+			if label, ok := conflictMarker(line, '|'); ok && section == "ours" {
+				h.oursTo, h.baseFrom = j, j+1
+				h.BaseLabel, h.HasBase = label, true
+				section = "base"
+			} else if _, ok := conflictMarker(line, '='); ok && section != "theirs" {
+				if section == "ours" {
+					h.oursTo = j
+				} else {
+					h.baseTo = j
+				}
+				h.theirsFrom = j + 1
+				section = "theirs"
+			} else if label, ok := conflictMarker(line, '>'); ok && section == "theirs" {
+				h.theirsTo = j
+				h.TheirsLabel = label
+				h.EndLine = j + 1
+				closed = true
+				i = j
+			} else if _, ok := conflictMarker(line, '<'); ok {
+				// 未闭合的区块，从新的起始标记重新开始
+				break
+			}
+		}
+		if !closed {
+			continue
+		}
+		h.Index = len(hunks)
+		h.Ours = trimCR(lines[h.oursFrom:h.oursTo])
+		h.Base = trimCR(lines[h.baseFrom:h.baseTo])
+		h.Theirs = trimCR(lines[h.theirsFrom:h.theirsTo])
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// trimCR 复制行并去掉行尾的 \r
+func trimCR(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = strings.TrimRight(l, "\r")
+	}
+	return out
+}
+
+// resolveConflictLines 按选择替换第 index 个冲突区块，返回新的行
+func resolveConflictLines(lines []string, index int, choice string) ([]string, error) {
+	hunks := parseConflictHunks(lines)
+	if index < 0 || index >= len(hunks) {
+		return nil, fmt.Errorf("冲突区块不存在: %d", index)
+	}
+	h := hunks[index]
+
+	var keep []string
+	switch choice {
+	case "ours":
+		keep = lines[h.oursFrom:h.oursTo]
+	case "theirs":
+		keep = lines[h.theirsFrom:h.theirsTo]
+	case "both":
+		keep = append(append([]string{}, lines[h.oursFrom:h.oursTo]...), lines[h.theirsFrom:h.theirsTo]...)
+	case "base":
+		if !h.HasBase {
+			return nil, fmt.Errorf("冲突区块没有基础版本")
+		}
+		keep = lines[h.baseFrom:h.baseTo]
+	default:
+		return nil, fmt.Errorf("未知的选择: %s", choice)
+	}
+
+	result := make([]string, 0, len(lines))
+	result = append(result, lines[:h.StartLine-1]...)
+	result = append(result, keep...)
+	result = append(result, lines[h.EndLine:]...)
+	return result, nil
+}
+
+// gitMergeState 返回仓库当前进行中的操作（没有时返回空）
+func gitMergeState(root string) string {
+	out, err := runGit(root, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return ""
+	}
+	gitDir := strings.TrimSpace(out)
+	switch {
+	case fileExists(filepath.Join(gitDir, "rebase-merge")) || fileExists(filepath.Join(gitDir, "rebase-apply")):
+		return "rebasing"
+	case fileExists(filepath.Join(gitDir, "MERGE_HEAD")):
+		return "merging"
+	case fileExists(filepath.Join(gitDir, "CHERRY_PICK_HEAD")):
+		return "cherry-picking"
+	case fileExists(filepath.Join(gitDir, "REVERT_HEAD")):
+		return "reverting"
+	}
+	return ""
+}
+
+// gitConflictKinds 列出冲突文件及其 XY 状态
+func gitConflictKinds(root string) (map[string]string, error) {
+	out, err := runGit(root, "status", "--porcelain=v2", "-z", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+	kinds := make(map[string]string)
+	for _, c := range parseGitStatusV2(out).Changes {
+		if c.Status == "U" {
+			kinds[c.Path] = c.Conflict
+		}
+	}
+	return kinds, nil
+}
+
+// --- App API ---
+
+// GetGitConflicts 列出冲突文件
+func (a *App) GetGitConflicts(dir string) ([]GitChange, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := gitConflictKinds(root)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []GitChange{}
+	for path, kind := range kinds {
+		conflicts = append(conflicts, GitChange{Path: path, Status: "U", Conflict: kind})
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	return conflicts, nil
+}
+
+// GetConflictFile 获取冲突文件的基础、我方、对方和工作区内容
+func (a *App) GetConflictFile(dir, path string) (*GitConflictFile, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel := gitRelPath(root, path)
+	kinds, err := gitConflictKinds(root)
+	if err != nil {
+		return nil, err
+	}
+	kind, ok := kinds[rel]
+	if !ok {
+		return nil, fmt.Errorf("文件没有冲突: %s", rel)
+	}
+
+	cf := &GitConflictFile{Path: rel, Kind: kind, MergeHead: gitMergeState(root), Hunks: []ConflictHunk{}}
+	stage := func(n int) (string, bool) {
+		out, err := runGit(root, "show", fmt.Sprintf(":%d:%s", n, rel))
+		return out, err == nil
+	}
+	cf.Base, cf.HasBase = stage(1)
+	cf.Ours, cf.HasOurs = stage(2)
+	cf.Theirs, cf.HasTheirs = stage(3)
+
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if err == nil {
+		cf.Exists = true
+		if isBinaryContent(data) {
+			cf.Binary = true
+		} else {
+			cf.Merged = string(data)
+			cf.Hunks = parseConflictHunks(strings.Split(cf.Merged, "\n"))
+		}
+	}
+	return cf, nil
+}
+
+// ResolveConflictHunk 按选择（ours、theirs、both、base）解决第 index 个冲突区块
+func (a *App) ResolveConflictHunk(dir, path string, index int, choice string) (*GitConflictFile, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel := gitRelPath(root, path)
+	abs := filepath.Join(root, filepath.FromSlash(rel))
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	lines, err := resolveConflictLines(strings.Split(string(data), "\n"), index, choice)
+	if err != nil {
+		return nil, err
+	}
+
+	a.snapshotFile(abs, "conflict")
+	if err := writeFileAtomic(abs, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("写入文件失败: %v", err)
+	}
+	return a.GetConflictFile(root, rel)
+}
+
+// GitAcceptConflictSide 整个文件采用我方（ours）或对方（theirs）版本并标记为已解决
+func (a *App) GitAcceptConflictSide(dir, path, side string) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	rel := gitRelPath(root, path)
+	stage := map[string]int{"ours": 2, "theirs": 3}[side]
+	if stage == 0 {
+		return fmt.Errorf("未知的选择: %s", side)
+	}
+	a.snapshotFile(filepath.Join(root, filepath.FromSlash(rel)), "conflict")
+
+	if _, err := runGit(root, "cat-file", "-e", fmt.Sprintf(":%d:%s", stage, rel)); err != nil {
+		// 该方删除了文件
+		_, err := runGit(root, "rm", "--quiet", "--", rel)
+		return err
+	}
+	if _, err := runGit(root, "checkout", "--"+side, "--", rel); err != nil {
+		return err
+	}
+	_, err = runGit(root, "add", "--", rel)
+	return err
+}
+
+// GitMarkResolved 将冲突文件标记为已解决（文件中仍有冲突标记时拒绝）
+func (a *App) GitMarkResolved(dir, path string) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	rel := gitRelPath(root, path)
+	if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel))); err == nil && !isBinaryContent(data) {
+		if hunks := parseConflictHunks(strings.Split(string(data), "\n")); len(hunks) > 0 {
+			return fmt.Errorf("文件中仍有 %d 处冲突标记: %s", len(hunks), rel)
+		}
+	}
+	_, err = runGit(root, "add", "-A", "--", rel)
+	return err
+}
+
+// buildConflictPrompt 生成让 AI 解决冲突的提示
+func buildConflictPrompt(cf *GitConflictFile) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Please resolve the git merge conflict in `%s` (conflict type %s", cf.Path, cf.Kind)
+	if cf.MergeHead != "" {
+		fmt.Fprintf(&sb, ", while %s", cf.MergeHead)
+	}
+	sb.WriteString(").\n\n")
+
+	switch {
+	case !cf.HasOurs:
+		sb.WriteString("The file was deleted on our side and modified on their side.\n")
+	case !cf.HasTheirs:
+		sb.WriteString("The file was modified on our side and deleted on their side.\n")
+	}
+
+	for _, h := range cf.Hunks {
+		fmt.Fprintf(&sb, "\nConflict %d (lines %d-%d):\n", h.Index+1, h.StartLine, h.EndLine)
+		fmt.Fprintf(&sb, "<<<<<<< ours (%s)\n%s\n", h.OursLabel, strings.Join(h.Ours, "\n"))
+		if h.HasBase {
+			fmt.Fprintf(&sb, "||||||| base (%s)\n%s\n", h.BaseLabel, strings.Join(h.Base, "\n"))
+		}
+		fmt.Fprintf(&sb, "=======\n%s\n>>>>>>> theirs (%s)\n", strings.Join(h.Theirs, "\n"), h.TheirsLabel)
+	}
+
+	sb.WriteString("\nUnderstand the intent of both sides, edit the file so that it combines them correctly, " +
+		"and remove every conflict marker. Do not stage or commit the file; I will review the result and mark it as resolved.")
+	return sb.String()
+}
+
+// AskAgentToResolveConflict 将冲突上下文发送到 OpenCode 会话，由 AI 解决（sessionID 为空时使用当前会话）
+func (a *App) AskAgentToResolveConflict(sessionID, dir, path string) error {
+	if sessionID == "" && a.httpServer != nil {
+		sessionID = a.httpServer.GetCurrentSession()
+	}
+	if sessionID == "" {
+		return fmt.Errorf("没有可用的会话")
+	}
+	cf, err := a.GetConflictFile(dir, path)
+	if err != nil {
+		return err
+	}
+	if cf.Binary {
+		return fmt.Errorf("二进制文件无法由 AI 解决: %s", cf.Path)
+	}
+	return a.SendMessage(sessionID, buildConflictPrompt(cf))
+}
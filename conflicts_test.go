@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseConflictHunks tests merge and diff3 style conflict markers
+func TestParseConflictHunks(t *testing.T) {
+	content := "top\n" +
+		"<<<<<<< HEAD\nours 1\n=======\ntheirs 1\n>>>>>>> feature\n" +
+		"middle\n" +
+		"<<<<<<< HEAD\r\nours 2\r\n||||||| base\r\nbase 2\r\n=======\r\ntheirs 2a\r\ntheirs 2b\r\n>>>>>>> feature\r\n" +
+		"<<<<<<< unclosed\n" +
+		"bottom\n"
+
+	hunks := parseConflictHunks(strings.Split(content, "\n"))
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+	h := hunks[0]
+	if h.StartLine != 2 || h.EndLine != 6 || h.HasBase || h.OursLabel != "HEAD" || h.TheirsLabel != "feature" ||
+		strings.Join(h.Ours, "|") != "ours 1" || strings.Join(h.Theirs, "|") != "theirs 1" {
+		t.Errorf("hunks[0] = %+v", h)
+	}
+	h = hunks[1]
+	if h.Index != 1 || h.StartLine != 8 || h.EndLine != 15 || !h.HasBase || h.BaseLabel != "base" ||
+		strings.Join(h.Base, "|") != "base 2" || strings.Join(h.Theirs, "|") != "theirs 2a|theirs 2b" {
+		t.Errorf("hunks[1] = %+v", h)
+	}
+}
+
+// TestResolveConflictLines tests each resolution choice
+func TestResolveConflictLines(t *testing.T) {
+	lines := strings.Split("a\n<<<<<<< HEAD\nours\n||||||| base\nbase\n=======\ntheirs\n>>>>>>> x\nz\n", "\n")
+	tests := []struct {
+		choice string
+		want   string
+	}{
+		{"ours", "a\nours\nz\n"},
+		{"theirs", "a\ntheirs\nz\n"},
+		{"both", "a\nours\ntheirs\nz\n"},
+		{"base", "a\nbase\nz\n"},
+	}
+	for _, tt := range tests {
+		got, err := resolveConflictLines(lines, 0, tt.choice)
+		if err != nil {
+			t.Fatalf("resolveConflictLines(%q) error = %v", tt.choice, err)
+		}
+		if strings.Join(got, "\n") != tt.want {
+			t.Errorf("resolveConflictLines(%q) = %q, want %q", tt.choice, strings.Join(got, "\n"), tt.want)
+		}
+	}
+	if _, err := resolveConflictLines(lines, 1, "ours"); err == nil {
+		t.Error("resolveConflictLines() accepted a missing hunk")
+	}
+	if _, err := resolveConflictLines(lines, 0, "mine"); err == nil {
+		t.Error("resolveConflictLines() accepted an unknown choice")
+	}
+}
+
+// TestConflictResolutionFlow tests detecting, resolving and marking a real merge conflict
+func TestConflictResolutionFlow(t *testing.T) {
+	dir := initTestRepo(t)
+	writeTestFile(t, dir, "a.txt", "1\n2\n3\n4\n5\n6\n7\n8\n9\n")
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "base")
+	mustGit(t, dir, "switch", "-q", "-c", "feature")
+	writeTestFile(t, dir, "a.txt", "1 feature\n2\n3\n4\n5\n6\n7\n8\n9 feature\n")
+	mustGit(t, dir, "commit", "-q", "-am", "feature")
+	mustGit(t, dir, "switch", "-q", "main")
+	writeTestFile(t, dir, "a.txt", "1 main\n2\n3\n4\n5\n6\n7\n8\n9 main\n")
+	mustGit(t, dir, "commit", "-q", "-am", "main")
+	if _, err := runGit(dir, "merge", "feature"); err == nil {
+		t.Fatal("merge succeeded, expected a conflict")
+	}
+
+	a := &App{}
+	status, err := a.GetGitStatus(dir)
+	if err != nil {
+		t.Fatalf("GetGitStatus() error = %v", err)
+	}
+	if status.MergeState != "merging" || len(status.Changes) != 1 || status.Changes[0].Conflict != "UU" {
+		t.Errorf("status = %+v", status)
+	}
+
+	cf, err := a.GetConflictFile(dir, "a.txt")
+	if err != nil {
+		t.Fatalf("GetConflictFile() error = %v", err)
+	}
+	if !cf.HasBase || !cf.HasOurs || !cf.HasTheirs || !strings.HasPrefix(cf.Ours, "1 main") || !strings.HasPrefix(cf.Theirs, "1 feature") || len(cf.Hunks) != 2 {
+		t.Fatalf("conflict file = %+v", cf)
+	}
+	if prompt := buildConflictPrompt(cf); !strings.Contains(prompt, "a.txt") || !strings.Contains(prompt, "9 feature") || !strings.Contains(prompt, "while merging") {
+		t.Errorf("prompt = %q", prompt)
+	}
+
+	if cf, err = a.ResolveConflictHunk(dir, "a.txt", 0, "theirs"); err != nil || len(cf.Hunks) != 1 {
+		t.Fatalf("ResolveConflictHunk() = %+v, %v", cf, err)
+	}
+	if err := a.GitMarkResolved(dir, "a.txt"); err == nil {
+		t.Error("GitMarkResolved() accepted a file with conflict markers")
+	}
+	if _, err = a.ResolveConflictHunk(dir, "a.txt", 0, "both"); err != nil {
+		t.Fatalf("ResolveConflictHunk() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.txt"))
+	if string(data) != "1 feature\n2\n3\n4\n5\n6\n7\n8\n9 main\n9 feature\n" {
+		t.Errorf("resolved content = %q", data)
+	}
+	if err := a.GitMarkResolved(dir, "a.txt"); err != nil {
+		t.Fatalf("GitMarkResolved() error = %v", err)
+	}
+	if conflicts, _ := a.GetGitConflicts(dir); len(conflicts) != 0 {
+		t.Errorf("conflicts after resolve = %+v", conflicts)
+	}
+}
@@ -0,0 +1,957 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DebugBreakpoint 断点
+type DebugBreakpoint struct {
+	ID         int    `json:"id,omitempty"`
+	Line       int    `json:"line"`
+	Condition  string `json:"condition,omitempty"`
+	LogMessage string `json:"logMessage,omitempty"`
+	Verified   bool   `json:"verified"`
+	Message    string `json:"message,omitempty"`
+}
+
+// DebugSessionInfo 调试会话信息
+type DebugSessionInfo struct {
+	ID       string `json:"id"`
+	Language string `json:"language"` // go, python, node
+	Program  string `json:"program"`
+	Status   string `json:"status"` // starting, running, stopped, terminated
+	ThreadID int    `json:"threadId,omitempty"`
+	Reason   string `json:"reason,omitempty"` // 最近一次暂停原因
+}
+
+// DebugThread 线程
+type DebugThread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// DebugStackFrame 调用栈帧
+type DebugStackFrame struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// DebugScope 作用域
+type DebugScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+// DebugVariable 变量
+type DebugVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// dapMessage DAP 协议消息（请求、响应、事件）
+type dapMessage struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"`
+	Command    string          `json:"command,omitempty"`
+	Event      string          `json:"event,omitempty"`
+	RequestSeq int             `json:"request_seq,omitempty"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// dapRequestTimeout 单个 DAP 请求的超时时间
+const dapRequestTimeout = 30 * time.Second
+
+// readDAPMessage 读取一条 Content-Length 分帧的 DAP 消息
+func readDAPMessage(r *bufio.Reader) (*dapMessage, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("无效的 Content-Length: %s", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("缺少 Content-Length 头")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	var msg dapMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("解析 DAP 消息失败: %v", err)
+	}
+	return &msg, nil
+}
+
+// writeDAPMessage 以 Content-Length 分帧写入一条 DAP 消息
+func writeDAPMessage(w io.Writer, msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// dapClient 单条 DAP 连接
+type dapClient struct {
+	conn        io.ReadWriteCloser
+	seq         int
+	pending     map[int]chan *dapMessage
+	initialized chan struct{}
+	closed      chan struct{}
+	onEvent     func(*dapMessage)
+	onRequest   func(*dapMessage) (interface{}, error)
+	mu          sync.Mutex
+	writeMu     sync.Mutex
+	initOnce    sync.Once
+	closeOnce   sync.Once
+}
+
+// newDAPClient 创建 DAP 客户端并开始读取消息
+func newDAPClient(conn io.ReadWriteCloser, onEvent func(*dapMessage), onRequest func(*dapMessage) (interface{}, error)) *dapClient {
+	c := &dapClient{
+		conn:        conn,
+		pending:     make(map[int]chan *dapMessage),
+		initialized: make(chan struct{}),
+		closed:      make(chan struct{}),
+		onEvent:     onEvent,
+		onRequest:   onRequest,
+	}
+	go c.readLoop()
+	return c
+}
+
+// readLoop 分发响应、事件和反向请求
+func (c *dapClient) readLoop() {
+	defer c.Close()
+	reader := bufio.NewReader(c.conn)
+	for {
+		msg, err := readDAPMessage(reader)
+		if err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "response":
+			c.mu.Lock()
+			ch, ok := c.pending[msg.RequestSeq]
+			delete(c.pending, msg.RequestSeq)
+			c.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+		case "event":
+			if msg.Event == "initialized" {
+				c.initOnce.Do(func() { close(c.initialized) })
+			}
+			if c.onEvent != nil {
+				c.onEvent(msg)
+			}
+		case "request":
+			go c.handleReverseRequest(msg)
+		}
+	}
+}
+
+// handleReverseRequest 响应调试适配器发来的请求（如 startDebugging）
+func (c *dapClient) handleReverseRequest(msg *dapMessage) {
+	var body interface{}
+	var err error
+	if c.onRequest != nil {
+		body, err = c.onRequest(msg)
+	} else {
+		err = fmt.Errorf("不支持的请求: %s", msg.Command)
+	}
+
+	resp := map[string]interface{}{
+		"type":        "response",
+		"request_seq": msg.Seq,
+		"command":     msg.Command,
+		"success":     err == nil,
+	}
+	if err != nil {
+		resp["message"] = err.Error()
+	}
+	if body != nil {
+		resp["body"] = body
+	}
+	c.send(resp)
+}
+
+// send 写入一条消息并分配序号
+func (c *dapClient) send(msg map[string]interface{}) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	c.mu.Unlock()
+	msg["seq"] = seq
+	return seq, writeDAPMessage(c.conn, msg)
+}
+
+// start 发送请求，返回等待响应的通道
+func (c *dapClient) start(command string, args interface{}) (<-chan *dapMessage, error) {
+	req := map[string]interface{}{
+		"type":    "request",
+		"command": command,
+	}
+	if args != nil {
+		req["arguments"] = args
+	}
+
+	ch := make(chan *dapMessage, 1)
+	c.writeMu.Lock()
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	c.pending[seq] = ch
+	c.mu.Unlock()
+	req["seq"] = seq
+	err := writeDAPMessage(c.conn, req)
+	c.writeMu.Unlock()
+
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, seq)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("发送 %s 请求失败: %v", command, err)
+	}
+	return ch, nil
+}
+
+// wait 等待响应并解析 body
+func (c *dapClient) wait(command string, ch <-chan *dapMessage, result interface{}) error {
+	select {
+	case resp := <-ch:
+		if !resp.Success {
+			return fmt.Errorf("%s 失败: %s", command, resp.Message)
+		}
+		if result != nil && len(resp.Body) > 0 {
+			if err := json.Unmarshal(resp.Body, result); err != nil {
+				return fmt.Errorf("解析 %s 响应失败: %v", command, err)
+			}
+		}
+		return nil
+	case <-c.closed:
+		return fmt.Errorf("调试适配器连接已关闭")
+	case <-time.After(dapRequestTimeout):
+		return fmt.Errorf("%s 请求超时", command)
+	}
+}
+
+// request 发送请求并同步等待响应
+func (c *dapClient) request(command string, args interface{}, result interface{}) error {
+	ch, err := c.start(command, args)
+	if err != nil {
+		return err
+	}
+	return c.wait(command, ch, result)
+}
+
+// Close 关闭连接
+func (c *dapClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.conn.Close()
+	})
+}
+
+// stdioConn 将子进程的 stdin/stdout 组合为连接
+type stdioConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+// debugSession 单个调试会话
+type debugSession struct {
+	info    DebugSessionInfo
+	cmd     *exec.Cmd
+	client  *dapClient
+	child   *dapClient // js-debug 通过 startDebugging 创建的子会话
+	address string     // TCP 适配器地址，用于子会话连接
+}
+
+// active 返回实际处理调试命令的连接
+func (s *debugSession) active() *dapClient {
+	if s.child != nil {
+		return s.child
+	}
+	return s.client
+}
+
+// DebugManager 调试管理器
+type DebugManager struct {
+	app         *App
+	sessions    map[string]*debugSession
+	breakpoints map[string][]DebugBreakpoint
+	nextID      int
+	mu          sync.Mutex
+}
+
+// NewDebugManager 创建调试管理器
+func NewDebugManager(app *App) *DebugManager {
+	return &DebugManager{
+		app:         app,
+		sessions:    make(map[string]*debugSession),
+		breakpoints: make(map[string][]DebugBreakpoint),
+	}
+}
+
+// detectDebugLanguage 根据文件扩展名推断调试语言
+func detectDebugLanguage(program string) string {
+	switch strings.ToLower(filepath.Ext(program)) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js", ".mjs", ".cjs", ".ts":
+		return "node"
+	}
+	if fileExists(filepath.Join(program, "go.mod")) || fileExists(filepath.Join(program, "main.go")) {
+		return "go"
+	}
+	return ""
+}
+
+// Start 启动调试会话
+func (dm *DebugManager) Start(language, program string, args []string, cwd string) (*DebugSessionInfo, error) {
+	if program == "" {
+		return nil, fmt.Errorf("调试目标为空")
+	}
+	if language == "" {
+		language = detectDebugLanguage(program)
+	}
+	if args == nil {
+		args = []string{}
+	}
+
+	dm.mu.Lock()
+	dm.nextID++
+	id := fmt.Sprintf("debug-%d", dm.nextID)
+	dm.mu.Unlock()
+
+	session := &debugSession{
+		info: DebugSessionInfo{ID: id, Language: language, Program: program, Status: "starting"},
+	}
+
+	var launchArgs map[string]interface{}
+	var err error
+	switch language {
+	case "go":
+		launchArgs = map[string]interface{}{"request": "launch", "mode": "debug", "program": program, "args": args, "cwd": cwd}
+		err = dm.startTCPAdapter(session, "dlv", func(addr string) []string {
+			return []string{"dap", "--listen", addr}
+		})
+	case "python":
+		launchArgs = map[string]interface{}{"request": "launch", "program": program, "args": args, "cwd": cwd, "console": "internalConsole", "justMyCode": true}
+		err = dm.startStdioAdapter(session, pythonCommand(), "-m", "debugpy.adapter")
+	case "node":
+		launchArgs = map[string]interface{}{"type": "pwa-node", "request": "launch", "program": program, "args": args, "cwd": cwd, "console": "internalConsole"}
+		err = dm.startJSDebugAdapter(session)
+	default:
+		return nil, fmt.Errorf("不支持调试该类型的文件: %s", program)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dm.mu.Lock()
+	dm.sessions[id] = session
+	dm.mu.Unlock()
+
+	// 适配器意外退出时清理会话
+	go func() {
+		<-session.client.closed
+		dm.Stop(id)
+	}()
+
+	if err := dm.configure(session, session.client, "launch", launchArgs, language); err != nil {
+		dm.Stop(id)
+		return nil, err
+	}
+
+	dm.mu.Lock()
+	if session.info.Status == "starting" {
+		session.info.Status = "running"
+	}
+	info := session.info
+	dm.mu.Unlock()
+	return &info, nil
+}
+
+// startTCPAdapter 启动监听 TCP 端口的调试适配器（delve、js-debug）
+func (dm *DebugManager) startTCPAdapter(session *debugSession, command string, buildArgs func(addr string) []string) error {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return fmt.Errorf("未找到调试器 %s，请先安装", command)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return fmt.Errorf("分配调试端口失败: %v", err)
+	}
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	cmd := exec.Command(path, buildArgs(addr)...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动调试器失败: %v", err)
+	}
+	session.cmd = cmd
+	session.address = addr
+
+	conn, err := dialWithRetry(addr, 10*time.Second)
+	if err != nil {
+		cmd.Process.Kill()
+		return fmt.Errorf("连接调试器失败: %v", err)
+	}
+	session.client = newDAPClient(conn, dm.eventHandler(session), dm.requestHandler(session))
+	return nil
+}
+
+// startStdioAdapter 启动通过 stdin/stdout 通信的调试适配器（debugpy）
+func (dm *DebugManager) startStdioAdapter(session *debugSession, command string, args ...string) error {
+	cmd := exec.Command(command, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = io.Discard
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动调试器失败（需要 pip install debugpy）: %v", err)
+	}
+	session.cmd = cmd
+	session.client = newDAPClient(stdioConn{Reader: stdout, WriteCloser: stdin}, dm.eventHandler(session), dm.requestHandler(session))
+	return nil
+}
+
+// startJSDebugAdapter 启动 js-debug 适配器（js-debug-adapter 命令或 JS_DEBUG_PATH 指向的 dapDebugServer.js）
+func (dm *DebugManager) startJSDebugAdapter(session *debugSession) error {
+	if server := os.Getenv("JS_DEBUG_PATH"); server != "" {
+		return dm.startTCPAdapter(session, "node", func(addr string) []string {
+			host, port, _ := net.SplitHostPort(addr)
+			return []string{server, port, host}
+		})
+	}
+	return dm.startTCPAdapter(session, "js-debug-adapter", func(addr string) []string {
+		host, port, _ := net.SplitHostPort(addr)
+		return []string{port, host}
+	})
+}
+
+// configure 执行 initialize → launch/attach → setBreakpoints → configurationDone 握手
+func (dm *DebugManager) configure(session *debugSession, client *dapClient, request string, launchArgs map[string]interface{}, adapterID string) error {
+	initArgs := map[string]interface{}{
+		"clientID":                      "opencode-desktop",
+		"clientName":                    "OpenCode Desktop",
+		"adapterID":                     adapterID,
+		"pathFormat":                    "path",
+		"linesStartAt1":                 true,
+		"columnsStartAt1":               true,
+		"supportsVariableType":          true,
+		"supportsStartDebuggingRequest": true,
+	}
+	if err := client.request("initialize", initArgs, nil); err != nil {
+		return err
+	}
+
+	// launch 响应可能在 configurationDone 之后才返回，先异步发送
+	launchCh, err := client.start(request, launchArgs)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-client.initialized:
+	case resp := <-launchCh:
+		if !resp.Success {
+			return fmt.Errorf("%s 失败: %s", request, resp.Message)
+		}
+		launchCh = nil
+		select {
+		case <-client.initialized:
+		case <-time.After(dapRequestTimeout):
+			return fmt.Errorf("等待调试器初始化超时")
+		}
+	case <-client.closed:
+		return fmt.Errorf("调试适配器连接已关闭")
+	case <-time.After(dapRequestTimeout):
+		return fmt.Errorf("等待调试器初始化超时")
+	}
+
+	dm.mu.Lock()
+	files := make(map[string][]DebugBreakpoint, len(dm.breakpoints))
+	for file, bps := range dm.breakpoints {
+		files[file] = bps
+	}
+	dm.mu.Unlock()
+	for file, bps := range files {
+		if verified, err := sendBreakpoints(client, file, bps); err == nil {
+			dm.updateBreakpoints(file, verified)
+		}
+	}
+
+	if err := client.request("configurationDone", nil, nil); err != nil {
+		return err
+	}
+	if launchCh != nil {
+		return client.wait(request, launchCh, nil)
+	}
+	return nil
+}
+
+// eventHandler 处理适配器事件并推送到前端
+func (dm *DebugManager) eventHandler(session *debugSession) func(*dapMessage) {
+	return func(msg *dapMessage) {
+		var body map[string]interface{}
+		json.Unmarshal(msg.Body, &body)
+		id := session.info.ID
+
+		switch msg.Event {
+		case "stopped":
+			dm.mu.Lock()
+			session.info.Status = "stopped"
+			if tid, ok := body["threadId"].(float64); ok {
+				session.info.ThreadID = int(tid)
+			}
+			session.info.Reason, _ = body["reason"].(string)
+			info := session.info
+			dm.mu.Unlock()
+			dm.app.emitEvent("debug-stopped", map[string]interface{}{"session": info, "body": body})
+		case "continued":
+			dm.mu.Lock()
+			session.info.Status = "running"
+			info := session.info
+			dm.mu.Unlock()
+			dm.app.emitEvent("debug-continued", info)
+		case "output":
+			dm.app.emitEvent("debug-output", map[string]interface{}{
+				"sessionId": id,
+				"category":  body["category"],
+				"output":    body["output"],
+			})
+		case "breakpoint":
+			dm.app.emitEvent("debug-breakpoint", map[string]interface{}{"sessionId": id, "body": body})
+		case "terminated", "exited":
+			if msg.Event == "exited" {
+				dm.app.emitEvent("debug-exited", map[string]interface{}{"sessionId": id, "exitCode": body["exitCode"]})
+				return
+			}
+			go dm.Stop(id)
+		}
+	}
+}
+
+// requestHandler 处理适配器的反向请求
+func (dm *DebugManager) requestHandler(session *debugSession) func(*dapMessage) (interface{}, error) {
+	return func(msg *dapMessage) (interface{}, error) {
+		if msg.Command != "startDebugging" {
+			return nil, fmt.Errorf("不支持的请求: %s", msg.Command)
+		}
+		if session.address == "" {
+			return nil, fmt.Errorf("当前调试器不支持子会话")
+		}
+
+		var args struct {
+			Configuration map[string]interface{} `json:"configuration"`
+			Request       string                 `json:"request"`
+		}
+		if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+
+		conn, err := dialWithRetry(session.address, 5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		child := newDAPClient(conn, dm.eventHandler(session), dm.requestHandler(session))
+
+		dm.mu.Lock()
+		if dm.sessions[session.info.ID] != session {
+			// 会话已结束，不再接管子会话
+			dm.mu.Unlock()
+			child.Close()
+			return nil, fmt.Errorf("调试会话已结束")
+		}
+		session.child = child
+		dm.mu.Unlock()
+
+		go func() {
+			if err := dm.configure(session, child, args.Request, args.Configuration, session.info.Language); err != nil {
+				dm.app.emitEvent("debug-output", map[string]interface{}{
+					"sessionId": session.info.ID,
+					"category":  "stderr",
+					"output":    fmt.Sprintf("子调试会话启动失败: %v\n", err),
+				})
+			}
+		}()
+		return nil, nil
+	}
+}
+
+// Stop 结束调试会话并清理适配器进程
+func (dm *DebugManager) Stop(id string) error {
+	dm.mu.Lock()
+	session, ok := dm.sessions[id]
+	var client, child *dapClient
+	if ok {
+		delete(dm.sessions, id)
+		session.info.Status = "terminated"
+		client, child = session.active(), session.child
+	}
+	dm.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-client.closed:
+	default:
+		done := make(chan struct{})
+		go func() {
+			client.request("disconnect", map[string]interface{}{"terminateDebuggee": true}, nil)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+		}
+	}
+
+	if child != nil {
+		child.Close()
+	}
+	session.client.Close()
+	if session.cmd != nil && session.cmd.Process != nil {
+		session.cmd.Process.Kill()
+		session.cmd.Wait()
+	}
+
+	dm.app.emitEvent("debug-terminated", session.info)
+	return nil
+}
+
+// StopAll 结束所有调试会话
+func (dm *DebugManager) StopAll() {
+	dm.mu.Lock()
+	ids := make([]string, 0, len(dm.sessions))
+	for id := range dm.sessions {
+		ids = append(ids, id)
+	}
+	dm.mu.Unlock()
+	for _, id := range ids {
+		dm.Stop(id)
+	}
+}
+
+// ListSessions 列出调试会话
+func (dm *DebugManager) ListSessions() []DebugSessionInfo {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	sessions := make([]DebugSessionInfo, 0, len(dm.sessions))
+	for _, s := range dm.sessions {
+		sessions = append(sessions, s.info)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions
+}
+
+// client 获取会话当前使用的 DAP 连接
+func (dm *DebugManager) client(id string) (*dapClient, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	session, ok := dm.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("调试会话不存在: %s", id)
+	}
+	return session.active(), nil
+}
+
+// SetBreakpoints 设置文件的全部断点，并同步到所有活动会话
+func (dm *DebugManager) SetBreakpoints(file string, breakpoints []DebugBreakpoint) ([]DebugBreakpoint, error) {
+	dm.mu.Lock()
+	if len(breakpoints) == 0 {
+		delete(dm.breakpoints, file)
+	} else {
+		dm.breakpoints[file] = breakpoints
+	}
+	clients := make([]*dapClient, 0, len(dm.sessions))
+	for _, s := range dm.sessions {
+		clients = append(clients, s.active())
+	}
+	dm.mu.Unlock()
+
+	result := breakpoints
+	for _, c := range clients {
+		verified, err := sendBreakpoints(c, file, breakpoints)
+		if err != nil {
+			return nil, err
+		}
+		result = verified
+	}
+	dm.updateBreakpoints(file, result)
+	if result == nil {
+		result = []DebugBreakpoint{}
+	}
+	return result, nil
+}
+
+// GetBreakpoints 获取所有文件的断点
+func (dm *DebugManager) GetBreakpoints() map[string][]DebugBreakpoint {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	result := make(map[string][]DebugBreakpoint, len(dm.breakpoints))
+	for file, bps := range dm.breakpoints {
+		result[file] = append([]DebugBreakpoint{}, bps...)
+	}
+	return result
+}
+
+// updateBreakpoints 记录适配器返回的断点验证状态
+func (dm *DebugManager) updateBreakpoints(file string, bps []DebugBreakpoint) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if _, ok := dm.breakpoints[file]; ok && len(bps) > 0 {
+		dm.breakpoints[file] = bps
+	}
+}
+
+// sendBreakpoints 发送 setBreakpoints 请求
+func sendBreakpoints(client *dapClient, file string, bps []DebugBreakpoint) ([]DebugBreakpoint, error) {
+	source := make([]map[string]interface{}, 0, len(bps))
+	for _, bp := range bps {
+		item := map[string]interface{}{"line": bp.Line}
+		if bp.Condition != "" {
+			item["condition"] = bp.Condition
+		}
+		if bp.LogMessage != "" {
+			item["logMessage"] = bp.LogMessage
+		}
+		source = append(source, item)
+	}
+
+	var resp struct {
+		Breakpoints []DebugBreakpoint `json:"breakpoints"`
+	}
+	args := map[string]interface{}{
+		"source":      map[string]interface{}{"path": file, "name": filepath.Base(file)},
+		"breakpoints": source,
+	}
+	if err := client.request("setBreakpoints", args, &resp); err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Breakpoints {
+		if i < len(bps) {
+			resp.Breakpoints[i].Condition = bps[i].Condition
+			resp.Breakpoints[i].LogMessage = bps[i].LogMessage
+			if resp.Breakpoints[i].Line == 0 {
+				resp.Breakpoints[i].Line = bps[i].Line
+			}
+		}
+	}
+	return resp.Breakpoints, nil
+}
+
+// Step 执行 continue / next / stepIn / stepOut / pause
+func (dm *DebugManager) Step(id, command string, threadID int) error {
+	switch command {
+	case "continue", "next", "stepIn", "stepOut", "pause":
+	default:
+		return fmt.Errorf("不支持的调试命令: %s", command)
+	}
+
+	client, err := dm.client(id)
+	if err != nil {
+		return err
+	}
+	if threadID == 0 {
+		dm.mu.Lock()
+		if s, ok := dm.sessions[id]; ok {
+			threadID = s.info.ThreadID
+		}
+		dm.mu.Unlock()
+	}
+	if err := client.request(command, map[string]interface{}{"threadId": threadID}, nil); err != nil {
+		return err
+	}
+
+	if command != "pause" {
+		dm.mu.Lock()
+		if s, ok := dm.sessions[id]; ok {
+			s.info.Status = "running"
+		}
+		dm.mu.Unlock()
+	}
+	return nil
+}
+
+// Threads 获取线程列表
+func (dm *DebugManager) Threads(id string) ([]DebugThread, error) {
+	client, err := dm.client(id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Threads []DebugThread `json:"threads"`
+	}
+	if err := client.request("threads", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Threads, nil
+}
+
+// StackTrace 获取线程调用栈
+func (dm *DebugManager) StackTrace(id string, threadID int) ([]DebugStackFrame, error) {
+	client, err := dm.client(id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		StackFrames []struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Line   int    `json:"line"`
+			Column int    `json:"column"`
+			Source *struct {
+				Path string `json:"path"`
+			} `json:"source"`
+		} `json:"stackFrames"`
+	}
+	if err := client.request("stackTrace", map[string]interface{}{"threadId": threadID, "levels": 100}, &resp); err != nil {
+		return nil, err
+	}
+
+	frames := make([]DebugStackFrame, 0, len(resp.StackFrames))
+	for _, f := range resp.StackFrames {
+		frame := DebugStackFrame{ID: f.ID, Name: f.Name, Line: f.Line, Column: f.Column}
+		if f.Source != nil {
+			frame.File = f.Source.Path
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// Scopes 获取栈帧的作用域
+func (dm *DebugManager) Scopes(id string, frameID int) ([]DebugScope, error) {
+	client, err := dm.client(id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Scopes []DebugScope `json:"scopes"`
+	}
+	if err := client.request("scopes", map[string]interface{}{"frameId": frameID}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Scopes, nil
+}
+
+// Variables 获取变量（作用域或展开的复合变量）
+func (dm *DebugManager) Variables(id string, reference int) ([]DebugVariable, error) {
+	client, err := dm.client(id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Variables []DebugVariable `json:"variables"`
+	}
+	if err := client.request("variables", map[string]interface{}{"variablesReference": reference}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Variables, nil
+}
+
+// Evaluate 在栈帧上下文中求值表达式
+func (dm *DebugManager) Evaluate(id, expression string, frameID int) (*DebugVariable, error) {
+	client, err := dm.client(id)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result             string `json:"result"`
+		Type               string `json:"type"`
+		VariablesReference int    `json:"variablesReference"`
+	}
+	args := map[string]interface{}{"expression": expression, "context": "repl"}
+	if frameID > 0 {
+		args["frameId"] = frameID
+	}
+	if err := client.request("evaluate", args, &resp); err != nil {
+		return nil, err
+	}
+	return &DebugVariable{Name: expression, Value: resp.Result, Type: resp.Type, VariablesReference: resp.VariablesReference}, nil
+}
+
+// freePort 获取一个空闲的本地端口
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// dialWithRetry 在超时时间内重试连接
+func dialWithRetry(addr string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestDAPMessageFraming tests Content-Length framing round trip
+func TestDAPMessageFraming(t *testing.T) {
+	var buf bytes.Buffer
+	msg := map[string]interface{}{"seq": 1, "type": "event", "event": "output", "body": map[string]string{"output": "hello"}}
+	if err := writeDAPMessage(&buf, msg); err != nil {
+		t.Fatalf("writeDAPMessage() error = %v", err)
+	}
+	if err := writeDAPMessage(&buf, map[string]interface{}{"seq": 2, "type": "response", "request_seq": 5, "success": true}); err != nil {
+		t.Fatalf("writeDAPMessage() error = %v", err)
+	}
+
+	reader := bufio.NewReader(&buf)
+	first, err := readDAPMessage(reader)
+	if err != nil {
+		t.Fatalf("readDAPMessage() error = %v", err)
+	}
+	if first.Type != "event" || first.Event != "output" {
+		t.Errorf("Unexpected first message: %+v", first)
+	}
+
+	second, err := readDAPMessage(reader)
+	if err != nil {
+		t.Fatalf("readDAPMessage() error = %v", err)
+	}
+	if second.RequestSeq != 5 || !second.Success {
+		t.Errorf("Unexpected second message: %+v", second)
+	}
+}
+
+// TestReadDAPMessageMissingLength tests rejection of frames without Content-Length
+func TestReadDAPMessageMissingLength(t *testing.T) {
+	reader := bufio.NewReader(bytes.NewBufferString("X-Other: 1\r\n\r\n{}"))
+	if _, err := readDAPMessage(reader); err == nil {
+		t.Error("Expected error for missing Content-Length")
+	}
+}
+
+// TestDAPClientRequest tests request/response correlation and event dispatch
+func TestDAPClientRequest(t *testing.T) {
+	clientConn, adapterConn := net.Pipe()
+	defer adapterConn.Close()
+
+	events := make(chan *dapMessage, 1)
+	client := newDAPClient(clientConn, func(m *dapMessage) { events <- m }, nil)
+	defer client.Close()
+
+	// Fake adapter: answer setBreakpoints and emit a stopped event
+	go func() {
+		reader := bufio.NewReader(adapterConn)
+		req, err := readDAPMessage(reader)
+		if err != nil {
+			return
+		}
+		var args struct {
+			Breakpoints []struct {
+				Line int `json:"line"`
+			} `json:"breakpoints"`
+		}
+		json.Unmarshal(req.Arguments, &args)
+
+		bps := []map[string]interface{}{}
+		for i, bp := range args.Breakpoints {
+			bps = append(bps, map[string]interface{}{"id": i + 1, "line": bp.Line, "verified": true})
+		}
+		writeDAPMessage(adapterConn, map[string]interface{}{
+			"seq": 1, "type": "response", "request_seq": req.Seq, "command": req.Command, "success": true,
+			"body": map[string]interface{}{"breakpoints": bps},
+		})
+		writeDAPMessage(adapterConn, map[string]interface{}{
+			"seq": 2, "type": "event", "event": "stopped", "body": map[string]interface{}{"reason": "breakpoint", "threadId": 1},
+		})
+	}()
+
+	verified, err := sendBreakpoints(client, "/work/main.go", []DebugBreakpoint{{Line: 10, Condition: "x > 1"}, {Line: 20}})
+	if err != nil {
+		t.Fatalf("sendBreakpoints() error = %v", err)
+	}
+	if len(verified) != 2 {
+		t.Fatalf("Expected 2 breakpoints, got %d", len(verified))
+	}
+	if !verified[0].Verified || verified[0].Line != 10 || verified[0].Condition != "x > 1" {
+		t.Errorf("Unexpected breakpoint: %+v", verified[0])
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Event != "stopped" {
+			t.Errorf("Expected stopped event, got %s", ev.Event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for stopped event")
+	}
+}
+
+// TestDetectDebugLanguage tests language detection by extension
+func TestDetectDebugLanguage(t *testing.T) {
+	tests := map[string]string{
+		"/app/main.go":   "go",
+		"/app/script.py": "python",
+		"/app/index.js":  "node",
+		"/app/README.md": "",
+	}
+	for program, expected := range tests {
+		if got := detectDebugLanguage(program); got != expected {
+			t.Errorf("detectDebugLanguage(%s) = %q, want %q", program, got, expected)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffHunk 统一 diff 的一个区块
+type DiffHunk struct {
+	OldStart int      `json:"oldStart"`
+	OldLines int      `json:"oldLines"`
+	NewStart int      `json:"newStart"`
+	NewLines int      `json:"newLines"`
+	Lines    []string `json:"lines"` // 以 ' '、'-'、'+' 开头
+}
+
+// diffOp 行级编辑操作
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	line string
+}
+
+// noNewlineMarker 统一 diff 中标记文件末尾缺少换行的行
+const noNewlineMarker = "\\ No newline at end of file"
+
+// splitLines 按行拆分文本（保留空的末行语义：末尾换行不产生额外空行）
+// 行尾的 \r 保留在行内，换行符从 CRLF 变为 LF（或相反）的行也会出现在 diff 中；
+// 末行缺少换行时附加 noNewlineMarker，使 "x" 与 "x\n" 被视为不同的行
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	noEOL := !strings.HasSuffix(s, "\n")
+	s = strings.TrimSuffix(s, "\n")
+	lines := strings.Split(s, "\n")
+	if noEOL {
+		lines[len(lines)-1] += "\n" + noNewlineMarker
+	}
+	return lines
+}
+
+// diffLines 使用 Myers 算法计算两组行之间的编辑序列
+func diffLines(a, b []string) []diffOp {
+	// 去掉公共前后缀以缩小问题规模
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+// myersDiff Myers O(ND) 差分
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	max := n + m
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		// 差异过大时放弃最小编辑序列，直接整体替换，避免轨迹占用过多内存
+		if d*len(v) > maxDiffTraceCells {
+			return replaceAllOps(a, b)
+		}
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackMyers(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// maxDiffTraceCells Myers 轨迹允许的最大单元数
+const maxDiffTraceCells = 32 * 1024 * 1024
+
+// replaceAllOps 删除全部旧行并添加全部新行
+func replaceAllOps(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, l := range a {
+		ops = append(ops, diffOp{'-', l})
+	}
+	for _, l := range b {
+		ops = append(ops, diffOp{'+', l})
+	}
+	return ops
+}
+
+// backtrackMyers 根据搜索轨迹回溯出编辑序列
+func backtrackMyers(trace [][]int, a, b []string, offset int) []diffOp {
+	x, y := len(a), len(b)
+	var ops []diffOp
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, diffOp{'+', b[y]})
+			} else {
+				x--
+				ops = append(ops, diffOp{'-', a[x]})
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// ComputeHunks 计算带上下文的 diff 区块
+func ComputeHunks(oldText, newText string, context int) []DiffHunk {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// 每个操作之前的旧/新行号（从 1 开始）
+	oldAt := make([]int, len(ops)+1)
+	newAt := make([]int, len(ops)+1)
+	oldAt[0], newAt[0] = 1, 1
+	var changes []int
+	for i, op := range ops {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if op.kind != '+' {
+			oldAt[i+1]++
+		}
+		if op.kind != '-' {
+			newAt[i+1]++
+		}
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	hunks := []DiffHunk{}
+	for g := 0; g < len(changes); {
+		// 相距不超过 2*context 的改动合并到同一区块
+		last := g
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		from := changes[g] - context
+		if from < 0 {
+			from = 0
+		}
+		to := changes[last] + context + 1
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		h := DiffHunk{OldStart: oldAt[from], NewStart: newAt[from]}
+		for _, op := range ops[from:to] {
+			// 缺少末尾换行的行后面跟一行标记，与 git 输出一致
+			line, marker, noEOL := strings.Cut(op.line, "\n")
+			h.Lines = append(h.Lines, string(op.kind)+line)
+			if noEOL {
+				h.Lines = append(h.Lines, marker)
+			}
+			if op.kind != '+' {
+				h.OldLines++
+			}
+			if op.kind != '-' {
+				h.NewLines++
+			}
+		}
+		// 统一 diff 约定：空区块的起始行号指向前一行
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		hunks = append(hunks, h)
+		g = last + 1
+	}
+	return hunks
+}
+
+// UnifiedDiff 生成统一 diff 文本
+func UnifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	hunks := ComputeHunks(oldText, newText, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		sb.WriteString(h.Header())
+		sb.WriteString("\n")
+		for _, l := range h.Lines {
+			sb.WriteString(l)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// Header 返回区块头 "@@ -a,b +c,d @@"
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+// TestUnifiedDiff tests hunk headers and context grouping
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	hunks := ComputeHunks(oldText, newText, 1)
+	if len(hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d: %+v", len(hunks), hunks)
+	}
+	if hunks[0].Header() != "@@ -1,3 +1,3 @@" {
+		t.Errorf("Unexpected first header: %s", hunks[0].Header())
+	}
+	if hunks[1].Header() != "@@ -10,1 +10,2 @@" {
+		t.Errorf("Unexpected second header: %s", hunks[1].Header())
+	}
+
+	if got := UnifiedDiff("a", "b", "x\n", "x\n", 3); got != "" {
+		t.Errorf("Expected empty diff for identical text, got %q", got)
+	}
+	expected := "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+x\n"
+	if got := UnifiedDiff("a", "b", "", "x\n", 3); got != expected {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, expected)
+	}
+
+	expected = "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-x\n\\ No newline at end of file\n+x\n"
+	if got := UnifiedDiff("a", "b", "x", "x\n", 3); got != expected {
+		t.Errorf("UnifiedDiff() missing newline = %q, want %q", got, expected)
+	}
+	if got := UnifiedDiff("a", "b", "x", "x", 3); got != "" {
+		t.Errorf("Expected empty diff for identical text without newline, got %q", got)
+	}
+
+	expected = "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-x\r\n+x\n"
+	if got := UnifiedDiff("a", "b", "x\r\n", "x\n", 3); got != expected {
+		t.Errorf("UnifiedDiff() line ending change = %q, want %q", got, expected)
+	}
+}
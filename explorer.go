@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExplorerSettings 文件浏览器过滤设置（按工作区保存在 .opencode/explorer.json）
+type ExplorerSettings struct {
+	ShowHidden     bool     `json:"showHidden"`     // 显示全部条目（忽略下面的过滤规则，仅做标记）
+	HideGitIgnored bool     `json:"hideGitIgnored"` // 隐藏被 .gitignore 忽略的条目
+	Exclude        []string `json:"exclude"`        // 额外隐藏的条目（gitignore 格式）
+	AlwaysShow     []string `json:"alwaysShow"`     // 即使以 "." 开头也显示的条目（gitignore 格式）
+	PageSize       int      `json:"pageSize"`       // 分页大小
+	ShowGitStatus  bool     `json:"showGitStatus"`  // 返回每个条目的 Git 状态
+}
+
+// DirPage 分页的目录列表
+type DirPage struct {
+	Dir     string      `json:"dir"`
+	Items   []*FileInfo `json:"items"`
+	Offset  int         `json:"offset"`
+	Total   int         `json:"total"`
+	HasMore bool        `json:"hasMore"`
+}
+
+// defaultExplorerSettings 默认设置（与旧版本隐藏的目录保持一致）
+func defaultExplorerSettings() ExplorerSettings {
+	return ExplorerSettings{
+		Exclude:       []string{".git/", "node_modules/", "__pycache__/", "vendor/", ".DS_Store"},
+		AlwaysShow:    []string{".github", ".opencode", ".vscode", ".gitignore", ".env.example"},
+		PageSize:      1000,
+		ShowGitStatus: true,
+	}
+}
+
+// explorerSettingsPath 工作区设置文件路径
+func explorerSettingsPath(root string) string {
+	return filepath.Join(root, ".opencode", "explorer.json")
+}
+
+// loadExplorerSettings 读取工作区设置，不存在时返回默认值
+func loadExplorerSettings(root string) (ExplorerSettings, error) {
+	settings := defaultExplorerSettings()
+	data, err := os.ReadFile(explorerSettingsPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("读取文件浏览器设置失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return defaultExplorerSettings(), fmt.Errorf("解析文件浏览器设置失败: %v", err)
+	}
+	if settings.PageSize <= 0 {
+		settings.PageSize = defaultExplorerSettings().PageSize
+	}
+	return settings, nil
+}
+
+// GetExplorerSettings 获取当前工作区的文件浏览器设置
+func (fm *FileManager) GetExplorerSettings() (ExplorerSettings, error) {
+	return loadExplorerSettings(fm.GetRootDir())
+}
+
+// SaveExplorerSettings 保存当前工作区的文件浏览器设置
+func (fm *FileManager) SaveExplorerSettings(settings ExplorerSettings) error {
+	if settings.PageSize <= 0 {
+		settings.PageSize = defaultExplorerSettings().PageSize
+	}
+	path := explorerSettingsPath(fm.GetRootDir())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("保存文件浏览器设置失败: %v", err)
+	}
+	return nil
+}
+
+// explorerFilter 编译后的过滤规则
+type explorerFilter struct {
+	settings   ExplorerSettings
+	exclude    []ignoreRule
+	alwaysShow []ignoreRule
+	gitignore  *IgnoreMatcher
+	root       string
+}
+
+// newExplorerFilter 根据设置创建过滤器
+func (fm *FileManager) newExplorerFilter(settings ExplorerSettings) *explorerFilter {
+	fm.mu.Lock()
+	root, ignore := fm.treeRoot, fm.treeIgnore
+	fm.mu.Unlock()
+	if root == "" || ignore == nil {
+		root = fm.rootDir
+		ignore = NewIgnoreMatcher(root)
+	}
+
+	f := &explorerFilter{settings: settings, gitignore: ignore, root: root}
+	for _, p := range settings.Exclude {
+		if rule, ok := parseIgnoreLine(p, ""); ok {
+			f.exclude = append(f.exclude, rule)
+		}
+	}
+	for _, p := range settings.AlwaysShow {
+		if rule, ok := parseIgnoreLine(p, ""); ok {
+			f.alwaysShow = append(f.alwaysShow, rule)
+		}
+	}
+	return f
+}
+
+// classify 返回条目是否应被隐藏，并给出 hidden/ignored 标记
+func (f *explorerFilter) classify(path string, isDir bool) (skip, hidden, ignored bool) {
+	name := filepath.Base(path)
+	rel, err := filepath.Rel(f.root, path)
+	inRoot := err == nil && !strings.HasPrefix(rel, "..")
+	if !inRoot {
+		rel = name
+	}
+	rel = filepath.ToSlash(rel)
+
+	hidden = strings.HasPrefix(name, ".") && !matchIgnoreRules(f.alwaysShow, rel, isDir)
+	excluded := matchIgnoreRules(f.exclude, rel, isDir)
+	if inRoot {
+		ignored = f.gitignore.Match(rel, isDir)
+	}
+
+	if f.settings.ShowHidden {
+		return false, hidden, ignored
+	}
+	skip = hidden || excluded || (ignored && f.settings.HideGitIgnored)
+	return skip, hidden, ignored
+}
+
+// matchIgnoreRules 按 gitignore 语义（后面的规则优先）判断是否匹配
+func matchIgnoreRules(rules []ignoreRule, rel string, isDir bool) bool {
+	matched := false
+	for _, rule := range rules {
+		if rule.matches(rel, isDir) {
+			matched = !rule.negate
+		}
+	}
+	return matched
+}
+
+// explorerEntry 排序用的轻量条目（分页前不做 stat）
+type explorerEntry struct {
+	entry   os.DirEntry
+	isDir   bool
+	hidden  bool
+	ignored bool
+}
+
+// ListDirPage 分页列出目录内容，offset 从 0 开始，limit <= 0 时使用设置中的分页大小
+func (fm *FileManager) ListDirPage(dir string, offset, limit int) (*DirPage, error) {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(fm.rootDir, dir)
+	}
+
+	settings, _ := loadExplorerSettings(fm.GetRootDir())
+	if limit <= 0 {
+		limit = settings.PageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+
+	filter := fm.newExplorerFilter(settings)
+	var visible []explorerEntry
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(path); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		skip, hidden, ignored := filter.classify(path, isDir)
+		if skip {
+			continue
+		}
+		visible = append(visible, explorerEntry{entry: entry, isDir: isDir, hidden: hidden, ignored: ignored})
+	}
+
+	// 文件夹在前，文件在后，各自按名称排序
+	sort.Slice(visible, func(i, j int) bool {
+		if visible[i].isDir != visible[j].isDir {
+			return visible[i].isDir
+		}
+		return strings.ToLower(visible[i].entry.Name()) < strings.ToLower(visible[j].entry.Name())
+	})
+
+	page := &DirPage{Dir: dir, Offset: offset, Total: len(visible), Items: []*FileInfo{}}
+	if offset >= len(visible) {
+		return page, nil
+	}
+	end := offset + limit
+	if end > len(visible) {
+		end = len(visible)
+	}
+	page.HasMore = end < len(visible)
+
+	var gitStatus map[string]string
+	if settings.ShowGitStatus {
+		gitStatus = gitStatusForDir(dir)
+	}
+
+	for _, e := range visible[offset:end] {
+		page.Items = append(page.Items, buildFileInfo(dir, e, gitStatus))
+	}
+	return page, nil
+}
+
+// buildFileInfo 读取条目元数据
+func buildFileInfo(dir string, e explorerEntry, gitStatus map[string]string) *FileInfo {
+	name := e.entry.Name()
+	path := filepath.Join(dir, name)
+	fi := &FileInfo{
+		Name:    name,
+		Path:    path,
+		Type:    "file",
+		Hidden:  e.hidden,
+		Ignored: e.ignored,
+	}
+	if e.isDir {
+		fi.Type = "folder"
+	}
+
+	if info, err := e.entry.Info(); err == nil {
+		fi.Size = info.Size()
+		fi.ModTime = info.ModTime().UnixMilli()
+		fi.Mode = info.Mode().String()
+		if info.Mode()&os.ModeSymlink != 0 {
+			fi.IsSymlink = true
+			fi.SymlinkTarget, _ = os.Readlink(path)
+		}
+	}
+	if gitStatus != nil {
+		fi.GitStatus = gitStatus[name]
+	}
+	return fi
+}
+
+// gitStatusForDir 返回目录下直接子条目的 Git 状态（子目录取其中任意变化）
+func gitStatusForDir(dir string) map[string]string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	top := strings.TrimSpace(string(out))
+	// 解析符号链接，保证与 dir 的相对路径计算一致
+	if resolved, err := filepath.EvalSymlinks(top); err == nil {
+		top = resolved
+	}
+	realDir := dir
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		realDir = resolved
+	}
+
+	cmd = exec.Command("git", "status", "--porcelain=v1", "-z", "--untracked-files=normal", "--", ".")
+	cmd.Dir = dir
+	out, err = cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	status := make(map[string]string)
+	records := strings.Split(string(out), "\x00")
+	for i := 0; i < len(records); i++ {
+		rec := records[i]
+		if len(rec) < 4 {
+			continue
+		}
+		code := gitStatusCode(rec[:2])
+		if rec[0] == 'R' || rec[0] == 'C' {
+			i++ // 跳过重命名的原路径
+		}
+
+		abs := filepath.Join(top, filepath.FromSlash(strings.TrimSuffix(rec[3:], "/")))
+		rel, err := filepath.Rel(realDir, abs)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		parts := strings.SplitN(rel, string(filepath.Separator), 2)
+		name := parts[0]
+		if len(parts) == 1 {
+			status[name] = code
+		} else if _, ok := status[name]; !ok {
+			status[name] = "M" // 目录内有变化
+		}
+	}
+	return status
+}
+
+// gitStatusCode 将 porcelain XY 状态转换为简短状态
+func gitStatusCode(xy string) string {
+	switch {
+	case xy == "??":
+		return "??"
+	case xy == "!!":
+		return "!!"
+	case strings.Contains(xy, "U") || xy == "AA" || xy == "DD":
+		return "U"
+	case strings.Contains(xy, "D"):
+		return "D"
+	case xy[0] == 'R' || xy[0] == 'C':
+		return "R"
+	case xy[0] == 'A':
+		return "A"
+	default:
+		return "M"
+	}
+}
+
+// --- App API ---
+
+// ListDirPage 分页列出目录内容
+func (a *App) ListDirPage(dir string, offset, limit int) (*DirPage, error) {
+	return a.fileMgr.ListDirPage(dir, offset, limit)
+}
+
+// GetExplorerSettings 获取文件浏览器设置
+func (a *App) GetExplorerSettings() (ExplorerSettings, error) {
+	return a.fileMgr.GetExplorerSettings()
+}
+
+// SaveExplorerSettings 保存文件浏览器设置
+func (a *App) SaveExplorerSettings(settings ExplorerSettings) error {
+	return a.fileMgr.SaveExplorerSettings(settings)
+}
+
+// SetShowHiddenFiles 切换显示隐藏文件
+func (a *App) SetShowHiddenFiles(show bool) error {
+	settings, err := a.fileMgr.GetExplorerSettings()
+	if err != nil {
+		return err
+	}
+	settings.ShowHidden = show
+	return a.fileMgr.SaveExplorerSettings(settings)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// setupExplorerWorkspace creates a workspace for explorer tests
+func setupExplorerWorkspace(t *testing.T) (*FileManager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".gitignore", "dist/\n")
+	writeTestFile(t, dir, ".env.example", "KEY=\n")
+	writeTestFile(t, dir, ".secret", "x\n")
+	writeTestFile(t, dir, ".github/workflows/ci.yml", "on: push\n")
+	writeTestFile(t, dir, "node_modules/pkg/index.js", "x\n")
+	writeTestFile(t, dir, "dist/app.js", "x\n")
+	writeTestFile(t, dir, "main.go", "package main\n")
+
+	fm := NewFileManager(&App{})
+	fm.rootDir = dir
+	return fm, dir
+}
+
+// listNames lists a directory and returns entry names
+func listNames(t *testing.T, fm *FileManager, dir string) map[string]*FileInfo {
+	t.Helper()
+	items, err := fm.ListDir(dir)
+	if err != nil {
+		t.Fatalf("ListDir() error = %v", err)
+	}
+	names := make(map[string]*FileInfo)
+	for _, item := range items {
+		names[item.Name] = item
+	}
+	return names
+}
+
+// TestListDirFilters tests default filters, gitignore hiding and show-hidden mode
+func TestListDirFilters(t *testing.T) {
+	fm, dir := setupExplorerWorkspace(t)
+
+	names := listNames(t, fm, dir)
+	for _, name := range []string{".github", ".env.example", ".gitignore", "dist", "main.go"} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("Expected %s to be listed", name)
+		}
+	}
+	for _, name := range []string{".secret", "node_modules"} {
+		if _, ok := names[name]; ok {
+			t.Errorf("Expected %s to be hidden", name)
+		}
+	}
+	if !names["dist"].Ignored {
+		t.Error("Expected dist to be marked as gitignored")
+	}
+
+	settings, _ := fm.GetExplorerSettings()
+	settings.HideGitIgnored = true
+	settings.Exclude = append(settings.Exclude, "*.go")
+	if err := fm.SaveExplorerSettings(settings); err != nil {
+		t.Fatalf("SaveExplorerSettings() error = %v", err)
+	}
+	names = listNames(t, fm, dir)
+	if _, ok := names["dist"]; ok {
+		t.Error("Expected gitignored dist to be hidden")
+	}
+	if _, ok := names["main.go"]; ok {
+		t.Error("Expected excluded main.go to be hidden")
+	}
+
+	settings.ShowHidden = true
+	fm.SaveExplorerSettings(settings)
+	names = listNames(t, fm, dir)
+	for _, name := range []string{".secret", "node_modules", "dist", "main.go"} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("Expected %s to be listed in show-hidden mode", name)
+		}
+	}
+	if !names[".secret"].Hidden {
+		t.Error("Expected .secret to be marked hidden")
+	}
+}
+
+// TestListDirPage tests pagination and ordering
+func TestListDirPage(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"c.txt", "a.txt", "b.txt", "e.txt", "d.txt"} {
+		writeTestFile(t, dir, name, "x\n")
+	}
+	writeTestFile(t, dir, "zdir/x.txt", "x\n")
+	fm := NewFileManager(&App{})
+	fm.rootDir = dir
+
+	page, err := fm.ListDirPage(dir, 0, 2)
+	if err != nil {
+		t.Fatalf("ListDirPage() error = %v", err)
+	}
+	if page.Total != 6 || !page.HasMore || len(page.Items) != 2 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	if page.Items[0].Name != "zdir" || page.Items[1].Name != "a.txt" {
+		t.Errorf("Expected folders first, got %s, %s", page.Items[0].Name, page.Items[1].Name)
+	}
+
+	page, _ = fm.ListDirPage(dir, 4, 2)
+	if page.HasMore || len(page.Items) != 2 || page.Items[1].Name != "e.txt" {
+		t.Errorf("Unexpected last page: %+v", page)
+	}
+	if page.Items[0].ModTime == 0 || page.Items[0].Mode == "" {
+		t.Errorf("Expected metadata, got %+v", page.Items[0])
+	}
+
+	if err := os.Symlink(filepath.Join(dir, "zdir"), filepath.Join(dir, "link")); err == nil {
+		names := listNames(t, fm, dir)
+		link := names["link"]
+		if link == nil || !link.IsSymlink || link.Type != "folder" || link.SymlinkTarget != filepath.Join(dir, "zdir") {
+			t.Errorf("Unexpected symlink info: %+v", link)
+		}
+	}
+}
+
+// TestListDirGitStatus tests git status metadata for files and folders
+func TestListDirGitStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, dir, "tracked.txt", "a\n")
+	writeTestFile(t, dir, "sub/inner.txt", "a\n")
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	writeTestFile(t, dir, "tracked.txt", "b\n")
+	writeTestFile(t, dir, "sub/inner.txt", "b\n")
+	writeTestFile(t, dir, "new.txt", "x\n")
+
+	fm := NewFileManager(&App{})
+	fm.rootDir = dir
+	names := listNames(t, fm, dir)
+	expected := map[string]string{"tracked.txt": "M", "new.txt": "??", "sub": "M"}
+	for name, status := range expected {
+		if names[name] == nil || names[name].GitStatus != status {
+			t.Errorf("GitStatus(%s) = %+v, want %s", name, names[name], status)
+		}
+	}
+}
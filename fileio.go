@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// maxEditableFileSize 编辑器可打开的最大文件大小
+const maxEditableFileSize = 5 * 1024 * 1024
+
+// binarySniffSize 判断二进制文件时检查的字节数
+const binarySniffSize = 8000
+
+// ErrFileConflict 保存时文件已在磁盘上被修改
+var ErrFileConflict = errors.New("文件已在磁盘上被修改")
+
+// FileContent 带编码信息的文件内容
+type FileContent struct {
+	Path       string `json:"path"`
+	Content    string `json:"content"`    // 已解码为 UTF-8，换行统一为 "\n"
+	Encoding   string `json:"encoding"`   // 见 textEncodings
+	LineEnding string `json:"lineEnding"` // "lf" 或 "crlf"
+	Hash       string `json:"hash"`       // 磁盘原始内容的 SHA-256，保存时作为 expectedHash
+	Size       int64  `json:"size"`
+	ModTime    int64  `json:"modTime"` // Unix 毫秒
+	IsBinary   bool   `json:"isBinary"`
+	TooLarge   bool   `json:"tooLarge"`
+	ReadOnly   bool   `json:"readOnly"`
+}
+
+// WriteFileOptions 保存选项
+type WriteFileOptions struct {
+	Encoding     string `json:"encoding,omitempty"`     // 为空时沿用文件原编码
+	LineEnding   string `json:"lineEnding,omitempty"`   // 为空时沿用文件原换行符
+	ExpectedHash string `json:"expectedHash,omitempty"` // 与磁盘内容不一致时拒绝保存
+	Force        bool   `json:"force,omitempty"`        // 忽略冲突和二进制检查
+}
+
+// textEncodings 支持的编码（名称 -> 编码器；UTF-8 与带 BOM 的编码单独处理）
+var textEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8,
+	"utf-8-bom":    unicode.UTF8,
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"gb18030":      simplifiedchinese.GB18030,
+	"gbk":          simplifiedchinese.GBK,
+	"big5":         traditionalchinese.Big5,
+	"shift_jis":    japanese.ShiftJIS,
+	"euc-jp":       japanese.EUCJP,
+	"euc-kr":       korean.EUCKR,
+	"windows-1252": charmap.Windows1252,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// legacyEncodingOrder 无 BOM 且不是合法 UTF-8 时依次尝试的编码
+var legacyEncodingOrder = []string{"gb18030", "big5", "shift_jis", "euc-kr"}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectEncoding 检测内容编码
+func detectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return "utf-8-bom"
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return "utf-16le"
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return "utf-16be"
+	case utf8.Valid(data):
+		return "utf-8"
+	}
+	for _, name := range legacyEncodingOrder {
+		decoded, err := textEncodings[name].NewDecoder().Bytes(data)
+		if err == nil && !bytes.ContainsRune(decoded, utf8.RuneError) {
+			return name
+		}
+	}
+	return "windows-1252"
+}
+
+// isBinaryContent 含 NUL 字节（且不是 UTF-16）视为二进制
+func isBinaryContent(data []byte) bool {
+	if bytes.HasPrefix(data, bomUTF16LE) || bytes.HasPrefix(data, bomUTF16BE) {
+		return false
+	}
+	if len(data) > binarySniffSize {
+		data = data[:binarySniffSize]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// decodeText 按编码解码为 UTF-8（去掉 BOM）
+func decodeText(data []byte, enc string) (string, error) {
+	switch enc {
+	case "utf-8":
+		return string(data), nil
+	case "utf-8-bom":
+		return string(bytes.TrimPrefix(data, bomUTF8)), nil
+	case "utf-16le":
+		data = bytes.TrimPrefix(data, bomUTF16LE)
+	case "utf-16be":
+		data = bytes.TrimPrefix(data, bomUTF16BE)
+	}
+	e, ok := textEncodings[enc]
+	if !ok {
+		return "", fmt.Errorf("不支持的编码: %s", enc)
+	}
+	decoded, err := e.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("解码失败 (%s): %v", enc, err)
+	}
+	return string(decoded), nil
+}
+
+// encodeText 将 UTF-8 文本按编码写出（需要时加 BOM）
+func encodeText(text, enc string) ([]byte, error) {
+	switch enc {
+	case "", "utf-8":
+		return []byte(text), nil
+	case "utf-8-bom":
+		return append(append([]byte{}, bomUTF8...), text...), nil
+	}
+	e, ok := textEncodings[enc]
+	if !ok {
+		return nil, fmt.Errorf("不支持的编码: %s", enc)
+	}
+	encoded, err := e.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("内容包含无法用 %s 编码的字符", enc)
+	}
+	switch enc {
+	case "utf-16le":
+		encoded = append(append([]byte{}, bomUTF16LE...), encoded...)
+	case "utf-16be":
+		encoded = append(append([]byte{}, bomUTF16BE...), encoded...)
+	}
+	return encoded, nil
+}
+
+// detectLineEnding 以出现较多的换行符为准
+func detectLineEnding(text string) string {
+	crlf := strings.Count(text, "\r\n")
+	lf := strings.Count(text, "\n") - crlf
+	if crlf > lf {
+		return "crlf"
+	}
+	return "lf"
+}
+
+// applyLineEnding 统一换行符
+func applyLineEnding(text, lineEnding string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if lineEnding == "crlf" {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	return text
+}
+
+// resolvePath 相对路径基于根目录
+func (fm *FileManager) resolvePath(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.Join(fm.rootDir, path)
+	}
+	return path
+}
+
+// ReadFileWithInfo 读取文件并检测编码、换行符；二进制或过大的文件只返回元数据
+func (fm *FileManager) ReadFileWithInfo(path string) (*FileContent, error) {
+	path = fm.resolvePath(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在: %v", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("不是文件: %s", path)
+	}
+
+	fc := &FileContent{
+		Path:     path,
+		Size:     info.Size(),
+		ModTime:  info.ModTime().UnixMilli(),
+		ReadOnly: info.Mode().Perm()&0200 == 0,
+	}
+	if info.Size() > maxEditableFileSize {
+		fc.TooLarge = true
+		return fc, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	fc.Hash = hashBytes(data)
+	if isBinaryContent(data) {
+		fc.IsBinary = true
+		return fc, nil
+	}
+
+	fc.Encoding = detectEncoding(data)
+	text, err := decodeText(data, fc.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	fc.LineEnding = detectLineEnding(text)
+	fc.Content = applyLineEnding(text, "lf")
+	return fc, nil
+}
+
+// WriteFileWithOptions 原子地保存文件，沿用原编码与换行符；ExpectedHash 不匹配时返回 ErrFileConflict
+func (fm *FileManager) WriteFileWithOptions(path, content string, opts WriteFileOptions) (*FileContent, error) {
+	path = fm.resolvePath(path)
+	// 写入符号链接指向的文件，而不是替换链接本身
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	perm := os.FileMode(0644)
+	enc, lineEnding := opts.Encoding, opts.LineEnding
+
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return nil, fmt.Errorf("不是文件: %s", path)
+		}
+		perm = info.Mode().Perm()
+
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败: %v", err)
+		}
+		if !opts.Force {
+			if opts.ExpectedHash != "" && hashBytes(current) != opts.ExpectedHash {
+				return nil, fmt.Errorf("%w: %s", ErrFileConflict, path)
+			}
+			if isBinaryContent(current) {
+				return nil, fmt.Errorf("二进制文件不能以文本方式保存: %s", path)
+			}
+		}
+		if enc == "" || lineEnding == "" {
+			existingEnc := detectEncoding(current)
+			if enc == "" {
+				enc = existingEnc
+			}
+			if lineEnding == "" {
+				if text, err := decodeText(current, existingEnc); err == nil && strings.Contains(text, "\n") {
+					lineEnding = detectLineEnding(text)
+				}
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	} else if opts.ExpectedHash != "" && !opts.Force {
+		return nil, fmt.Errorf("%w: %s 已被删除", ErrFileConflict, path)
+	}
+
+	if lineEnding == "" {
+		lineEnding = detectLineEnding(content)
+	}
+	data, err := encodeText(applyLineEnding(content, lineEnding), enc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %v", err)
+	}
+	fm.app.snapshotFile(path, "save")
+	if err := writeFileAtomic(path, data, perm); err != nil {
+		return nil, fmt.Errorf("写入文件失败: %v", err)
+	}
+	fm.app.snapshotFile(path, "save")
+
+	if enc == "" {
+		enc = "utf-8"
+	}
+	fc := &FileContent{
+		Path:       path,
+		Encoding:   enc,
+		LineEnding: lineEnding,
+		Hash:       hashBytes(data),
+		Size:       int64(len(data)),
+	}
+	if info, err := os.Stat(path); err == nil {
+		fc.ModTime = info.ModTime().UnixMilli()
+	}
+	return fc, nil
+}
+
+// supportedEncodings 返回支持的编码名称
+func supportedEncodings() []string {
+	return []string{"utf-8", "utf-8-bom", "utf-16le", "utf-16be", "gb18030", "gbk", "big5", "shift_jis", "euc-jp", "euc-kr", "windows-1252", "iso-8859-1"}
+}
+
+// --- App API ---
+
+// ReadFileWithInfo 读取文件内容及编码、换行符、哈希等信息
+func (a *App) ReadFileWithInfo(path string) (*FileContent, error) {
+	return a.fileMgr.ReadFileWithInfo(path)
+}
+
+// WriteFileWithOptions 保存文件（可指定编码、换行符和期望的哈希）
+func (a *App) WriteFileWithOptions(path, content string, opts WriteFileOptions) (*FileContent, error) {
+	return a.fileMgr.WriteFileWithOptions(path, content, opts)
+}
+
+// GetSupportedEncodings 获取支持的文件编码
+func (a *App) GetSupportedEncodings() []string {
+	return supportedEncodings()
+}
+
+// ReopenWithEncoding 以指定编码重新解码文件
+func (a *App) ReopenWithEncoding(path, enc string) (*FileContent, error) {
+	fc, err := a.fileMgr.ReadFileWithInfo(path)
+	if err != nil || fc.IsBinary || fc.TooLarge {
+		return fc, err
+	}
+	data, err := os.ReadFile(fc.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	text, err := decodeText(data, enc)
+	if err != nil {
+		return nil, err
+	}
+	fc.Encoding = enc
+	fc.Hash = hashBytes(data)
+	fc.LineEnding = detectLineEnding(text)
+	fc.Content = applyLineEnding(text, "lf")
+	return fc, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFileEncodingRoundTrip tests that BOM, encodings and line endings survive a save
+func TestFileEncodingRoundTrip(t *testing.T) {
+	gbk, _ := encodeText("中文内容\r\n第二行\r\n", "gb18030")
+	tests := []struct {
+		name       string
+		data       []byte
+		encoding   string
+		lineEnding string
+	}{
+		{"utf-8", []byte("a\nb\n"), "utf-8", "lf"},
+		{"utf-8 bom crlf", append([]byte{0xEF, 0xBB, 0xBF}, "a\r\nb\r\n"...), "utf-8-bom", "crlf"},
+		{"utf-16le", []byte{0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0}, "utf-16le", "lf"},
+		{"gb18030", gbk, "gb18030", "crlf"},
+	}
+
+	dir := t.TempDir()
+	fm := NewFileManager(&App{})
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".txt")
+		if err := os.WriteFile(path, tt.data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		fc, err := fm.ReadFileWithInfo(path)
+		if err != nil {
+			t.Fatalf("%s: ReadFileWithInfo() error = %v", tt.name, err)
+		}
+		if fc.Encoding != tt.encoding || fc.LineEnding != tt.lineEnding {
+			t.Errorf("%s: detected %s/%s, want %s/%s", tt.name, fc.Encoding, fc.LineEnding, tt.encoding, tt.lineEnding)
+		}
+
+		if _, err := fm.WriteFileWithOptions(path, fc.Content, WriteFileOptions{ExpectedHash: fc.Hash}); err != nil {
+			t.Fatalf("%s: WriteFileWithOptions() error = %v", tt.name, err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != string(tt.data) {
+			t.Errorf("%s: round trip changed content: %q -> %q", tt.name, tt.data, data)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+			t.Errorf("%s: expected permissions to be preserved, got %v", tt.name, info.Mode().Perm())
+		}
+	}
+}
+
+// TestWriteFileConflict tests that a stale expected hash rejects the save
+func TestWriteFileConflict(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "a.txt", "one\n")
+	fm := NewFileManager(&App{})
+
+	fc, err := fm.ReadFileWithInfo(path)
+	if err != nil {
+		t.Fatalf("ReadFileWithInfo() error = %v", err)
+	}
+	writeTestFile(t, dir, "a.txt", "changed by agent\n")
+
+	_, err = fm.WriteFileWithOptions(path, "mine\n", WriteFileOptions{ExpectedHash: fc.Hash})
+	if !errors.Is(err, ErrFileConflict) {
+		t.Fatalf("Expected ErrFileConflict, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "changed by agent\n" {
+		t.Error("Conflicting save must not modify the file")
+	}
+
+	if _, err := fm.WriteFileWithOptions(path, "mine\n", WriteFileOptions{ExpectedHash: fc.Hash, Force: true}); err != nil {
+		t.Fatalf("Forced save error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "mine\n" {
+		t.Errorf("Unexpected content after forced save: %q", data)
+	}
+}
+
+// TestReadFileGuards tests binary and large file detection
+func TestReadFileGuards(t *testing.T) {
+	dir := t.TempDir()
+	fm := NewFileManager(&App{})
+
+	bin := filepath.Join(dir, "a.bin")
+	os.WriteFile(bin, []byte{0x89, 'P', 'N', 'G', 0, 0, 1}, 0644)
+	fc, err := fm.ReadFileWithInfo(bin)
+	if err != nil || !fc.IsBinary || fc.Content != "" {
+		t.Errorf("Expected binary file, got %+v, %v", fc, err)
+	}
+	if _, err := fm.ReadFile(bin); err == nil {
+		t.Error("Expected ReadFile to reject binary file")
+	}
+	if _, err := fm.WriteFileWithOptions(bin, "text", WriteFileOptions{}); err == nil {
+		t.Error("Expected text save over binary file to be rejected")
+	}
+
+	large := filepath.Join(dir, "large.txt")
+	f, _ := os.Create(large)
+	f.Truncate(maxEditableFileSize + 1)
+	f.Close()
+	fc, err = fm.ReadFileWithInfo(large)
+	if err != nil || !fc.TooLarge {
+		t.Errorf("Expected large file guard, got %+v, %v", fc, err)
+	}
+}
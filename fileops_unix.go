@@ -3,7 +3,9 @@
 package main
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 )
 
@@ -19,3 +21,21 @@ func openInFileManager(path string) error {
 	}
 	return cmd.Start()
 }
+
+// systemTrashDirs 返回系统回收站的文件目录和 .trashinfo 目录
+// Linux 使用 freedesktop 回收站；macOS 使用 ~/.Trash，恢复信息保存在应用目录 appInfoDir
+func systemTrashDirs(appInfoDir string) (filesDir, infoDir string, ok bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", false
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, ".Trash"), appInfoDir, true
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	trash := filepath.Join(dataHome, "Trash")
+	return filepath.Join(trash, "files"), filepath.Join(trash, "info"), true
+}
@@ -14,3 +14,8 @@ func openInFileManager(path string) error {
 	cmd := exec.Command("explorer", "/select,", absPath)
 	return cmd.Start()
 }
+
+// systemTrashDirs Windows 回收站需要 Shell API，统一使用应用回收站
+func systemTrashDirs(appInfoDir string) (filesDir, infoDir string, ok bool) {
+	return "", "", false
+}
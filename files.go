@@ -2,14 +2,14 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
-	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // FileInfo 文件信息
@@ -19,6 +19,15 @@ type FileInfo struct {
 	Type     string      `json:"type"` // "file" or "folder"
 	Size     int64       `json:"size"`
 	Children []*FileInfo `json:"children,omitempty"`
+
+	ModTime       int64  `json:"modTime,omitempty"` // 修改时间（Unix 毫秒）
+	Mode          string `json:"mode,omitempty"`    // 权限，如 "-rw-r--r--"
+	IsSymlink     bool   `json:"isSymlink,omitempty"`
+	SymlinkTarget string `json:"symlinkTarget,omitempty"`
+	GitStatus     string `json:"gitStatus,omitempty"` // "M"、"A"、"D"、"R"、"U"、"??"
+	Hidden        bool   `json:"hidden,omitempty"`    // 以 "." 开头
+	Ignored       bool   `json:"ignored,omitempty"`   // 被 .gitignore 忽略
+
 }
 
 // FileManager 文件管理器
@@ -28,6 +37,16 @@ type FileManager struct {
 	watcher      *fsnotify.Watcher
 	watchedFiles map[string]bool
 	mu           sync.Mutex
+
+	// 工作区递归监听
+	treeRoot      string
+	treeIgnore    *IgnoreMatcher
+	watchedDirs   map[string]bool
+	pendingEvents []rawFileEvent
+	flushTimer    *time.Timer
+
+	// 可撤销的重命名/移动/复制
+	operations []FileOperation
 }
 
 // NewFileManager 创建文件管理器
@@ -37,6 +56,7 @@ func NewFileManager(app *App) *FileManager {
 		app:          app,
 		rootDir:      homeDir,
 		watchedFiles: make(map[string]bool),
+		watchedDirs:  make(map[string]bool),
 	}
 	return fm
 }
@@ -60,10 +80,7 @@ func (fm *FileManager) StartWatcher() error {
 				if !ok {
 					return
 				}
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					// 文件被修改，通知前端
-					wailsRuntime.EventsEmit(fm.app.ctx, "file-changed", event.Name)
-				}
+				fm.handleWatchEvent(event)
 			case err, ok := <-watcher.Errors:
 				if !ok {
 					return
@@ -91,10 +108,15 @@ func (fm *FileManager) WatchFile(path string) error {
 		return nil
 	}
 
-	if err := fm.watcher.Add(path); err != nil {
-		return err
+	// 所在目录已被递归监听时无需单独添加
+	if !fm.watchedDirs[filepath.Dir(path)] {
+		if err := fm.watcher.Add(path); err != nil {
+			return err
+		}
 	}
 	fm.watchedFiles[path] = true
+	// 记录打开时的内容，之后的外部修改可以回退到这个版本
+	go fm.app.snapshotFile(path, "open")
 	return nil
 }
 
@@ -104,7 +126,9 @@ func (fm *FileManager) UnwatchFile(path string) {
 	defer fm.mu.Unlock()
 
 	if fm.watcher != nil && fm.watchedFiles[path] {
-		fm.watcher.Remove(path)
+		if !fm.watchedDirs[filepath.Dir(path)] {
+			fm.watcher.Remove(path)
+		}
 		delete(fm.watchedFiles, path)
 	}
 }
@@ -119,6 +143,9 @@ func (fm *FileManager) SetRootDir(dir string) error {
 		return fmt.Errorf("不是目录: %s", dir)
 	}
 	fm.rootDir = dir
+	if err := fm.watchTree(dir); err != nil {
+		fmt.Println("启动目录监听失败:", err)
+	}
 	return nil
 }
 
@@ -127,104 +154,34 @@ func (fm *FileManager) GetRootDir() string {
 	return fm.rootDir
 }
 
-// ListDir 列出目录内容
+// ListDir 列出目录内容（按工作区的文件浏览器设置过滤，不分页）
 func (fm *FileManager) ListDir(dir string) ([]*FileInfo, error) {
-	// 如果是相对路径，基于 rootDir
-	if !filepath.IsAbs(dir) {
-		dir = filepath.Join(fm.rootDir, dir)
-	}
-
-	entries, err := os.ReadDir(dir)
+	page, err := fm.ListDirPage(dir, 0, math.MaxInt32)
 	if err != nil {
-		return nil, fmt.Errorf("读取目录失败: %v", err)
-	}
-
-	var files []*FileInfo
-	var folders []*FileInfo
-
-	for _, entry := range entries {
-		// 跳过隐藏文件和常见忽略目录
-		name := entry.Name()
-		if strings.HasPrefix(name, ".") {
-			continue
-		}
-		if name == "node_modules" || name == "__pycache__" || name == "vendor" {
-			continue
-		}
-
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
-
-		fileInfo := &FileInfo{
-			Name: name,
-			Path: filepath.Join(dir, name),
-			Size: info.Size(),
-		}
-
-		if entry.IsDir() {
-			fileInfo.Type = "folder"
-			folders = append(folders, fileInfo)
-		} else {
-			fileInfo.Type = "file"
-			files = append(files, fileInfo)
-		}
+		return nil, err
 	}
-
-	// 文件夹在前，文件在后，各自按名称排序
-	sort.Slice(folders, func(i, j int) bool {
-		return strings.ToLower(folders[i].Name) < strings.ToLower(folders[j].Name)
-	})
-	sort.Slice(files, func(i, j int) bool {
-		return strings.ToLower(files[i].Name) < strings.ToLower(files[j].Name)
-	})
-
-	return append(folders, files...), nil
+	return page.Items, nil
 }
 
-// ReadFile 读取文件内容
+// ReadFile 读取文件内容（解码为 UTF-8，换行统一为 "\n"）
 func (fm *FileManager) ReadFile(path string) (string, error) {
-	// 如果是相对路径，基于 rootDir
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(fm.rootDir, path)
-	}
-
-	// 检查文件大小，限制 5MB
-	info, err := os.Stat(path)
+	fc, err := fm.ReadFileWithInfo(path)
 	if err != nil {
-		return "", fmt.Errorf("文件不存在: %v", err)
+		return "", err
 	}
-	if info.Size() > 5*1024*1024 {
+	if fc.TooLarge {
 		return "", fmt.Errorf("文件太大 (>5MB)")
 	}
-
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("读取文件失败: %v", err)
+	if fc.IsBinary {
+		return "", fmt.Errorf("不支持打开二进制文件")
 	}
-
-	return string(content), nil
+	return fc.Content, nil
 }
 
-// WriteFile 写入文件内容
+// WriteFile 写入文件内容（原子写入，沿用原文件的编码和换行符）
 func (fm *FileManager) WriteFile(path, content string) error {
-	// 如果是相对路径，基于 rootDir
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(fm.rootDir, path)
-	}
-
-	// 确保目录存在
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %v", err)
-	}
-
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		return fmt.Errorf("写入文件失败: %v", err)
-	}
-
-	return nil
+	_, err := fm.WriteFileWithOptions(path, content, WriteFileOptions{})
+	return err
 }
 
 // GetFileInfo 获取文件信息
@@ -251,7 +208,7 @@ func (fm *FileManager) GetFileInfo(path string) (*FileInfo, error) {
 	}, nil
 }
 
-// DeletePath 删除文件或文件夹
+// DeletePath 删除文件或文件夹（移入回收站）
 func (fm *FileManager) DeletePath(path string) error {
 	if !filepath.IsAbs(path) {
 		path = filepath.Join(fm.rootDir, path)
@@ -262,6 +219,20 @@ func (fm *FileManager) DeletePath(path string) error {
 		return fmt.Errorf("不能删除根目录")
 	}
 
+	if fm.app != nil && fm.app.historyMgr != nil {
+		if err := fm.app.historyMgr.SnapshotTree(path, "delete"); err != nil {
+			fmt.Println("记录文件历史失败:", err)
+		}
+	}
+	return fm.trashPath(path)
+}
+
+// DeletePathPermanently 永久删除文件或文件夹
+func (fm *FileManager) DeletePathPermanently(path string) error {
+	path = fm.resolvePath(path)
+	if path == fm.rootDir || path == "/" {
+		return fmt.Errorf("不能删除根目录")
+	}
 	return os.RemoveAll(path)
 }
 
@@ -283,6 +254,7 @@ func (fm *FileManager) RenamePath(oldPath, newName string) (string, error) {
 		return "", fmt.Errorf("重命名失败: %v", err)
 	}
 
+	fm.recordOperation("rename", oldPath, newPath)
 	return newPath, nil
 }
 
@@ -310,9 +282,15 @@ func (fm *FileManager) CopyPath(src, destDir string) (string, error) {
 	}
 
 	if srcInfo.IsDir() {
-		return destPath, fm.copyDir(src, destPath)
+		err = fm.copyDir(src, destPath)
+	} else {
+		err = fm.copyFile(src, destPath)
+	}
+	if err != nil {
+		return destPath, err
 	}
-	return destPath, fm.copyFile(src, destPath)
+	fm.recordOperation("copy", src, destPath)
+	return destPath, nil
 }
 
 // MovePath 移动文件或文件夹
@@ -335,6 +313,7 @@ func (fm *FileManager) MovePath(src, destDir string) (string, error) {
 		return "", fmt.Errorf("移动失败: %v", err)
 	}
 
+	fm.recordOperation("move", src, destPath)
 	return destPath, nil
 }
 
@@ -437,3 +416,37 @@ func (fm *FileManager) copyDir(src, dest string) error {
 
 	return nil
 }
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，避免写入中途崩溃导致文件被截断
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
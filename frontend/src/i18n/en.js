@@ -227,7 +227,12 @@ export default {
     caseSensitive: 'Match Case',
     useRegex: 'Use Regular Expression',
     toggleReplace: 'Toggle Replace',
-    replaceConfirm: 'Replace all "{search}" with "{replace}"?',
+    replacePreview: '{count} replacements in {files} files',
+    replaceTruncated: 'Too many matches, only part of them are shown',
+    replaceNothing: 'Nothing to replace',
+    applyReplace: 'Replace',
+    cancelReplace: 'Cancel',
+    undoReplace: 'Undo',
     replaceSuccess: 'Successfully replaced in {count} files',
     replaceFailed: 'Replace failed',
   },
@@ -245,6 +250,7 @@ export default {
     commitMessage: 'Enter commit message',
     commitFailed: 'Commit failed',
     pushFailed: 'Push failed',
+    credentialTitle: 'Git Authentication',
     pullFailed: 'Pull failed',
     discardConfirm: 'Are you sure you want to discard changes to this file?',
   },
@@ -225,7 +225,12 @@ export default {
     caseSensitive: '大文字小文字を区別',
     useRegex: '正規表現を使用',
     toggleReplace: '置換を切り替え',
-    replaceConfirm: '"{search}" を "{replace}" に置換しますか？',
+    replacePreview: '{files} 個のファイルで {count} 件の置換',
+    replaceTruncated: '一致が多すぎるため、一部のみ表示しています',
+    replaceNothing: '置換対象がありません',
+    applyReplace: '置換',
+    cancelReplace: 'キャンセル',
+    undoReplace: '元に戻す',
     replaceSuccess: '{count} 個のファイルを置換しました',
     replaceFailed: '置換に失敗しました',
   },
@@ -243,6 +248,7 @@ export default {
     commitMessage: 'コミットメッセージを入力',
     commitFailed: 'コミット失敗',
     pushFailed: 'プッシュ失敗',
+    credentialTitle: 'Git 認証',
     pullFailed: 'プル失敗',
     discardConfirm: 'このファイルの変更を破棄してもよろしいですか？',
   },
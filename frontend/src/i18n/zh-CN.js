@@ -227,7 +227,12 @@ export default {
     caseSensitive: '区分大小写',
     useRegex: '使用正则表达式',
     toggleReplace: '切换替换',
-    replaceConfirm: '确定要将 "{search}" 替换为 "{replace}" 吗？',
+    replacePreview: '{files} 个文件中共 {count} 处替换',
+    replaceTruncated: '匹配过多，仅显示部分结果',
+    replaceNothing: '没有可替换的内容',
+    applyReplace: '替换',
+    cancelReplace: '取消',
+    undoReplace: '撤销',
     replaceSuccess: '成功替换 {count} 个文件',
     replaceFailed: '替换失败',
   },
@@ -245,6 +250,7 @@ export default {
     commitMessage: '请输入提交信息',
     commitFailed: '提交失败',
     pushFailed: '推送失败',
+    credentialTitle: 'Git 认证',
     pullFailed: '拉取失败',
     discardConfirm: '确定要丢弃此文件的更改吗？',
   },
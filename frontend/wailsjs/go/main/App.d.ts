@@ -2,12 +2,20 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AcceptTurn(arg1:string):Promise<void>;
+
+export function AcceptTurnHunk(arg1:string,arg2:string,arg3:number):Promise<void>;
+
 export function AddKiroAccount(arg1:string,arg2:Record<string, any>):Promise<void>;
 
-export function AddMCPServer(arg1:string,arg2:main.MCPServer):Promise<Record<string, main.MCPServerStatus>>;
+export function AddMCPServer(arg1:string,arg2:main.MCPServer,arg3:string):Promise<Record<string, main.MCPServerStatus>>;
 
 export function AddTag(arg1:main.Tag):Promise<void>;
 
+export function ApplyReplace(arg1:string,arg2:Array<main.ReplaceSelection>):Promise<main.ReplaceOperation>;
+
+export function AskAgentToResolveConflict(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function AuthenticateKiro():Promise<void>;
 
 export function AutoStartOpenCode():Promise<void>;
@@ -20,10 +28,16 @@ export function BatchRefreshKiroQuota(arg1:Array<string>):Promise<void>;
 
 export function BatchRefreshKiroTokens(arg1:Array<string>):Promise<void>;
 
+export function CallMCPTool(arg1:string,arg2:string,arg3:Record<string, any>):Promise<main.MCPToolCallResult>;
+
+export function CancelSearch(arg1:string):Promise<void>;
+
 export function CancelSession(arg1:string):Promise<void>;
 
 export function CheckConnection():Promise<boolean>;
 
+export function CheckMCPHealth():Promise<Record<string, main.MCPProbeResult>>;
+
 export function CleanupTempFiles():Promise<void>;
 
 export function CloseTerminal(arg1:number):Promise<void>;
@@ -46,22 +60,60 @@ export function CreateSession():Promise<main.Session>;
 
 export function CreateSkill(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<void>;
 
-export function CreateSkillFromTemplate(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+export function CreateSkillFromTemplate(arg1:string,arg2:string,arg3:boolean,arg4:Record<string, string>):Promise<void>;
 
 export function CreateTerminal():Promise<number>;
 
+export function CreateWorktreeSession(arg1:string,arg2:string,arg3:string):Promise<main.WorktreeSession>;
+
+export function DebugEvaluate(arg1:string,arg2:string,arg3:number):Promise<main.DebugVariable>;
+
+export function DebugStep(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function DeleteMCPSecret(arg1:string):Promise<void>;
+
 export function DeletePath(arg1:string):Promise<void>;
 
+export function DeletePathPermanently(arg1:string):Promise<void>;
+
 export function DeleteSkill(arg1:string):Promise<void>;
 
+export function DeleteSkillFile(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteSkillTemplate(arg1:string):Promise<void>;
+
 export function DeleteTag(arg1:string):Promise<void>;
 
+export function DeleteTrashItem(arg1:string):Promise<void>;
+
+export function DiffFileHistory(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function DiffWorktreeSession(arg1:string):Promise<main.WorktreeDiff>;
+
+export function DiscardReplacePreview(arg1:string):Promise<void>;
+
+export function DiscardWorktreeSession(arg1:string):Promise<void>;
+
 export function DisconnectMCPServer(arg1:string):Promise<void>;
 
+export function EmptyTrash():Promise<void>;
+
+export function EnableSearchIndex(arg1:boolean):Promise<void>;
+
+export function EndSessionTurn(arg1:string):Promise<main.SessionTurn>;
+
 export function ExportKiroAccounts(arg1:string):Promise<string>;
 
+export function ExportMCPServers(arg1:string,arg2:Array<string>):Promise<main.MCPExportResult>;
+
+export function ExportMCPServersToFile(arg1:string,arg2:string,arg3:Array<string>):Promise<main.MCPExportResult>;
+
+export function ExportSkill(arg1:string,arg2:string):Promise<string>;
+
 export function FixOhMyOpenCode():Promise<void>;
 
+export function GenerateCommitMessage(arg1:string,arg2:main.CommitMessageOptions):Promise<main.CommitMessageResult>;
+
 export function GetAccountSettings():Promise<main.AccountSettings>;
 
 export function GetActiveKiroAccount():Promise<main.KiroAccount>;
@@ -72,14 +124,46 @@ export function GetAntigravityAuthStatus():Promise<main.AntigravityAuthStatus>;
 
 export function GetAppConfig():Promise<main.AppConfig>;
 
+export function GetBreakpoints():Promise<Record<string, Array<main.DebugBreakpoint>>>;
+
+export function GetCommitMessageSettings():Promise<main.CommitMessageSettings>;
+
 export function GetConfig():Promise<main.ConfigInfo>;
 
 export function GetConfigModels():Promise<Array<main.ConfigModel>>;
 
 export function GetConfigPaths():Promise<main.ConfigPaths>;
 
+export function GetConflictFile(arg1:string,arg2:string):Promise<main.GitConflictFile>;
+
+export function GetDebugScopes(arg1:string,arg2:number):Promise<Array<main.DebugScope>>;
+
+export function GetDebugSessions():Promise<Array<main.DebugSessionInfo>>;
+
+export function GetDebugStackTrace(arg1:string,arg2:number):Promise<Array<main.DebugStackFrame>>;
+
+export function GetDebugThreads(arg1:string):Promise<Array<main.DebugThread>>;
+
+export function GetDebugVariables(arg1:string,arg2:number):Promise<Array<main.DebugVariable>>;
+
+export function GetExplorerSettings():Promise<main.ExplorerSettings>;
+
+export function GetFileHistory(arg1:string):Promise<Array<main.HistoryEntry>>;
+
+export function GetFileHistoryContent(arg1:string,arg2:string):Promise<string>;
+
+export function GetGitConflicts(arg1:string):Promise<Array<main.GitChange>>;
+
+export function GetGitCredentialPrompts():Promise<Array<main.GitCredentialPrompt>>;
+
+export function GetGitJob(arg1:string):Promise<main.GitJob>;
+
+export function GetGitJobs():Promise<Array<main.GitJob>>;
+
 export function GetGitStatus(arg1:string):Promise<main.GitStatus>;
 
+export function GetHistoryFiles():Promise<Array<main.HistoryFile>>;
+
 export function GetKiroAccountStats():Promise<Record<string, any>>;
 
 export function GetKiroAccounts():Promise<Array<main.KiroAccount>>;
@@ -88,12 +172,28 @@ export function GetKiroAuthStatus():Promise<main.KiroAuthStatus>;
 
 export function GetKiroQuota(arg1:string):Promise<main.QuotaInfo>;
 
+export function GetLastFileOperation():Promise<main.FileOperation>;
+
 export function GetMCPConfig():Promise<main.MCPConfig>;
 
+export function GetMCPConfigLayers():Promise<Array<main.MCPConfigLayer>>;
+
 export function GetMCPConfigPath():Promise<string>;
 
+export function GetMCPImportSources():Promise<Array<main.MCPImportSource>>;
+
+export function GetMCPLayerPath(arg1:string):Promise<string>;
+
 export function GetMCPMarket():Promise<Array<main.MCPMarketItem>>;
 
+export function GetMCPMarketSettings():Promise<main.MCPMarketSettings>;
+
+export function GetMCPMarketStatus():Promise<main.MCPMarketStatus>;
+
+export function GetMCPOAuthStatus(arg1:string):Promise<main.MCPOAuthStatus>;
+
+export function GetMCPSecrets():Promise<Array<main.MCPSecretInfo>>;
+
 export function GetMCPStatus():Promise<Record<string, main.MCPServerStatus>>;
 
 export function GetMCPTools():Promise<Array<main.MCPTool>>;
@@ -110,10 +210,14 @@ export function GetQuotaAlerts():Promise<Array<main.QuotaAlert>>;
 
 export function GetRemoteControlInfo():Promise<Record<string, any>>;
 
+export function GetReplaceOperations():Promise<Array<main.ReplaceOperation>>;
+
 export function GetServerURL():Promise<string>;
 
 export function GetSessionMessages(arg1:string):Promise<Array<main.Message>>;
 
+export function GetSessionTurns(arg1:string):Promise<Array<main.SessionTurn>>;
+
 export function GetSessions():Promise<Array<main.Session>>;
 
 export function GetSkill(arg1:string):Promise<main.SkillInfo>;
@@ -124,34 +228,106 @@ export function GetSkills():Promise<Array<main.SkillInfo>>;
 
 export function GetStorageInfo():Promise<Record<string, any>>;
 
+export function GetSupportedEncodings():Promise<Array<string>>;
+
 export function GetSystemMachineID():Promise<string>;
 
 export function GetTags():Promise<Array<main.Tag>>;
 
+export function GetTaskRun(arg1:string):Promise<main.TaskRun>;
+
+export function GetTaskRuns():Promise<Array<main.TaskRun>>;
+
+export function GetTasks():Promise<Array<main.Task>>;
+
 export function GetTerminals():Promise<Array<number>>;
 
+export function GetTrashItems():Promise<Array<main.TrashItem>>;
+
+export function GetTrashSettings():Promise<main.TrashSettings>;
+
+export function GetTurnChanges(arg1:string):Promise<main.TurnChanges>;
+
 export function GetUIUXProMaxStatus():Promise<main.UIUXProMaxStatus>;
 
 export function GetWorkDir():Promise<string>;
 
+export function GetWorktreeSessions(arg1:string):Promise<Array<main.WorktreeSession>>;
+
+export function GitAcceptConflictSide(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function GitAdd(arg1:string,arg2:string):Promise<void>;
 
+export function GitAnswerCredentialPrompt(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function GitBlame(arg1:string,arg2:string,arg3:string):Promise<main.GitBlame>;
+
+export function GitBranches(arg1:string):Promise<Array<main.GitBranch>>;
+
+export function GitCancelJob(arg1:string):Promise<void>;
+
+export function GitCheckoutBranch(arg1:string,arg2:string):Promise<void>;
+
 export function GitCommit(arg1:string,arg2:string):Promise<void>;
 
+export function GitCreateBranch(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<void>;
+
+export function GitDeleteBranch(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function GitDiffAll(arg1:string,arg2:boolean):Promise<Array<main.GitFileDiff>>;
+
+export function GitDiffFile(arg1:string,arg2:string,arg3:boolean):Promise<main.GitFileDiff>;
+
 export function GitDiscard(arg1:string,arg2:string):Promise<void>;
 
+export function GitDiscardHunk(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function GitFetchAsync(arg1:string,arg2:main.GitRemoteOptions):Promise<string>;
+
+export function GitLog(arg1:string,arg2:number,arg3:number,arg4:string):Promise<main.GitLogPage>;
+
+export function GitMarkResolved(arg1:string,arg2:string):Promise<void>;
+
 export function GitPull(arg1:string):Promise<void>;
 
+export function GitPullAsync(arg1:string,arg2:main.GitRemoteOptions):Promise<string>;
+
 export function GitPush(arg1:string):Promise<void>;
 
+export function GitPushAsync(arg1:string,arg2:main.GitRemoteOptions):Promise<string>;
+
+export function GitShowFile(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function GitStageHunk(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function GitStashApply(arg1:string,arg2:number):Promise<void>;
+
+export function GitStashDrop(arg1:string,arg2:number):Promise<void>;
+
+export function GitStashList(arg1:string):Promise<Array<main.GitStash>>;
+
+export function GitStashPop(arg1:string,arg2:number):Promise<void>;
+
+export function GitStashPush(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function GitUnstage(arg1:string,arg2:string):Promise<void>;
+
+export function GitUnstageHunk(arg1:string,arg2:string,arg3:number):Promise<void>;
+
 export function HandleKiroOAuthCallback(arg1:string,arg2:string):Promise<main.KiroAccount>;
 
 export function ImportKiroAccounts(arg1:string,arg2:string):Promise<void>;
 
+export function ImportMCPServers(arg1:string,arg2:string,arg3:string,arg4:Array<string>,arg5:string,arg6:string):Promise<main.MCPImportResult>;
+
+export function ImportSkill(arg1:string,arg2:boolean,arg3:boolean):Promise<main.SkillInfo>;
+
 export function InstallAntigravityAuth():Promise<void>;
 
 export function InstallKiroAuth():Promise<void>;
 
+export function InstallMCPMarketItem(arg1:string,arg2:Record<string, string>,arg3:string):Promise<Record<string, main.MCPServerStatus>>;
+
 export function InstallOhMyOpenCode():Promise<void>;
 
 export function InstallOpenCode():Promise<void>;
@@ -160,8 +336,16 @@ export function InstallUIUXProMax():Promise<void>;
 
 export function ListDir(arg1:string):Promise<Array<main.FileInfo>>;
 
+export function ListDirPage(arg1:string,arg2:number,arg3:number):Promise<main.DirPage>;
+
 export function LogToTerminal(arg1:string):Promise<void>;
 
+export function LogoutMCPOAuth(arg1:string):Promise<void>;
+
+export function MergeWorktreeSession(arg1:string,arg2:main.WorktreeMergeOptions):Promise<main.WorktreeMergeResult>;
+
+export function MoveMCPEnvToSecrets(arg1:string):Promise<Array<string>>;
+
 export function MovePath(arg1:string,arg2:string):Promise<string>;
 
 export function OpenFolder():Promise<string>;
@@ -170,63 +354,135 @@ export function OpenInFinder(arg1:string):Promise<void>;
 
 export function OpenMCPConfigFile():Promise<string>;
 
+export function PreviewMCPImport(arg1:string,arg2:string,arg3:string):Promise<main.MCPImportPreview>;
+
+export function PreviewReplace(arg1:main.ReplaceOptions):Promise<main.ReplacePreview>;
+
+export function PreviewSkillImport(arg1:string,arg2:boolean):Promise<main.SkillImportPreview>;
+
+export function ProbeMCPServer(arg1:string):Promise<main.MCPProbeResult>;
+
 export function ReadFileContent(arg1:string):Promise<string>;
 
+export function ReadFileWithInfo(arg1:string):Promise<main.FileContent>;
+
+export function ReadSkillFile(arg1:string,arg2:string):Promise<string>;
+
 export function RefreshActiveKiroQuota():Promise<void>;
 
 export function RefreshKiroQuota(arg1:string):Promise<void>;
 
 export function RefreshKiroToken(arg1:string):Promise<void>;
 
+export function RefreshMCPMarket():Promise<main.MCPMarketStatus>;
+
 export function RemoveKiroAccount(arg1:string):Promise<void>;
 
 export function RemoveMCPServer(arg1:string):Promise<void>;
 
 export function RenamePath(arg1:string,arg2:string):Promise<string>;
 
+export function ReopenWithEncoding(arg1:string,arg2:string):Promise<main.FileContent>;
+
 export function ReplaceInFiles(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<number>;
 
 export function ResetConfiguration():Promise<void>;
 
 export function ResizeTerminal(arg1:number,arg2:number,arg3:number):Promise<void>;
 
+export function ResolveConflictHunk(arg1:string,arg2:string,arg3:number,arg4:string):Promise<main.GitConflictFile>;
+
 export function RestartOpenCode():Promise<void>;
 
+export function RestoreFileHistory(arg1:string,arg2:string):Promise<void>;
+
+export function RestoreTrashItem(arg1:string):Promise<string>;
+
+export function RevertTurn(arg1:string):Promise<void>;
+
+export function RevertTurnFile(arg1:string,arg2:string):Promise<void>;
+
+export function RevertTurnHunk(arg1:string,arg2:string,arg3:number):Promise<void>;
+
 export function RunFile(arg1:string):Promise<string>;
 
+export function RunFileTask(arg1:string):Promise<string>;
+
+export function RunTask(arg1:string):Promise<string>;
+
+export function SaveCommitMessageSettings(arg1:main.CommitMessageSettings):Promise<void>;
+
+export function SaveExplorerSettings(arg1:main.ExplorerSettings):Promise<void>;
+
 export function SaveImageToWorkDir(arg1:main.ImageData):Promise<string>;
 
 export function SaveMCPConfig(arg1:main.MCPConfig):Promise<void>;
 
+export function SaveMCPMarketSettings(arg1:main.MCPMarketSettings):Promise<void>;
+
+export function SaveSkillAsTemplate(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<void>;
+
+export function SaveTrashSettings(arg1:main.TrashSettings):Promise<void>;
+
 export function SearchInFiles(arg1:string,arg2:string,arg3:boolean,arg4:boolean):Promise<Array<main.SearchResult>>;
 
+export function SearchWorkspace(arg1:main.SearchOptions):Promise<main.SearchSummary>;
+
+export function SelectSkillPackage():Promise<string>;
+
 export function SendMessage(arg1:string,arg2:string):Promise<void>;
 
 export function SendMessageWithModel(arg1:string,arg2:string,arg3:string,arg4:Array<main.ImageData>):Promise<void>;
 
 export function SetActiveFile(arg1:string,arg2:string):Promise<void>;
 
+export function SetBreakpoints(arg1:string,arg2:Array<main.DebugBreakpoint>):Promise<Array<main.DebugBreakpoint>>;
+
+export function SetMCPSecret(arg1:string,arg2:string):Promise<void>;
+
 export function SetOpenCodeWorkDir(arg1:string):Promise<void>;
 
 export function SetServerURL(arg1:string):Promise<void>;
 
+export function SetShowHiddenFiles(arg1:boolean):Promise<void>;
+
+export function SetSkillEnabled(arg1:string,arg2:boolean,arg3:string):Promise<void>;
+
+export function SetSkillMetadata(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function SetWorkDir(arg1:string):Promise<void>;
 
+export function StartDebug(arg1:string,arg2:string,arg3:Array<string>):Promise<main.DebugSessionInfo>;
+
 export function StartKiroOAuth(arg1:string):Promise<string>;
 
+export function StartMCPOAuth(arg1:string,arg2:main.MCPOAuthOptions):Promise<string>;
+
 export function StartOpenCode():Promise<void>;
 
 export function StartRemoteControl(arg1:number):Promise<Record<string, any>>;
 
+export function StartSearch(arg1:main.SearchOptions):Promise<string>;
+
+export function StopDebug(arg1:string):Promise<void>;
+
 export function StopOpenCode():Promise<void>;
 
 export function StopRemoteControl():Promise<void>;
 
+export function StopTask(arg1:string):Promise<void>;
+
 export function SubscribeEvents():Promise<void>;
 
 export function SwitchKiroAccount(arg1:string):Promise<void>;
 
-export function ToggleMCPServer(arg1:string,arg2:boolean):Promise<void>;
+export function TestMCPServer(arg1:string,arg2:main.MCPServer):Promise<main.MCPProbeResult>;
+
+export function ToggleMCPServer(arg1:string,arg2:boolean,arg3:string):Promise<void>;
+
+export function UndoFileOperation():Promise<main.FileOperation>;
+
+export function UndoReplace(arg1:string):Promise<void>;
 
 export function UninstallAntigravityAuth():Promise<void>;
 
@@ -256,8 +512,16 @@ export function ValidateConfiguration():Promise<void>;
 
 export function ValidateKiroToken(arg1:string):Promise<main.TokenInfo>;
 
+export function ValidateMCPMarketValues(arg1:string,arg2:Record<string, string>):Promise<Array<main.MCPValidationError>>;
+
+export function ValidateMCPToolArgs(arg1:Record<string, any>,arg2:Record<string, any>):Promise<Array<main.MCPValidationError>>;
+
 export function WatchFile(arg1:string):Promise<void>;
 
 export function WriteFileContent(arg1:string,arg2:string):Promise<void>;
 
+export function WriteFileWithOptions(arg1:string,arg2:string,arg3:main.WriteFileOptions):Promise<main.FileContent>;
+
+export function WriteSkillFile(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function WriteTerminal(arg1:number,arg2:string):Promise<void>;
@@ -2,18 +2,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptTurn(arg1) {
+  return window['go']['main']['App']['AcceptTurn'](arg1);
+}
+
+export function AcceptTurnHunk(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AcceptTurnHunk'](arg1, arg2, arg3);
+}
+
 export function AddKiroAccount(arg1, arg2) {
   return window['go']['main']['App']['AddKiroAccount'](arg1, arg2);
 }
 
-export function AddMCPServer(arg1, arg2) {
-  return window['go']['main']['App']['AddMCPServer'](arg1, arg2);
+export function AddMCPServer(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddMCPServer'](arg1, arg2, arg3);
 }
 
 export function AddTag(arg1) {
   return window['go']['main']['App']['AddTag'](arg1);
 }
 
+export function ApplyReplace(arg1, arg2) {
+  return window['go']['main']['App']['ApplyReplace'](arg1, arg2);
+}
+
+export function AskAgentToResolveConflict(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AskAgentToResolveConflict'](arg1, arg2, arg3);
+}
+
 export function AuthenticateKiro() {
   return window['go']['main']['App']['AuthenticateKiro']();
 }
@@ -38,6 +54,14 @@ export function BatchRefreshKiroTokens(arg1) {
   return window['go']['main']['App']['BatchRefreshKiroTokens'](arg1);
 }
 
+export function CallMCPTool(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CallMCPTool'](arg1, arg2, arg3);
+}
+
+export function CancelSearch(arg1) {
+  return window['go']['main']['App']['CancelSearch'](arg1);
+}
+
 export function CancelSession(arg1) {
   return window['go']['main']['App']['CancelSession'](arg1);
 }
@@ -46,6 +70,10 @@ export function CheckConnection() {
   return window['go']['main']['App']['CheckConnection']();
 }
 
+export function CheckMCPHealth() {
+  return window['go']['main']['App']['CheckMCPHealth']();
+}
+
 export function CleanupTempFiles() {
   return window['go']['main']['App']['CleanupTempFiles']();
 }
@@ -90,38 +118,114 @@ export function CreateSkill(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateSkill'](arg1, arg2, arg3, arg4);
 }
 
-export function CreateSkillFromTemplate(arg1, arg2, arg3) {
-  return window['go']['main']['App']['CreateSkillFromTemplate'](arg1, arg2, arg3);
+export function CreateSkillFromTemplate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreateSkillFromTemplate'](arg1, arg2, arg3, arg4);
 }
 
 export function CreateTerminal() {
   return window['go']['main']['App']['CreateTerminal']();
 }
 
+export function CreateWorktreeSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateWorktreeSession'](arg1, arg2, arg3);
+}
+
+export function DebugEvaluate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DebugEvaluate'](arg1, arg2, arg3);
+}
+
+export function DebugStep(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DebugStep'](arg1, arg2, arg3);
+}
+
+export function DeleteMCPSecret(arg1) {
+  return window['go']['main']['App']['DeleteMCPSecret'](arg1);
+}
+
 export function DeletePath(arg1) {
   return window['go']['main']['App']['DeletePath'](arg1);
 }
 
+export function DeletePathPermanently(arg1) {
+  return window['go']['main']['App']['DeletePathPermanently'](arg1);
+}
+
 export function DeleteSkill(arg1) {
   return window['go']['main']['App']['DeleteSkill'](arg1);
 }
 
+export function DeleteSkillFile(arg1, arg2) {
+  return window['go']['main']['App']['DeleteSkillFile'](arg1, arg2);
+}
+
+export function DeleteSkillTemplate(arg1) {
+  return window['go']['main']['App']['DeleteSkillTemplate'](arg1);
+}
+
 export function DeleteTag(arg1) {
   return window['go']['main']['App']['DeleteTag'](arg1);
 }
 
+export function DeleteTrashItem(arg1) {
+  return window['go']['main']['App']['DeleteTrashItem'](arg1);
+}
+
+export function DiffFileHistory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DiffFileHistory'](arg1, arg2, arg3);
+}
+
+export function DiffWorktreeSession(arg1) {
+  return window['go']['main']['App']['DiffWorktreeSession'](arg1);
+}
+
+export function DiscardReplacePreview(arg1) {
+  return window['go']['main']['App']['DiscardReplacePreview'](arg1);
+}
+
+export function DiscardWorktreeSession(arg1) {
+  return window['go']['main']['App']['DiscardWorktreeSession'](arg1);
+}
+
 export function DisconnectMCPServer(arg1) {
   return window['go']['main']['App']['DisconnectMCPServer'](arg1);
 }
 
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function EnableSearchIndex(arg1) {
+  return window['go']['main']['App']['EnableSearchIndex'](arg1);
+}
+
+export function EndSessionTurn(arg1) {
+  return window['go']['main']['App']['EndSessionTurn'](arg1);
+}
+
 export function ExportKiroAccounts(arg1) {
   return window['go']['main']['App']['ExportKiroAccounts'](arg1);
 }
 
+export function ExportMCPServers(arg1, arg2) {
+  return window['go']['main']['App']['ExportMCPServers'](arg1, arg2);
+}
+
+export function ExportMCPServersToFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportMCPServersToFile'](arg1, arg2, arg3);
+}
+
+export function ExportSkill(arg1, arg2) {
+  return window['go']['main']['App']['ExportSkill'](arg1, arg2);
+}
+
 export function FixOhMyOpenCode() {
   return window['go']['main']['App']['FixOhMyOpenCode']();
 }
 
+export function GenerateCommitMessage(arg1, arg2) {
+  return window['go']['main']['App']['GenerateCommitMessage'](arg1, arg2);
+}
+
 export function GetAccountSettings() {
   return window['go']['main']['App']['GetAccountSettings']();
 }
@@ -142,6 +246,14 @@ export function GetAppConfig() {
   return window['go']['main']['App']['GetAppConfig']();
 }
 
+export function GetBreakpoints() {
+  return window['go']['main']['App']['GetBreakpoints']();
+}
+
+export function GetCommitMessageSettings() {
+  return window['go']['main']['App']['GetCommitMessageSettings']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -154,10 +266,66 @@ export function GetConfigPaths() {
   return window['go']['main']['App']['GetConfigPaths']();
 }
 
+export function GetConflictFile(arg1, arg2) {
+  return window['go']['main']['App']['GetConflictFile'](arg1, arg2);
+}
+
+export function GetDebugScopes(arg1, arg2) {
+  return window['go']['main']['App']['GetDebugScopes'](arg1, arg2);
+}
+
+export function GetDebugSessions() {
+  return window['go']['main']['App']['GetDebugSessions']();
+}
+
+export function GetDebugStackTrace(arg1, arg2) {
+  return window['go']['main']['App']['GetDebugStackTrace'](arg1, arg2);
+}
+
+export function GetDebugThreads(arg1) {
+  return window['go']['main']['App']['GetDebugThreads'](arg1);
+}
+
+export function GetDebugVariables(arg1, arg2) {
+  return window['go']['main']['App']['GetDebugVariables'](arg1, arg2);
+}
+
+export function GetExplorerSettings() {
+  return window['go']['main']['App']['GetExplorerSettings']();
+}
+
+export function GetFileHistory(arg1) {
+  return window['go']['main']['App']['GetFileHistory'](arg1);
+}
+
+export function GetFileHistoryContent(arg1, arg2) {
+  return window['go']['main']['App']['GetFileHistoryContent'](arg1, arg2);
+}
+
+export function GetGitConflicts(arg1) {
+  return window['go']['main']['App']['GetGitConflicts'](arg1);
+}
+
+export function GetGitCredentialPrompts() {
+  return window['go']['main']['App']['GetGitCredentialPrompts']();
+}
+
+export function GetGitJob(arg1) {
+  return window['go']['main']['App']['GetGitJob'](arg1);
+}
+
+export function GetGitJobs() {
+  return window['go']['main']['App']['GetGitJobs']();
+}
+
 export function GetGitStatus(arg1) {
   return window['go']['main']['App']['GetGitStatus'](arg1);
 }
 
+export function GetHistoryFiles() {
+  return window['go']['main']['App']['GetHistoryFiles']();
+}
+
 export function GetKiroAccountStats() {
   return window['go']['main']['App']['GetKiroAccountStats']();
 }
@@ -174,18 +342,50 @@ export function GetKiroQuota(arg1) {
   return window['go']['main']['App']['GetKiroQuota'](arg1);
 }
 
+export function GetLastFileOperation() {
+  return window['go']['main']['App']['GetLastFileOperation']();
+}
+
 export function GetMCPConfig() {
   return window['go']['main']['App']['GetMCPConfig']();
 }
 
+export function GetMCPConfigLayers() {
+  return window['go']['main']['App']['GetMCPConfigLayers']();
+}
+
 export function GetMCPConfigPath() {
   return window['go']['main']['App']['GetMCPConfigPath']();
 }
 
+export function GetMCPImportSources() {
+  return window['go']['main']['App']['GetMCPImportSources']();
+}
+
+export function GetMCPLayerPath(arg1) {
+  return window['go']['main']['App']['GetMCPLayerPath'](arg1);
+}
+
 export function GetMCPMarket() {
   return window['go']['main']['App']['GetMCPMarket']();
 }
 
+export function GetMCPMarketSettings() {
+  return window['go']['main']['App']['GetMCPMarketSettings']();
+}
+
+export function GetMCPMarketStatus() {
+  return window['go']['main']['App']['GetMCPMarketStatus']();
+}
+
+export function GetMCPOAuthStatus(arg1) {
+  return window['go']['main']['App']['GetMCPOAuthStatus'](arg1);
+}
+
+export function GetMCPSecrets() {
+  return window['go']['main']['App']['GetMCPSecrets']();
+}
+
 export function GetMCPStatus() {
   return window['go']['main']['App']['GetMCPStatus']();
 }
@@ -218,6 +418,10 @@ export function GetRemoteControlInfo() {
   return window['go']['main']['App']['GetRemoteControlInfo']();
 }
 
+export function GetReplaceOperations() {
+  return window['go']['main']['App']['GetReplaceOperations']();
+}
+
 export function GetServerURL() {
   return window['go']['main']['App']['GetServerURL']();
 }
@@ -226,6 +430,10 @@ export function GetSessionMessages(arg1) {
   return window['go']['main']['App']['GetSessionMessages'](arg1);
 }
 
+export function GetSessionTurns(arg1) {
+  return window['go']['main']['App']['GetSessionTurns'](arg1);
+}
+
 export function GetSessions() {
   return window['go']['main']['App']['GetSessions']();
 }
@@ -246,6 +454,10 @@ export function GetStorageInfo() {
   return window['go']['main']['App']['GetStorageInfo']();
 }
 
+export function GetSupportedEncodings() {
+  return window['go']['main']['App']['GetSupportedEncodings']();
+}
+
 export function GetSystemMachineID() {
   return window['go']['main']['App']['GetSystemMachineID']();
 }
@@ -254,10 +466,34 @@ export function GetTags() {
   return window['go']['main']['App']['GetTags']();
 }
 
+export function GetTaskRun(arg1) {
+  return window['go']['main']['App']['GetTaskRun'](arg1);
+}
+
+export function GetTaskRuns() {
+  return window['go']['main']['App']['GetTaskRuns']();
+}
+
+export function GetTasks() {
+  return window['go']['main']['App']['GetTasks']();
+}
+
 export function GetTerminals() {
   return window['go']['main']['App']['GetTerminals']();
 }
 
+export function GetTrashItems() {
+  return window['go']['main']['App']['GetTrashItems']();
+}
+
+export function GetTrashSettings() {
+  return window['go']['main']['App']['GetTrashSettings']();
+}
+
+export function GetTurnChanges(arg1) {
+  return window['go']['main']['App']['GetTurnChanges'](arg1);
+}
+
 export function GetUIUXProMaxStatus() {
   return window['go']['main']['App']['GetUIUXProMaxStatus']();
 }
@@ -266,26 +502,130 @@ export function GetWorkDir() {
   return window['go']['main']['App']['GetWorkDir']();
 }
 
+export function GetWorktreeSessions(arg1) {
+  return window['go']['main']['App']['GetWorktreeSessions'](arg1);
+}
+
+export function GitAcceptConflictSide(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitAcceptConflictSide'](arg1, arg2, arg3);
+}
+
 export function GitAdd(arg1, arg2) {
   return window['go']['main']['App']['GitAdd'](arg1, arg2);
 }
 
+export function GitAnswerCredentialPrompt(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitAnswerCredentialPrompt'](arg1, arg2, arg3);
+}
+
+export function GitBlame(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitBlame'](arg1, arg2, arg3);
+}
+
+export function GitBranches(arg1) {
+  return window['go']['main']['App']['GitBranches'](arg1);
+}
+
+export function GitCancelJob(arg1) {
+  return window['go']['main']['App']['GitCancelJob'](arg1);
+}
+
+export function GitCheckoutBranch(arg1, arg2) {
+  return window['go']['main']['App']['GitCheckoutBranch'](arg1, arg2);
+}
+
 export function GitCommit(arg1, arg2) {
   return window['go']['main']['App']['GitCommit'](arg1, arg2);
 }
 
+export function GitCreateBranch(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GitCreateBranch'](arg1, arg2, arg3, arg4);
+}
+
+export function GitDeleteBranch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitDeleteBranch'](arg1, arg2, arg3);
+}
+
+export function GitDiffAll(arg1, arg2) {
+  return window['go']['main']['App']['GitDiffAll'](arg1, arg2);
+}
+
+export function GitDiffFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitDiffFile'](arg1, arg2, arg3);
+}
+
 export function GitDiscard(arg1, arg2) {
   return window['go']['main']['App']['GitDiscard'](arg1, arg2);
 }
 
+export function GitDiscardHunk(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitDiscardHunk'](arg1, arg2, arg3);
+}
+
+export function GitFetchAsync(arg1, arg2) {
+  return window['go']['main']['App']['GitFetchAsync'](arg1, arg2);
+}
+
+export function GitLog(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GitLog'](arg1, arg2, arg3, arg4);
+}
+
+export function GitMarkResolved(arg1, arg2) {
+  return window['go']['main']['App']['GitMarkResolved'](arg1, arg2);
+}
+
 export function GitPull(arg1) {
   return window['go']['main']['App']['GitPull'](arg1);
 }
 
+export function GitPullAsync(arg1, arg2) {
+  return window['go']['main']['App']['GitPullAsync'](arg1, arg2);
+}
+
 export function GitPush(arg1) {
   return window['go']['main']['App']['GitPush'](arg1);
 }
 
+export function GitPushAsync(arg1, arg2) {
+  return window['go']['main']['App']['GitPushAsync'](arg1, arg2);
+}
+
+export function GitShowFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitShowFile'](arg1, arg2, arg3);
+}
+
+export function GitStageHunk(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitStageHunk'](arg1, arg2, arg3);
+}
+
+export function GitStashApply(arg1, arg2) {
+  return window['go']['main']['App']['GitStashApply'](arg1, arg2);
+}
+
+export function GitStashDrop(arg1, arg2) {
+  return window['go']['main']['App']['GitStashDrop'](arg1, arg2);
+}
+
+export function GitStashList(arg1) {
+  return window['go']['main']['App']['GitStashList'](arg1);
+}
+
+export function GitStashPop(arg1, arg2) {
+  return window['go']['main']['App']['GitStashPop'](arg1, arg2);
+}
+
+export function GitStashPush(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitStashPush'](arg1, arg2, arg3);
+}
+
+export function GitUnstage(arg1, arg2) {
+  return window['go']['main']['App']['GitUnstage'](arg1, arg2);
+}
+
+export function GitUnstageHunk(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitUnstageHunk'](arg1, arg2, arg3);
+}
+
 export function HandleKiroOAuthCallback(arg1, arg2) {
   return window['go']['main']['App']['HandleKiroOAuthCallback'](arg1, arg2);
 }
@@ -294,6 +634,14 @@ export function ImportKiroAccounts(arg1, arg2) {
   return window['go']['main']['App']['ImportKiroAccounts'](arg1, arg2);
 }
 
+export function ImportMCPServers(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['ImportMCPServers'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function ImportSkill(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportSkill'](arg1, arg2, arg3);
+}
+
 export function InstallAntigravityAuth() {
   return window['go']['main']['App']['InstallAntigravityAuth']();
 }
@@ -302,6 +650,10 @@ export function InstallKiroAuth() {
   return window['go']['main']['App']['InstallKiroAuth']();
 }
 
+export function InstallMCPMarketItem(arg1, arg2, arg3) {
+  return window['go']['main']['App']['InstallMCPMarketItem'](arg1, arg2, arg3);
+}
+
 export function InstallOhMyOpenCode() {
   return window['go']['main']['App']['InstallOhMyOpenCode']();
 }
@@ -318,10 +670,26 @@ export function ListDir(arg1) {
   return window['go']['main']['App']['ListDir'](arg1);
 }
 
+export function ListDirPage(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ListDirPage'](arg1, arg2, arg3);
+}
+
 export function LogToTerminal(arg1) {
   return window['go']['main']['App']['LogToTerminal'](arg1);
 }
 
+export function LogoutMCPOAuth(arg1) {
+  return window['go']['main']['App']['LogoutMCPOAuth'](arg1);
+}
+
+export function MergeWorktreeSession(arg1, arg2) {
+  return window['go']['main']['App']['MergeWorktreeSession'](arg1, arg2);
+}
+
+export function MoveMCPEnvToSecrets(arg1) {
+  return window['go']['main']['App']['MoveMCPEnvToSecrets'](arg1);
+}
+
 export function MovePath(arg1, arg2) {
   return window['go']['main']['App']['MovePath'](arg1, arg2);
 }
@@ -338,10 +706,34 @@ export function OpenMCPConfigFile() {
   return window['go']['main']['App']['OpenMCPConfigFile']();
 }
 
+export function PreviewMCPImport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewMCPImport'](arg1, arg2, arg3);
+}
+
+export function PreviewReplace(arg1) {
+  return window['go']['main']['App']['PreviewReplace'](arg1);
+}
+
+export function PreviewSkillImport(arg1, arg2) {
+  return window['go']['main']['App']['PreviewSkillImport'](arg1, arg2);
+}
+
+export function ProbeMCPServer(arg1) {
+  return window['go']['main']['App']['ProbeMCPServer'](arg1);
+}
+
 export function ReadFileContent(arg1) {
   return window['go']['main']['App']['ReadFileContent'](arg1);
 }
 
+export function ReadFileWithInfo(arg1) {
+  return window['go']['main']['App']['ReadFileWithInfo'](arg1);
+}
+
+export function ReadSkillFile(arg1, arg2) {
+  return window['go']['main']['App']['ReadSkillFile'](arg1, arg2);
+}
+
 export function RefreshActiveKiroQuota() {
   return window['go']['main']['App']['RefreshActiveKiroQuota']();
 }
@@ -354,6 +746,10 @@ export function RefreshKiroToken(arg1) {
   return window['go']['main']['App']['RefreshKiroToken'](arg1);
 }
 
+export function RefreshMCPMarket() {
+  return window['go']['main']['App']['RefreshMCPMarket']();
+}
+
 export function RemoveKiroAccount(arg1) {
   return window['go']['main']['App']['RemoveKiroAccount'](arg1);
 }
@@ -366,6 +762,10 @@ export function RenamePath(arg1, arg2) {
   return window['go']['main']['App']['RenamePath'](arg1, arg2);
 }
 
+export function ReopenWithEncoding(arg1, arg2) {
+  return window['go']['main']['App']['ReopenWithEncoding'](arg1, arg2);
+}
+
 export function ReplaceInFiles(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['ReplaceInFiles'](arg1, arg2, arg3, arg4);
 }
@@ -378,14 +778,54 @@ export function ResizeTerminal(arg1, arg2, arg3) {
   return window['go']['main']['App']['ResizeTerminal'](arg1, arg2, arg3);
 }
 
+export function ResolveConflictHunk(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ResolveConflictHunk'](arg1, arg2, arg3, arg4);
+}
+
 export function RestartOpenCode() {
   return window['go']['main']['App']['RestartOpenCode']();
 }
 
+export function RestoreFileHistory(arg1, arg2) {
+  return window['go']['main']['App']['RestoreFileHistory'](arg1, arg2);
+}
+
+export function RestoreTrashItem(arg1) {
+  return window['go']['main']['App']['RestoreTrashItem'](arg1);
+}
+
+export function RevertTurn(arg1) {
+  return window['go']['main']['App']['RevertTurn'](arg1);
+}
+
+export function RevertTurnFile(arg1, arg2) {
+  return window['go']['main']['App']['RevertTurnFile'](arg1, arg2);
+}
+
+export function RevertTurnHunk(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RevertTurnHunk'](arg1, arg2, arg3);
+}
+
 export function RunFile(arg1) {
   return window['go']['main']['App']['RunFile'](arg1);
 }
 
+export function RunFileTask(arg1) {
+  return window['go']['main']['App']['RunFileTask'](arg1);
+}
+
+export function RunTask(arg1) {
+  return window['go']['main']['App']['RunTask'](arg1);
+}
+
+export function SaveCommitMessageSettings(arg1) {
+  return window['go']['main']['App']['SaveCommitMessageSettings'](arg1);
+}
+
+export function SaveExplorerSettings(arg1) {
+  return window['go']['main']['App']['SaveExplorerSettings'](arg1);
+}
+
 export function SaveImageToWorkDir(arg1) {
   return window['go']['main']['App']['SaveImageToWorkDir'](arg1);
 }
@@ -394,10 +834,30 @@ export function SaveMCPConfig(arg1) {
   return window['go']['main']['App']['SaveMCPConfig'](arg1);
 }
 
+export function SaveMCPMarketSettings(arg1) {
+  return window['go']['main']['App']['SaveMCPMarketSettings'](arg1);
+}
+
+export function SaveSkillAsTemplate(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SaveSkillAsTemplate'](arg1, arg2, arg3, arg4);
+}
+
+export function SaveTrashSettings(arg1) {
+  return window['go']['main']['App']['SaveTrashSettings'](arg1);
+}
+
 export function SearchInFiles(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['SearchInFiles'](arg1, arg2, arg3, arg4);
 }
 
+export function SearchWorkspace(arg1) {
+  return window['go']['main']['App']['SearchWorkspace'](arg1);
+}
+
+export function SelectSkillPackage() {
+  return window['go']['main']['App']['SelectSkillPackage']();
+}
+
 export function SendMessage(arg1, arg2) {
   return window['go']['main']['App']['SendMessage'](arg1, arg2);
 }
@@ -410,6 +870,14 @@ export function SetActiveFile(arg1, arg2) {
   return window['go']['main']['App']['SetActiveFile'](arg1, arg2);
 }
 
+export function SetBreakpoints(arg1, arg2) {
+  return window['go']['main']['App']['SetBreakpoints'](arg1, arg2);
+}
+
+export function SetMCPSecret(arg1, arg2) {
+  return window['go']['main']['App']['SetMCPSecret'](arg1, arg2);
+}
+
 export function SetOpenCodeWorkDir(arg1) {
   return window['go']['main']['App']['SetOpenCodeWorkDir'](arg1);
 }
@@ -418,14 +886,34 @@ export function SetServerURL(arg1) {
   return window['go']['main']['App']['SetServerURL'](arg1);
 }
 
+export function SetShowHiddenFiles(arg1) {
+  return window['go']['main']['App']['SetShowHiddenFiles'](arg1);
+}
+
+export function SetSkillEnabled(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSkillEnabled'](arg1, arg2, arg3);
+}
+
+export function SetSkillMetadata(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSkillMetadata'](arg1, arg2, arg3);
+}
+
 export function SetWorkDir(arg1) {
   return window['go']['main']['App']['SetWorkDir'](arg1);
 }
 
+export function StartDebug(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartDebug'](arg1, arg2, arg3);
+}
+
 export function StartKiroOAuth(arg1) {
   return window['go']['main']['App']['StartKiroOAuth'](arg1);
 }
 
+export function StartMCPOAuth(arg1, arg2) {
+  return window['go']['main']['App']['StartMCPOAuth'](arg1, arg2);
+}
+
 export function StartOpenCode() {
   return window['go']['main']['App']['StartOpenCode']();
 }
@@ -434,6 +922,14 @@ export function StartRemoteControl(arg1) {
   return window['go']['main']['App']['StartRemoteControl'](arg1);
 }
 
+export function StartSearch(arg1) {
+  return window['go']['main']['App']['StartSearch'](arg1);
+}
+
+export function StopDebug(arg1) {
+  return window['go']['main']['App']['StopDebug'](arg1);
+}
+
 export function StopOpenCode() {
   return window['go']['main']['App']['StopOpenCode']();
 }
@@ -442,6 +938,10 @@ export function StopRemoteControl() {
   return window['go']['main']['App']['StopRemoteControl']();
 }
 
+export function StopTask(arg1) {
+  return window['go']['main']['App']['StopTask'](arg1);
+}
+
 export function SubscribeEvents() {
   return window['go']['main']['App']['SubscribeEvents']();
 }
@@ -450,8 +950,20 @@ export function SwitchKiroAccount(arg1) {
   return window['go']['main']['App']['SwitchKiroAccount'](arg1);
 }
 
-export function ToggleMCPServer(arg1, arg2) {
-  return window['go']['main']['App']['ToggleMCPServer'](arg1, arg2);
+export function TestMCPServer(arg1, arg2) {
+  return window['go']['main']['App']['TestMCPServer'](arg1, arg2);
+}
+
+export function ToggleMCPServer(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ToggleMCPServer'](arg1, arg2, arg3);
+}
+
+export function UndoFileOperation() {
+  return window['go']['main']['App']['UndoFileOperation']();
+}
+
+export function UndoReplace(arg1) {
+  return window['go']['main']['App']['UndoReplace'](arg1);
 }
 
 export function UninstallAntigravityAuth() {
@@ -510,6 +1022,14 @@ export function ValidateKiroToken(arg1) {
   return window['go']['main']['App']['ValidateKiroToken'](arg1);
 }
 
+export function ValidateMCPMarketValues(arg1, arg2) {
+  return window['go']['main']['App']['ValidateMCPMarketValues'](arg1, arg2);
+}
+
+export function ValidateMCPToolArgs(arg1, arg2) {
+  return window['go']['main']['App']['ValidateMCPToolArgs'](arg1, arg2);
+}
+
 export function WatchFile(arg1) {
   return window['go']['main']['App']['WatchFile'](arg1);
 }
@@ -518,6 +1038,14 @@ export function WriteFileContent(arg1, arg2) {
   return window['go']['main']['App']['WriteFileContent'](arg1, arg2);
 }
 
+export function WriteFileWithOptions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WriteFileWithOptions'](arg1, arg2, arg3);
+}
+
+export function WriteSkillFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WriteSkillFile'](arg1, arg2, arg3);
+}
+
 export function WriteTerminal(arg1, arg2) {
   return window['go']['main']['App']['WriteTerminal'](arg1, arg2);
 }
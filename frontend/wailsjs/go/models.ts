@@ -190,6 +190,70 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class CommitMessageOptions {
+	    model: string;
+	    review: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitMessageOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.review = source["review"];
+	    }
+	}
+	export class CommitMessageResult {
+	    message: string;
+	    subject: string;
+	    body: string;
+	    conventional: boolean;
+	    review?: string;
+	    files: string[];
+	    summarizedFiles: string[];
+	    truncated: boolean;
+	    model: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitMessageResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.subject = source["subject"];
+	        this.body = source["body"];
+	        this.conventional = source["conventional"];
+	        this.review = source["review"];
+	        this.files = source["files"];
+	        this.summarizedFiles = source["summarizedFiles"];
+	        this.truncated = source["truncated"];
+	        this.model = source["model"];
+	    }
+	}
+	export class CommitMessageSettings {
+	    model: string;
+	    promptTemplate: string;
+	    reviewTemplate: string;
+	    language: string;
+	    maxDiffChars: number;
+	    maxFileChars: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommitMessageSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.promptTemplate = source["promptTemplate"];
+	        this.reviewTemplate = source["reviewTemplate"];
+	        this.language = source["language"];
+	        this.maxDiffChars = source["maxDiffChars"];
+	        this.maxFileChars = source["maxFileChars"];
+	    }
+	}
 	export class ConfigInfo {
 	    model: string;
 	
@@ -223,138 +287,200 @@ export namespace main {
 	    }
 	}
 	
-	export class FileInfo {
+	export class ConflictHunk {
+	    index: number;
+	    startLine: number;
+	    endLine: number;
+	    ours: string[];
+	    base: string[];
+	    theirs: string[];
+	    oursLabel: string;
+	    baseLabel?: string;
+	    theirsLabel: string;
+	    hasBase: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.ours = source["ours"];
+	        this.base = source["base"];
+	        this.theirs = source["theirs"];
+	        this.oursLabel = source["oursLabel"];
+	        this.baseLabel = source["baseLabel"];
+	        this.theirsLabel = source["theirsLabel"];
+	        this.hasBase = source["hasBase"];
+	    }
+	}
+	export class DebugBreakpoint {
+	    id?: number;
+	    line: number;
+	    condition?: string;
+	    logMessage?: string;
+	    verified: boolean;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugBreakpoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.line = source["line"];
+	        this.condition = source["condition"];
+	        this.logMessage = source["logMessage"];
+	        this.verified = source["verified"];
+	        this.message = source["message"];
+	    }
+	}
+	export class DebugScope {
 	    name: string;
-	    path: string;
-	    type: string;
-	    size: number;
-	    children?: FileInfo[];
+	    variablesReference: number;
+	    expensive: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileInfo(source);
+	        return new DebugScope(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.type = source["type"];
-	        this.size = source["size"];
-	        this.children = this.convertValues(source["children"], FileInfo);
+	        this.variablesReference = source["variablesReference"];
+	        this.expensive = source["expensive"];
 	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
 	}
-	export class GitChange {
-	    path: string;
+	export class DebugSessionInfo {
+	    id: string;
+	    language: string;
+	    program: string;
 	    status: string;
-	    staged: boolean;
+	    threadId?: number;
+	    reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new GitChange(source);
+	        return new DebugSessionInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
+	        this.id = source["id"];
+	        this.language = source["language"];
+	        this.program = source["program"];
 	        this.status = source["status"];
-	        this.staged = source["staged"];
+	        this.threadId = source["threadId"];
+	        this.reason = source["reason"];
 	    }
 	}
-	export class GitStatus {
-	    branch: string;
-	    changes: GitChange[];
-	    hasRepo: boolean;
+	export class DebugStackFrame {
+	    id: number;
+	    name: string;
+	    file?: string;
+	    line: number;
+	    column: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new GitStatus(source);
+	        return new DebugStackFrame(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.branch = source["branch"];
-	        this.changes = this.convertValues(source["changes"], GitChange);
-	        this.hasRepo = source["hasRepo"];
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.file = source["file"];
+	        this.line = source["line"];
+	        this.column = source["column"];
 	    }
+	}
+	export class DebugThread {
+	    id: number;
+	    name: string;
 	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
+	    static createFrom(source: any = {}) {
+	        return new DebugThread(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	    }
 	}
-	export class ImageData {
+	export class DebugVariable {
 	    name: string;
-	    type: string;
-	    data: string;
+	    value: string;
+	    type?: string;
+	    variablesReference: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ImageData(source);
+	        return new DebugVariable(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
+	        this.value = source["value"];
 	        this.type = source["type"];
-	        this.data = source["data"];
+	        this.variablesReference = source["variablesReference"];
 	    }
 	}
-	export class QuotaDetail {
-	    used: number;
-	    total: number;
+	export class DiffHunk {
+	    oldStart: number;
+	    oldLines: number;
+	    newStart: number;
+	    newLines: number;
+	    lines: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new QuotaDetail(source);
+	        return new DiffHunk(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.used = source["used"];
-	        this.total = source["total"];
+	        this.oldStart = source["oldStart"];
+	        this.oldLines = source["oldLines"];
+	        this.newStart = source["newStart"];
+	        this.newLines = source["newLines"];
+	        this.lines = source["lines"];
 	    }
 	}
-	export class QuotaInfo {
-	    main: QuotaDetail;
-	    trial: QuotaDetail;
-	    reward: QuotaDetail;
+	export class FileInfo {
+	    name: string;
+	    path: string;
+	    type: string;
+	    size: number;
+	    children?: FileInfo[];
+	    modTime?: number;
+	    mode?: string;
+	    isSymlink?: boolean;
+	    symlinkTarget?: string;
+	    gitStatus?: string;
+	    hidden?: boolean;
+	    ignored?: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new QuotaInfo(source);
+	        return new FileInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.main = this.convertValues(source["main"], QuotaDetail);
-	        this.trial = this.convertValues(source["trial"], QuotaDetail);
-	        this.reward = this.convertValues(source["reward"], QuotaDetail);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.type = source["type"];
+	        this.size = source["size"];
+	        this.children = this.convertValues(source["children"], FileInfo);
+	        this.modTime = source["modTime"];
+	        this.mode = source["mode"];
+	        this.isSymlink = source["isSymlink"];
+	        this.symlinkTarget = source["symlinkTarget"];
+	        this.gitStatus = source["gitStatus"];
+	        this.hidden = source["hidden"];
+	        this.ignored = source["ignored"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -375,55 +501,24 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class KiroAccount {
-	    id: string;
-	    email: string;
-	    displayName: string;
-	    avatar?: string;
-	    // Go type: time
-	    tokenExpiry: any;
-	    loginMethod: string;
-	    provider?: string;
-	    subscriptionType: string;
-	    quota: QuotaInfo;
-	    tags: string[];
-	    notes?: string;
-	    isActive: boolean;
-	    // Go type: time
-	    lastUsed: any;
-	    // Go type: time
-	    createdAt: any;
-	    machineId?: string;
-	    sqmId?: string;
-	    devDeviceId?: string;
-	    userId?: string;
-	    profileArn?: string;
+	export class DirPage {
+	    dir: string;
+	    items: FileInfo[];
+	    offset: number;
+	    total: number;
+	    hasMore: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new KiroAccount(source);
+	        return new DirPage(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.email = source["email"];
-	        this.displayName = source["displayName"];
-	        this.avatar = source["avatar"];
-	        this.tokenExpiry = this.convertValues(source["tokenExpiry"], null);
-	        this.loginMethod = source["loginMethod"];
-	        this.provider = source["provider"];
-	        this.subscriptionType = source["subscriptionType"];
-	        this.quota = this.convertValues(source["quota"], QuotaInfo);
-	        this.tags = source["tags"];
-	        this.notes = source["notes"];
-	        this.isActive = source["isActive"];
-	        this.lastUsed = this.convertValues(source["lastUsed"], null);
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.machineId = source["machineId"];
-	        this.sqmId = source["sqmId"];
-	        this.devDeviceId = source["devDeviceId"];
-	        this.userId = source["userId"];
-	        this.profileArn = source["profileArn"];
+	        this.dir = source["dir"];
+	        this.items = this.convertValues(source["items"], FileInfo);
+	        this.offset = source["offset"];
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -444,57 +539,76 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class KiroAuthStatus {
-	    installed: boolean;
-	    version: string;
-	    latestVersion: string;
-	    updateAvailable: boolean;
+	export class ExplorerSettings {
+	    showHidden: boolean;
+	    hideGitIgnored: boolean;
+	    exclude: string[];
+	    alwaysShow: string[];
+	    pageSize: number;
+	    showGitStatus: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new KiroAuthStatus(source);
+	        return new ExplorerSettings(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.installed = source["installed"];
-	        this.version = source["version"];
-	        this.latestVersion = source["latestVersion"];
-	        this.updateAvailable = source["updateAvailable"];
+	        this.showHidden = source["showHidden"];
+	        this.hideGitIgnored = source["hideGitIgnored"];
+	        this.exclude = source["exclude"];
+	        this.alwaysShow = source["alwaysShow"];
+	        this.pageSize = source["pageSize"];
+	        this.showGitStatus = source["showGitStatus"];
 	    }
 	}
-	
-	export class MCPServer {
-	    type: string;
-	    command?: string[];
-	    url?: string;
-	    enabled: boolean;
-	    environment?: Record<string, string>;
-	    timeout?: number;
+	export class FileContent {
+	    path: string;
+	    content: string;
+	    encoding: string;
+	    lineEnding: string;
+	    hash: string;
+	    size: number;
+	    modTime: number;
+	    isBinary: boolean;
+	    tooLarge: boolean;
+	    readOnly: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPServer(source);
+	        return new FileContent(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.type = source["type"];
-	        this.command = source["command"];
-	        this.url = source["url"];
-	        this.enabled = source["enabled"];
-	        this.environment = source["environment"];
-	        this.timeout = source["timeout"];
+	        this.path = source["path"];
+	        this.content = source["content"];
+	        this.encoding = source["encoding"];
+	        this.lineEnding = source["lineEnding"];
+	        this.hash = source["hash"];
+	        this.size = source["size"];
+	        this.modTime = source["modTime"];
+	        this.isBinary = source["isBinary"];
+	        this.tooLarge = source["tooLarge"];
+	        this.readOnly = source["readOnly"];
 	    }
 	}
-	export class MCPConfig {
-	    mcp: Record<string, MCPServer>;
+	
+	export class FileOperation {
+	    kind: string;
+	    source: string;
+	    target: string;
+	    // Go type: time
+	    time: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPConfig(source);
+	        return new FileOperation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.mcp = this.convertValues(source["mcp"], MCPServer, true);
+	        this.kind = source["kind"];
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.time = this.convertValues(source["time"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -515,127 +629,164 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class MCPMarketItem {
-	    name: string;
-	    description: string;
-	    command: string[];
-	    envVars?: string[];
-	    category: string;
-	    docsUrl?: string;
-	    configTips?: string;
+	export class GitBlameLine {
+	    line: number;
+	    origLine: number;
+	    hash: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPMarketItem(source);
+	        return new GitBlameLine(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.command = source["command"];
-	        this.envVars = source["envVars"];
-	        this.category = source["category"];
-	        this.docsUrl = source["docsUrl"];
-	        this.configTips = source["configTips"];
+	        this.line = source["line"];
+	        this.origLine = source["origLine"];
+	        this.hash = source["hash"];
 	    }
 	}
-	
-	export class MCPTool {
-	    id: string;
-	    description: string;
-	    parameters: any;
+	export class GitBlameCommit {
+	    hash: string;
+	    author: string;
+	    email: string;
+	    time: number;
+	    summary: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new MCPTool(source);
+	        return new GitBlameCommit(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.description = source["description"];
-	        this.parameters = source["parameters"];
+	        this.hash = source["hash"];
+	        this.author = source["author"];
+	        this.email = source["email"];
+	        this.time = source["time"];
+	        this.summary = source["summary"];
 	    }
 	}
-	export class Message {
-	    role: string;
-	    content: string;
+	export class GitBlame {
+	    path: string;
+	    commits: Record<string, GitBlameCommit>;
+	    lines: GitBlameLine[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Message(source);
+	        return new GitBlame(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.role = source["role"];
-	        this.content = source["content"];
+	        this.path = source["path"];
+	        this.commits = this.convertValues(source["commits"], GitBlameCommit, true);
+	        this.lines = this.convertValues(source["lines"], GitBlameLine);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class OhMyOpenCodeStatus {
-	    installed: boolean;
-	    version: string;
 	
-	    static createFrom(source: any = {}) {
-	        return new OhMyOpenCodeStatus(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.installed = source["installed"];
-	        this.version = source["version"];
-	    }
-	}
-	export class OpenCodeStatus {
-	    installed: boolean;
-	    running: boolean;
-	    connected: boolean;
-	    path: string;
-	    version: string;
-	    port: number;
-	    workDir: string;
+	export class GitBranch {
+	    name: string;
+	    ref: string;
+	    current: boolean;
+	    remote: boolean;
+	    upstream?: string;
+	    ahead: number;
+	    behind: number;
+	    gone: boolean;
+	    hash: string;
+	    time: number;
+	    subject: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new OpenCodeStatus(source);
+	        return new GitBranch(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.installed = source["installed"];
-	        this.running = source["running"];
-	        this.connected = source["connected"];
-	        this.path = source["path"];
-	        this.version = source["version"];
-	        this.port = source["port"];
-	        this.workDir = source["workDir"];
+	        this.name = source["name"];
+	        this.ref = source["ref"];
+	        this.current = source["current"];
+	        this.remote = source["remote"];
+	        this.upstream = source["upstream"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.gone = source["gone"];
+	        this.hash = source["hash"];
+	        this.time = source["time"];
+	        this.subject = source["subject"];
 	    }
 	}
-	export class Provider {
-	    id: string;
-	    name: string;
+	export class GitChange {
+	    path: string;
+	    origPath?: string;
+	    status: string;
+	    staged: boolean;
+	    conflict?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Provider(source);
+	        return new GitChange(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.origPath = source["origPath"];
+	        this.status = source["status"];
+	        this.staged = source["staged"];
+	        this.conflict = source["conflict"];
 	    }
 	}
-	export class ProviderInfo {
-	    all: Provider[];
-	    connected: string[];
-	    default: Record<string, string>;
+	export class GitConflictFile {
+	    path: string;
+	    kind: string;
+	    mergeHead: string;
+	    base: string;
+	    ours: string;
+	    theirs: string;
+	    hasBase: boolean;
+	    hasOurs: boolean;
+	    hasTheirs: boolean;
+	    merged: string;
+	    exists: boolean;
+	    binary: boolean;
+	    hunks: ConflictHunk[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ProviderInfo(source);
+	        return new GitConflictFile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.all = this.convertValues(source["all"], Provider);
-	        this.connected = source["connected"];
-	        this.default = source["default"];
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.mergeHead = source["mergeHead"];
+	        this.base = source["base"];
+	        this.ours = source["ours"];
+	        this.theirs = source["theirs"];
+	        this.hasBase = source["hasBase"];
+	        this.hasOurs = source["hasOurs"];
+	        this.hasTheirs = source["hasTheirs"];
+	        this.merged = source["merged"];
+	        this.exists = source["exists"];
+	        this.binary = source["binary"];
+	        this.hunks = this.convertValues(source["hunks"], ConflictHunk);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -656,139 +807,223 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class QuotaAlert {
-	    accountId: string;
-	    accountName: string;
-	    quotaType: string;
-	    usage: number;
-	    message: string;
+	export class GitCredentialPrompt {
+	    id: string;
+	    jobId: string;
+	    prompt: string;
+	    secret: boolean;
+	    kind: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new QuotaAlert(source);
+	        return new GitCredentialPrompt(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.accountId = source["accountId"];
-	        this.accountName = source["accountName"];
-	        this.quotaType = source["quotaType"];
-	        this.usage = source["usage"];
-	        this.message = source["message"];
+	        this.id = source["id"];
+	        this.jobId = source["jobId"];
+	        this.prompt = source["prompt"];
+	        this.secret = source["secret"];
+	        this.kind = source["kind"];
 	    }
 	}
-	
-	
-	export class SearchResult {
+	export class GitFileDiff {
 	    path: string;
-	    line: number;
-	    content: string;
-	    match: string;
+	    oldPath?: string;
+	    status: string;
+	    binary: boolean;
+	    hunks: DiffHunk[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchResult(source);
+	        return new GitFileDiff(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.path = source["path"];
-	        this.line = source["line"];
-	        this.content = source["content"];
-	        this.match = source["match"];
+	        this.oldPath = source["oldPath"];
+	        this.status = source["status"];
+	        this.binary = source["binary"];
+	        this.hunks = this.convertValues(source["hunks"], DiffHunk);
 	    }
-	}
 	
-	export class Session {
-	    id: string;
-	    title: string;
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GitRefUpdate {
+	    from: string;
+	    to: string;
+	    status: string;
+	    reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Session(source);
+	        return new GitRefUpdate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.title = source["title"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.status = source["status"];
+	        this.reason = source["reason"];
 	    }
 	}
-	export class SkillInfo {
-	    name: string;
-	    description: string;
-	    path: string;
-	    source: string;
-	    content?: string;
-	    enabled: boolean;
+	export class GitJobResult {
+	    updatedRefs: GitRefUpdate[];
+	    rejectedRefs: GitRefUpdate[];
+	    conflicts: string[];
+	    upToDate: boolean;
+	    authFailed: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new SkillInfo(source);
+	        return new GitJobResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.path = source["path"];
-	        this.source = source["source"];
-	        this.content = source["content"];
-	        this.enabled = source["enabled"];
+	        this.updatedRefs = this.convertValues(source["updatedRefs"], GitRefUpdate);
+	        this.rejectedRefs = this.convertValues(source["rejectedRefs"], GitRefUpdate);
+	        this.conflicts = source["conflicts"];
+	        this.upToDate = source["upToDate"];
+	        this.authFailed = source["authFailed"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class SkillTemplate {
+	export class GitJob {
 	    id: string;
-	    name: string;
-	    description: string;
-	    content: string;
-	    category: string;
+	    dir: string;
+	    operation: string;
+	    args: string[];
+	    status: string;
+	    phase: string;
+	    percent: number;
+	    output: string;
+	    result?: GitJobResult;
+	    error?: string;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    finishedAt?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new SkillTemplate(source);
+	        return new GitJob(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.content = source["content"];
-	        this.category = source["category"];
+	        this.dir = source["dir"];
+	        this.operation = source["operation"];
+	        this.args = source["args"];
+	        this.status = source["status"];
+	        this.phase = source["phase"];
+	        this.percent = source["percent"];
+	        this.output = source["output"];
+	        this.result = this.convertValues(source["result"], GitJobResult);
+	        this.error = source["error"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	
-	export class Tag {
-	    name: string;
-	    color: string;
-	    description?: string;
+	export class GitLogFile {
+	    path: string;
+	    oldPath?: string;
+	    status: string;
+	    additions: number;
+	    deletions: number;
+	    binary: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new Tag(source);
+	        return new GitLogFile(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.color = source["color"];
-	        this.description = source["description"];
+	        this.path = source["path"];
+	        this.oldPath = source["oldPath"];
+	        this.status = source["status"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.binary = source["binary"];
 	    }
 	}
-	export class TokenInfo {
-	    access_token: string;
-	    refresh_token: string;
-	    // Go type: time
-	    expires_at: any;
-	    token_type: string;
-	    profile_arn?: string;
+	export class GitLogEntry {
+	    hash: string;
+	    shortHash: string;
+	    parents: string[];
+	    author: string;
+	    email: string;
+	    time: number;
+	    refs: string;
+	    subject: string;
+	    files: GitLogFile[];
 	
 	    static createFrom(source: any = {}) {
-	        return new TokenInfo(source);
+	        return new GitLogEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.access_token = source["access_token"];
-	        this.refresh_token = source["refresh_token"];
-	        this.expires_at = this.convertValues(source["expires_at"], null);
-	        this.token_type = source["token_type"];
-	        this.profile_arn = source["profile_arn"];
+	        this.hash = source["hash"];
+	        this.shortHash = source["shortHash"];
+	        this.parents = source["parents"];
+	        this.author = source["author"];
+	        this.email = source["email"];
+	        this.time = source["time"];
+	        this.refs = source["refs"];
+	        this.subject = source["subject"];
+	        this.files = this.convertValues(source["files"], GitLogFile);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -809,22 +1044,2378 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class UIUXProMaxStatus {
-	    installed: boolean;
-	    version: string;
-	    latestVersion: string;
-	    updateAvailable: boolean;
+	
+	export class GitLogPage {
+	    commits: GitLogEntry[];
+	    skip: number;
+	    hasMore: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new UIUXProMaxStatus(source);
+	        return new GitLogPage(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.installed = source["installed"];
-	        this.version = source["version"];
-	        this.latestVersion = source["latestVersion"];
-	        this.updateAvailable = source["updateAvailable"];
+	        this.commits = this.convertValues(source["commits"], GitLogEntry);
+	        this.skip = source["skip"];
+	        this.hasMore = source["hasMore"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class GitRemoteOptions {
+	    remote: string;
+	    branch: string;
+	    setUpstream: boolean;
+	    force: boolean;
+	    tags: boolean;
+	    rebase?: boolean;
+	    prune: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitRemoteOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remote = source["remote"];
+	        this.branch = source["branch"];
+	        this.setUpstream = source["setUpstream"];
+	        this.force = source["force"];
+	        this.tags = source["tags"];
+	        this.rebase = source["rebase"];
+	        this.prune = source["prune"];
+	    }
+	}
+	export class GitStash {
+	    index: number;
+	    ref: string;
+	    message: string;
+	    time: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStash(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.ref = source["ref"];
+	        this.message = source["message"];
+	        this.time = source["time"];
+	    }
+	}
+	export class GitStatus {
+	    branch: string;
+	    changes: GitChange[];
+	    hasRepo: boolean;
+	    root: string;
+	    head: string;
+	    detached: boolean;
+	    upstream: string;
+	    ahead: number;
+	    behind: number;
+	    mergeState?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.changes = this.convertValues(source["changes"], GitChange);
+	        this.hasRepo = source["hasRepo"];
+	        this.root = source["root"];
+	        this.head = source["head"];
+	        this.detached = source["detached"];
+	        this.upstream = source["upstream"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.mergeState = source["mergeState"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryEntry {
+	    id: string;
+	    // Go type: time
+	    time: any;
+	    hash: string;
+	    size: number;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.hash = source["hash"];
+	        this.size = source["size"];
+	        this.source = source["source"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryFile {
+	    path: string;
+	    count: number;
+	    // Go type: time
+	    lastTime: any;
+	    exists: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.count = source["count"];
+	        this.lastTime = this.convertValues(source["lastTime"], null);
+	        this.exists = source["exists"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImageData {
+	    name: string;
+	    type: string;
+	    data: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.data = source["data"];
+	    }
+	}
+	export class QuotaDetail {
+	    used: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuotaDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.used = source["used"];
+	        this.total = source["total"];
+	    }
+	}
+	export class QuotaInfo {
+	    main: QuotaDetail;
+	    trial: QuotaDetail;
+	    reward: QuotaDetail;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuotaInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.main = this.convertValues(source["main"], QuotaDetail);
+	        this.trial = this.convertValues(source["trial"], QuotaDetail);
+	        this.reward = this.convertValues(source["reward"], QuotaDetail);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class KiroAccount {
+	    id: string;
+	    email: string;
+	    displayName: string;
+	    avatar?: string;
+	    // Go type: time
+	    tokenExpiry: any;
+	    loginMethod: string;
+	    provider?: string;
+	    subscriptionType: string;
+	    quota: QuotaInfo;
+	    tags: string[];
+	    notes?: string;
+	    isActive: boolean;
+	    // Go type: time
+	    lastUsed: any;
+	    // Go type: time
+	    createdAt: any;
+	    machineId?: string;
+	    sqmId?: string;
+	    devDeviceId?: string;
+	    userId?: string;
+	    profileArn?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new KiroAccount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.email = source["email"];
+	        this.displayName = source["displayName"];
+	        this.avatar = source["avatar"];
+	        this.tokenExpiry = this.convertValues(source["tokenExpiry"], null);
+	        this.loginMethod = source["loginMethod"];
+	        this.provider = source["provider"];
+	        this.subscriptionType = source["subscriptionType"];
+	        this.quota = this.convertValues(source["quota"], QuotaInfo);
+	        this.tags = source["tags"];
+	        this.notes = source["notes"];
+	        this.isActive = source["isActive"];
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.machineId = source["machineId"];
+	        this.sqmId = source["sqmId"];
+	        this.devDeviceId = source["devDeviceId"];
+	        this.userId = source["userId"];
+	        this.profileArn = source["profileArn"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class KiroAuthStatus {
+	    installed: boolean;
+	    version: string;
+	    latestVersion: string;
+	    updateAvailable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new KiroAuthStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.installed = source["installed"];
+	        this.version = source["version"];
+	        this.latestVersion = source["latestVersion"];
+	        this.updateAvailable = source["updateAvailable"];
+	    }
+	}
+	
+	export class MCPServer {
+	    type: string;
+	    command?: string[];
+	    url?: string;
+	    headers?: Record<string, string>;
+	    enabled: boolean;
+	    environment?: Record<string, string>;
+	    timeout?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.command = source["command"];
+	        this.url = source["url"];
+	        this.headers = source["headers"];
+	        this.enabled = source["enabled"];
+	        this.environment = source["environment"];
+	        this.timeout = source["timeout"];
+	    }
+	}
+	export class MCPConfig {
+	    mcp: Record<string, MCPServer>;
+	    layers?: Record<string, Array<string>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mcp = this.convertValues(source["mcp"], MCPServer, true);
+	        this.layers = source["layers"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPConfigLayer {
+	    name: string;
+	    path: string;
+	    exists: boolean;
+	    servers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPConfigLayer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.exists = source["exists"];
+	        this.servers = source["servers"];
+	    }
+	}
+	export class MCPResourceRef {
+	    uri: string;
+	    mimeType?: string;
+	    text?: string;
+	    blob?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPResourceRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uri = source["uri"];
+	        this.mimeType = source["mimeType"];
+	        this.text = source["text"];
+	        this.blob = source["blob"];
+	    }
+	}
+	export class MCPContent {
+	    type: string;
+	    text?: string;
+	    data?: string;
+	    mimeType?: string;
+	    uri?: string;
+	    name?: string;
+	    resource?: MCPResourceRef;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.text = source["text"];
+	        this.data = source["data"];
+	        this.mimeType = source["mimeType"];
+	        this.uri = source["uri"];
+	        this.name = source["name"];
+	        this.resource = this.convertValues(source["resource"], MCPResourceRef);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPExportResult {
+	    content: string;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.warnings = source["warnings"];
+	    }
+	}
+	export class MCPImportEntry {
+	    name: string;
+	    server: MCPServer;
+	    conflict: boolean;
+	    identical: boolean;
+	    existing?: MCPServer;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPImportEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.server = this.convertValues(source["server"], MCPServer);
+	        this.conflict = source["conflict"];
+	        this.identical = source["identical"];
+	        this.existing = this.convertValues(source["existing"], MCPServer);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPImportPreview {
+	    format: string;
+	    entries: MCPImportEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.entries = this.convertValues(source["entries"], MCPImportEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPImportResult {
+	    imported: string[];
+	    skipped: string[];
+	    renamed: Record<string, string>;
+	    failed: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.imported = source["imported"];
+	        this.skipped = source["skipped"];
+	        this.renamed = source["renamed"];
+	        this.failed = source["failed"];
+	    }
+	}
+	export class MCPImportSource {
+	    format: string;
+	    label: string;
+	    path: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPImportSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.label = source["label"];
+	        this.path = source["path"];
+	        this.count = source["count"];
+	    }
+	}
+	export class MCPMarketParam {
+	    name: string;
+	    label?: string;
+	    description?: string;
+	    type: string;
+	    target: string;
+	    required: boolean;
+	    default?: string;
+	    options?: string[];
+	    pattern?: string;
+	    placeholder?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPMarketParam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.description = source["description"];
+	        this.type = source["type"];
+	        this.target = source["target"];
+	        this.required = source["required"];
+	        this.default = source["default"];
+	        this.options = source["options"];
+	        this.pattern = source["pattern"];
+	        this.placeholder = source["placeholder"];
+	    }
+	}
+	export class MCPMarketItem {
+	    name: string;
+	    description: string;
+	    category: string;
+	    type: string;
+	    command?: string[];
+	    url?: string;
+	    params: MCPMarketParam[];
+	    envVars?: string[];
+	    docsUrl?: string;
+	    configTips?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPMarketItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.category = source["category"];
+	        this.type = source["type"];
+	        this.command = source["command"];
+	        this.url = source["url"];
+	        this.params = this.convertValues(source["params"], MCPMarketParam);
+	        this.envVars = source["envVars"];
+	        this.docsUrl = source["docsUrl"];
+	        this.configTips = source["configTips"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MCPMarketSettings {
+	    registryUrl: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPMarketSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.registryUrl = source["registryUrl"];
+	    }
+	}
+	export class MCPMarketStatus {
+	    source: string;
+	    location?: string;
+	    count: number;
+	    // Go type: time
+	    fetchedAt?: any;
+	    fromCache: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPMarketStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.location = source["location"];
+	        this.count = source["count"];
+	        this.fetchedAt = this.convertValues(source["fetchedAt"], null);
+	        this.fromCache = source["fromCache"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPOAuthOptions {
+	    clientId?: string;
+	    clientSecret?: string;
+	    scope?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPOAuthOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.clientId = source["clientId"];
+	        this.clientSecret = source["clientSecret"];
+	        this.scope = source["scope"];
+	    }
+	}
+	export class MCPOAuthStatus {
+	    server: string;
+	    authorized: boolean;
+	    // Go type: time
+	    expiresAt?: any;
+	    scope?: string;
+	    refreshable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPOAuthStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server = source["server"];
+	        this.authorized = source["authorized"];
+	        this.expiresAt = this.convertValues(source["expiresAt"], null);
+	        this.scope = source["scope"];
+	        this.refreshable = source["refreshable"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class  {
+	    name: string;
+	    description?: string;
+	    required?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new (source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.required = source["required"];
+	    }
+	}
+	export class MCPPromptInfo {
+	    name: string;
+	    description?: string;
+	    arguments?: [];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPPromptInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.arguments = this.convertValues(source["arguments"], );
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPResourceInfo {
+	    uri: string;
+	    name: string;
+	    description?: string;
+	    mimeType?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPResourceInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uri = source["uri"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.mimeType = source["mimeType"];
+	    }
+	}
+	export class MCPToolInfo {
+	    name: string;
+	    title?: string;
+	    description?: string;
+	    inputSchema?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.inputSchema = source["inputSchema"];
+	    }
+	}
+	export class MCPServerInfo {
+	    name: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPServerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	    }
+	}
+	export class MCPProbeResult {
+	    name: string;
+	    status: string;
+	    error?: string;
+	    warnings?: string[];
+	    serverInfo: MCPServerInfo;
+	    protocolVersion?: string;
+	    initLatencyMs: number;
+	    latencyMs: number;
+	    tools: MCPToolInfo[];
+	    resources: MCPResourceInfo[];
+	    prompts: MCPPromptInfo[];
+	    // Go type: time
+	    checkedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPProbeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.warnings = source["warnings"];
+	        this.serverInfo = this.convertValues(source["serverInfo"], MCPServerInfo);
+	        this.protocolVersion = source["protocolVersion"];
+	        this.initLatencyMs = source["initLatencyMs"];
+	        this.latencyMs = source["latencyMs"];
+	        this.tools = this.convertValues(source["tools"], MCPToolInfo);
+	        this.resources = this.convertValues(source["resources"], MCPResourceInfo);
+	        this.prompts = this.convertValues(source["prompts"], MCPPromptInfo);
+	        this.checkedAt = this.convertValues(source["checkedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class MCPSecretInfo {
+	    name: string;
+	    // Go type: time
+	    updatedAt: any;
+	    usedBy: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPSecretInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.usedBy = source["usedBy"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class MCPTool {
+	    id: string;
+	    server?: string;
+	    name?: string;
+	    description: string;
+	    parameters: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.server = source["server"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parameters = source["parameters"];
+	    }
+	}
+	export class MCPTrafficEntry {
+	    direction: string;
+	    method: string;
+	    message?: number[];
+	    error?: string;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPTrafficEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.direction = source["direction"];
+	        this.method = source["method"];
+	        this.message = source["message"];
+	        this.error = source["error"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MCPValidationError {
+	    path: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPValidationError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.message = source["message"];
+	    }
+	}
+	export class MCPToolCallResult {
+	    server: string;
+	    tool: string;
+	    called: boolean;
+	    isError: boolean;
+	    error?: string;
+	    validationErrors: MCPValidationError[];
+	    content: MCPContent[];
+	    structuredContent?: any;
+	    connectMs: number;
+	    callMs: number;
+	    traffic: MCPTrafficEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPToolCallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.server = source["server"];
+	        this.tool = source["tool"];
+	        this.called = source["called"];
+	        this.isError = source["isError"];
+	        this.error = source["error"];
+	        this.validationErrors = this.convertValues(source["validationErrors"], MCPValidationError);
+	        this.content = this.convertValues(source["content"], MCPContent);
+	        this.structuredContent = source["structuredContent"];
+	        this.connectMs = source["connectMs"];
+	        this.callMs = source["callMs"];
+	        this.traffic = this.convertValues(source["traffic"], MCPTrafficEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class Message {
+	    role: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.content = source["content"];
+	    }
+	}
+	export class OhMyOpenCodeStatus {
+	    installed: boolean;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OhMyOpenCodeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.installed = source["installed"];
+	        this.version = source["version"];
+	    }
+	}
+	export class OpenCodeStatus {
+	    installed: boolean;
+	    running: boolean;
+	    connected: boolean;
+	    path: string;
+	    version: string;
+	    port: number;
+	    workDir: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenCodeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.installed = source["installed"];
+	        this.running = source["running"];
+	        this.connected = source["connected"];
+	        this.path = source["path"];
+	        this.version = source["version"];
+	        this.port = source["port"];
+	        this.workDir = source["workDir"];
+	    }
+	}
+	export class Provider {
+	    id: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Provider(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	    }
+	}
+	export class ProviderInfo {
+	    all: Provider[];
+	    connected: string[];
+	    default: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProviderInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.all = this.convertValues(source["all"], Provider);
+	        this.connected = source["connected"];
+	        this.default = source["default"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuotaAlert {
+	    accountId: string;
+	    accountName: string;
+	    quotaType: string;
+	    usage: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuotaAlert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.accountId = source["accountId"];
+	        this.accountName = source["accountName"];
+	        this.quotaType = source["quotaType"];
+	        this.usage = source["usage"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	
+	export class ReplaceChange {
+	    line: number;
+	    before: string;
+	    after: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.count = source["count"];
+	    }
+	}
+	export class ReplaceFilePreview {
+	    path: string;
+	    relPath: string;
+	    changes: ReplaceChange[];
+	    count: number;
+	    diff: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceFilePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.relPath = source["relPath"];
+	        this.changes = this.convertValues(source["changes"], ReplaceChange);
+	        this.count = source["count"];
+	        this.diff = source["diff"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplaceJournalFile {
+	    path: string;
+	    originalHash: string;
+	    newHash: string;
+	    backup: string;
+	    mode: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceJournalFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.originalHash = source["originalHash"];
+	        this.newHash = source["newHash"];
+	        this.backup = source["backup"];
+	        this.mode = source["mode"];
+	        this.count = source["count"];
+	    }
+	}
+	export class ReplaceOperation {
+	    id: string;
+	    query: string;
+	    replace: string;
+	    files: ReplaceJournalFile[];
+	    // Go type: time
+	    appliedAt: any;
+	    undone: boolean;
+	    // Go type: time
+	    undoneAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceOperation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.query = source["query"];
+	        this.replace = source["replace"];
+	        this.files = this.convertValues(source["files"], ReplaceJournalFile);
+	        this.appliedAt = this.convertValues(source["appliedAt"], null);
+	        this.undone = source["undone"];
+	        this.undoneAt = this.convertValues(source["undoneAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchOptions {
+	    dir: string;
+	    query: string;
+	    caseSensitive: boolean;
+	    useRegex: boolean;
+	    wholeWord: boolean;
+	    includes?: string[];
+	    excludes?: string[];
+	    includeIgnored: boolean;
+	    contextLines: number;
+	    maxResults: number;
+	    useIndex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.query = source["query"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.useRegex = source["useRegex"];
+	        this.wholeWord = source["wholeWord"];
+	        this.includes = source["includes"];
+	        this.excludes = source["excludes"];
+	        this.includeIgnored = source["includeIgnored"];
+	        this.contextLines = source["contextLines"];
+	        this.maxResults = source["maxResults"];
+	        this.useIndex = source["useIndex"];
+	    }
+	}
+	export class ReplaceOptions {
+	    search: SearchOptions;
+	    replace: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.search = this.convertValues(source["search"], SearchOptions);
+	        this.replace = source["replace"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplacePreview {
+	    id: string;
+	    files: ReplaceFilePreview[];
+	    fileCount: number;
+	    replaceCount: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplacePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.files = this.convertValues(source["files"], ReplaceFilePreview);
+	        this.fileCount = source["fileCount"];
+	        this.replaceCount = source["replaceCount"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplaceSelection {
+	    path: string;
+	    lines?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplaceSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class SearchMatch {
+	    column: number;
+	    length: number;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.column = source["column"];
+	        this.length = source["length"];
+	        this.text = source["text"];
+	    }
+	}
+	export class SearchLine {
+	    line: number;
+	    content: string;
+	    matches: SearchMatch[];
+	    before?: string[];
+	    after?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.content = source["content"];
+	        this.matches = this.convertValues(source["matches"], SearchMatch);
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchFileResult {
+	    path: string;
+	    relPath: string;
+	    lines: SearchLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchFileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.relPath = source["relPath"];
+	        this.lines = this.convertValues(source["lines"], SearchLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class SearchResult {
+	    path: string;
+	    line: number;
+	    column: number;
+	    content: string;
+	    match: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.content = source["content"];
+	        this.match = source["match"];
+	    }
+	}
+	export class SearchSummary {
+	    id?: string;
+	    files?: SearchFileResult[];
+	    fileCount: number;
+	    matchCount: number;
+	    scannedFiles: number;
+	    truncated: boolean;
+	    cancelled: boolean;
+	    durationMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.files = this.convertValues(source["files"], SearchFileResult);
+	        this.fileCount = source["fileCount"];
+	        this.matchCount = source["matchCount"];
+	        this.scannedFiles = source["scannedFiles"];
+	        this.truncated = source["truncated"];
+	        this.cancelled = source["cancelled"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Session {
+	    id: string;
+	    title: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	    }
+	}
+	export class SessionTurn {
+	    id: string;
+	    sessionId: string;
+	    index: number;
+	    root: string;
+	    prompt: string;
+	    baseCommit: string;
+	    afterCommit?: string;
+	    status: string;
+	    fileCount: number;
+	    hunkStates?: Record<string, string>;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    endedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionTurn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sessionId = source["sessionId"];
+	        this.index = source["index"];
+	        this.root = source["root"];
+	        this.prompt = source["prompt"];
+	        this.baseCommit = source["baseCommit"];
+	        this.afterCommit = source["afterCommit"];
+	        this.status = source["status"];
+	        this.fileCount = source["fileCount"];
+	        this.hunkStates = source["hunkStates"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.endedAt = this.convertValues(source["endedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SkillFileDiff {
+	    path: string;
+	    status: string;
+	    diff?: string;
+	    binary: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillFileDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.status = source["status"];
+	        this.diff = source["diff"];
+	        this.binary = source["binary"];
+	    }
+	}
+	export class SkillIssue {
+	    severity: string;
+	    field?: string;
+	    line?: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.severity = source["severity"];
+	        this.field = source["field"];
+	        this.line = source["line"];
+	        this.message = source["message"];
+	    }
+	}
+	export class SkillInfo {
+	    name: string;
+	    description: string;
+	    version?: string;
+	    author?: string;
+	    path: string;
+	    source: string;
+	    content?: string;
+	    files: string[];
+	    enabled: boolean;
+	    activation?: string;
+	    issues: SkillIssue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.version = source["version"];
+	        this.author = source["author"];
+	        this.path = source["path"];
+	        this.source = source["source"];
+	        this.content = source["content"];
+	        this.files = source["files"];
+	        this.enabled = source["enabled"];
+	        this.activation = source["activation"];
+	        this.issues = this.convertValues(source["issues"], SkillIssue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SkillImportPreview {
+	    name: string;
+	    version: string;
+	    author: string;
+	    description: string;
+	    files: string[];
+	    hasManifest: boolean;
+	    warnings: string[];
+	    installed?: SkillInfo;
+	    downgrade: boolean;
+	    changes: SkillFileDiff[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.author = source["author"];
+	        this.description = source["description"];
+	        this.files = source["files"];
+	        this.hasManifest = source["hasManifest"];
+	        this.warnings = source["warnings"];
+	        this.installed = this.convertValues(source["installed"], SkillInfo);
+	        this.downgrade = source["downgrade"];
+	        this.changes = this.convertValues(source["changes"], SkillFileDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SkillTemplateVariable {
+	    name: string;
+	    label: string;
+	    description: string;
+	    default: string;
+	    required: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillTemplateVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.required = source["required"];
+	    }
+	}
+	export class SkillTemplate {
+	    id: string;
+	    name: string;
+	    description: string;
+	    content: string;
+	    category: string;
+	    source: string;
+	    path?: string;
+	    variables: SkillTemplateVariable[];
+	    files: string[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkillTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.content = source["content"];
+	        this.category = source["category"];
+	        this.source = source["source"];
+	        this.path = source["path"];
+	        this.variables = this.convertValues(source["variables"], SkillTemplateVariable);
+	        this.files = source["files"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class Tag {
+	    name: string;
+	    color: string;
+	    description?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.color = source["color"];
+	        this.description = source["description"];
+	    }
+	}
+	export class Task {
+	    id: string;
+	    label: string;
+	    source: string;
+	    command: string;
+	    args?: string[];
+	    cwd?: string;
+	    env?: Record<string, string>;
+	    problemMatcher?: string;
+	    commandLine: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Task(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.source = source["source"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	        this.cwd = source["cwd"];
+	        this.env = source["env"];
+	        this.problemMatcher = source["problemMatcher"];
+	        this.commandLine = source["commandLine"];
+	    }
+	}
+	export class TaskDiagnostic {
+	    file: string;
+	    line: number;
+	    column?: number;
+	    severity: string;
+	    message: string;
+	    code?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.code = source["code"];
+	    }
+	}
+	export class TaskRun {
+	    id: string;
+	    task: Task;
+	    status: string;
+	    exitCode: number;
+	    output: string;
+	    diagnostics: TaskDiagnostic[];
+	    error?: string;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    finishedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.task = this.convertValues(source["task"], Task);
+	        this.status = source["status"];
+	        this.exitCode = source["exitCode"];
+	        this.output = source["output"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], TaskDiagnostic);
+	        this.error = source["error"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TokenInfo {
+	    access_token: string;
+	    refresh_token: string;
+	    // Go type: time
+	    expires_at: any;
+	    token_type: string;
+	    profile_arn?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.access_token = source["access_token"];
+	        this.refresh_token = source["refresh_token"];
+	        this.expires_at = this.convertValues(source["expires_at"], null);
+	        this.token_type = source["token_type"];
+	        this.profile_arn = source["profile_arn"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrashItem {
+	    id: string;
+	    name: string;
+	    originalPath: string;
+	    trashPath: string;
+	    infoPath?: string;
+	    // Go type: time
+	    deletedAt: any;
+	    isDir: boolean;
+	    size: number;
+	    system: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.originalPath = source["originalPath"];
+	        this.trashPath = source["trashPath"];
+	        this.infoPath = source["infoPath"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	        this.isDir = source["isDir"];
+	        this.size = source["size"];
+	        this.system = source["system"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrashSettings {
+	    useSystemTrash: boolean;
+	    retentionDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.useSystemTrash = source["useSystemTrash"];
+	        this.retentionDays = source["retentionDays"];
+	    }
+	}
+	export class TurnHunk {
+	    oldStart: number;
+	    oldLines: number;
+	    newStart: number;
+	    newLines: number;
+	    lines: string[];
+	    key: string;
+	    state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TurnHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oldStart = source["oldStart"];
+	        this.oldLines = source["oldLines"];
+	        this.newStart = source["newStart"];
+	        this.newLines = source["newLines"];
+	        this.lines = source["lines"];
+	        this.key = source["key"];
+	        this.state = source["state"];
+	    }
+	}
+	export class TurnFileChange {
+	    path: string;
+	    oldPath?: string;
+	    status: string;
+	    binary: boolean;
+	    hunks: TurnHunk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TurnFileChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.oldPath = source["oldPath"];
+	        this.status = source["status"];
+	        this.binary = source["binary"];
+	        this.hunks = this.convertValues(source["hunks"], TurnHunk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TurnChanges {
+	    turn: SessionTurn;
+	    files: TurnFileChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TurnChanges(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.turn = this.convertValues(source["turn"], SessionTurn);
+	        this.files = this.convertValues(source["files"], TurnFileChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class UIUXProMaxStatus {
+	    installed: boolean;
+	    version: string;
+	    latestVersion: string;
+	    updateAvailable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UIUXProMaxStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.installed = source["installed"];
+	        this.version = source["version"];
+	        this.latestVersion = source["latestVersion"];
+	        this.updateAvailable = source["updateAvailable"];
+	    }
+	}
+	export class WorktreeSession {
+	    sessionId: string;
+	    title: string;
+	    repoRoot: string;
+	    path: string;
+	    branch: string;
+	    baseBranch: string;
+	    baseCommit: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.title = source["title"];
+	        this.repoRoot = source["repoRoot"];
+	        this.path = source["path"];
+	        this.branch = source["branch"];
+	        this.baseBranch = source["baseBranch"];
+	        this.baseCommit = source["baseCommit"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorktreeDiff {
+	    session: WorktreeSession;
+	    mergeBase: string;
+	    ahead: number;
+	    behind: number;
+	    dirty: boolean;
+	    files: GitFileDiff[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = this.convertValues(source["session"], WorktreeSession);
+	        this.mergeBase = source["mergeBase"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.dirty = source["dirty"];
+	        this.files = this.convertValues(source["files"], GitFileDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorktreeMergeOptions {
+	    message: string;
+	    squash: boolean;
+	    cleanup: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeMergeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.message = source["message"];
+	        this.squash = source["squash"];
+	        this.cleanup = source["cleanup"];
+	    }
+	}
+	export class WorktreeMergeResult {
+	    merged: boolean;
+	    commit?: string;
+	    conflicts: string[];
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeMergeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.merged = source["merged"];
+	        this.commit = source["commit"];
+	        this.conflicts = source["conflicts"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	export class WriteFileOptions {
+	    encoding?: string;
+	    lineEnding?: string;
+	    expectedHash?: string;
+	    force?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WriteFileOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.encoding = source["encoding"];
+	        this.lineEnding = source["lineEnding"];
+	        this.expectedHash = source["expectedHash"];
+	        this.force = source["force"];
 	    }
 	}
 
@@ -1,23 +1,82 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
 // GitStatus Git 状态
 type GitStatus struct {
-	Branch  string      `json:"branch"`
-	Changes []GitChange `json:"changes"`
-	HasRepo bool        `json:"hasRepo"`
+	Branch   string      `json:"branch"`
+	Changes  []GitChange `json:"changes"`
+	HasRepo  bool        `json:"hasRepo"`
+	Root     string      `json:"root"`     // 仓库根目录，变更路径均相对于它
+	Head     string      `json:"head"`     // HEAD 提交，新仓库为空
+	Detached bool        `json:"detached"` // HEAD 是否处于分离状态
+	Upstream string      `json:"upstream"`
+	Ahead    int         `json:"ahead"`
+	Behind   int         `json:"behind"`
+	// 进行中的操作：merging、rebasing、cherry-picking、reverting
+	MergeState string `json:"mergeState,omitempty"`
 }
 
-// GitChange Git 变更
+// GitChange Git 变更（同一文件同时有已暂存和未暂存的修改时分为两条）
 type GitChange struct {
-	Path   string `json:"path"`
-	Status string `json:"status"` // M=modified, A=added, D=deleted, ??=untracked
-	Staged bool   `json:"staged"`
+	Path     string `json:"path"`
+	OrigPath string `json:"origPath,omitempty"` // 重命名/复制前的路径
+	Status   string `json:"status"`             // M=modified, A=added, D=deleted, R=renamed, C=copied, T=type changed, U=conflict, ??=untracked
+	Staged   bool   `json:"staged"`
+	Conflict string `json:"conflict,omitempty"` // 冲突类型 XY：UU、AA、DD、AU、UA、DU、UD
+}
+
+// runGit 在 dir 中执行 git 命令，失败时错误中包含 git 的输出
+func runGit(dir string, args ...string) (string, error) {
+	return runGitInput(dir, "", args...)
+}
+
+// runGitInput 执行 git 命令并通过 stdin 传入内容
+func runGitInput(dir, input string, args ...string) (string, error) {
+	return runGitCmd(dir, input, nil, args...)
+}
+
+// runGitEnv 执行 git 命令并附加环境变量（如 GIT_INDEX_FILE）
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	return runGitCmd(dir, "", env, args...)
+}
+
+// runGitCmd 执行 git 命令
+func runGitCmd(dir, input string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return stdout.String(), fmt.Errorf("git %s 失败: %v: %s", args[0], err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// gitTopLevel 返回仓库根目录
+func gitTopLevel(dir string) (string, error) {
+	out, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("不是 Git 仓库: %s", dir)
+	}
+	return strings.TrimSpace(out), nil
 }
 
 // GetGitStatus 获取 Git 状态
@@ -27,74 +86,126 @@ func (a *App) GetGitStatus(dir string) (*GitStatus, error) {
 	}
 
 	// 检查是否是 Git 仓库
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = dir
-	if err := cmd.Run(); err != nil {
+	root, err := gitTopLevel(dir)
+	if err != nil {
 		return &GitStatus{HasRepo: false}, nil
 	}
 
-	status := &GitStatus{HasRepo: true, Changes: []GitChange{}}
-
-	// 获取当前分支
-	cmd = exec.Command("git", "branch", "--show-current")
-	cmd.Dir = dir
-	if output, err := cmd.Output(); err == nil {
-		status.Branch = strings.TrimSpace(string(output))
-	}
-
-	// 获取状态
-	cmd = exec.Command("git", "status", "--porcelain")
-	cmd.Dir = dir
-	output, err := cmd.Output()
+	output, err := runGit(root, "status", "--porcelain=v2", "-z", "--branch", "--untracked-files=all")
 	if err != nil {
-		return status, err
+		return &GitStatus{HasRepo: true, Root: root, Changes: []GitChange{}}, err
 	}
+	status := parseGitStatusV2(output)
+	status.Root = root
+	status.MergeState = gitMergeState(root)
+	return status, nil
+}
+
+// parseGitStatusV2 解析 git status --porcelain=v2 -z --branch 的输出
+func parseGitStatusV2(output string) *GitStatus {
+	status := &GitStatus{HasRepo: true, Changes: []GitChange{}}
+	records := strings.Split(output, "\x00")
 
-	// 解析状态
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if len(line) < 4 {
+	for i := 0; i < len(records); i++ {
+		rec := records[i]
+		if rec == "" {
 			continue
 		}
+		switch rec[0] {
+		case '#':
+			parseGitBranchHeader(status, rec)
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(rec, " ", 9)
+			if len(fields) == 9 {
+				status.Changes = append(status.Changes, gitChangesFromXY(fields[1], fields[8], "")...)
+			}
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>\0<origPath>
+			fields := strings.SplitN(rec, " ", 10)
+			orig := ""
+			if i+1 < len(records) {
+				i++
+				orig = records[i]
+			}
+			if len(fields) == 10 {
+				status.Changes = append(status.Changes, gitChangesFromXY(fields[1], fields[9], orig)...)
+			}
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(rec, " ", 11)
+			if len(fields) == 11 {
+				status.Changes = append(status.Changes, GitChange{Path: fields[10], Status: "U", Conflict: fields[1]})
+			}
+		case '?':
+			status.Changes = append(status.Changes, GitChange{Path: rec[2:], Status: "??"})
+		}
+	}
+	return status
+}
 
-		statusCode := line[0:2]
-		path := strings.TrimSpace(line[3:])
-
-		change := GitChange{Path: path}
-
-		// 解析状态码
-		switch {
-		case strings.HasPrefix(statusCode, "M"):
-			change.Status = "M"
-			change.Staged = statusCode[0] == 'M'
-		case strings.HasPrefix(statusCode, "A"):
-			change.Status = "A"
-			change.Staged = true
-		case strings.HasPrefix(statusCode, "D"):
-			change.Status = "D"
-			change.Staged = statusCode[0] == 'D'
-		case strings.HasPrefix(statusCode, "??"):
-			change.Status = "??"
-			change.Staged = false
-		case strings.HasPrefix(statusCode, "R"):
-			change.Status = "R"
-			change.Staged = true
-		default:
-			change.Status = "M"
-			change.Staged = false
+// parseGitBranchHeader 解析 "# branch.*" 头
+func parseGitBranchHeader(status *GitStatus, rec string) {
+	fields := strings.Fields(rec)
+	if len(fields) < 3 {
+		return
+	}
+	switch fields[1] {
+	case "branch.oid":
+		if fields[2] != "(initial)" {
+			status.Head = fields[2]
 		}
+	case "branch.head":
+		if fields[2] == "(detached)" {
+			status.Detached = true
+		} else {
+			status.Branch = fields[2]
+		}
+	case "branch.upstream":
+		status.Upstream = fields[2]
+	case "branch.ab":
+		if len(fields) >= 4 {
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	}
+}
 
-		status.Changes = append(status.Changes, change)
+// gitChangesFromXY 根据 XY 状态生成暂存区和工作区的变更
+func gitChangesFromXY(xy, path, orig string) []GitChange {
+	var changes []GitChange
+	if len(xy) != 2 {
+		return changes
 	}
+	if x := xy[0]; x != '.' {
+		c := GitChange{Path: path, Status: string(x), Staged: true}
+		if x == 'R' || x == 'C' {
+			c.OrigPath = orig
+		}
+		changes = append(changes, c)
+	}
+	if y := xy[1]; y != '.' {
+		c := GitChange{Path: path, Status: string(y)}
+		if y == 'R' || y == 'C' {
+			c.OrigPath = orig
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
 
-	return status, nil
+// gitRepoDir 返回仓库根目录（GitChange 的路径相对于它），不是仓库时返回 dir
+func gitRepoDir(dir string) string {
+	if root, err := gitTopLevel(dir); err == nil {
+		return root
+	}
+	return dir
 }
 
 // GitAdd 添加文件到暂存区
 func (a *App) GitAdd(dir, path string) error {
-	cmd := exec.Command("git", "add", path)
-	cmd.Dir = dir
-	return cmd.Run()
+	_, err := runGit(gitRepoDir(dir), "add", "--", path)
+	return err
 }
 
 // GitCommit 提交更改
@@ -108,31 +219,18 @@ func (a *App) GitCommit(dir, message string) error {
 	return nil
 }
 
-// GitPush 推送到远程
+// GitPush 推送到远程（等待后台操作完成，期间可通过凭据请求事件认证）
 func (a *App) GitPush(dir string) error {
-	cmd := exec.Command("git", "push")
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%v: %s", err, string(output))
-	}
-	return nil
+	return a.runGitJobSync(dir, "push")
 }
 
 // GitPull 从远程拉取
 func (a *App) GitPull(dir string) error {
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = dir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%v: %s", err, string(output))
-	}
-	return nil
+	return a.runGitJobSync(dir, "pull")
 }
 
 // GitDiscard 丢弃更改
 func (a *App) GitDiscard(dir, path string) error {
-	cmd := exec.Command("git", "checkout", "--", path)
-	cmd.Dir = dir
-	return cmd.Run()
+	_, err := runGit(gitRepoDir(dir), "checkout", "--", path)
+	return err
 }
@@ -0,0 +1,770 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitRemoteOptions 推送/拉取/获取的选项
+type GitRemoteOptions struct {
+	Remote      string `json:"remote"`      // 为空时使用默认远程
+	Branch      string `json:"branch"`      // 为空时使用当前分支的上游
+	SetUpstream bool   `json:"setUpstream"` // push -u
+	Force       bool   `json:"force"`       // push --force-with-lease
+	Tags        bool   `json:"tags"`
+	Rebase      *bool  `json:"rebase,omitempty"` // pull --rebase / --no-rebase，未设置时沿用 git 配置
+	Prune       bool   `json:"prune"`            // fetch --prune
+}
+
+// GitRefUpdate 推送结果中的单个引用
+type GitRefUpdate struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status string `json:"status"` // ok, forced, new, deleted, up-to-date, rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// GitJobResult 网络操作的结构化结果
+type GitJobResult struct {
+	UpdatedRefs  []GitRefUpdate `json:"updatedRefs"`
+	RejectedRefs []GitRefUpdate `json:"rejectedRefs"`
+	Conflicts    []string       `json:"conflicts"`
+	UpToDate     bool           `json:"upToDate"`
+	AuthFailed   bool           `json:"authFailed"`
+}
+
+// GitJob 后台 Git 网络操作
+type GitJob struct {
+	ID         string        `json:"id"`
+	Dir        string        `json:"dir"`
+	Operation  string        `json:"operation"` // push, pull, fetch
+	Args       []string      `json:"args"`
+	Status     string        `json:"status"` // running, success, failed, cancelled
+	Phase      string        `json:"phase"`
+	Percent    int           `json:"percent"`
+	Output     string        `json:"output"`
+	Result     *GitJobResult `json:"result,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"`
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// GitCredentialPrompt 需要用户输入的凭据/口令请求
+type GitCredentialPrompt struct {
+	ID     string `json:"id"`
+	JobID  string `json:"jobId"`
+	Prompt string `json:"prompt"`
+	Secret bool   `json:"secret"` // 密码、口令，界面需隐藏输入
+	Kind   string `json:"kind"`   // username, password, passphrase, confirm
+}
+
+// gitPromptAnswer 用户对凭据请求的回复
+type gitPromptAnswer struct {
+	Answer    string `json:"answer"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+// pendingGitPrompt 等待回复的凭据请求
+type pendingGitPrompt struct {
+	prompt GitCredentialPrompt
+	answer chan gitPromptAnswer
+}
+
+// askpass 辅助程序使用的环境变量：GIT_ASKPASS 指向本程序，通过这些变量回连应用
+const (
+	askpassURLEnv   = "OPENCODE_DESKTOP_ASKPASS_URL"
+	askpassTokenEnv = "OPENCODE_DESKTOP_ASKPASS_TOKEN"
+	askpassJobEnv   = "OPENCODE_DESKTOP_ASKPASS_JOB"
+)
+
+// gitPromptTimeout 等待用户输入凭据的最长时间
+const gitPromptTimeout = 5 * time.Minute
+
+// maxGitJobs 保留的历史操作数
+const maxGitJobs = 30
+
+// GitJobManager Git 后台操作管理器
+type GitJobManager struct {
+	app     *App
+	jobs    map[string]*GitJob
+	order   []string
+	nextID  int
+	prompts map[string]*pendingGitPrompt
+
+	askpassURL   string
+	askpassToken string
+	askpassSrv   *http.Server
+
+	mu sync.Mutex
+}
+
+// NewGitJobManager 创建 Git 后台操作管理器
+func NewGitJobManager(app *App) *GitJobManager {
+	return &GitJobManager{
+		app:     app,
+		jobs:    make(map[string]*GitJob),
+		prompts: make(map[string]*pendingGitPrompt),
+	}
+}
+
+// Start 在后台执行 git 命令，立即返回操作 ID
+func (gm *GitJobManager) Start(dir, operation string, args []string) (string, error) {
+	gm.mu.Lock()
+	if err := gm.ensureAskpassServerLocked(); err != nil {
+		gm.mu.Unlock()
+		return "", err
+	}
+	gm.nextID++
+	jobID := fmt.Sprintf("git-%d", gm.nextID)
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &GitJob{
+		ID:        jobID,
+		Dir:       dir,
+		Operation: operation,
+		Args:      args,
+		Status:    "running",
+		StartedAt: time.Now(),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	gm.jobs[jobID] = job
+	gm.order = append(gm.order, jobID)
+	gm.pruneLocked()
+	gm.mu.Unlock()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), gm.askpassEnv(jobID)...)
+	cmd.WaitDelay = 2 * time.Second
+
+	var stdout bytes.Buffer
+	pr, pw := io.Pipe()
+	cmd.Stdout = &stdout
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		gm.finish(job, "", fmt.Errorf("启动 git 失败: %v", err))
+		return jobID, nil
+	}
+	gm.app.emitEvent("git-job-started", job.snapshot())
+
+	streamed := make(chan struct{})
+	go func() {
+		defer close(streamed)
+		gm.streamProgress(job, pr)
+	}()
+
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		<-streamed
+		gm.finish(job, stdout.String(), err)
+	}()
+
+	return jobID, nil
+}
+
+// streamProgress 读取 git 的 stderr，进度行以 \r 分隔
+func (gm *GitJobManager) streamProgress(job *GitJob, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " ")
+		if line == "" {
+			continue
+		}
+		if phase, percent, ok := parseGitProgress(line); ok {
+			gm.mu.Lock()
+			changed := job.Phase != phase || job.Percent != percent
+			job.Phase, job.Percent = phase, percent
+			gm.mu.Unlock()
+			if changed {
+				gm.app.emitEvent("git-job-progress", map[string]interface{}{"jobId": job.ID, "phase": phase, "percent": percent})
+			}
+			if percent < 100 {
+				continue
+			}
+		}
+		gm.mu.Lock()
+		if len(job.Output) < maxTaskOutput {
+			job.Output += line + "\n"
+		}
+		gm.mu.Unlock()
+		gm.app.emitEvent("git-job-output", map[string]interface{}{"jobId": job.ID, "line": line})
+	}
+	io.Copy(io.Discard, r)
+}
+
+// scanProgressLines 按 \n 或 \r 分行
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+var gitProgressRe = regexp.MustCompile(`^(?:remote: )?([A-Za-z][A-Za-z ]*[a-z]):\s+(\d+)%`)
+
+// parseGitProgress 解析 "Receiving objects:  45% (9/20)" 形式的进度行
+func parseGitProgress(line string) (string, int, bool) {
+	m := gitProgressRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", 0, false
+	}
+	return m[1], atoiOrZero(m[2]), true
+}
+
+// finish 记录操作结束状态并解析结构化结果
+func (gm *GitJobManager) finish(job *GitJob, stdout string, err error) {
+	gm.mu.Lock()
+	output := job.Output
+	gm.mu.Unlock()
+
+	result := &GitJobResult{UpdatedRefs: []GitRefUpdate{}, RejectedRefs: []GitRefUpdate{}, Conflicts: []string{}}
+	if job.Operation == "push" {
+		for _, u := range parsePushPorcelain(stdout) {
+			if u.Status == "rejected" {
+				result.RejectedRefs = append(result.RejectedRefs, u)
+			} else {
+				result.UpdatedRefs = append(result.UpdatedRefs, u)
+			}
+		}
+	}
+	combined := stdout + "\n" + output
+	result.Conflicts = parseGitConflicts(combined)
+	if job.Operation == "pull" && err != nil {
+		// 冲突以索引为准，输出中的 CONFLICT 行可能不完整
+		if out, uErr := runGit(job.Dir, "-c", "core.quotePath=false", "diff", "--name-only", "--diff-filter=U"); uErr == nil && strings.TrimSpace(out) != "" {
+			result.Conflicts = strings.Split(strings.TrimSpace(out), "\n")
+		}
+	}
+	result.UpToDate = strings.Contains(combined, "Already up to date") || strings.Contains(combined, "Everything up-to-date")
+	result.AuthFailed = isGitAuthFailure(combined)
+
+	gm.mu.Lock()
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Result = result
+	job.Status = "success"
+	if err != nil {
+		job.Status = "failed"
+		job.Error = gitJobError(err, output)
+	}
+	if job.ctx.Err() != nil {
+		job.Status = "cancelled"
+		job.Error = ""
+	}
+	if stdout != "" && len(job.Output) < maxTaskOutput {
+		job.Output += stdout
+	}
+	job.cancel()
+	snapshot := job.snapshot()
+	if gm.runningLocked() == 0 {
+		// 没有进行中的操作时关闭凭据服务，下次启动操作时重新监听
+		gm.closeAskpassServerLocked()
+	}
+	gm.mu.Unlock()
+
+	close(job.done)
+	gm.app.emitEvent("git-job-finished", snapshot)
+}
+
+// gitJobError 取 stderr 中的错误行作为错误信息
+func gitJobError(err error, output string) string {
+	var lines []string
+	for _, l := range strings.Split(output, "\n") {
+		if strings.HasPrefix(l, "fatal: ") || strings.HasPrefix(l, "error: ") || strings.HasPrefix(l, " ! ") {
+			lines = append(lines, strings.TrimSpace(l))
+		}
+	}
+	if len(lines) == 0 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Sprintf("git 退出码 %d", exitErr.ExitCode())
+		}
+		return err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parsePushPorcelain 解析 git push --porcelain 的输出
+func parsePushPorcelain(output string) []GitRefUpdate {
+	updates := []GitRefUpdate{}
+	for _, line := range strings.Split(output, "\n") {
+		// <flag>\t<from>:<to>\t<summary> (<reason>)
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 || len(fields[0]) != 1 {
+			continue
+		}
+		from, to, _ := strings.Cut(fields[1], ":")
+		u := GitRefUpdate{From: from, To: to}
+		switch fields[0] {
+		case " ":
+			u.Status = "ok"
+		case "+":
+			u.Status = "forced"
+		case "-":
+			u.Status = "deleted"
+		case "*":
+			u.Status = "new"
+		case "=":
+			u.Status = "up-to-date"
+		case "!":
+			u.Status = "rejected"
+		default:
+			continue
+		}
+		if i := strings.Index(fields[2], "("); i >= 0 && strings.HasSuffix(fields[2], ")") {
+			u.Reason = fields[2][i+1 : len(fields[2])-1]
+		}
+		updates = append(updates, u)
+	}
+	return updates
+}
+
+var gitConflictRe = regexp.MustCompile(`^CONFLICT \([^)]*\): .*?(?:Merge conflict in |deleted in \S+ and modified in \S+\. Version \S+ of )(.+?)(?: left in tree\.)?$`)
+
+// parseGitConflicts 从 merge/rebase 输出中提取冲突文件
+func parseGitConflicts(output string) []string {
+	conflicts := []string{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		m := gitConflictRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		conflicts = append(conflicts, m[1])
+	}
+	return conflicts
+}
+
+// isGitAuthFailure 判断是否为认证失败
+func isGitAuthFailure(output string) bool {
+	for _, s := range []string{"Authentication failed", "could not read Username", "could not read Password", "Permission denied (publickey", "Host key verification failed", "403 Forbidden"} {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancel 取消正在运行的操作，同时取消其等待中的凭据请求
+func (gm *GitJobManager) Cancel(jobID string) error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	job, ok := gm.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("Git 操作不存在: %s", jobID)
+	}
+	if job.Status == "running" {
+		job.cancel()
+	}
+	return nil
+}
+
+// Wait 等待操作结束
+func (gm *GitJobManager) Wait(jobID string) (*GitJob, error) {
+	gm.mu.Lock()
+	job, ok := gm.jobs[jobID]
+	gm.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("Git 操作不存在: %s", jobID)
+	}
+	<-job.done
+	return gm.Get(jobID)
+}
+
+// Get 获取操作记录
+func (gm *GitJobManager) Get(jobID string) (*GitJob, error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	job, ok := gm.jobs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("Git 操作不存在: %s", jobID)
+	}
+	snapshot := job.snapshot()
+	return &snapshot, nil
+}
+
+// List 按时间倒序列出操作记录（不含输出）
+func (gm *GitJobManager) List() []GitJob {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	jobs := make([]GitJob, 0, len(gm.order))
+	for i := len(gm.order) - 1; i >= 0; i-- {
+		snapshot := gm.jobs[gm.order[i]].snapshot()
+		snapshot.Output = ""
+		jobs = append(jobs, snapshot)
+	}
+	return jobs
+}
+
+// pruneLocked 删除超出上限的已结束记录（调用方需持有锁）
+func (gm *GitJobManager) pruneLocked() {
+	for len(gm.order) > maxGitJobs {
+		oldest := gm.order[0]
+		if gm.jobs[oldest].Status == "running" {
+			break
+		}
+		delete(gm.jobs, oldest)
+		gm.order = gm.order[1:]
+	}
+}
+
+// snapshot 复制操作记录（不含内部字段）
+func (j *GitJob) snapshot() GitJob {
+	s := *j
+	s.ctx, s.cancel, s.done = nil, nil, nil
+	s.Args = append([]string{}, j.Args...)
+	return s
+}
+
+// --- 凭据请求 ---
+
+// askpassEnv 为 git 子进程设置 askpass 辅助程序
+func (gm *GitJobManager) askpassEnv(jobID string) []string {
+	env := []string{
+		"GIT_TERMINAL_PROMPT=0",
+		askpassURLEnv + "=" + gm.askpassURL,
+		askpassTokenEnv + "=" + gm.askpassToken,
+		askpassJobEnv + "=" + jobID,
+	}
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, "GIT_ASKPASS="+exe, "SSH_ASKPASS="+exe, "SSH_ASKPASS_REQUIRE=force")
+	}
+	return env
+}
+
+// ensureAskpassServer 启动仅监听本机的凭据请求服务
+func (gm *GitJobManager) ensureAskpassServer() error {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	return gm.ensureAskpassServerLocked()
+}
+
+// ensureAskpassServerLocked 启动凭据请求服务（调用方需持有锁）
+func (gm *GitJobManager) ensureAskpassServerLocked() error {
+	if gm.askpassSrv != nil {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("启动凭据服务失败: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/askpass", gm.handleAskpass)
+	gm.askpassSrv = &http.Server{Handler: mux}
+	gm.askpassURL = "http://" + ln.Addr().String() + "/askpass"
+	gm.askpassToken = generateToken()
+	go gm.askpassSrv.Serve(ln)
+	return nil
+}
+
+// closeAskpassServerLocked 关闭凭据请求服务（调用方需持有锁）
+func (gm *GitJobManager) closeAskpassServerLocked() {
+	if gm.askpassSrv == nil {
+		return
+	}
+	gm.askpassSrv.Close()
+	gm.askpassSrv = nil
+	gm.askpassURL, gm.askpassToken = "", ""
+}
+
+// runningLocked 统计进行中的操作数（调用方需持有锁）
+func (gm *GitJobManager) runningLocked() int {
+	n := 0
+	for _, job := range gm.jobs {
+		if job.Status == "running" {
+			n++
+		}
+	}
+	return n
+}
+
+// StopAll 取消所有进行中的操作并关闭凭据服务（应用退出时调用）
+func (gm *GitJobManager) StopAll() {
+	gm.mu.Lock()
+	for _, job := range gm.jobs {
+		if job.Status == "running" {
+			job.cancel()
+		}
+	}
+	gm.closeAskpassServerLocked()
+	gm.mu.Unlock()
+}
+
+// handleAskpass 处理 askpass 辅助程序的请求，阻塞直到用户回复
+func (gm *GitJobManager) handleAskpass(w http.ResponseWriter, r *http.Request) {
+	gm.mu.Lock()
+	token := gm.askpassToken
+	gm.mu.Unlock()
+	if r.Method != http.MethodPost || token == "" || r.Header.Get("X-Askpass-Token") != token {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	var req struct {
+		JobID  string `json:"jobId"`
+		Prompt string `json:"prompt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	gm.mu.Lock()
+	job, ok := gm.jobs[req.JobID]
+	if !ok || job.Status != "running" {
+		gm.mu.Unlock()
+		http.Error(w, "job not running", http.StatusGone)
+		return
+	}
+	gm.nextID++
+	kind, secret := classifyGitPrompt(req.Prompt)
+	pending := &pendingGitPrompt{
+		prompt: GitCredentialPrompt{
+			ID:     fmt.Sprintf("prompt-%d", gm.nextID),
+			JobID:  req.JobID,
+			Prompt: req.Prompt,
+			Secret: secret,
+			Kind:   kind,
+		},
+		answer: make(chan gitPromptAnswer, 1),
+	}
+	gm.prompts[pending.prompt.ID] = pending
+	ctx := job.ctx
+	gm.mu.Unlock()
+
+	gm.app.emitEvent("git-credential-prompt", pending.prompt)
+
+	answer := gitPromptAnswer{Cancelled: true}
+	select {
+	case answer = <-pending.answer:
+	case <-ctx.Done():
+	case <-r.Context().Done():
+	case <-time.After(gitPromptTimeout):
+	}
+
+	gm.mu.Lock()
+	delete(gm.prompts, pending.prompt.ID)
+	gm.mu.Unlock()
+	gm.app.emitEvent("git-credential-prompt-closed", map[string]string{"id": pending.prompt.ID, "jobId": req.JobID})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(answer)
+}
+
+// classifyGitPrompt 根据提示文本判断请求类型
+func classifyGitPrompt(prompt string) (string, bool) {
+	lower := strings.ToLower(prompt)
+	switch {
+	case strings.Contains(lower, "passphrase"):
+		return "passphrase", true
+	case strings.Contains(lower, "password"), strings.Contains(lower, "token"):
+		return "password", true
+	case strings.Contains(lower, "username"):
+		return "username", false
+	case strings.Contains(lower, "(yes/no"):
+		return "confirm", false
+	}
+	return "password", true
+}
+
+// Answer 回复凭据请求，cancel 为 true 时放弃认证
+func (gm *GitJobManager) Answer(promptID, answer string, cancel bool) error {
+	gm.mu.Lock()
+	pending, ok := gm.prompts[promptID]
+	gm.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("凭据请求不存在: %s", promptID)
+	}
+	select {
+	case pending.answer <- gitPromptAnswer{Answer: answer, Cancelled: cancel}:
+	default:
+	}
+	return nil
+}
+
+// PendingPrompts 列出等待回复的凭据请求（界面刷新后恢复）
+func (gm *GitJobManager) PendingPrompts() []GitCredentialPrompt {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	prompts := []GitCredentialPrompt{}
+	for _, p := range gm.prompts {
+		prompts = append(prompts, p.prompt)
+	}
+	return prompts
+}
+
+// runGitAskpass 以 askpass 辅助程序模式运行时向应用请求凭据并输出，返回 true 表示已处理
+func runGitAskpass() bool {
+	url := os.Getenv(askpassURLEnv)
+	if url == "" {
+		return false
+	}
+	prompt := strings.Join(os.Args[1:], " ")
+	body, _ := json.Marshal(map[string]string{"jobId": os.Getenv(askpassJobEnv), "prompt": prompt})
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		os.Exit(1)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Askpass-Token", os.Getenv(askpassTokenEnv))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "askpass: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	var answer gitPromptAnswer
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&answer) != nil || answer.Cancelled {
+		os.Exit(1)
+	}
+	fmt.Println(answer.Answer)
+	return true
+}
+
+// --- App API ---
+
+// gitRemoteArgs 生成推送/拉取/获取的参数
+func gitRemoteArgs(operation string, opts GitRemoteOptions) []string {
+	args := []string{operation, "--progress"}
+	switch operation {
+	case "push":
+		args = append(args, "--porcelain")
+		if opts.SetUpstream {
+			args = append(args, "--set-upstream")
+		}
+		if opts.Force {
+			args = append(args, "--force-with-lease")
+		}
+		if opts.Tags {
+			args = append(args, "--follow-tags")
+		}
+	case "pull":
+		if opts.Rebase != nil {
+			if *opts.Rebase {
+				args = append(args, "--rebase")
+			} else {
+				args = append(args, "--no-rebase")
+			}
+		}
+		if opts.Tags {
+			args = append(args, "--tags")
+		}
+	case "fetch":
+		if opts.Prune {
+			args = append(args, "--prune")
+		}
+		if opts.Tags {
+			args = append(args, "--tags")
+		}
+		if opts.Remote == "" {
+			args = append(args, "--all")
+		}
+	}
+	if opts.Remote != "" {
+		args = append(args, opts.Remote)
+		if opts.Branch != "" {
+			args = append(args, opts.Branch)
+		}
+	}
+	return args
+}
+
+// GitPushAsync 在后台推送，返回操作 ID
+func (a *App) GitPushAsync(dir string, opts GitRemoteOptions) (string, error) {
+	return a.gitJobMgr.Start(dir, "push", gitRemoteArgs("push", opts))
+}
+
+// GitPullAsync 在后台拉取，返回操作 ID
+func (a *App) GitPullAsync(dir string, opts GitRemoteOptions) (string, error) {
+	return a.gitJobMgr.Start(dir, "pull", gitRemoteArgs("pull", opts))
+}
+
+// GitFetchAsync 在后台获取，返回操作 ID
+func (a *App) GitFetchAsync(dir string, opts GitRemoteOptions) (string, error) {
+	return a.gitJobMgr.Start(dir, "fetch", gitRemoteArgs("fetch", opts))
+}
+
+// GitCancelJob 取消 Git 后台操作
+func (a *App) GitCancelJob(jobID string) error {
+	return a.gitJobMgr.Cancel(jobID)
+}
+
+// GetGitJob 获取 Git 后台操作
+func (a *App) GetGitJob(jobID string) (*GitJob, error) {
+	return a.gitJobMgr.Get(jobID)
+}
+
+// GetGitJobs 列出 Git 后台操作
+func (a *App) GetGitJobs() []GitJob {
+	return a.gitJobMgr.List()
+}
+
+// GitAnswerCredentialPrompt 回复凭据请求
+func (a *App) GitAnswerCredentialPrompt(promptID, answer string, cancel bool) error {
+	return a.gitJobMgr.Answer(promptID, answer, cancel)
+}
+
+// GetGitCredentialPrompts 获取等待回复的凭据请求
+func (a *App) GetGitCredentialPrompts() []GitCredentialPrompt {
+	return a.gitJobMgr.PendingPrompts()
+}
+
+// runGitJobSync 启动操作并等待结束，失败时返回包含结构化信息的错误
+func (a *App) runGitJobSync(dir, operation string) error {
+	jobID, err := a.gitJobMgr.Start(dir, operation, gitRemoteArgs(operation, GitRemoteOptions{}))
+	if err != nil {
+		return err
+	}
+	job, err := a.gitJobMgr.Wait(jobID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case job.Status == "cancelled":
+		return fmt.Errorf("操作已取消")
+	case job.Status == "success":
+		return nil
+	case len(job.Result.RejectedRefs) > 0:
+		var refs []string
+		for _, r := range job.Result.RejectedRefs {
+			refs = append(refs, fmt.Sprintf("%s (%s)", r.To, r.Reason))
+		}
+		return fmt.Errorf("推送被拒绝: %s", strings.Join(refs, ", "))
+	case len(job.Result.Conflicts) > 0:
+		return fmt.Errorf("合并冲突: %s", strings.Join(job.Result.Conflicts, ", "))
+	}
+	return fmt.Errorf("%s", job.Error)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseGitProgress tests progress line parsing
+func TestParseGitProgress(t *testing.T) {
+	tests := []struct {
+		line    string
+		phase   string
+		percent int
+		ok      bool
+	}{
+		{"Receiving objects:  45% (9/20), 1.2 MiB | 3 MiB/s", "Receiving objects", 45, true},
+		{"remote: Counting objects: 100% (20/20), done.", "Counting objects", 100, true},
+		{"Writing objects: 100% (3/3), 260 bytes | 260.00 KiB/s, done.", "Writing objects", 100, true},
+		{"To github.com:user/repo.git", "", 0, false},
+		{"fatal: Authentication failed", "", 0, false},
+	}
+	for _, tt := range tests {
+		phase, percent, ok := parseGitProgress(tt.line)
+		if phase != tt.phase || percent != tt.percent || ok != tt.ok {
+			t.Errorf("parseGitProgress(%q) = %q, %d, %v", tt.line, phase, percent, ok)
+		}
+	}
+}
+
+// TestParsePushPorcelain tests structured push results
+func TestParsePushPorcelain(t *testing.T) {
+	output := "To github.com:user/repo.git\n" +
+		"=\trefs/heads/dev:refs/heads/dev\t[up to date]\n" +
+		" \trefs/heads/main:refs/heads/main\t1111111..2222222\n" +
+		"*\trefs/heads/feature:refs/heads/feature\t[new branch]\n" +
+		"!\trefs/heads/old:refs/heads/old\t[rejected] (non-fast-forward)\n" +
+		"!\trefs/heads/prod:refs/heads/prod\t[remote rejected] (pre-receive hook declined)\n" +
+		"Done\n"
+
+	updates := parsePushPorcelain(output)
+	want := []GitRefUpdate{
+		{From: "refs/heads/dev", To: "refs/heads/dev", Status: "up-to-date"},
+		{From: "refs/heads/main", To: "refs/heads/main", Status: "ok"},
+		{From: "refs/heads/feature", To: "refs/heads/feature", Status: "new"},
+		{From: "refs/heads/old", To: "refs/heads/old", Status: "rejected", Reason: "non-fast-forward"},
+		{From: "refs/heads/prod", To: "refs/heads/prod", Status: "rejected", Reason: "pre-receive hook declined"},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("updates = %+v", updates)
+	}
+	for i, u := range updates {
+		if u != want[i] {
+			t.Errorf("updates[%d] = %+v, want %+v", i, u, want[i])
+		}
+	}
+}
+
+// TestParseGitConflicts tests conflict extraction from merge output
+func TestParseGitConflicts(t *testing.T) {
+	output := "Auto-merging a.go\n" +
+		"CONFLICT (content): Merge conflict in a.go\n" +
+		"CONFLICT (add/add): Merge conflict in dir/b c.txt\n" +
+		"CONFLICT (modify/delete): d.go deleted in HEAD and modified in origin/main. Version origin/main of d.go left in tree.\n" +
+		"Automatic merge failed; fix conflicts and then commit the result.\n"
+
+	got := parseGitConflicts(output)
+	want := []string{"a.go", "dir/b c.txt", "d.go"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("parseGitConflicts() = %v, want %v", got, want)
+	}
+}
+
+// TestGitJobPushRejected tests background push and pull jobs against a local remote
+func TestGitJobPushRejected(t *testing.T) {
+	remote := initTestRepo(t)
+	mustGit(t, remote, "config", "receive.denyCurrentBranch", "ignore")
+	writeTestFile(t, remote, "a.txt", "remote\n")
+	mustGit(t, remote, "add", ".")
+	mustGit(t, remote, "commit", "-q", "-m", "remote")
+
+	local := t.TempDir()
+	mustGit(t, local, "clone", "-q", remote, ".")
+	writeTestFile(t, remote, "a.txt", "remote 2\n")
+	mustGit(t, remote, "commit", "-q", "-am", "remote 2")
+	writeTestFile(t, local, "a.txt", "local\n")
+	mustGit(t, local, "commit", "-q", "-am", "local")
+
+	gm := NewGitJobManager(&App{})
+	jobID, err := gm.Start(local, "push", gitRemoteArgs("push", GitRemoteOptions{Remote: "origin", Branch: "main"}))
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	job, err := gm.Wait(jobID)
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if job.Status != "failed" || len(job.Result.RejectedRefs) != 1 || job.Result.RejectedRefs[0].To != "refs/heads/main" {
+		t.Errorf("job = %+v, result = %+v", job, job.Result)
+	}
+
+	noRebase := false
+	jobID, _ = gm.Start(local, "pull", gitRemoteArgs("pull", GitRemoteOptions{Rebase: &noRebase}))
+	job, _ = gm.Wait(jobID)
+	if job.Status != "failed" || len(job.Result.Conflicts) != 1 || job.Result.Conflicts[0] != "a.txt" {
+		t.Errorf("pull job = %+v, result = %+v", job, job.Result)
+	}
+	if jobs := gm.List(); len(jobs) != 2 || jobs[0].ID != jobID {
+		t.Errorf("List() = %+v", jobs)
+	}
+	if gm.askpassSrv != nil {
+		t.Error("Expected askpass server to be closed when no job is running")
+	}
+}
+
+// TestGitRemoteArgsRebase tests that pull only overrides pull.rebase when requested
+func TestGitRemoteArgsRebase(t *testing.T) {
+	rebase, noRebase := true, false
+	tests := []struct {
+		rebase   *bool
+		expected string
+	}{
+		{nil, "pull --progress"},
+		{&rebase, "pull --progress --rebase"},
+		{&noRebase, "pull --progress --no-rebase"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(gitRemoteArgs("pull", GitRemoteOptions{Rebase: tt.rebase}), " "); got != tt.expected {
+			t.Errorf("gitRemoteArgs() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+// TestGitAskpassRoundTrip tests that askpass requests reach the UI and return the answer
+func TestGitAskpassRoundTrip(t *testing.T) {
+	gm := NewGitJobManager(&App{})
+	if err := gm.ensureAskpassServer(); err != nil {
+		t.Fatalf("ensureAskpassServer() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gm.jobs["git-1"] = &GitJob{ID: "git-1", Status: "running", ctx: ctx, cancel: cancel, done: make(chan struct{})}
+
+	ask := func(token string) (int, gitPromptAnswer) {
+		body, _ := json.Marshal(map[string]string{"jobId": "git-1", "prompt": "Password for 'https://user@example.com': "})
+		req, _ := http.NewRequest(http.MethodPost, gm.askpassURL, bytes.NewReader(body))
+		req.Header.Set("X-Askpass-Token", token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("askpass request error = %v", err)
+			return 0, gitPromptAnswer{}
+		}
+		defer resp.Body.Close()
+		var answer gitPromptAnswer
+		json.NewDecoder(resp.Body).Decode(&answer)
+		return resp.StatusCode, answer
+	}
+
+	if code, _ := ask("wrong"); code != http.StatusForbidden {
+		t.Errorf("status with wrong token = %d", code)
+	}
+
+	result := make(chan gitPromptAnswer, 1)
+	go func() {
+		_, answer := ask(gm.askpassToken)
+		result <- answer
+	}()
+
+	var prompts []GitCredentialPrompt
+	for i := 0; i < 100 && len(prompts) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		prompts = gm.PendingPrompts()
+	}
+	if len(prompts) != 1 || prompts[0].Kind != "password" || !prompts[0].Secret {
+		t.Fatalf("PendingPrompts() = %+v", prompts)
+	}
+	if err := gm.Answer(prompts[0].ID, "s3cret", false); err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	if answer := <-result; answer.Cancelled || answer.Answer != "s3cret" {
+		t.Errorf("answer = %+v", answer)
+	}
+
+	// Cancelling the job ends the pending prompt as cancelled
+	go func() {
+		_, answer := ask(gm.askpassToken)
+		result <- answer
+	}()
+	for i := 0; i < 100 && len(gm.PendingPrompts()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	gm.Cancel("git-1")
+	if answer := <-result; !answer.Cancelled {
+		t.Errorf("answer after cancel = %+v", answer)
+	}
+}
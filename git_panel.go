@@ -0,0 +1,742 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitFileDiff 单个文件的 diff
+type GitFileDiff struct {
+	Path    string     `json:"path"`
+	OldPath string     `json:"oldPath,omitempty"`
+	Status  string     `json:"status"` // M、A、D、R
+	Binary  bool       `json:"binary"`
+	Hunks   []DiffHunk `json:"hunks"`
+
+	header []string // "diff --git" 到第一个 "@@" 之间的行，用于生成单个区块的补丁
+}
+
+// GitBranch 分支
+type GitBranch struct {
+	Name     string `json:"name"`
+	Ref      string `json:"ref"`
+	Current  bool   `json:"current"`
+	Remote   bool   `json:"remote"`
+	Upstream string `json:"upstream,omitempty"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Gone     bool   `json:"gone"` // 上游分支已被删除
+	Hash     string `json:"hash"`
+	Time     int64  `json:"time"`
+	Subject  string `json:"subject"`
+}
+
+// GitStash 储藏
+type GitStash struct {
+	Index   int    `json:"index"`
+	Ref     string `json:"ref"`
+	Message string `json:"message"`
+	Time    int64  `json:"time"`
+}
+
+// GitLogFile 提交中的文件变更
+type GitLogFile struct {
+	Path      string `json:"path"`
+	OldPath   string `json:"oldPath,omitempty"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary"`
+}
+
+// GitLogEntry 提交记录
+type GitLogEntry struct {
+	Hash      string       `json:"hash"`
+	ShortHash string       `json:"shortHash"`
+	Parents   []string     `json:"parents"`
+	Author    string       `json:"author"`
+	Email     string       `json:"email"`
+	Time      int64        `json:"time"`
+	Refs      string       `json:"refs"`
+	Subject   string       `json:"subject"`
+	Files     []GitLogFile `json:"files"`
+}
+
+// GitLogPage 分页的提交记录
+type GitLogPage struct {
+	Commits []GitLogEntry `json:"commits"`
+	Skip    int           `json:"skip"`
+	HasMore bool          `json:"hasMore"`
+}
+
+// GitBlameCommit blame 中引用的提交
+type GitBlameCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Email   string `json:"email"`
+	Time    int64  `json:"time"`
+	Summary string `json:"summary"`
+}
+
+// GitBlameLine 每一行对应的提交
+type GitBlameLine struct {
+	Line     int    `json:"line"`
+	OrigLine int    `json:"origLine"`
+	Hash     string `json:"hash"`
+}
+
+// GitBlame blame 结果
+type GitBlame struct {
+	Path    string                    `json:"path"`
+	Commits map[string]GitBlameCommit `json:"commits"`
+	Lines   []GitBlameLine            `json:"lines"`
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// gitRelPath 将路径转换为相对仓库根目录的路径
+func gitRelPath(root, path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// parseGitDiff 解析 git diff 输出
+func parseGitDiff(output string) []GitFileDiff {
+	var files []GitFileDiff
+	var cur *GitFileDiff
+	var hunk *DiffHunk
+
+	flush := func() {
+		if cur != nil {
+			files = append(files, *cur)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			cur = &GitFileDiff{Status: "M", Hunks: []DiffHunk{}, header: []string{line}}
+			hunk = nil
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+			cur.Hunks = append(cur.Hunks, DiffHunk{
+				OldStart: atoiOrZero(m[1]),
+				OldLines: atoiDefault(m[2], 1),
+				NewStart: atoiOrZero(m[3]),
+				NewLines: atoiDefault(m[4], 1),
+				Lines:    []string{},
+			})
+			hunk = &cur.Hunks[len(cur.Hunks)-1]
+			continue
+		}
+
+		if hunk != nil {
+			if line != "" && strings.ContainsRune(" +-\\", rune(line[0])) {
+				hunk.Lines = append(hunk.Lines, line)
+			}
+			continue
+		}
+
+		cur.header = append(cur.header, line)
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = "A"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = "D"
+		case strings.HasPrefix(line, "rename from "):
+			cur.Status = "R"
+			cur.OldPath = unquoteGitPath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = unquoteGitPath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			cur.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			if p := diffSidePath(line[4:]); p != "" && cur.OldPath == "" && cur.Status != "A" {
+				cur.OldPath = p
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if p := diffSidePath(line[4:]); p != "" {
+				cur.Path = p
+			}
+		}
+	}
+	flush()
+
+	for i := range files {
+		f := &files[i]
+		if f.Path == "" {
+			f.Path = f.OldPath
+		}
+		if f.Status != "R" {
+			f.OldPath = ""
+		}
+		if f.Path == "" {
+			// 二进制文件没有 ---/+++ 行，从 "diff --git a/x b/x" 中取路径
+			header := strings.TrimPrefix(f.header[0], "diff --git ")
+			if half := len(header) / 2; strings.HasPrefix(header, "a/") && header[half:half+3] == " b/" {
+				f.Path = header[half+3:]
+			}
+		}
+	}
+	return files
+}
+
+// diffSidePath 解析 "--- a/path" 中的路径，/dev/null 返回空
+func diffSidePath(s string) string {
+	s = unquoteGitPath(strings.TrimRight(s, "\t"))
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// unquoteGitPath 处理 git 对特殊字符路径加的引号
+func unquoteGitPath(s string) string {
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
+
+// atoiDefault 字符串为空时返回默认值
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	return atoiOrZero(s)
+}
+
+// patch 生成只包含第 index 个区块的补丁
+func (d *GitFileDiff) patch(index int) (string, error) {
+	if index < 0 || index >= len(d.Hunks) {
+		return "", fmt.Errorf("区块不存在: %d", index)
+	}
+	h := d.Hunks[index]
+	var sb strings.Builder
+	for _, l := range d.header {
+		sb.WriteString(l + "\n")
+	}
+	sb.WriteString(h.Header() + "\n")
+	for _, l := range h.Lines {
+		sb.WriteString(l + "\n")
+	}
+	return sb.String(), nil
+}
+
+// text 返回文件完整的补丁文本
+func (d *GitFileDiff) text() string {
+	var sb strings.Builder
+	for _, l := range d.header {
+		sb.WriteString(l + "\n")
+	}
+	for _, h := range d.Hunks {
+		sb.WriteString(h.Header() + "\n")
+		for _, l := range h.Lines {
+			sb.WriteString(l + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// stat 统计新增和删除的行数
+func (d *GitFileDiff) stat() (int, int) {
+	added, deleted := 0, 0
+	for _, h := range d.Hunks {
+		for _, l := range h.Lines {
+			switch {
+			case strings.HasPrefix(l, "+"):
+				added++
+			case strings.HasPrefix(l, "-"):
+				deleted++
+			}
+		}
+	}
+	return added, deleted
+}
+
+// loadGitFileDiff 读取单个文件已暂存或未暂存的 diff
+func loadGitFileDiff(root, path string, staged bool) (*GitFileDiff, error) {
+	args := []string{"-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "-M", "-U3"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	out, err := runGit(root, append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	if diffs := parseGitDiff(out); len(diffs) > 0 {
+		return &diffs[0], nil
+	}
+
+	if !staged {
+		// 未跟踪的文件：与空文件比较
+		if others, _ := runGit(root, "ls-files", "--others", "--exclude-standard", "--", path); strings.TrimSpace(others) != "" {
+			data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+			if err != nil {
+				return nil, fmt.Errorf("读取文件失败: %v", err)
+			}
+			d := &GitFileDiff{Path: path, Status: "A", Hunks: []DiffHunk{}}
+			if isBinaryContent(data) {
+				d.Binary = true
+			} else {
+				d.Hunks = ComputeHunks("", string(data), 3)
+			}
+			return d, nil
+		}
+	}
+	return &GitFileDiff{Path: path, Status: "M", Hunks: []DiffHunk{}}, nil
+}
+
+// GitDiffFile 获取单个文件的 diff（staged 为 true 时比较暂存区与 HEAD）
+func (a *App) GitDiffFile(dir, path string, staged bool) (*GitFileDiff, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	return loadGitFileDiff(root, gitRelPath(root, path), staged)
+}
+
+// GitDiffAll 获取全部已暂存或未暂存的 diff
+func (a *App) GitDiffAll(dir string, staged bool) ([]GitFileDiff, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "-M", "-U3"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	out, err := runGit(root, args...)
+	if err != nil {
+		return nil, err
+	}
+	diffs := parseGitDiff(out)
+	if diffs == nil {
+		diffs = []GitFileDiff{}
+	}
+	return diffs, nil
+}
+
+// applyGitHunk 将单个区块应用到暂存区或工作区
+func applyGitHunk(root, path string, staged bool, index int, args ...string) error {
+	d, err := loadGitFileDiff(root, path, staged)
+	if err != nil {
+		return err
+	}
+	if d.header == nil {
+		return fmt.Errorf("该文件不支持按区块操作: %s", path)
+	}
+	patch, err := d.patch(index)
+	if err != nil {
+		return err
+	}
+	_, err = runGitInput(root, patch, append([]string{"apply", "--whitespace=nowarn"}, append(args, "-")...)...)
+	return err
+}
+
+// GitStageHunk 暂存文件的第 index 个未暂存区块（未跟踪文件直接暂存整个文件）
+func (a *App) GitStageHunk(dir, path string, index int) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	rel := gitRelPath(root, path)
+	d, err := loadGitFileDiff(root, rel, false)
+	if err != nil {
+		return err
+	}
+	if d.header == nil && d.Status == "A" {
+		_, err := runGit(root, "add", "--", rel)
+		return err
+	}
+	return applyGitHunk(root, rel, false, index, "--cached")
+}
+
+// GitUnstageHunk 取消暂存文件的第 index 个已暂存区块
+func (a *App) GitUnstageHunk(dir, path string, index int) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	return applyGitHunk(root, gitRelPath(root, path), true, index, "--cached", "-R")
+}
+
+// GitDiscardHunk 丢弃工作区中文件的第 index 个区块
+func (a *App) GitDiscardHunk(dir, path string, index int) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	rel := gitRelPath(root, path)
+	a.snapshotFile(filepath.Join(root, filepath.FromSlash(rel)), "git-discard")
+	return applyGitHunk(root, rel, false, index, "-R")
+}
+
+// GitUnstage 取消暂存整个文件
+func (a *App) GitUnstage(dir, path string) error {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return err
+	}
+	rel := gitRelPath(root, path)
+	if _, err := runGit(root, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// 新仓库还没有 HEAD
+		_, err = runGit(root, "rm", "--cached", "--quiet", "--", rel)
+		return err
+	}
+	_, err = runGit(root, "reset", "--quiet", "HEAD", "--", rel)
+	return err
+}
+
+// --- 分支 ---
+
+var trackRe = regexp.MustCompile(`(ahead|behind) (\d+)`)
+
+// parseGitBranches 解析 for-each-ref 输出
+func parseGitBranches(output string) []GitBranch {
+	branches := []GitBranch{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 8 {
+			continue
+		}
+		ref := fields[0]
+		if strings.HasPrefix(ref, "refs/remotes/") && strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		b := GitBranch{
+			Ref:      ref,
+			Name:     fields[1],
+			Current:  fields[2] == "*",
+			Remote:   strings.HasPrefix(ref, "refs/remotes/"),
+			Upstream: fields[3],
+			Gone:     fields[4] == "gone",
+			Hash:     fields[5],
+			Subject:  fields[7],
+		}
+		b.Time, _ = strconv.ParseInt(fields[6], 10, 64)
+		for _, m := range trackRe.FindAllStringSubmatch(fields[4], -1) {
+			n, _ := strconv.Atoi(m[2])
+			if m[1] == "ahead" {
+				b.Ahead = n
+			} else {
+				b.Behind = n
+			}
+		}
+		branches = append(branches, b)
+	}
+	return branches
+}
+
+// GitBranches 列出本地和远程分支
+func (a *App) GitBranches(dir string) ([]GitBranch, error) {
+	format := "%(refname)%00%(refname:short)%00%(HEAD)%00%(upstream:short)%00%(upstream:track,nobracket)%00%(objectname:short)%00%(committerdate:unix)%00%(subject)"
+	out, err := runGit(dir, "for-each-ref", "--format="+format, "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	return parseGitBranches(out), nil
+}
+
+// GitCreateBranch 创建分支，startPoint 为空时基于 HEAD
+func (a *App) GitCreateBranch(dir, name, startPoint string, checkout bool) error {
+	if _, err := runGit(dir, "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("分支名称无效: %s", name)
+	}
+	if err := checkGitRefArg(startPoint); err != nil {
+		return err
+	}
+	args := []string{"branch", name}
+	if checkout {
+		args = []string{"switch", "-c", name}
+	}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	_, err := runGit(dir, args...)
+	return err
+}
+
+// GitCheckoutBranch 切换分支；远程分支会创建对应的跟踪分支
+func (a *App) GitCheckoutBranch(dir, name string) error {
+	if err := checkGitRefArg(name); err != nil {
+		return err
+	}
+	if _, err := runGit(dir, "show-ref", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+		_, err := runGit(dir, "switch", name)
+		return err
+	}
+	if _, err := runGit(dir, "show-ref", "--verify", "--quiet", "refs/remotes/"+name); err == nil {
+		_, err := runGit(dir, "switch", "--track", name)
+		return err
+	}
+	_, err := runGit(dir, "switch", name)
+	return err
+}
+
+// GitDeleteBranch 删除本地分支
+func (a *App) GitDeleteBranch(dir, name string, force bool) error {
+	if err := checkGitRefArg(name); err != nil {
+		return err
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := runGit(dir, "branch", flag, name)
+	return err
+}
+
+// checkGitRefArg 拒绝以 "-" 开头的分支名，避免被 git 当作选项解析
+func checkGitRefArg(name string) error {
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("分支名称无效: %s", name)
+	}
+	return nil
+}
+
+// --- 储藏 ---
+
+// GitStashList 列出储藏
+func (a *App) GitStashList(dir string) ([]GitStash, error) {
+	out, err := runGit(dir, "stash", "list", "--format=%gd%x00%gs%x00%ct")
+	if err != nil {
+		return nil, err
+	}
+	stashes := []GitStash{}
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 3 {
+			continue
+		}
+		s := GitStash{Index: i, Ref: fields[0], Message: fields[1]}
+		s.Time, _ = strconv.ParseInt(fields[2], 10, 64)
+		stashes = append(stashes, s)
+	}
+	return stashes, nil
+}
+
+// GitStashPush 储藏当前更改
+func (a *App) GitStashPush(dir, message string, includeUntracked bool) error {
+	args := []string{"stash", "push"}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	_, err := runGit(dir, args...)
+	return err
+}
+
+// GitStashPop 应用并删除储藏
+func (a *App) GitStashPop(dir string, index int) error {
+	_, err := runGit(dir, "stash", "pop", fmt.Sprintf("stash@{%d}", index))
+	return err
+}
+
+// GitStashApply 应用储藏（保留）
+func (a *App) GitStashApply(dir string, index int) error {
+	_, err := runGit(dir, "stash", "apply", fmt.Sprintf("stash@{%d}", index))
+	return err
+}
+
+// GitStashDrop 删除储藏
+func (a *App) GitStashDrop(dir string, index int) error {
+	_, err := runGit(dir, "stash", "drop", fmt.Sprintf("stash@{%d}", index))
+	return err
+}
+
+// --- 提交记录 ---
+
+// gitLogFormat 提交头字段（以 \x1e 分隔提交，\x00 分隔字段）
+const gitLogFormat = "%x1e%H%x00%h%x00%P%x00%an%x00%ae%x00%at%x00%D%x00%s"
+
+// gitLogHeaderFields 提交头的字段数
+const gitLogHeaderFields = 8
+
+// parseGitLog 解析 git log -z --raw --numstat 输出
+func parseGitLog(output string) []GitLogEntry {
+	commits := []GitLogEntry{}
+	for _, chunk := range strings.Split(output, "\x1e") {
+		tokens := strings.Split(chunk, "\x00")
+		if len(tokens) < gitLogHeaderFields {
+			continue
+		}
+		c := GitLogEntry{
+			Hash:      tokens[0],
+			ShortHash: tokens[1],
+			Parents:   strings.Fields(tokens[2]),
+			Author:    tokens[3],
+			Email:     tokens[4],
+			Refs:      tokens[6],
+			Subject:   tokens[7],
+			Files:     []GitLogFile{},
+		}
+		c.Time, _ = strconv.ParseInt(tokens[5], 10, 64)
+
+		rest := tokens[gitLogHeaderFields:]
+		numstat := 0
+		for i := 0; i < len(rest); i++ {
+			tok := strings.TrimLeft(rest[i], "\n")
+			switch {
+			case tok == "":
+				continue
+			case strings.HasPrefix(tok, ":"):
+				// :<mode> <mode> <sha> <sha> <status>\0<path>[\0<newpath>]
+				fields := strings.Fields(tok)
+				status := fields[len(fields)-1]
+				f := GitLogFile{Status: status[:1]}
+				if i+1 < len(rest) {
+					i++
+					f.Path = rest[i]
+				}
+				if (f.Status == "R" || f.Status == "C") && i+1 < len(rest) {
+					i++
+					f.OldPath, f.Path = f.Path, rest[i]
+				}
+				c.Files = append(c.Files, f)
+			default:
+				// <add>\t<del>\t<path> 或重命名时 <add>\t<del>\t\0<old>\0<new>
+				parts := strings.SplitN(tok, "\t", 3)
+				if len(parts) == 3 && parts[2] == "" {
+					i += 2
+				}
+				if len(parts) >= 2 && numstat < len(c.Files) {
+					f := &c.Files[numstat]
+					if parts[0] == "-" {
+						f.Binary = true
+					} else {
+						f.Additions, _ = strconv.Atoi(parts[0])
+						f.Deletions, _ = strconv.Atoi(parts[1])
+					}
+				}
+				numstat++
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// GitLog 分页获取提交记录（包含文件变更），path 不为空时只显示该路径的提交
+func (a *App) GitLog(dir string, skip, limit int, path string) (*GitLogPage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if skip < 0 {
+		skip = 0
+	}
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-c", "core.quotePath=false", "log", "-z", "--format=" + gitLogFormat, "--raw", "--numstat", "-M",
+		fmt.Sprintf("--skip=%d", skip), fmt.Sprintf("--max-count=%d", limit+1)}
+	if path != "" {
+		args = append(args, "--", gitRelPath(root, path))
+	}
+	out, err := runGit(root, args...)
+	if err != nil {
+		// 新仓库还没有提交
+		if _, headErr := runGit(root, "rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return &GitLogPage{Commits: []GitLogEntry{}, Skip: skip}, nil
+		}
+		return nil, err
+	}
+
+	page := &GitLogPage{Commits: parseGitLog(out), Skip: skip}
+	if len(page.Commits) > limit {
+		page.Commits = page.Commits[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// GitShowFile 获取某个提交中文件的内容（用于查看历史版本）
+func (a *App) GitShowFile(dir, rev, path string) (string, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return "", err
+	}
+	return runGit(root, "show", rev+":"+gitRelPath(root, path))
+}
+
+// --- blame ---
+
+var blameHeaderRe = regexp.MustCompile(`^([0-9a-f]{40}) (\d+) (\d+)`)
+
+// parseGitBlame 解析 git blame --porcelain 输出
+func parseGitBlame(output string) *GitBlame {
+	blame := &GitBlame{Commits: make(map[string]GitBlameCommit), Lines: []GitBlameLine{}}
+	var cur *GitBlameCommit
+	var line GitBlameLine
+
+	for _, l := range strings.Split(output, "\n") {
+		if m := blameHeaderRe.FindStringSubmatch(l); m != nil {
+			line = GitBlameLine{Hash: m[1], OrigLine: atoiOrZero(m[2]), Line: atoiOrZero(m[3])}
+			c, ok := blame.Commits[m[1]]
+			if !ok {
+				c = GitBlameCommit{Hash: m[1]}
+			}
+			cur = &c
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "\t"):
+			blame.Commits[cur.Hash] = *cur
+			blame.Lines = append(blame.Lines, line)
+			cur = nil
+		case strings.HasPrefix(l, "author "):
+			cur.Author = strings.TrimPrefix(l, "author ")
+		case strings.HasPrefix(l, "author-mail "):
+			cur.Email = strings.Trim(strings.TrimPrefix(l, "author-mail "), "<>")
+		case strings.HasPrefix(l, "author-time "):
+			cur.Time, _ = strconv.ParseInt(strings.TrimPrefix(l, "author-time "), 10, 64)
+		case strings.HasPrefix(l, "summary "):
+			cur.Summary = strings.TrimPrefix(l, "summary ")
+		}
+	}
+	return blame
+}
+
+// GitBlame 获取文件每一行的最后修改提交；contents 不为空时对编辑器中未保存的内容做 blame
+func (a *App) GitBlame(dir, path, contents string) (*GitBlame, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel := gitRelPath(root, path)
+	args := []string{"blame", "--porcelain"}
+	if contents != "" {
+		args = append(args, "--contents", "-")
+	}
+	out, err := runGitInput(root, contents, append(args, "--", rel)...)
+	if err != nil {
+		return nil, err
+	}
+	blame := parseGitBlame(out)
+	blame.Path = rel
+	return blame, nil
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initTestRepo creates a git repository with a deterministic identity
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("EvalSymlinks() error = %v", err)
+	}
+	mustGit(t, dir, "init", "-q", "-b", "main")
+	return dir
+}
+
+// mustGit runs a git command and fails the test on error
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runGit(dir, args...)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return out
+}
+
+// TestParseGitStatusV2 tests porcelain v2 parsing including renames and split staged/unstaged entries
+func TestParseGitStatusV2(t *testing.T) {
+	output := strings.Join([]string{
+		"# branch.oid 1234567890abcdef1234567890abcdef12345678",
+		"# branch.head main",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -1",
+		"1 MM N... 100644 100644 100644 aaa bbb dir/my file.go",
+		"2 R. N... 100644 100644 100644 aaa bbb R100 new name.txt",
+		"old name.txt",
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.txt",
+		"? untracked.txt",
+		"",
+	}, "\x00")
+
+	status := parseGitStatusV2(output)
+	if status.Branch != "main" || status.Upstream != "origin/main" || status.Ahead != 2 || status.Behind != 1 {
+		t.Errorf("branch info = %+v", status)
+	}
+	if status.Head == "" || status.Detached {
+		t.Errorf("Head = %q, Detached = %v", status.Head, status.Detached)
+	}
+
+	want := []GitChange{
+		{Path: "dir/my file.go", Status: "M", Staged: true},
+		{Path: "dir/my file.go", Status: "M"},
+		{Path: "new name.txt", OrigPath: "old name.txt", Status: "R", Staged: true},
+		{Path: "conflict.txt", Status: "U", Conflict: "UU"},
+		{Path: "untracked.txt", Status: "??"},
+	}
+	if len(status.Changes) != len(want) {
+		t.Fatalf("Changes = %+v, want %+v", status.Changes, want)
+	}
+	for i, c := range status.Changes {
+		if c != want[i] {
+			t.Errorf("Changes[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
+
+// TestParseGitDiff tests parsing of multi-file diffs with renames, new files and binary files
+func TestParseGitDiff(t *testing.T) {
+	output := `diff --git a/a.txt b/a.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@ func main
+-old
++new
+ same
+@@ -10 +10 @@
+-x
+\ No newline at end of file
++y
+\ No newline at end of file
+diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+diff --git a/added file.txt b/added file.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/added file.txt
+@@ -0,0 +1 @@
++hello
+diff --git a/img.png b/img.png
+index 4444444..5555555 100644
+Binary files a/img.png and b/img.png differ
+`
+	diffs := parseGitDiff(output)
+	if len(diffs) != 4 {
+		t.Fatalf("len(diffs) = %d, want 4", len(diffs))
+	}
+
+	tests := []struct {
+		path, oldPath, status string
+		binary                bool
+		hunks                 int
+	}{
+		{"a.txt", "", "M", false, 2},
+		{"new.go", "old.go", "R", false, 0},
+		{"added file.txt", "", "A", false, 1},
+		{"img.png", "", "M", true, 0},
+	}
+	for i, tt := range tests {
+		d := diffs[i]
+		if d.Path != tt.path || d.OldPath != tt.oldPath || d.Status != tt.status || d.Binary != tt.binary || len(d.Hunks) != tt.hunks {
+			t.Errorf("diffs[%d] = {%q %q %q %v %d}, want %+v", i, d.Path, d.OldPath, d.Status, d.Binary, len(d.Hunks), tt)
+		}
+	}
+
+	h := diffs[0].Hunks[1]
+	if h.OldStart != 10 || h.OldLines != 1 || h.NewStart != 10 || h.NewLines != 1 || len(h.Lines) != 4 {
+		t.Errorf("hunk = %+v", h)
+	}
+	patch, err := diffs[0].patch(0)
+	if err != nil {
+		t.Fatalf("patch() error = %v", err)
+	}
+	if !strings.HasPrefix(patch, "diff --git a/a.txt b/a.txt\n") || !strings.Contains(patch, "@@ -1,2 +1,2 @@\n-old\n+new\n same\n") || strings.Contains(patch, "+y") {
+		t.Errorf("patch = %q", patch)
+	}
+}
+
+// TestParseGitLog tests parsing of log output with raw and numstat records
+func TestParseGitLog(t *testing.T) {
+	output := "\x1eaaaa\x00aa\x00p1 p2\x00Alice\x00alice@example.com\x001700000000\x00HEAD -> main\x00Merge stuff\x00" +
+		"\n:100644 100644 111 222 M\x00a.go\x00:100644 100644 333 444 R090\x00old.go\x00new.go\x00" +
+		"3\t1\ta.go\x002\t0\t\x00old.go\x00new.go\x00" +
+		"\x1ebbbb\x00bb\x00\x00Bob\x00bob@example.com\x001600000000\x00\x00Initial\x00" +
+		"\n:000000 100644 000 555 A\x00logo.png\x00-\t-\tlogo.png\x00"
+
+	commits := parseGitLog(output)
+	if len(commits) != 2 {
+		t.Fatalf("len(commits) = %d, want 2", len(commits))
+	}
+
+	c := commits[0]
+	if c.Hash != "aaaa" || len(c.Parents) != 2 || c.Author != "Alice" || c.Time != 1700000000 || c.Refs != "HEAD -> main" || c.Subject != "Merge stuff" {
+		t.Errorf("commit = %+v", c)
+	}
+	wantFiles := []GitLogFile{
+		{Path: "a.go", Status: "M", Additions: 3, Deletions: 1},
+		{Path: "new.go", OldPath: "old.go", Status: "R", Additions: 2},
+	}
+	if len(c.Files) != len(wantFiles) {
+		t.Fatalf("Files = %+v", c.Files)
+	}
+	for i, f := range c.Files {
+		if f != wantFiles[i] {
+			t.Errorf("Files[%d] = %+v, want %+v", i, f, wantFiles[i])
+		}
+	}
+
+	if f := commits[1].Files; len(f) != 1 || f[0].Status != "A" || !f[0].Binary || len(commits[1].Parents) != 0 {
+		t.Errorf("second commit = %+v", commits[1])
+	}
+}
+
+// TestGitHunkStaging tests staging, unstaging and discarding a single hunk in a real repository
+func TestGitHunkStaging(t *testing.T) {
+	dir := initTestRepo(t)
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line")
+	}
+	original := strings.Join(lines, "\n") + "\n"
+	writeTestFile(t, dir, "file.txt", original)
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "init")
+
+	lines[0] = "first changed"
+	lines[19] = "last changed"
+	path := writeTestFile(t, dir, "file.txt", strings.Join(lines, "\n")+"\n")
+
+	a := &App{}
+	d, err := a.GitDiffFile(dir, path, false)
+	if err != nil {
+		t.Fatalf("GitDiffFile() error = %v", err)
+	}
+	if len(d.Hunks) != 2 {
+		t.Fatalf("len(Hunks) = %d, want 2", len(d.Hunks))
+	}
+
+	if err := a.GitStageHunk(dir, "file.txt", 1); err != nil {
+		t.Fatalf("GitStageHunk() error = %v", err)
+	}
+	staged := mustGit(t, dir, "diff", "--cached")
+	if !strings.Contains(staged, "+last changed") || strings.Contains(staged, "+first changed") {
+		t.Errorf("staged diff = %q", staged)
+	}
+
+	if err := a.GitUnstageHunk(dir, "file.txt", 0); err != nil {
+		t.Fatalf("GitUnstageHunk() error = %v", err)
+	}
+	if staged := mustGit(t, dir, "diff", "--cached"); staged != "" {
+		t.Errorf("staged diff after unstage = %q", staged)
+	}
+
+	if err := a.GitDiscardHunk(dir, "file.txt", 0); err != nil {
+		t.Fatalf("GitDiscardHunk() error = %v", err)
+	}
+	unstaged := mustGit(t, dir, "diff")
+	if strings.Contains(unstaged, "+first changed") || !strings.Contains(unstaged, "+last changed") {
+		t.Errorf("unstaged diff after discard = %q", unstaged)
+	}
+
+	status, err := a.GetGitStatus(dir)
+	if err != nil {
+		t.Fatalf("GetGitStatus() error = %v", err)
+	}
+	if status.Root != dir || status.Branch != "main" || len(status.Changes) != 1 || status.Changes[0].Staged {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+// TestGitBranchesStashAndLog tests branch, stash and log APIs against a real repository
+func TestGitBranchesStashAndLog(t *testing.T) {
+	dir := initTestRepo(t)
+	writeTestFile(t, dir, "a.txt", "one\n")
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "first")
+	writeTestFile(t, dir, "a.txt", "one\ntwo\n")
+	mustGit(t, dir, "commit", "-q", "-am", "second")
+
+	a := &App{}
+	if err := a.GitCreateBranch(dir, "bad..name", "", false); err == nil {
+		t.Error("GitCreateBranch() accepted an invalid name")
+	}
+	if err := a.GitCreateBranch(dir, "feature", "HEAD~1", true); err != nil {
+		t.Fatalf("GitCreateBranch() error = %v", err)
+	}
+	branches, err := a.GitBranches(dir)
+	if err != nil {
+		t.Fatalf("GitBranches() error = %v", err)
+	}
+	current := ""
+	for _, b := range branches {
+		if b.Current {
+			current = b.Name
+		}
+	}
+	if len(branches) != 2 || current != "feature" {
+		t.Errorf("branches = %+v", branches)
+	}
+	if err := a.GitCheckoutBranch(dir, "--orphan=x"); err == nil {
+		t.Error("GitCheckoutBranch() accepted an option-like name")
+	}
+	if err := a.GitDeleteBranch(dir, "-D", false); err == nil {
+		t.Error("GitDeleteBranch() accepted an option-like name")
+	}
+	if err := a.GitCheckoutBranch(dir, "main"); err != nil {
+		t.Fatalf("GitCheckoutBranch() error = %v", err)
+	}
+	if err := a.GitDeleteBranch(dir, "feature", false); err != nil {
+		t.Fatalf("GitDeleteBranch() error = %v", err)
+	}
+
+	writeTestFile(t, dir, "a.txt", "changed\n")
+	writeTestFile(t, dir, "new.txt", "new\n")
+	if err := a.GitStashPush(dir, "wip", true); err != nil {
+		t.Fatalf("GitStashPush() error = %v", err)
+	}
+	stashes, err := a.GitStashList(dir)
+	if err != nil {
+		t.Fatalf("GitStashList() error = %v", err)
+	}
+	if len(stashes) != 1 || stashes[0].Ref != "stash@{0}" || !strings.Contains(stashes[0].Message, "wip") {
+		t.Fatalf("stashes = %+v", stashes)
+	}
+	if err := a.GitStashPop(dir, 0); err != nil {
+		t.Fatalf("GitStashPop() error = %v", err)
+	}
+	if stashes, _ := a.GitStashList(dir); len(stashes) != 0 {
+		t.Errorf("stashes after pop = %+v", stashes)
+	}
+
+	page, err := a.GitLog(dir, 0, 1, "")
+	if err != nil {
+		t.Fatalf("GitLog() error = %v", err)
+	}
+	if len(page.Commits) != 1 || !page.HasMore || page.Commits[0].Subject != "second" {
+		t.Fatalf("page = %+v", page)
+	}
+	if f := page.Commits[0].Files; len(f) != 1 || f[0].Path != "a.txt" || f[0].Additions != 1 {
+		t.Errorf("files = %+v", f)
+	}
+	page, err = a.GitLog(dir, 1, 1, "a.txt")
+	if err != nil || len(page.Commits) != 1 || page.HasMore || page.Commits[0].Subject != "first" {
+		t.Errorf("GitLog(skip=1) = %+v, %v", page, err)
+	}
+
+	blame, err := a.GitBlame(dir, filepath.Join(dir, "a.txt"), "one\ntwo\nthree\n")
+	if err != nil {
+		t.Fatalf("GitBlame() error = %v", err)
+	}
+	if len(blame.Lines) != 3 || blame.Commits[blame.Lines[1].Hash].Summary != "second" {
+		t.Errorf("blame = %+v", blame)
+	}
+}
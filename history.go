@@ -0,0 +1,491 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistoryMaxBytes   = 256 * 1024 * 1024 // 历史快照总大小上限
+	defaultHistoryMaxEntries = 100               // 每个文件最多保留的快照数
+	maxHistoryDirFiles       = 2000              // 删除目录时最多快照的文件数
+)
+
+// HistoryEntry 一次文件快照
+type HistoryEntry struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Hash   string    `json:"hash"`
+	Size   int64     `json:"size"`
+	Source string    `json:"source"` // "save", "replace", "delete", "restore", "external", "open" 等
+}
+
+// HistoryFile 有历史记录的文件
+type HistoryFile struct {
+	Path     string    `json:"path"`
+	Count    int       `json:"count"`
+	LastTime time.Time `json:"lastTime"`
+	Exists   bool      `json:"exists"`
+}
+
+// historyTimeline 单个文件的时间线（按时间升序保存）
+type historyTimeline struct {
+	Path    string         `json:"path"`
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// HistoryManager 本地文件历史（与 git 无关）
+type HistoryManager struct {
+	app        *App
+	dir        string
+	maxBytes   int64
+	maxEntries int
+	totalSize  int64          // 被引用对象的总大小
+	refCount   map[string]int // 对象被快照引用的次数，nil 表示尚未统计
+	mu         sync.Mutex
+}
+
+// NewHistoryManager 创建文件历史管理器
+func NewHistoryManager(app *App) *HistoryManager {
+	return &HistoryManager{
+		app:        app,
+		maxBytes:   defaultHistoryMaxBytes,
+		maxEntries: defaultHistoryMaxEntries,
+	}
+}
+
+// baseDir 返回历史目录（位于配置数据目录下）
+func (hm *HistoryManager) baseDir() (string, error) {
+	if hm.dir != "" {
+		return hm.dir, os.MkdirAll(hm.dir, 0755)
+	}
+	dir, err := hm.app.getDataDir("history")
+	if err != nil {
+		return "", err
+	}
+	hm.dir = dir
+	return dir, nil
+}
+
+// timelinePath 文件时间线的保存位置
+func (hm *HistoryManager) timelinePath(base, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(base, "files", hex.EncodeToString(sum[:16])+".json")
+}
+
+// objectPath 内容对象的保存位置（按哈希去重）
+func objectPath(base, hash string) string {
+	return filepath.Join(base, "objects", hash[:2], hash)
+}
+
+// loadTimeline 读取时间线，不存在时返回空时间线
+func (hm *HistoryManager) loadTimeline(base, path string) (*historyTimeline, error) {
+	tl := &historyTimeline{Path: path}
+	data, err := os.ReadFile(hm.timelinePath(base, path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tl, nil
+		}
+		return nil, fmt.Errorf("读取文件历史失败: %v", err)
+	}
+	if err := json.Unmarshal(data, tl); err != nil {
+		return nil, fmt.Errorf("解析文件历史失败: %v", err)
+	}
+	return tl, nil
+}
+
+// saveTimeline 保存时间线，没有快照时删除
+func (hm *HistoryManager) saveTimeline(base string, tl *historyTimeline) error {
+	path := hm.timelinePath(base, tl.Path)
+	if len(tl.Entries) == 0 {
+		os.Remove(path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0600)
+}
+
+// Snapshot 记录文件当前内容；与最近一次快照相同时跳过。文件不存在、过大或为目录时忽略
+func (hm *HistoryManager) Snapshot(path, source string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxEditableFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	return hm.recordLocked(path, data, source)
+}
+
+// SnapshotTree 记录路径下所有文件（用于删除目录前）
+func (hm *HistoryManager) SnapshotTree(root, source string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		return hm.Snapshot(root, source)
+	}
+
+	count := 0
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (alwaysIgnoredDirs[d.Name()] || defaultSkippedDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if count >= maxHistoryDirFiles {
+			return filepath.SkipAll
+		}
+		count++
+		return hm.Snapshot(path, source)
+	})
+}
+
+// recordLocked 写入内容对象并追加快照（调用方需持有锁）
+func (hm *HistoryManager) recordLocked(path string, data []byte, source string) error {
+	base, err := hm.baseDir()
+	if err != nil {
+		return err
+	}
+	tl, err := hm.loadTimeline(base, path)
+	if err != nil {
+		return err
+	}
+
+	hash := hashBytes(data)
+	if n := len(tl.Entries); n > 0 && tl.Entries[n-1].Hash == hash {
+		return nil
+	}
+	hm.loadRefsLocked(base)
+
+	obj := objectPath(base, hash)
+	if _, err := os.Stat(obj); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(obj), 0755); err != nil {
+			return fmt.Errorf("保存文件历史失败: %v", err)
+		}
+		if err := writeFileAtomic(obj, data, 0600); err != nil {
+			return fmt.Errorf("保存文件历史失败: %v", err)
+		}
+	}
+
+	now := time.Now()
+	entry := HistoryEntry{
+		ID:     fmt.Sprintf("%d", now.UnixNano()),
+		Time:   now,
+		Hash:   hash,
+		Size:   int64(len(data)),
+		Source: source,
+	}
+	tl.Entries = append(tl.Entries, entry)
+	var trimmed []HistoryEntry
+	if len(tl.Entries) > hm.maxEntries {
+		trimmed = tl.Entries[:len(tl.Entries)-hm.maxEntries]
+		tl.Entries = tl.Entries[len(tl.Entries)-hm.maxEntries:]
+	}
+	if err := hm.saveTimeline(base, tl); err != nil {
+		return fmt.Errorf("保存文件历史失败: %v", err)
+	}
+
+	hm.retainLocked(entry)
+	for _, e := range trimmed {
+		hm.releaseLocked(base, e)
+	}
+	return hm.enforceRetentionLocked(base)
+}
+
+// loadRefsLocked 首次使用时统计对象引用，并删除不被任何时间线引用的对象（调用方需持有锁）
+func (hm *HistoryManager) loadRefsLocked(base string) {
+	if hm.refCount != nil {
+		return
+	}
+	hm.refCount = make(map[string]int)
+	hm.totalSize = 0
+	for _, tl := range hm.allTimelinesLocked(base) {
+		for _, e := range tl.Entries {
+			hm.retainLocked(e)
+		}
+	}
+
+	objects := filepath.Join(base, "objects")
+	filepath.WalkDir(objects, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && hm.refCount[d.Name()] == 0 {
+			os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// retainLocked 增加对象引用，首次引用时计入总大小（调用方需持有锁）
+func (hm *HistoryManager) retainLocked(e HistoryEntry) {
+	if hm.refCount[e.Hash] == 0 {
+		hm.totalSize += e.Size
+	}
+	hm.refCount[e.Hash]++
+}
+
+// releaseLocked 减少对象引用，不再被引用时删除对象（调用方需持有锁）
+func (hm *HistoryManager) releaseLocked(base string, e HistoryEntry) {
+	hm.refCount[e.Hash]--
+	if hm.refCount[e.Hash] > 0 {
+		return
+	}
+	delete(hm.refCount, e.Hash)
+	hm.totalSize -= e.Size
+	os.Remove(objectPath(base, e.Hash))
+}
+
+// allTimelinesLocked 读取全部时间线（调用方需持有锁）
+func (hm *HistoryManager) allTimelinesLocked(base string) []*historyTimeline {
+	entries, err := os.ReadDir(filepath.Join(base, "files"))
+	if err != nil {
+		return nil
+	}
+	var timelines []*historyTimeline
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(base, "files", e.Name()))
+		if err != nil {
+			continue
+		}
+		var tl historyTimeline
+		if json.Unmarshal(data, &tl) == nil && tl.Path != "" {
+			timelines = append(timelines, &tl)
+		}
+	}
+	return timelines
+}
+
+// enforceRetentionLocked 总大小超出上限时从最旧的快照开始删除，并清理未引用的对象
+func (hm *HistoryManager) enforceRetentionLocked(base string) error {
+	hm.loadRefsLocked(base)
+	if hm.totalSize <= hm.maxBytes {
+		return nil
+	}
+
+	timelines := hm.allTimelinesLocked(base)
+	type ref struct {
+		tl    *historyTimeline
+		entry HistoryEntry
+	}
+	var refs []ref
+	for _, tl := range timelines {
+		for _, e := range tl.Entries {
+			refs = append(refs, ref{tl, e})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].entry.Time.Before(refs[j].entry.Time) })
+
+	removed := make(map[*historyTimeline]map[string]bool)
+	for _, r := range refs {
+		if hm.totalSize <= hm.maxBytes {
+			break
+		}
+		if removed[r.tl] == nil {
+			removed[r.tl] = make(map[string]bool)
+		}
+		removed[r.tl][r.entry.ID] = true
+		hm.releaseLocked(base, r.entry)
+	}
+
+	for tl, ids := range removed {
+		kept := tl.Entries[:0]
+		for _, e := range tl.Entries {
+			if !ids[e.ID] {
+				kept = append(kept, e)
+			}
+		}
+		tl.Entries = kept
+		hm.saveTimeline(base, tl)
+	}
+	return nil
+}
+
+// dirSize 统计目录下文件总大小
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// Timeline 返回文件的快照列表（最新的在前）
+func (hm *HistoryManager) Timeline(path string) ([]HistoryEntry, error) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	base, err := hm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	tl, err := hm.loadTimeline(base, path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, len(tl.Entries))
+	for i, e := range tl.Entries {
+		entries[len(entries)-1-i] = e
+	}
+	return entries, nil
+}
+
+// Content 返回某个快照的内容
+func (hm *HistoryManager) Content(path, entryID string) ([]byte, error) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	base, err := hm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	tl, err := hm.loadTimeline(base, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range tl.Entries {
+		if e.ID == entryID {
+			data, err := os.ReadFile(objectPath(base, e.Hash))
+			if err != nil {
+				return nil, fmt.Errorf("快照内容已被清理: %v", err)
+			}
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("快照不存在: %s", entryID)
+}
+
+// Diff 比较两个快照，toID 为空时与磁盘上的当前内容比较
+func (hm *HistoryManager) Diff(path, fromID, toID string) (string, error) {
+	from, err := hm.Content(path, fromID)
+	if err != nil {
+		return "", err
+	}
+	var to []byte
+	toName := "current"
+	if toID == "" {
+		to, _ = os.ReadFile(path)
+	} else {
+		if to, err = hm.Content(path, toID); err != nil {
+			return "", err
+		}
+		toName = toID
+	}
+	name := filepath.Base(path)
+	return UnifiedDiff(name+"@"+fromID, name+"@"+toName, string(from), string(to), 3), nil
+}
+
+// Restore 将文件恢复为某个快照（恢复前先记录当前内容）
+func (hm *HistoryManager) Restore(path, entryID string) error {
+	data, err := hm.Content(path, entryID)
+	if err != nil {
+		return err
+	}
+	if err := hm.Snapshot(path, "restore"); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := writeFileAtomic(path, data, perm); err != nil {
+		return fmt.Errorf("恢复文件失败: %v", err)
+	}
+	return hm.Snapshot(path, "restore")
+}
+
+// Files 列出有历史记录的文件（包括已删除的文件）
+func (hm *HistoryManager) Files() ([]HistoryFile, error) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	base, err := hm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	files := []HistoryFile{}
+	for _, tl := range hm.allTimelinesLocked(base) {
+		if len(tl.Entries) == 0 {
+			continue
+		}
+		files = append(files, HistoryFile{
+			Path:     tl.Path,
+			Count:    len(tl.Entries),
+			LastTime: tl.Entries[len(tl.Entries)-1].Time,
+			Exists:   fileExists(tl.Path),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].LastTime.After(files[j].LastTime) })
+	return files, nil
+}
+
+// snapshotFile 在修改文件前记录历史（历史管理器未初始化时忽略）
+func (a *App) snapshotFile(path, source string) {
+	if a == nil || a.historyMgr == nil {
+		return
+	}
+	if err := a.historyMgr.Snapshot(path, source); err != nil {
+		fmt.Println("记录文件历史失败:", err)
+	}
+}
+
+// --- App API ---
+
+// GetFileHistory 获取文件的本地历史
+func (a *App) GetFileHistory(path string) ([]HistoryEntry, error) {
+	return a.historyMgr.Timeline(a.fileMgr.resolvePath(path))
+}
+
+// GetFileHistoryContent 获取某个快照的内容
+func (a *App) GetFileHistoryContent(path, entryID string) (string, error) {
+	data, err := a.historyMgr.Content(a.fileMgr.resolvePath(path), entryID)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DiffFileHistory 比较两个快照（toID 为空时与当前内容比较）
+func (a *App) DiffFileHistory(path, fromID, toID string) (string, error) {
+	return a.historyMgr.Diff(a.fileMgr.resolvePath(path), fromID, toID)
+}
+
+// RestoreFileHistory 恢复文件到某个快照
+func (a *App) RestoreFileHistory(path, entryID string) error {
+	return a.historyMgr.Restore(a.fileMgr.resolvePath(path), entryID)
+}
+
+// GetHistoryFiles 列出有本地历史的文件
+func (a *App) GetHistoryFiles() ([]HistoryFile, error) {
+	return a.historyMgr.Files()
+}
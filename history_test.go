@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestHistoryApp creates an app whose history is stored in a temp dir
+func newTestHistoryApp(t *testing.T) *App {
+	t.Helper()
+	app := &App{}
+	app.fileMgr = NewFileManager(app)
+	app.historyMgr = NewHistoryManager(app)
+	app.historyMgr.dir = t.TempDir()
+	return app
+}
+
+// TestHistorySnapshotsOnWriteAndRestore tests timelines, diff and restore
+func TestHistorySnapshotsOnWriteAndRestore(t *testing.T) {
+	app := newTestHistoryApp(t)
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "a.txt", "v1\n")
+
+	if err := app.fileMgr.WriteFile(path, "v2\n"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := app.fileMgr.WriteFile(path, "v3\n"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	entries, err := app.historyMgr.Timeline(path)
+	if err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 distinct snapshots, got %d: %+v", len(entries), entries)
+	}
+	oldest := entries[len(entries)-1]
+	content, _ := app.historyMgr.Content(path, oldest.ID)
+	if string(content) != "v1\n" {
+		t.Errorf("Oldest snapshot = %q, want v1", content)
+	}
+
+	diff, err := app.historyMgr.Diff(path, oldest.ID, "")
+	if err != nil || !strings.Contains(diff, "-v1") || !strings.Contains(diff, "+v3") {
+		t.Errorf("Unexpected diff %q, %v", diff, err)
+	}
+
+	if err := app.historyMgr.Restore(path, oldest.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "v1\n" {
+		t.Errorf("Restored content = %q", data)
+	}
+}
+
+// TestHistorySnapshotsDeletedTree tests that deleted directories can be recovered
+func TestHistorySnapshotsDeletedTree(t *testing.T) {
+	app := newTestHistoryApp(t)
+	dir := t.TempDir()
+	app.fileMgr.rootDir = dir
+	path := writeTestFile(t, dir, "pkg/a.go", "package pkg\n")
+
+	if err := app.fileMgr.DeletePath(filepath.Join(dir, "pkg")); err != nil {
+		t.Fatalf("DeletePath() error = %v", err)
+	}
+	files, _ := app.historyMgr.Files()
+	if len(files) != 1 || files[0].Path != path || files[0].Exists {
+		t.Fatalf("Unexpected history files: %+v", files)
+	}
+
+	entries, _ := app.historyMgr.Timeline(path)
+	if err := app.historyMgr.Restore(path, entries[0].ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "package pkg\n" {
+		t.Errorf("Restored content = %q", data)
+	}
+}
+
+// TestHistoryRetention tests size-based pruning of the oldest snapshots
+func TestHistoryRetention(t *testing.T) {
+	app := newTestHistoryApp(t)
+	app.historyMgr.maxBytes = 25
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+
+	for _, content := range []string{"0123456789", "abcdefghij", "ABCDEFGHIJ"} {
+		os.WriteFile(path, []byte(content), 0644)
+		if err := app.historyMgr.Snapshot(path, "save"); err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+	}
+
+	entries, _ := app.historyMgr.Timeline(path)
+	if len(entries) != 2 {
+		t.Fatalf("Expected oldest snapshot to be pruned, got %d entries", len(entries))
+	}
+	if content, _ := app.historyMgr.Content(path, entries[1].ID); string(content) != "abcdefghij" {
+		t.Errorf("Unexpected remaining snapshot %q", content)
+	}
+	if size := dirSize(filepath.Join(app.historyMgr.dir, "objects")); size != 20 {
+		t.Errorf("Expected pruned objects to be removed, size = %d", size)
+	}
+}
+
+// TestHistoryTrimRemovesObjects tests that snapshots dropped by the per-file limit release their objects
+func TestHistoryTrimRemovesObjects(t *testing.T) {
+	app := newTestHistoryApp(t)
+	app.historyMgr.maxEntries = 2
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	other := filepath.Join(dir, "b.txt")
+
+	os.WriteFile(other, []byte("0123456789"), 0644)
+	if err := app.historyMgr.Snapshot(other, "save"); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	for _, content := range []string{"0123456789", "abcdefghij", "ABCDEFGHIJ", "klmnopqrst"} {
+		os.WriteFile(path, []byte(content), 0644)
+		if err := app.historyMgr.Snapshot(path, "save"); err != nil {
+			t.Fatalf("Snapshot() error = %v", err)
+		}
+	}
+
+	// "0123456789" is still referenced by b.txt, "abcdefghij" is orphaned
+	if size := dirSize(filepath.Join(app.historyMgr.dir, "objects")); size != 30 {
+		t.Errorf("Expected trimmed objects to be removed, size = %d", size)
+	}
+	if app.historyMgr.totalSize != 30 {
+		t.Errorf("Expected total size of referenced objects 30, got %d", app.historyMgr.totalSize)
+	}
+
+	// Rebuilding the index removes leftover orphaned objects
+	orphan := objectPath(app.historyMgr.dir, hashBytes([]byte("orphan")))
+	os.MkdirAll(filepath.Dir(orphan), 0755)
+	os.WriteFile(orphan, []byte("orphan"), 0600)
+	app.historyMgr.refCount = nil
+	os.WriteFile(other, []byte("uvwxyz0123"), 0644)
+	if err := app.historyMgr.Snapshot(other, "save"); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Errorf("Expected orphaned object to be removed, stat error = %v", err)
+	}
+	if app.historyMgr.totalSize != 40 {
+		t.Errorf("Expected total size 40, got %d", app.historyMgr.totalSize)
+	}
+}
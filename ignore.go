@@ -0,0 +1,275 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// alwaysIgnoredDirs 无论是否配置都跳过的版本控制目录
+var alwaysIgnoredDirs = map[string]bool{
+	".git": true,
+	".svn": true,
+	".hg":  true,
+}
+
+// ignoreRule 一条 .gitignore 规则
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	base    string // 规则所在目录（相对根目录，"" 表示根目录）
+}
+
+// IgnoreMatcher 按 .gitignore 语义判断路径是否被忽略，支持嵌套的 .gitignore
+type IgnoreMatcher struct {
+	root  string
+	rules map[string][]ignoreRule // 目录 -> 该目录下 .gitignore 的规则
+	extra []ignoreRule            // 额外规则（如工作区配置的排除项），优先级最高
+	mu    sync.Mutex
+}
+
+// NewIgnoreMatcher 创建忽略规则匹配器
+func NewIgnoreMatcher(root string) *IgnoreMatcher {
+	return &IgnoreMatcher{
+		root:  root,
+		rules: make(map[string][]ignoreRule),
+	}
+}
+
+// AddPatterns 添加额外的 gitignore 格式规则（相对根目录）
+func (m *IgnoreMatcher) AddPatterns(patterns []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range patterns {
+		if rule, ok := parseIgnoreLine(p, ""); ok {
+			m.extra = append(m.extra, rule)
+		}
+	}
+}
+
+// Match 判断路径是否被忽略（rel 为相对根目录的路径）。祖先目录被忽略时子路径也视为忽略
+func (m *IgnoreMatcher) Match(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	if rel == "" || rel == "." {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.MatchEntry(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.MatchEntry(rel, isDir)
+}
+
+// MatchEntry 只判断路径本身（不检查祖先目录），适用于自顶向下的遍历
+func (m *IgnoreMatcher) MatchEntry(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	if isDir && alwaysIgnoredDirs[path.Base(rel)] {
+		return true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ignored := false
+	dir := path.Dir(rel)
+	dirs := []string{""}
+	if dir != "." {
+		parts := strings.Split(dir, "/")
+		for i := range parts {
+			dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+		}
+	}
+
+	for _, d := range dirs {
+		for _, rule := range m.loadRulesLocked(d) {
+			if rule.matches(rel, isDir) {
+				ignored = !rule.negate
+			}
+		}
+	}
+	for _, rule := range m.extra {
+		if rule.matches(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// loadRulesLocked 读取并缓存目录下的 .gitignore（调用方需持有锁）
+func (m *IgnoreMatcher) loadRulesLocked(dir string) []ignoreRule {
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+
+	var rules []ignoreRule
+	files := []string{filepath.Join(m.root, filepath.FromSlash(dir), ".gitignore")}
+	if dir == "" {
+		files = append([]string{filepath.Join(m.root, ".git", "info", "exclude")}, files...)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if rule, ok := parseIgnoreLine(line, dir); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	m.rules[dir] = rules
+	return rules
+}
+
+// Invalidate 清除指定目录的规则缓存（.gitignore 变化时调用），dir 为空时清除全部
+func (m *IgnoreMatcher) Invalidate(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if dir == "" {
+		m.rules = make(map[string][]ignoreRule)
+		return
+	}
+	delete(m.rules, filepath.ToSlash(dir))
+}
+
+// matches 判断规则是否匹配路径
+func (r ignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	sub := rel
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		sub = rel[len(r.base)+1:]
+	}
+	return r.re.MatchString(sub)
+}
+
+// parseIgnoreLine 解析一行 .gitignore 规则
+func parseIgnoreLine(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, "\r")
+	// 去掉未转义的行尾空格
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line, false)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// compileGlob 编译 include/exclude glob；不含 "/" 的模式匹配任意层级
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimSpace(filepath.ToSlash(pattern))
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	expr := globToRegexp(strings.TrimPrefix(pattern, "/"), true)
+	if strings.Contains(pattern, "/") {
+		return regexp.Compile("^" + expr + "$")
+	}
+	return regexp.Compile("^(?:.*/)?" + expr + "$")
+}
+
+// globToRegexp 将 glob 转换为正则表达式（支持 **、*、?、[...]，braces 为 true 时支持 {a,b}）
+func globToRegexp(glob string, braces bool) string {
+	var sb strings.Builder
+	inBrace := false
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				atStart := i == 0 || glob[i-1] == '/'
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' && atStart {
+					// "**/" 匹配零或多级目录
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '{':
+			if braces {
+				inBrace = true
+				sb.WriteString("(?:")
+			} else {
+				sb.WriteString(`\{`)
+			}
+		case '}':
+			if braces && inBrace {
+				inBrace = false
+				sb.WriteString(")")
+			} else {
+				sb.WriteString(`\}`)
+			}
+		case ',':
+			if braces && inBrace {
+				sb.WriteString("|")
+			} else {
+				sb.WriteString(",")
+			}
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
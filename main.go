@@ -15,6 +15,11 @@ import (
 var assets embed.FS
 
 func main() {
+	// 作为 GIT_ASKPASS 辅助程序被 git 调用时，只转发凭据请求
+	if runGitAskpass() {
+		return
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -34,6 +39,7 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 25, G: 22, B: 29, A: 1},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
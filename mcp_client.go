@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// mcpProtocolVersion 客户端请求的 MCP 协议版本
+const mcpProtocolVersion = "2025-06-18"
+
+// mcpDefaultTimeout 未配置超时时探测的默认超时（本地服务器首次启动可能需要下载依赖）
+const mcpDefaultTimeout = 30 * time.Second
+
+// jsonRPCRequest JSON-RPC 请求或通知（ID 为空时为通知）
+type jsonRPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// jsonRPCMessage 收到的 JSON-RPC 消息（响应、服务器请求或通知）
+type jsonRPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+// jsonRPCError JSON-RPC 错误
+type jsonRPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *jsonRPCError) Error() string {
+	return fmt.Sprintf("MCP 错误 %d: %s", e.Code, e.Message)
+}
+
+// errMCPNeedsAuth 远程服务器要求认证
+var errMCPNeedsAuth = errors.New("服务器要求认证")
+
+// mcpTransport MCP 传输层
+type mcpTransport interface {
+	// send 发送请求并等待响应；通知返回 nil
+	send(ctx context.Context, req jsonRPCRequest) (*jsonRPCMessage, error)
+	close() error
+}
+
+// MCPServerInfo 服务器自报的名称和版本
+type MCPServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// MCPToolInfo tools/list 返回的工具
+type MCPToolInfo struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// MCPResourceInfo resources/list 返回的资源
+type MCPResourceInfo struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// MCPPromptInfo prompts/list 返回的提示模板
+type MCPPromptInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Arguments   []struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Required    bool   `json:"required,omitempty"`
+	} `json:"arguments,omitempty"`
+}
+
+// mcpClient 已完成初始化握手的 MCP 客户端
+type mcpClient struct {
+	transport       mcpTransport
+	nextID          int64
+	serverInfo      MCPServerInfo
+	protocolVersion string
+	capabilities    map[string]json.RawMessage
+}
+
+// connectMCP 启动或连接 MCP 服务器并完成 initialize 握手
+func connectMCP(ctx context.Context, server MCPServer, dir string) (*mcpClient, error) {
+	transport, err := newMCPTransport(server, dir)
+	if err != nil {
+		return nil, err
+	}
+	return initializeMCP(ctx, transport)
+}
+
+// newMCPTransport 按服务器类型创建传输层（本地服务器会被启动）
+func newMCPTransport(server MCPServer, dir string) (mcpTransport, error) {
+	if server.Type == "remote" {
+		return newMCPHTTPTransport(server)
+	}
+	return newMCPStdioTransport(server, dir)
+}
+
+// mcpVersionedTransport 需要在握手后携带协议版本的传输层
+type mcpVersionedTransport interface {
+	setProtocolVersion(version string)
+}
+
+// initializeMCP 在传输层上完成握手，失败时关闭传输层
+func initializeMCP(ctx context.Context, transport mcpTransport) (*mcpClient, error) {
+	c := &mcpClient{transport: transport}
+	var init struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo      MCPServerInfo              `json:"serverInfo"`
+	}
+	err := c.call(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "opencode-desktop", "version": "1.0.0"},
+	}, &init)
+	if err == nil {
+		c.serverInfo, c.protocolVersion, c.capabilities = init.ServerInfo, init.ProtocolVersion, init.Capabilities
+		if vt, ok := transport.(mcpVersionedTransport); ok {
+			vt.setProtocolVersion(init.ProtocolVersion)
+		}
+		_, err = transport.send(ctx, jsonRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+	}
+	if err != nil {
+		transport.close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// call 发送请求并将结果解码到 out
+func (c *mcpClient) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	resp, err := c.transport.send(ctx, jsonRPCRequest{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if out != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("解析 %s 响应失败: %v", method, err)
+		}
+	}
+	return nil
+}
+
+// hasCapability 服务器是否声明了某项能力
+func (c *mcpClient) hasCapability(name string) bool {
+	_, ok := c.capabilities[name]
+	return ok
+}
+
+// mcpListResult 分页列表结果
+type mcpListResult struct {
+	Tools      []MCPToolInfo     `json:"tools"`
+	Resources  []MCPResourceInfo `json:"resources"`
+	Prompts    []MCPPromptInfo   `json:"prompts"`
+	NextCursor string            `json:"nextCursor"`
+}
+
+// list 调用 */list 方法并合并所有分页
+func (c *mcpClient) list(ctx context.Context, method string) (*mcpListResult, error) {
+	all := &mcpListResult{}
+	cursor := ""
+	for page := 0; page < 100; page++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var result mcpListResult
+		if err := c.call(ctx, method, params, &result); err != nil {
+			return nil, err
+		}
+		all.Tools = append(all.Tools, result.Tools...)
+		all.Resources = append(all.Resources, result.Resources...)
+		all.Prompts = append(all.Prompts, result.Prompts...)
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	return all, nil
+}
+
+func (c *mcpClient) close() error {
+	return c.transport.close()
+}
+
+// --- stdio 传输 ---
+
+// mcpStdioTransport 通过子进程标准输入输出传输换行分隔的 JSON-RPC 消息
+type mcpStdioTransport struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stderr  *tailBuffer
+	pending map[string]chan *jsonRPCMessage
+	done    chan struct{}
+	err     error // 进程退出原因，done 关闭后可读
+	writeMu sync.Mutex
+	mu      sync.Mutex
+}
+
+// tailBuffer 只保留最后 limit 字节的输出
+type tailBuffer struct {
+	buf   []byte
+	limit int
+	mu    sync.Mutex
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.TrimSpace(string(b.buf))
+}
+
+func newMCPStdioTransport(server MCPServer, dir string) (*mcpStdioTransport, error) {
+	if len(server.Command) == 0 || server.Command[0] == "" {
+		return nil, fmt.Errorf("未配置启动命令")
+	}
+	cmd := exec.Command(server.Command[0], server.Command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range server.Environment {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	hideProcessWindow(cmd)
+	cmd.WaitDelay = 2 * time.Second
+
+	t := &mcpStdioTransport{
+		cmd:     cmd,
+		stderr:  &tailBuffer{limit: 4096},
+		pending: make(map[string]chan *jsonRPCMessage),
+		done:    make(chan struct{}),
+	}
+	cmd.Stderr = t.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("启动 MCP 服务器失败: %v", err)
+	}
+	t.stdin = stdin
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+// readLoop 读取服务器消息，响应分发给等待的请求，服务器请求直接应答
+func (t *mcpStdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue // 部分服务器会向 stdout 打印日志
+		}
+		var msg jsonRPCMessage
+		if json.Unmarshal(line, &msg) != nil {
+			continue
+		}
+		switch {
+		case msg.Method != "" && len(msg.ID) > 0:
+			t.replyServerRequest(msg)
+		case len(msg.ID) > 0:
+			t.mu.Lock()
+			ch, ok := t.pending[string(msg.ID)]
+			delete(t.pending, string(msg.ID))
+			t.mu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+		}
+	}
+
+	err := t.cmd.Wait()
+	if err == nil {
+		err = fmt.Errorf("进程已退出")
+	}
+	if stderr := t.stderr.String(); stderr != "" {
+		err = fmt.Errorf("MCP 服务器已退出: %v: %s", err, stderr)
+	} else {
+		err = fmt.Errorf("MCP 服务器已退出: %v", err)
+	}
+	t.err = err
+	close(t.done)
+}
+
+// replyServerRequest 应答服务器发起的请求（只支持 ping）
+func (t *mcpStdioTransport) replyServerRequest(msg jsonRPCMessage) {
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+	if msg.Method == "ping" {
+		reply["result"] = map[string]interface{}{}
+	} else {
+		reply["error"] = jsonRPCError{Code: -32601, Message: "Method not found"}
+	}
+	t.write(reply)
+}
+
+func (t *mcpStdioTransport) write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *mcpStdioTransport) send(ctx context.Context, req jsonRPCRequest) (*jsonRPCMessage, error) {
+	var ch chan *jsonRPCMessage
+	var key string
+	if req.ID != nil {
+		key = fmt.Sprint(*req.ID)
+		ch = make(chan *jsonRPCMessage, 1)
+		t.mu.Lock()
+		t.pending[key] = ch
+		t.mu.Unlock()
+		defer func() {
+			t.mu.Lock()
+			delete(t.pending, key)
+			t.mu.Unlock()
+		}()
+	}
+	if err := t.write(req); err != nil {
+		select {
+		case <-t.done:
+			return nil, t.err
+		default:
+			return nil, fmt.Errorf("写入 MCP 服务器失败: %v", err)
+		}
+	}
+	if ch == nil {
+		return nil, nil
+	}
+	select {
+	case msg := <-ch:
+		return msg, nil
+	case <-t.done:
+		return nil, t.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s 超时: %v", req.Method, ctx.Err())
+	}
+}
+
+// close 关闭标准输入让服务器自行退出，超时后强制结束整个进程组（npx 等启动器会派生子进程）
+func (t *mcpStdioTransport) close() error {
+	t.stdin.Close()
+	select {
+	case <-t.done:
+	case <-time.After(2 * time.Second):
+		killProcessTree(t.cmd)
+		<-t.done
+	}
+	return nil
+}
+
+// --- Streamable HTTP 传输 ---
+
+// mcpHTTPTransport Streamable HTTP 传输，响应可能是 JSON 或 SSE 流
+type mcpHTTPTransport struct {
+	url             string
+	headers         map[string]string
+	client          *http.Client
+	sessionID       string
+	protocolVersion string
+	mu              sync.Mutex
+}
+
+func newMCPHTTPTransport(server MCPServer) (*mcpHTTPTransport, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("未配置服务器 URL")
+	}
+	return &mcpHTTPTransport{
+		url:     server.URL,
+		headers: server.Headers,
+		client:  &http.Client{},
+	}, nil
+}
+
+func (t *mcpHTTPTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	t.protocolVersion = version
+	t.mu.Unlock()
+}
+
+func (t *mcpHTTPTransport) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set("MCP-Protocol-Version", t.protocolVersion)
+	}
+	t.mu.Unlock()
+	return req, nil
+}
+
+func (t *mcpHTTPTransport) send(ctx context.Context, rpc jsonRPCRequest) (*jsonRPCMessage, error) {
+	body, err := json.Marshal(rpc)
+	if err != nil {
+		return nil, err
+	}
+	req, err := t.newRequest(ctx, "POST", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求 MCP 服务器失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, errMCPNeedsAuth
+	case resp.StatusCode >= 300:
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("MCP 服务器返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if rpc.ID == nil {
+		return nil, nil
+	}
+
+	want := fmt.Sprint(*rpc.ID)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readSSEResponse(resp.Body, want)
+	}
+	var msg jsonRPCMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("解析 MCP 响应失败: %v", err)
+	}
+	return &msg, nil
+}
+
+// readSSEResponse 从 SSE 流中读取指定 ID 的响应
+func readSSEResponse(r io.Reader, id string) (*jsonRPCMessage, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	for {
+		more := scanner.Scan()
+		line := scanner.Text()
+		if more && line != "" {
+			if strings.HasPrefix(line, "data:") {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+			continue
+		}
+		// 空行或流结束时分发事件
+		if data.Len() > 0 {
+			var msg jsonRPCMessage
+			if json.Unmarshal([]byte(data.String()), &msg) == nil && msg.Method == "" && string(msg.ID) == id {
+				return &msg, nil
+			}
+			data.Reset()
+		}
+		if !more {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 MCP 响应失败: %v", err)
+	}
+	return nil, fmt.Errorf("MCP 响应流中没有请求 %s 的结果", id)
+}
+
+// close 结束服务器会话
+func (t *mcpHTTPTransport) close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, err := t.newRequest(ctx, "DELETE", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMCPHelperServer is not a real test: it runs as a fake stdio MCP server when re-executed by other tests
+func TestMCPHelperServer(t *testing.T) {
+	if os.Getenv("MCP_TEST_HELPER") == "" {
+		return
+	}
+	if os.Getenv("MCP_TEST_HELPER") == "crash" {
+		fmt.Fprintln(os.Stderr, "missing API_KEY")
+		os.Exit(3)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	reply := func(id json.RawMessage, result interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result})
+		out.Write(append(data, '\n'))
+		out.Flush()
+	}
+	fmt.Fprintln(out, "starting fake server") // log noise on stdout must be ignored
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg struct {
+			ID     json.RawMessage        `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		json.Unmarshal(scanner.Bytes(), &msg)
+		switch msg.Method {
+		case "initialize":
+			// server-initiated ping before answering
+			fmt.Fprintln(out, `{"jsonrpc":"2.0","id":"srv-1","method":"ping"}`)
+			reply(msg.ID, map[string]interface{}{
+				"protocolVersion": mcpProtocolVersion,
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}, "resources": map[string]interface{}{}},
+				"serverInfo":      map[string]string{"name": "fake", "version": "0.1"},
+			})
+		case "tools/list":
+			if msg.Params["cursor"] == nil {
+				reply(msg.ID, map[string]interface{}{
+					"tools": []map[string]interface{}{{"name": "read_file", "description": "Read a file", "inputSchema": map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+						"required":   []string{"path"},
+					}}},
+					"nextCursor": "page2",
+				})
+			} else {
+				reply(msg.ID, map[string]interface{}{"tools": []map[string]interface{}{{"name": "write.file"}}})
+			}
+		case "tools/call":
+			args, _ := msg.Params["arguments"].(map[string]interface{})
+			if args["path"] == "missing" {
+				reply(msg.ID, map[string]interface{}{"isError": true, "content": []map[string]string{{"type": "text", "text": "not found"}}})
+				break
+			}
+			reply(msg.ID, map[string]interface{}{
+				"content": []map[string]interface{}{
+					{"type": "text", "text": "hello"},
+					{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+					{"type": "resource", "resource": map[string]string{"uri": "file:///a.txt", "text": "hello"}},
+				},
+				"structuredContent": map[string]int{"size": 5},
+			})
+		case "resources/list":
+			data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID, "error": map[string]interface{}{"code": -32603, "message": "boom"}})
+			out.Write(append(data, '\n'))
+			out.Flush()
+		}
+	}
+	os.Exit(0)
+}
+
+// helperServer returns a local MCPServer that re-executes the test binary as a fake MCP server
+func helperServer(mode string) MCPServer {
+	return MCPServer{
+		Type:        "local",
+		Command:     []string{os.Args[0], "-test.run=^TestMCPHelperServer$"},
+		Environment: map[string]string{"MCP_TEST_HELPER": mode},
+		Enabled:     true,
+		Timeout:     10000,
+	}
+}
+
+// TestProbeMCPStdio tests the stdio handshake, pagination, capability filtering and list errors
+func TestProbeMCPStdio(t *testing.T) {
+	result := probeMCPServer(context.Background(), "fake", helperServer("1"), "")
+	if result.Status != "connected" {
+		t.Fatalf("status = %q error = %q", result.Status, result.Error)
+	}
+	if result.ServerInfo.Name != "fake" || result.ProtocolVersion != mcpProtocolVersion {
+		t.Errorf("serverInfo = %+v protocol = %q", result.ServerInfo, result.ProtocolVersion)
+	}
+	if len(result.Tools) != 2 || result.Tools[0].Name != "read_file" || result.Tools[1].Name != "write.file" {
+		t.Errorf("tools = %+v", result.Tools)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "resources/list") {
+		t.Errorf("warnings = %v", result.Warnings)
+	}
+	if len(result.Prompts) != 0 {
+		t.Errorf("prompts should not be listed without capability: %+v", result.Prompts)
+	}
+}
+
+// TestProbeMCPStdioCrash tests that a server exiting during startup reports its stderr
+func TestProbeMCPStdioCrash(t *testing.T) {
+	result := probeMCPServer(context.Background(), "fake", helperServer("crash"), "")
+	if result.Status != "failed" || !strings.Contains(result.Error, "missing API_KEY") {
+		t.Errorf("status = %q error = %q", result.Status, result.Error)
+	}
+
+	result = probeMCPServer(context.Background(), "none", MCPServer{Type: "local"}, "")
+	if result.Status != "failed" {
+		t.Errorf("status without command = %q", result.Status)
+	}
+}
+
+// TestProbeMCPHTTP tests the streamable HTTP transport with JSON and SSE responses and session headers
+func TestProbeMCPHTTP(t *testing.T) {
+	var sawSession, sawHeader bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			return
+		}
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&msg)
+		if r.Header.Get("Authorization") == "Bearer secret" {
+			sawHeader = true
+		}
+		switch msg.Method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "sess-1")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"remote","version":"2"}}}`, msg.ID)
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+		case "tools/list":
+			sawSession = r.Header.Get("Mcp-Session-Id") == "sess-1" && r.Header.Get("MCP-Protocol-Version") == "2025-06-18"
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%s,\n", msg.ID)
+			fmt.Fprint(w, "data: \"result\":{\"tools\":[{\"name\":\"search\"}]}}\n\n")
+		}
+	}))
+	defer srv.Close()
+
+	server := MCPServer{Type: "remote", URL: srv.URL, Enabled: true, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	result := probeMCPServer(context.Background(), "remote", server, "")
+	if result.Status != "connected" {
+		t.Fatalf("status = %q error = %q", result.Status, result.Error)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "search" {
+		t.Errorf("tools = %+v", result.Tools)
+	}
+	if !sawSession || !sawHeader {
+		t.Errorf("sawSession = %v sawHeader = %v", sawSession, sawHeader)
+	}
+}
+
+// TestProbeMCPHTTPNeedsAuth tests that 401 responses map to needs_auth
+func TestProbeMCPHTTPNeedsAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	result := probeMCPServer(context.Background(), "remote", MCPServer{Type: "remote", URL: srv.URL}, "")
+	if result.Status != "needs_auth" {
+		t.Errorf("status = %q error = %q", result.Status, result.Error)
+	}
+}
+
+// TestMCPManagerCheckAll tests that disabled servers are not started and results are cached
+func TestMCPManagerCheckAll(t *testing.T) {
+	mm := NewMCPManager(&App{})
+	config := &MCPConfig{MCP: map[string]MCPServer{
+		"fake": helperServer("1"),
+		"off":  {Type: "local", Command: []string{"does-not-exist"}},
+	}}
+
+	results := mm.checkAll(config, mcpProbeMaxAge)
+	if results["off"].Status != "disabled" || results["fake"].Status != "connected" {
+		t.Fatalf("results = %+v", results)
+	}
+	again := mm.checkAll(config, mcpProbeMaxAge)
+	if again["fake"] != results["fake"] {
+		t.Error("cached result not reused")
+	}
+	mm.reset()
+	if mm.cached("fake", mcpProbeMaxAge) != nil {
+		t.Error("reset() did not clear cache")
+	}
+
+	tools := mcpToolsFromProbes(results)
+	if len(tools) != 2 || tools[1].ID != "mcp_fake_write_file" || tools[1].Name != "write.file" || tools[1].Server != "fake" {
+		t.Errorf("tools = %+v", tools)
+	}
+}
+
+// TestMCPManagerCachedAll tests that cached lookups do not block and refresh in the background
+func TestMCPManagerCachedAll(t *testing.T) {
+	mm := NewMCPManager(&App{})
+	config := &MCPConfig{MCP: map[string]MCPServer{"fake": helperServer("1")}}
+
+	if results := mm.cachedAll(config, mcpProbeMaxAge); len(results) != 0 {
+		t.Fatalf("Expected no results before the first probe, got %+v", results)
+	}
+	for i := 0; i < 200 && mm.cached("fake", mcpProbeMaxAge) == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if results := mm.cachedAll(config, mcpProbeMaxAge); results["fake"] == nil || results["fake"].Status != "connected" {
+		t.Errorf("results after background probe = %+v", results)
+	}
+}
+
+// TestReadSSEResponse tests selecting the matching response from an SSE stream
+func TestReadSSEResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  string
+		wantErr bool
+	}{
+		{"match", "data: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{}}\n\n", false},
+		{"no trailing blank line", "data: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{}}", false},
+		{"other id", "data: {\"jsonrpc\":\"2.0\",\"id\":8,\"result\":{}}\n\n", true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := readSSEResponse(strings.NewReader(tt.stream), "7")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(msg.ID) != "7" {
+				t.Errorf("id = %s", msg.ID)
+			}
+		})
+	}
+}
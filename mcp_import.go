@@ -0,0 +1,596 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 支持导入导出的外部配置格式
+const (
+	MCPFormatClaude = "claude" // Claude Desktop: claude_desktop_config.json 中的 mcpServers
+	MCPFormatCursor = "cursor" // Cursor: .cursor/mcp.json 中的 mcpServers
+	MCPFormatVSCode = "vscode" // VS Code: .vscode/mcp.json 中的 servers，或 settings.json 中的 mcp.servers
+	MCPFormatJSON   = "json"   // 通用的 {"mcpServers": {...}} 片段
+
+	MCPFormatVSCodeSettings = "vscode-settings" // 导出到 VS Code 用户设置 settings.json 中的 mcp.servers
+)
+
+// MCPImportSource 检测到的外部配置文件
+type MCPImportSource struct {
+	Format string `json:"format"`
+	Label  string `json:"label"`
+	Path   string `json:"path"`
+	Count  int    `json:"count"` // 文件中的服务器数量
+}
+
+// MCPImportEntry 待导入的服务器
+type MCPImportEntry struct {
+	Name      string     `json:"name"`
+	Server    MCPServer  `json:"server"`
+	Conflict  bool       `json:"conflict"`  // 已存在同名服务器
+	Identical bool       `json:"identical"` // 与已存在的配置完全相同
+	Existing  *MCPServer `json:"existing,omitempty"`
+	Warnings  []string   `json:"warnings"`
+}
+
+// MCPImportPreview 导入预览
+type MCPImportPreview struct {
+	Format  string           `json:"format"`
+	Entries []MCPImportEntry `json:"entries"`
+}
+
+// MCPImportResult 导入结果
+type MCPImportResult struct {
+	Imported []string          `json:"imported"`
+	Skipped  []string          `json:"skipped"`
+	Renamed  map[string]string `json:"renamed"` // 原名称 -> 导入后的名称
+	Failed   map[string]string `json:"failed"`  // 写入配置失败的服务器 -> 错误信息
+}
+
+// MCPExportResult 导出结果
+type MCPExportResult struct {
+	Content  string   `json:"content"`
+	Warnings []string `json:"warnings"`
+}
+
+// mcpExternalServer 外部工具使用的服务器定义（各格式字段的并集）
+type mcpExternalServer struct {
+	Type     string            `json:"type,omitempty"` // stdio, sse, http
+	Command  string            `json:"command,omitempty"`
+	Args     []string          `json:"args,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Disabled bool              `json:"disabled,omitempty"`
+}
+
+var (
+	// VS Code 和 Cursor 的环境变量写法 ${env:NAME}
+	mcpExternalEnvRe = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+	// Claude 的环境变量写法 ${NAME}；VS Code 和 Cursor 中同样的写法是 ${workspaceFolder} 等预定义变量
+	mcpExternalBareVarRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	// VS Code 的输入变量 ${input:id}，需要用户在 VS Code 中交互填写
+	mcpExternalInputRe = regexp.MustCompile(`\$\{input:[^}]+\}`)
+	// OpenCode 的环境变量引用 {env:NAME}
+	mcpOpenCodeEnvRe = regexp.MustCompile(`\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// stripJSONC 去掉 JSONC 中的注释和尾随逗号（VS Code 的 settings.json 使用该格式）
+func stripJSONC(data []byte) []byte {
+	var out bytes.Buffer
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ',':
+			// 后面只有空白就遇到 } 或 ] 时是尾随逗号
+			j := i + 1
+			for j < len(data) && (data[j] == ' ' || data[j] == '\t' || data[j] == '\n' || data[j] == '\r') {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				continue
+			}
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// parseExternalMCPConfig 解析外部配置，format 为空时根据内容判断
+// 支持 {"mcpServers": {...}}、{"servers": {...}}、{"mcp": {"servers": {...}}} 以及直接粘贴的 {"name": {...}}
+func parseExternalMCPConfig(format string, data []byte) (string, map[string]mcpExternalServer, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(stripJSONC(data), &root); err != nil {
+		return "", nil, fmt.Errorf("解析 MCP 配置失败: %v", err)
+	}
+
+	var raw json.RawMessage
+	detected := MCPFormatJSON
+	switch {
+	case root["mcpServers"] != nil:
+		raw = root["mcpServers"]
+	case root["servers"] != nil:
+		raw, detected = root["servers"], MCPFormatVSCode
+	case root["mcp"] != nil:
+		var nested struct {
+			Servers json.RawMessage `json:"servers"`
+		}
+		if json.Unmarshal(root["mcp"], &nested) == nil && nested.Servers != nil {
+			raw, detected = nested.Servers, MCPFormatVSCode
+		}
+	case root["mcp.servers"] != nil:
+		raw, detected = root["mcp.servers"], MCPFormatVSCode
+	}
+	if raw == nil {
+		// 直接粘贴的服务器表，每一项都需要有 command 或 url
+		for _, v := range root {
+			var s mcpExternalServer
+			if json.Unmarshal(v, &s) != nil || (s.Command == "" && s.URL == "") {
+				return "", nil, fmt.Errorf("未找到 mcpServers 或 servers 配置")
+			}
+		}
+		raw, _ = json.Marshal(root)
+	}
+
+	servers := make(map[string]mcpExternalServer)
+	if err := json.Unmarshal(raw, &servers); err != nil {
+		return "", nil, fmt.Errorf("解析 MCP 服务器列表失败: %v", err)
+	}
+	if format == "" {
+		format = detected
+	}
+	return format, servers, nil
+}
+
+// convertExternalValue 将外部变量写法转换为 OpenCode 的 {env:NAME}
+// ${NAME} 只在 Claude 格式中表示环境变量；其他格式中可能是 ${workspaceFolder} 等编辑器预定义变量，保留原样并提示
+func convertExternalValue(format, value string, warn func(string)) string {
+	if mcpExternalInputRe.MatchString(value) {
+		warn(fmt.Sprintf("%s 使用了 VS Code 输入变量，需要手动填写", value))
+	}
+	value = mcpExternalEnvRe.ReplaceAllString(value, "{env:$1}")
+	if format == MCPFormatClaude {
+		return mcpExternalBareVarRe.ReplaceAllString(value, "{env:$1}")
+	}
+	if mcpExternalBareVarRe.MatchString(value) {
+		warn(fmt.Sprintf("%s 使用了编辑器的预定义变量，OpenCode 不会展开，需要手动修改", value))
+	}
+	return value
+}
+
+// externalToMCPServer 将外部定义转换为 MCPServer
+func externalToMCPServer(format string, ext mcpExternalServer) (MCPServer, []string) {
+	warnings := []string{}
+	warn := func(msg string) { warnings = append(warnings, msg) }
+	convertMap := func(values map[string]string) map[string]string {
+		if len(values) == 0 {
+			return nil
+		}
+		out := make(map[string]string, len(values))
+		for k, v := range values {
+			out[k] = convertExternalValue(format, v, warn)
+		}
+		return out
+	}
+
+	server := MCPServer{Enabled: !ext.Disabled, Environment: convertMap(ext.Env)}
+	if ext.URL != "" && ext.Command == "" {
+		server.Type = "remote"
+		server.URL = convertExternalValue(format, ext.URL, warn)
+		server.Headers = convertMap(ext.Headers)
+		if ext.Type == "sse" {
+			warn("SSE 传输已被 MCP 规范弃用，请确认服务器同时支持 Streamable HTTP")
+		}
+		return server, warnings
+	}
+	server.Type = "local"
+	server.Command = []string{convertExternalValue(format, ext.Command, warn)}
+	for _, arg := range ext.Args {
+		server.Command = append(server.Command, convertExternalValue(format, arg, warn))
+	}
+	if ext.Command == "" {
+		warn("缺少启动命令")
+	}
+	return server, warnings
+}
+
+// exportValue 将 OpenCode 的引用转换为目标格式的写法
+func exportValue(format, value string, warn func(string)) string {
+	if mcpSecretRefRe.MatchString(value) {
+		warn(fmt.Sprintf("%s 引用了桌面端保存的密钥，导出后需要手动填写", value))
+	}
+	if format == MCPFormatVSCode || format == MCPFormatCursor {
+		return mcpOpenCodeEnvRe.ReplaceAllString(value, "${env:$1}")
+	}
+	if mcpOpenCodeEnvRe.MatchString(value) {
+		warn(fmt.Sprintf("%s 在目标工具中不会展开环境变量", value))
+	}
+	return value
+}
+
+// mcpServerToExternal 将 MCPServer 转换为目标格式的定义
+func mcpServerToExternal(format string, server MCPServer) (mcpExternalServer, []string) {
+	warnings := []string{}
+	warn := func(msg string) { warnings = append(warnings, msg) }
+	exportMap := func(values map[string]string) map[string]string {
+		if len(values) == 0 {
+			return nil
+		}
+		out := make(map[string]string, len(values))
+		for k, v := range values {
+			out[k] = exportValue(format, v, warn)
+		}
+		return out
+	}
+
+	ext := mcpExternalServer{Env: exportMap(server.Environment)}
+	if server.Type == "remote" {
+		if format == MCPFormatClaude {
+			// Claude Desktop 只支持 stdio，通过 mcp-remote 桥接远程服务器
+			ext.Command = "npx"
+			ext.Args = []string{"-y", "mcp-remote", exportValue(format, server.URL, warn)}
+			keys := make([]string, 0, len(server.Headers))
+			for k := range server.Headers {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				ext.Args = append(ext.Args, "--header", k+": "+exportValue(format, server.Headers[k], warn))
+			}
+			warn("Claude Desktop 不支持远程服务器，已通过 mcp-remote 桥接")
+			return ext, warnings
+		}
+		ext.URL = exportValue(format, server.URL, warn)
+		ext.Headers = exportMap(server.Headers)
+		if format == MCPFormatVSCode {
+			ext.Type = "http"
+		}
+		return ext, warnings
+	}
+	if len(server.Command) > 0 {
+		ext.Command = exportValue(format, server.Command[0], warn)
+		for _, arg := range server.Command[1:] {
+			ext.Args = append(ext.Args, exportValue(format, arg, warn))
+		}
+	}
+	if format == MCPFormatVSCode {
+		ext.Type = "stdio"
+	}
+	return ext, warnings
+}
+
+// exportMCPServers 生成目标格式的配置内容
+func exportMCPServers(format string, servers map[string]MCPServer) (map[string]mcpExternalServer, []string) {
+	if format == MCPFormatVSCodeSettings {
+		format = MCPFormatVSCode
+	}
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make(map[string]mcpExternalServer, len(servers))
+	warnings := []string{}
+	for _, name := range names {
+		ext, warns := mcpServerToExternal(format, servers[name])
+		out[name] = ext
+		for _, w := range warns {
+			warnings = append(warnings, name+": "+w)
+		}
+	}
+	return out, warnings
+}
+
+// mcpServersPath 目标格式中保存服务器列表的字段路径
+func mcpServersPath(format string) []string {
+	switch format {
+	case MCPFormatVSCode:
+		return []string{"servers"}
+	case MCPFormatVSCodeSettings:
+		return []string{"mcp", "servers"}
+	}
+	return []string{"mcpServers"}
+}
+
+// previewMCPImport 对比已有配置生成导入预览
+func previewMCPImport(format string, external map[string]mcpExternalServer, existing map[string]MCPServer) *MCPImportPreview {
+	preview := &MCPImportPreview{Format: format, Entries: []MCPImportEntry{}}
+	for name, ext := range external {
+		server, warnings := externalToMCPServer(format, ext)
+		entry := MCPImportEntry{Name: name, Server: server, Warnings: warnings}
+		if old, ok := existing[name]; ok {
+			old := old
+			entry.Conflict = true
+			entry.Existing = &old
+			entry.Identical = reflect.DeepEqual(old, server)
+		}
+		preview.Entries = append(preview.Entries, entry)
+	}
+	sort.Slice(preview.Entries, func(i, j int) bool { return preview.Entries[i].Name < preview.Entries[j].Name })
+	return preview
+}
+
+// resolveMCPImportName 按冲突策略决定导入后的名称，返回空字符串表示跳过
+// conflict 为 skip（默认）、overwrite 或 rename
+func resolveMCPImportName(name, conflict string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	switch conflict {
+	case "overwrite":
+		return name
+	case "rename":
+		for i := 2; ; i++ {
+			candidate := fmt.Sprintf("%s-%d", name, i)
+			if !taken[candidate] {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// mcpImportSourcePaths 各工具配置文件的常见位置
+func mcpImportSourcePaths(homeDir, configDir, workDir string) []MCPImportSource {
+	claudeDir := filepath.Join(configDir, "Claude")
+	codeDir := filepath.Join(configDir, "Code", "User")
+	sources := []MCPImportSource{
+		{Format: MCPFormatClaude, Label: "Claude Desktop", Path: filepath.Join(claudeDir, "claude_desktop_config.json")},
+		{Format: MCPFormatCursor, Label: "Cursor（全局）", Path: filepath.Join(homeDir, ".cursor", "mcp.json")},
+		{Format: MCPFormatVSCode, Label: "VS Code（用户）", Path: filepath.Join(codeDir, "mcp.json")},
+		{Format: MCPFormatVSCode, Label: "VS Code（用户设置）", Path: filepath.Join(codeDir, "settings.json")},
+	}
+	if workDir != "" {
+		sources = append(sources,
+			MCPImportSource{Format: MCPFormatCursor, Label: "Cursor（项目）", Path: filepath.Join(workDir, ".cursor", "mcp.json")},
+			MCPImportSource{Format: MCPFormatVSCode, Label: "VS Code（项目）", Path: filepath.Join(workDir, ".vscode", "mcp.json")},
+		)
+	}
+	return sources
+}
+
+// readMCPImport 读取粘贴的内容或文件
+func readMCPImport(format, path, content string) (string, map[string]mcpExternalServer, error) {
+	data := []byte(content)
+	if strings.TrimSpace(content) == "" {
+		if path == "" {
+			return "", nil, fmt.Errorf("请选择配置文件或粘贴配置内容")
+		}
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return "", nil, fmt.Errorf("读取配置失败: %v", err)
+		}
+	}
+	return parseExternalMCPConfig(format, data)
+}
+
+// --- App API ---
+
+// GetMCPImportSources 列出本机已存在且包含 MCP 服务器的外部配置文件
+func (a *App) GetMCPImportSources() []MCPImportSource {
+	homeDir, _ := os.UserHomeDir()
+	configDir, _ := os.UserConfigDir()
+	sources := []MCPImportSource{}
+	for _, source := range mcpImportSourcePaths(homeDir, configDir, a.mcpMgr.workDir()) {
+		data, err := os.ReadFile(source.Path)
+		if err != nil {
+			continue
+		}
+		if _, servers, err := parseExternalMCPConfig(source.Format, data); err == nil && len(servers) > 0 {
+			source.Count = len(servers)
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// PreviewMCPImport 解析外部配置（文件路径或粘贴的内容），标出与已有服务器的冲突
+func (a *App) PreviewMCPImport(format, path, content string) (*MCPImportPreview, error) {
+	format, external, err := readMCPImport(format, path, content)
+	if err != nil {
+		return nil, err
+	}
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	return previewMCPImport(format, external, config.MCP), nil
+}
+
+// ImportMCPServers 导入选中的服务器（names 为空时导入全部），conflict 为 skip、overwrite 或 rename
+// 明文的环境变量和请求头会移入加密存储
+func (a *App) ImportMCPServers(format, path, content string, names []string, conflict, layer string) (*MCPImportResult, error) {
+	format, external, err := readMCPImport(format, path, content)
+	if err != nil {
+		return nil, err
+	}
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		for name := range external {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	taken := make(map[string]bool)
+	for name := range config.MCP {
+		taken[name] = true
+	}
+	result := &MCPImportResult{Imported: []string{}, Skipped: []string{}, Renamed: map[string]string{}, Failed: map[string]string{}}
+	for _, name := range names {
+		ext, ok := external[name]
+		if !ok {
+			return nil, fmt.Errorf("配置中没有该服务器: %s", name)
+		}
+		target := resolveMCPImportName(name, conflict, taken)
+		if target == "" {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		server, _ := externalToMCPServer(format, ext)
+		// 外部配置中明文的环境变量和请求头通常是令牌，加密保存后配置中只写引用
+		if a.mcpSecrets != nil {
+			if _, err := a.mcpSecrets.moveMCPValuesToSecrets(target, server); err != nil {
+				result.Failed[name] = err.Error()
+				continue
+			}
+		}
+		if err := a.saveMCPServer(target, server, layer); err != nil {
+			result.Failed[name] = err.Error()
+			continue
+		}
+		if _, err := a.syncMCPServer(target, server); err != nil {
+			// OpenCode 未运行时配置已写入，启动后会自动加载
+			a.emitEvent("output-log", fmt.Sprintf("同步 MCP 服务器 %s 失败: %v", target, err))
+		}
+		taken[target] = true
+		result.Imported = append(result.Imported, target)
+		if target != name {
+			result.Renamed[name] = target
+		}
+	}
+	return result, nil
+}
+
+// ExportMCPServers 将服务器导出为其他工具的配置格式（names 为空时导出全部已启用的服务器）
+func (a *App) ExportMCPServers(format string, names []string) (*MCPExportResult, error) {
+	selected, err := a.selectMCPServers(names)
+	if err != nil {
+		return nil, err
+	}
+	servers, warnings := exportMCPServers(format, selected)
+	var root interface{} = servers
+	keys := mcpServersPath(format)
+	for i := len(keys) - 1; i >= 0; i-- {
+		root = map[string]interface{}{keys[i]: root}
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	return &MCPExportResult{Content: string(data), Warnings: warnings}, nil
+}
+
+// ExportMCPServersToFile 将服务器合并写入其他工具的配置文件，保留文件中的其他字段和服务器
+// VS Code 的 .vscode/mcp.json 使用 vscode 格式，用户设置 settings.json 使用 vscode-settings 格式
+func (a *App) ExportMCPServersToFile(format, path string, names []string) (*MCPExportResult, error) {
+	if format == MCPFormatVSCode && strings.EqualFold(filepath.Base(path), "settings.json") {
+		return nil, fmt.Errorf("settings.json 需使用 VS Code 用户设置格式导出")
+	}
+	selected, err := a.selectMCPServers(names)
+	if err != nil {
+		return nil, err
+	}
+	root := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if !bytes.Equal(stripJSONC(data), data) {
+			return nil, fmt.Errorf("%s 包含注释，写入会丢失注释，请复制导出内容手动粘贴", filepath.Base(path))
+		}
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取配置失败: %v", err)
+	}
+
+	// 逐层找到服务器列表所在的对象，缺失时创建
+	parent := root
+	keys := mcpServersPath(format)
+	for _, key := range keys[:len(keys)-1] {
+		child, _ := parent[key].(map[string]interface{})
+		if child == nil {
+			child = make(map[string]interface{})
+			parent[key] = child
+		}
+		parent = child
+	}
+	key := keys[len(keys)-1]
+	existing, _ := parent[key].(map[string]interface{})
+	if existing == nil {
+		existing = make(map[string]interface{})
+	}
+	servers, warnings := exportMCPServers(format, selected)
+	for name, ext := range servers {
+		existing[name] = ext
+	}
+	parent[key] = existing
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("写入配置失败: %v", err)
+	}
+	a.emitEvent("output-log", fmt.Sprintf("已导出 %d 个 MCP 服务器到 %s", len(servers), path))
+	return &MCPExportResult{Content: string(data), Warnings: warnings}, nil
+}
+
+// selectMCPServers 按名称选取服务器，names 为空时选取全部已启用的服务器
+func (a *App) selectMCPServers(names []string) (map[string]MCPServer, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]MCPServer)
+	if len(names) == 0 {
+		for name, server := range config.MCP {
+			if server.Enabled {
+				selected[name] = server
+			}
+		}
+		return selected, nil
+	}
+	for _, name := range names {
+		server, ok := config.MCP[name]
+		if !ok {
+			return nil, fmt.Errorf("MCP 服务器不存在: %s", name)
+		}
+		selected[name] = server
+	}
+	return selected, nil
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStripJSONC tests removing comments and trailing commas outside strings
+func TestStripJSONC(t *testing.T) {
+	input := `{
+  // line comment
+  "url": "http://a//b", /* block */
+  "list": [1, 2,],
+  "s": "a,}",
+}`
+	var v map[string]interface{}
+	if err := json.Unmarshal(stripJSONC([]byte(input)), &v); err != nil {
+		t.Fatalf("err = %v\n%s", err, stripJSONC([]byte(input)))
+	}
+	if v["url"] != "http://a//b" || v["s"] != "a,}" || len(v["list"].([]interface{})) != 2 {
+		t.Errorf("v = %v", v)
+	}
+}
+
+// TestParseExternalMCPConfig tests format detection and mapping for each supported layout
+func TestParseExternalMCPConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		input      string
+		wantFormat string
+		wantName   string
+		want       MCPServer
+		warnings   int
+	}{
+		{
+			name:       "claude desktop",
+			input:      `{"mcpServers":{"fs":{"command":"npx","args":["-y","server-fs","/tmp"],"env":{"KEY":"v"}}}}`,
+			format:     MCPFormatClaude,
+			wantFormat: MCPFormatClaude,
+			wantName:   "fs",
+			want:       MCPServer{Type: "local", Command: []string{"npx", "-y", "server-fs", "/tmp"}, Environment: map[string]string{"KEY": "v"}, Enabled: true},
+		},
+		{
+			name:       "cursor remote",
+			input:      `{"mcpServers":{"remote":{"url":"https://x/mcp","headers":{"Authorization":"Bearer ${env:TOKEN}"},"disabled":true}}}`,
+			wantFormat: MCPFormatJSON,
+			wantName:   "remote",
+			want:       MCPServer{Type: "remote", URL: "https://x/mcp", Headers: map[string]string{"Authorization": "Bearer {env:TOKEN}"}},
+		},
+		{
+			name:       "vscode mcp.json",
+			input:      `{"inputs":[],"servers":{"gh":{"type":"http","url":"https://api/mcp","headers":{"X":"${input:token}"}}}}`,
+			wantFormat: MCPFormatVSCode,
+			wantName:   "gh",
+			want:       MCPServer{Type: "remote", URL: "https://api/mcp", Headers: map[string]string{"X": "${input:token}"}, Enabled: true},
+			warnings:   1,
+		},
+		{
+			name: "vscode settings.json",
+			input: `{
+  "editor.fontSize": 14, // comment
+  "mcp": {"servers": {"py": {"type": "stdio", "command": "uvx", "args": ["srv"], "env": {"HOME_DIR": "${HOME}"},},},},
+}`,
+			wantFormat: MCPFormatVSCode,
+			wantName:   "py",
+			want:       MCPServer{Type: "local", Command: []string{"uvx", "srv"}, Environment: map[string]string{"HOME_DIR": "${HOME}"}, Enabled: true},
+			warnings:   1,
+		},
+		{
+			name:       "claude bare variable",
+			input:      `{"mcpServers":{"fs":{"command":"fs","args":["${ROOT}"],"env":{"KEY":"${env:KEY}"}}}}`,
+			format:     MCPFormatClaude,
+			wantFormat: MCPFormatClaude,
+			wantName:   "fs",
+			want:       MCPServer{Type: "local", Command: []string{"fs", "{env:ROOT}"}, Environment: map[string]string{"KEY": "{env:KEY}"}, Enabled: true},
+		},
+		{
+			name:       "cursor workspace folder",
+			input:      `{"mcpServers":{"fs":{"command":"fs","args":["${workspaceFolder}"]}}}`,
+			format:     MCPFormatCursor,
+			wantFormat: MCPFormatCursor,
+			wantName:   "fs",
+			want:       MCPServer{Type: "local", Command: []string{"fs", "${workspaceFolder}"}, Enabled: true},
+			warnings:   1,
+		},
+		{
+			name:       "pasted server map",
+			input:      `{"sse":{"type":"sse","url":"https://s/sse"}}`,
+			wantFormat: MCPFormatJSON,
+			wantName:   "sse",
+			want:       MCPServer{Type: "remote", URL: "https://s/sse", Enabled: true},
+			warnings:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, servers, err := parseExternalMCPConfig(tt.format, []byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			server, warnings := externalToMCPServer(format, servers[tt.wantName])
+			if !reflect.DeepEqual(server, tt.want) {
+				t.Errorf("server = %+v, want %+v", server, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("warnings = %v", warnings)
+			}
+		})
+	}
+
+	for _, bad := range []string{`not json`, `{"theme":"dark"}`, `{"mcpServers":[1]}`} {
+		if _, _, err := parseExternalMCPConfig("", []byte(bad)); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
+
+// TestPreviewMCPImport tests conflict and identical detection plus rename/skip/overwrite naming
+func TestPreviewMCPImport(t *testing.T) {
+	existing := map[string]MCPServer{
+		"same":  {Type: "local", Command: []string{"a"}, Enabled: true},
+		"other": {Type: "local", Command: []string{"b"}, Enabled: true},
+	}
+	external := map[string]mcpExternalServer{
+		"same":  {Command: "a"},
+		"other": {Command: "c"},
+		"new":   {Command: "d"},
+	}
+	preview := previewMCPImport(MCPFormatCursor, external, existing)
+	got := map[string][2]bool{}
+	for _, e := range preview.Entries {
+		got[e.Name] = [2]bool{e.Conflict, e.Identical}
+	}
+	want := map[string][2]bool{"new": {false, false}, "other": {true, false}, "same": {true, true}}
+	if !reflect.DeepEqual(got, want) || preview.Entries[0].Name != "new" {
+		t.Errorf("entries = %+v", preview.Entries)
+	}
+
+	taken := map[string]bool{"x": true, "x-2": true}
+	tests := []struct {
+		conflict string
+		want     string
+	}{
+		{"skip", ""},
+		{"", ""},
+		{"overwrite", "x"},
+		{"rename", "x-3"},
+	}
+	for _, tt := range tests {
+		if got := resolveMCPImportName("x", tt.conflict, taken); got != tt.want {
+			t.Errorf("resolveMCPImportName(%q) = %q, want %q", tt.conflict, got, tt.want)
+		}
+	}
+	if resolveMCPImportName("free", "skip", taken) != "free" {
+		t.Error("free name should be kept")
+	}
+}
+
+// TestExportMCPServers tests the reverse mapping and that exports can be imported again
+func TestExportMCPServers(t *testing.T) {
+	servers := map[string]MCPServer{
+		"fs":     {Type: "local", Command: []string{"npx", "srv"}, Environment: map[string]string{"TOKEN": "{env:GH}", "KEY": "{secret:k}"}, Enabled: true},
+		"remote": {Type: "remote", URL: "https://r/mcp", Headers: map[string]string{"X-Key": "abc"}, Enabled: true},
+	}
+
+	vscode, warnings := exportMCPServers(MCPFormatVSCode, servers)
+	if vscode["fs"].Type != "stdio" || vscode["fs"].Env["TOKEN"] != "${env:GH}" || vscode["remote"].Type != "http" {
+		t.Errorf("vscode = %+v", vscode)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "fs:") {
+		t.Errorf("warnings = %v", warnings)
+	}
+
+	claude, warnings := exportMCPServers(MCPFormatClaude, servers)
+	want := []string{"-y", "mcp-remote", "https://r/mcp", "--header", "X-Key: abc"}
+	if claude["remote"].Command != "npx" || !reflect.DeepEqual(claude["remote"].Args, want) {
+		t.Errorf("claude remote = %+v", claude["remote"])
+	}
+	if len(warnings) != 3 {
+		t.Errorf("warnings = %v", warnings)
+	}
+
+	// round trip through the cursor format
+	cursor, _ := exportMCPServers(MCPFormatCursor, servers)
+	data, _ := json.Marshal(map[string]interface{}{"mcpServers": cursor})
+	_, parsed, err := parseExternalMCPConfig(MCPFormatCursor, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, server := range servers {
+		back, _ := externalToMCPServer(MCPFormatCursor, parsed[name])
+		if !reflect.DeepEqual(back, server) {
+			t.Errorf("%s round trip = %+v, want %+v", name, back, server)
+		}
+	}
+}
+
+// TestExportMCPServersToFile tests merging into an existing file and refusing files with comments
+func TestExportMCPServersToFile(t *testing.T) {
+	home, work := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(home, ".opencode"), 0755)
+	os.WriteFile(filepath.Join(home, ".opencode", "config.json"), []byte(`{"mcp":{"fs":{"type":"local","command":["fs"],"enabled":true}}}`), 0644)
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	a := &App{}
+	a.mcpMgr = NewMCPManager(a)
+
+	target := filepath.Join(work, "mcp.json")
+	os.WriteFile(target, []byte(`{"mcpServers":{"keep":{"command":"k"}},"other":1}`), 0644)
+	if _, err := a.ExportMCPServersToFile(MCPFormatCursor, target, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(target)
+	var root struct {
+		MCPServers map[string]mcpExternalServer `json:"mcpServers"`
+		Other      int                          `json:"other"`
+	}
+	json.Unmarshal(data, &root)
+	if root.Other != 1 || root.MCPServers["keep"].Command != "k" || root.MCPServers["fs"].Command != "fs" {
+		t.Errorf("file = %s", data)
+	}
+
+	settings := filepath.Join(work, "settings.json")
+	os.WriteFile(settings, []byte(`{"editor.fontSize":12,"mcp":{"inputs":[]}}`), 0644)
+	if _, err := a.ExportMCPServersToFile(MCPFormatVSCode, settings, nil); err == nil {
+		t.Error("expected error for mcp.json format written to settings.json")
+	}
+	if _, err := a.ExportMCPServersToFile(MCPFormatVSCodeSettings, settings, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(settings)
+	var vscode struct {
+		FontSize int `json:"editor.fontSize"`
+		MCP      struct {
+			Inputs  []interface{}                `json:"inputs"`
+			Servers map[string]mcpExternalServer `json:"servers"`
+		} `json:"mcp"`
+	}
+	json.Unmarshal(data, &vscode)
+	if vscode.FontSize != 12 || vscode.MCP.Inputs == nil || vscode.MCP.Servers["fs"].Type != "stdio" {
+		t.Errorf("settings = %s", data)
+	}
+
+	commented := filepath.Join(work, "commented.json")
+	os.WriteFile(commented, []byte("{\n// c\n}"), 0644)
+	if _, err := a.ExportMCPServersToFile(MCPFormatVSCodeSettings, commented, nil); err == nil {
+		t.Error("expected error for file with comments")
+	}
+}
+
+// TestImportMCPServersReportsWriteFailures tests that servers whose config write fails are not reported as imported
+func TestImportMCPServersReportsWriteFailures(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	a := &App{}
+	a.mcpMgr = NewMCPManager(a)
+
+	result, err := a.ImportMCPServers(MCPFormatCursor, "", `{"mcpServers":{"fs":{"command":"fs"}}}`, nil, "skip", "missing-layer")
+	if err != nil {
+		t.Fatalf("ImportMCPServers() error = %v", err)
+	}
+	if len(result.Imported) != 0 || result.Failed["fs"] == "" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+// TestImportMCPServersMovesLiteralsToSecrets tests that imported plaintext env and header values are stored as secrets
+func TestImportMCPServersMovesLiteralsToSecrets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	a := newTestSecretApp(t)
+	a.mcpMgr = NewMCPManager(a)
+	var synced map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&synced)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	a.serverURL, a.httpClient = srv.URL, srv.Client()
+
+	content := `{"mcpServers":{"gh":{"command":"gh-mcp","env":{"GITHUB_TOKEN":"ghp_plain","HOME_DIR":"${env:HOME}"}}}}`
+	result, err := a.ImportMCPServers(MCPFormatCursor, "", content, nil, "skip", MCPLayerGlobal)
+	if err != nil || len(result.Imported) != 1 {
+		t.Fatalf("ImportMCPServers() = %+v, %v", result, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".opencode", "config.json"))
+	if strings.Contains(string(data), "ghp_plain") || !strings.Contains(string(data), "{secret:gh_GITHUB_TOKEN}") || !strings.Contains(string(data), "{env:HOME}") {
+		t.Errorf("config = %s", data)
+	}
+	if value, err := a.mcpSecrets.get("gh_GITHUB_TOKEN"); err != nil || value != "ghp_plain" {
+		t.Errorf("secret = %q, %v", value, err)
+	}
+	// OpenCode receives the resolved value
+	if env, _ := synced["config"].(map[string]interface{})["environment"].(map[string]interface{}); env["GITHUB_TOKEN"] != "ghp_plain" {
+		t.Errorf("synced = %v", synced)
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MCP 配置层，优先级从低到高
+const (
+	MCPLayerGlobal  = "global"  // ~/.opencode/config.json，所有项目共享
+	MCPLayerProject = "project" // <项目>/.opencode/config.json，随项目提交
+	MCPLayerLocal   = "local"   // <项目>/.opencode/config.local.json，仅本机使用，不提交
+)
+
+// mcpLocalConfigName 本机覆盖配置文件名
+const mcpLocalConfigName = "config.local.json"
+
+// MCPConfigLayer 配置层信息
+type MCPConfigLayer struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Exists  bool     `json:"exists"`
+	Servers []string `json:"servers"` // 该层定义或覆盖的服务器
+}
+
+// mcpLayer 配置层文件
+type mcpLayer struct {
+	Name string
+	Path string
+}
+
+// mcpLayerPaths 返回按优先级从低到高排列的配置层，未打开项目时只有全局层
+func mcpLayerPaths(homeDir, workDir string) []mcpLayer {
+	layers := []mcpLayer{{MCPLayerGlobal, filepath.Join(homeDir, ".opencode", "config.json")}}
+	if workDir != "" {
+		layers = append(layers,
+			mcpLayer{MCPLayerProject, filepath.Join(workDir, ".opencode", "config.json")},
+			mcpLayer{MCPLayerLocal, filepath.Join(workDir, ".opencode", mcpLocalConfigName)},
+		)
+	}
+	return layers
+}
+
+// mcpLayers 返回当前工作目录的配置层
+func (a *App) mcpLayers() []mcpLayer {
+	homeDir, _ := os.UserHomeDir()
+	workDir := ""
+	if a.openCode != nil {
+		workDir = a.openCode.GetWorkDir()
+	}
+	return mcpLayerPaths(homeDir, workDir)
+}
+
+// findMCPLayer 按名称查找配置层
+func findMCPLayer(layers []mcpLayer, name string) (mcpLayer, error) {
+	for _, l := range layers {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return mcpLayer{}, fmt.Errorf("配置层不可用: %s", name)
+}
+
+// defaultMCPLayer 未指定层时新服务器写入的层（有项目时为项目层）
+func defaultMCPLayer(layers []mcpLayer) string {
+	if len(layers) > 1 {
+		return MCPLayerProject
+	}
+	return MCPLayerGlobal
+}
+
+// projectOnlyMCPLayers 服务器是否只在项目层定义（OpenCode 能直接读取）
+func projectOnlyMCPLayers(layers []string) bool {
+	for _, l := range layers {
+		if l != MCPLayerProject {
+			return false
+		}
+	}
+	return true
+}
+
+// readMCPLayer 读取配置文件，返回完整内容和其中的 mcp 字段
+func readMCPLayer(path string) (map[string]interface{}, map[string]map[string]interface{}, error) {
+	file := make(map[string]interface{})
+	servers := make(map[string]map[string]interface{})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, servers, nil
+		}
+		return nil, nil, fmt.Errorf("读取配置失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("解析配置 %s 失败: %v", path, err)
+	}
+	if file == nil {
+		file = make(map[string]interface{})
+	}
+	if raw, ok := file["mcp"].(map[string]interface{}); ok {
+		for name, v := range raw {
+			if entry, ok := v.(map[string]interface{}); ok {
+				servers[name] = entry
+			}
+		}
+	}
+	return file, servers, nil
+}
+
+// writeMCPLayer 写回 mcp 字段并保留文件中的其他字段
+func writeMCPLayer(layer mcpLayer, file map[string]interface{}, servers map[string]map[string]interface{}) error {
+	file["mcp"] = servers
+	return writeConfigLayer(layer, file)
+}
+
+// writeConfigLayer 写入配置层文件；本机层会加入 .gitignore
+func writeConfigLayer(layer mcpLayer, file map[string]interface{}) error {
+	dir := filepath.Dir(layer.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+	if err := os.WriteFile(layer.Path, data, 0644); err != nil {
+		return fmt.Errorf("写入配置失败: %v", err)
+	}
+	if layer.Name == MCPLayerLocal {
+		return ensureGitignored(dir, mcpLocalConfigName)
+	}
+	return nil
+}
+
+// ensureGitignored 确保目录下的 .gitignore 包含指定条目
+func ensureGitignored(dir, entry string) error {
+	path := filepath.Join(dir, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return nil
+		}
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content+entry+"\n"), 0644)
+}
+
+// mergeMCPLayers 按优先级合并各层的 mcp 配置；同名服务器逐字段覆盖，
+// 因此本机层可以只写 {"enabled": false} 来关闭项目中的服务器
+func mergeMCPLayers(layers []mcpLayer) (*MCPConfig, error) {
+	merged := make(map[string]map[string]interface{})
+	config := &MCPConfig{MCP: make(map[string]MCPServer), Layers: make(map[string][]string)}
+	for _, layer := range layers {
+		_, servers, err := readMCPLayer(layer.Path)
+		if err != nil {
+			return nil, err
+		}
+		for name, entry := range servers {
+			if merged[name] == nil {
+				merged[name] = make(map[string]interface{})
+			}
+			for k, v := range entry {
+				merged[name][k] = v
+			}
+			config.Layers[name] = append(config.Layers[name], layer.Name)
+		}
+	}
+	for name, entry := range merged {
+		data, _ := json.Marshal(entry)
+		var server MCPServer
+		if err := json.Unmarshal(data, &server); err != nil {
+			return nil, fmt.Errorf("解析 MCP 服务器 %s 失败: %v", name, err)
+		}
+		config.MCP[name] = server
+	}
+	return config, nil
+}
+
+// updateMCPLayer 修改某一层的 mcp 配置并写回
+func updateMCPLayer(layer mcpLayer, fn func(servers map[string]map[string]interface{}) error) error {
+	file, servers, err := readMCPLayer(layer.Path)
+	if err != nil {
+		return err
+	}
+	if err := fn(servers); err != nil {
+		return err
+	}
+	return writeMCPLayer(layer, file, servers)
+}
+
+// mcpServerEntry 将服务器配置转换为配置文件中的对象
+func mcpServerEntry(server MCPServer) map[string]interface{} {
+	data, _ := json.Marshal(server)
+	entry := make(map[string]interface{})
+	json.Unmarshal(data, &entry)
+	return entry
+}
+
+// saveMCPLayers 将合并后的配置写回各层：新增和修改的服务器写入其生效层（新服务器写入默认层），
+// 删除的服务器从定义它的各层中移除；未改动的服务器不会被展开写入
+func saveMCPLayers(layers []mcpLayer, config MCPConfig) ([]string, error) {
+	current, err := mergeMCPLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]map[string]*MCPServer) // 层 -> 服务器 -> 新配置（nil 表示删除）
+	mark := func(layer, name string, server *MCPServer) {
+		if changes[layer] == nil {
+			changes[layer] = make(map[string]*MCPServer)
+		}
+		changes[layer][name] = server
+	}
+	for name, server := range config.MCP {
+		if old, ok := current.MCP[name]; ok && reflect.DeepEqual(old, server) {
+			continue
+		}
+		server := server
+		target := defaultMCPLayer(layers)
+		if defined := current.Layers[name]; len(defined) > 0 {
+			target = defined[len(defined)-1]
+		}
+		mark(target, name, &server)
+	}
+	for name := range current.MCP {
+		if _, ok := config.MCP[name]; !ok {
+			for _, layer := range current.Layers[name] {
+				mark(layer, name, nil)
+			}
+		}
+	}
+
+	var written []string
+	for _, layer := range layers {
+		layerChanges, ok := changes[layer.Name]
+		if !ok {
+			continue
+		}
+		err := updateMCPLayer(layer, func(servers map[string]map[string]interface{}) error {
+			for name, server := range layerChanges {
+				if server == nil {
+					delete(servers, name)
+				} else {
+					servers[name] = mcpServerEntry(*server)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return written, err
+		}
+		written = append(written, layer.Path)
+	}
+	return written, nil
+}
+
+// --- App API ---
+
+// GetMCPConfigLayers 列出 MCP 配置层及各层定义的服务器
+func (a *App) GetMCPConfigLayers() ([]MCPConfigLayer, error) {
+	var result []MCPConfigLayer
+	for _, layer := range a.mcpLayers() {
+		_, servers, err := readMCPLayer(layer.Path)
+		if err != nil {
+			return nil, err
+		}
+		info := MCPConfigLayer{Name: layer.Name, Path: layer.Path, Exists: fileExists(layer.Path), Servers: []string{}}
+		for name := range servers {
+			info.Servers = append(info.Servers, name)
+		}
+		sort.Strings(info.Servers)
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// GetMCPLayerPath 获取指定配置层的文件路径
+func (a *App) GetMCPLayerPath(layer string) (string, error) {
+	l, err := findMCPLayer(a.mcpLayers(), layer)
+	if err != nil {
+		return "", err
+	}
+	return l.Path, nil
+}
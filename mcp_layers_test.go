@@ -0,0 +1,165 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestLayers creates a global/project/local layer set in temp directories
+func writeTestLayers(t *testing.T, global, project, local string) []mcpLayer {
+	t.Helper()
+	home, work := t.TempDir(), t.TempDir()
+	layers := mcpLayerPaths(home, work)
+	for i, content := range []string{global, project, local} {
+		if content == "" {
+			continue
+		}
+		os.MkdirAll(filepath.Dir(layers[i].Path), 0755)
+		if err := os.WriteFile(layers[i].Path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return layers
+}
+
+// TestMCPLayerPaths tests layer order and the global-only fallback without a workspace
+func TestMCPLayerPaths(t *testing.T) {
+	layers := mcpLayerPaths("/home/u", "/work")
+	var names []string
+	for _, l := range layers {
+		names = append(names, l.Name)
+	}
+	if !reflect.DeepEqual(names, []string{MCPLayerGlobal, MCPLayerProject, MCPLayerLocal}) {
+		t.Errorf("names = %v", names)
+	}
+	if defaultMCPLayer(layers) != MCPLayerProject {
+		t.Errorf("default = %q", defaultMCPLayer(layers))
+	}
+
+	layers = mcpLayerPaths("/home/u", "")
+	if len(layers) != 1 || defaultMCPLayer(layers) != MCPLayerGlobal {
+		t.Errorf("layers without workspace = %+v", layers)
+	}
+	if _, err := findMCPLayer(layers, MCPLayerLocal); err == nil {
+		t.Error("local layer should be unavailable without workspace")
+	}
+}
+
+// TestMergeMCPLayers tests precedence, partial overrides and layer attribution
+func TestMergeMCPLayers(t *testing.T) {
+	layers := writeTestLayers(t,
+		`{"mcp":{"github":{"type":"remote","url":"https://g","enabled":true},"fs":{"type":"local","command":["fs"],"enabled":true}}}`,
+		`{"mcp":{"fs":{"type":"local","command":["fs","--root","."],"enabled":true},"db":{"type":"local","command":["db"],"enabled":true}}}`,
+		`{"mcp":{"db":{"enabled":false}}}`,
+	)
+	config, err := mergeMCPLayers(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		layers  []string
+		command []string
+		enabled bool
+	}{
+		{"github", []string{"global"}, nil, true},
+		{"fs", []string{"global", "project"}, []string{"fs", "--root", "."}, true},
+		{"db", []string{"project", "local"}, []string{"db"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := config.MCP[tt.name]
+			if !reflect.DeepEqual(config.Layers[tt.name], tt.layers) {
+				t.Errorf("layers = %v, want %v", config.Layers[tt.name], tt.layers)
+			}
+			if !reflect.DeepEqual(server.Command, tt.command) || server.Enabled != tt.enabled {
+				t.Errorf("server = %+v", server)
+			}
+		})
+	}
+
+	if !projectOnlyMCPLayers(nil) || projectOnlyMCPLayers(config.Layers["db"]) {
+		t.Error("projectOnlyMCPLayers mismatch")
+	}
+
+	bad := writeTestLayers(t, "", "{broken", "")
+	if _, err := mergeMCPLayers(bad); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+// TestSaveMCPLayers tests that edits go to the winning layer, removals clear every layer and other fields survive
+func TestSaveMCPLayers(t *testing.T) {
+	layers := writeTestLayers(t,
+		`{"theme":"dark","mcp":{"github":{"type":"remote","url":"https://g","enabled":true},"fs":{"type":"local","command":["fs"],"enabled":true}}}`,
+		`{"$schema":"https://opencode.ai/config.json","mcp":{"fs":{"type":"local","command":["fs"],"enabled":true}}}`,
+		"",
+	)
+	config, err := mergeMCPLayers(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gh := config.MCP["github"]
+	gh.URL = "https://g2"
+	config.MCP["github"] = gh
+	delete(config.MCP, "fs")
+	config.MCP["new"] = MCPServer{Type: "local", Command: []string{"new"}, Enabled: true}
+
+	written, err := saveMCPLayers(layers, *config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 2 {
+		t.Errorf("written = %v", written)
+	}
+
+	global, globalServers, _ := readMCPLayer(layers[0].Path)
+	project, projectServers, _ := readMCPLayer(layers[1].Path)
+	if global["theme"] != "dark" || project["$schema"] == nil {
+		t.Error("unrelated fields were lost")
+	}
+	if globalServers["github"]["url"] != "https://g2" || globalServers["fs"] != nil {
+		t.Errorf("global = %v", globalServers)
+	}
+	if projectServers["new"] == nil || projectServers["fs"] != nil || projectServers["github"] != nil {
+		t.Errorf("project = %v", projectServers)
+	}
+	if fileExists(layers[2].Path) {
+		t.Error("local layer should not be created")
+	}
+
+	// saving unchanged config writes nothing
+	config, _ = mergeMCPLayers(layers)
+	if written, _ := saveMCPLayers(layers, *config); len(written) != 0 {
+		t.Errorf("unchanged save wrote %v", written)
+	}
+}
+
+// TestWriteLocalMCPLayerGitignore tests that the local layer is git-ignored once
+func TestWriteLocalMCPLayerGitignore(t *testing.T) {
+	layers := writeTestLayers(t, "", "", "")
+	dir := filepath.Dir(layers[2].Path)
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules"), 0644)
+
+	for i := 0; i < 2; i++ {
+		err := updateMCPLayer(layers[2], func(servers map[string]map[string]interface{}) error {
+			servers["db"] = map[string]interface{}{"enabled": false}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if string(data) != "node_modules\n"+mcpLocalConfigName+"\n" {
+		t.Errorf(".gitignore = %q", data)
+	}
+	if strings.Count(string(data), mcpLocalConfigName) != 1 {
+		t.Error("entry duplicated")
+	}
+}
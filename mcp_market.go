@@ -0,0 +1,486 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinMCPRegistry 内置的 MCP 服务器索引，未配置索引地址或加载失败时使用
+//
+//go:embed mcp_registry.json
+var builtinMCPRegistry []byte
+
+// mcpRegistryMaxAge 远程索引缓存的有效期，过期后下次打开市场时重新下载
+const mcpRegistryMaxAge = 24 * time.Hour
+
+// mcpParamRefRe 命令参数和 URL 中的参数占位符，如 {{dir}}
+var mcpParamRefRe = regexp.MustCompile(`\{\{([A-Za-z0-9_.-]+)\}\}`)
+
+// MCPMarketParam 安装服务器时需要填写的参数
+type MCPMarketParam struct {
+	Name        string   `json:"name"` // target 为 env/header 时即变量名或请求头名
+	Label       string   `json:"label,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type"`   // string, number, boolean, enum, path, url, secret
+	Target      string   `json:"target"` // arg（替换命令或 URL 中的 {{name}}）、env 或 header
+	Required    bool     `json:"required"`
+	Default     string   `json:"default,omitempty"`
+	Options     []string `json:"options,omitempty"` // enum 的可选值
+	Pattern     string   `json:"pattern,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+}
+
+// MCPRegistryIndex MCP 服务器索引文件
+type MCPRegistryIndex struct {
+	Version int             `json:"version"`
+	Servers []MCPMarketItem `json:"servers"`
+}
+
+// MCPMarketSettings MCP 市场设置
+type MCPMarketSettings struct {
+	RegistryURL string `json:"registryUrl"` // 索引地址：http(s) URL 或本地 JSON 文件，为空时使用内置索引
+}
+
+// MCPMarketStatus 当前使用的索引
+type MCPMarketStatus struct {
+	Source    string    `json:"source"` // builtin, file, remote
+	Location  string    `json:"location,omitempty"`
+	Count     int       `json:"count"`
+	FetchedAt time.Time `json:"fetchedAt,omitempty"`
+	FromCache bool      `json:"fromCache"` // 远程索引无法访问，使用了磁盘缓存
+	Error     string    `json:"error,omitempty"`
+}
+
+// mcpRegistryCache 远程索引的磁盘缓存
+type mcpRegistryCache struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Index     json.RawMessage `json:"index"`
+}
+
+// parseMCPRegistry 解析并校验索引，envVars 简写会转换为必填的环境变量参数
+func parseMCPRegistry(data []byte) ([]MCPMarketItem, error) {
+	var index MCPRegistryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("解析 MCP 索引失败: %v", err)
+	}
+	if index.Version > 1 {
+		return nil, fmt.Errorf("不支持的 MCP 索引版本: %d", index.Version)
+	}
+	seen := make(map[string]bool)
+	for i := range index.Servers {
+		item := &index.Servers[i]
+		if item.Name == "" {
+			return nil, fmt.Errorf("MCP 索引第 %d 项缺少名称", i+1)
+		}
+		if seen[item.Name] {
+			return nil, fmt.Errorf("MCP 索引中的服务器重复: %s", item.Name)
+		}
+		seen[item.Name] = true
+		if err := normalizeMCPMarketItem(item); err != nil {
+			return nil, fmt.Errorf("MCP 索引项 %s 无效: %v", item.Name, err)
+		}
+	}
+	return index.Servers, nil
+}
+
+// normalizeMCPMarketItem 补全默认值并检查占位符都有对应参数
+func normalizeMCPMarketItem(item *MCPMarketItem) error {
+	if item.Type == "" {
+		item.Type = "local"
+	}
+	switch item.Type {
+	case "local":
+		if len(item.Command) == 0 {
+			return fmt.Errorf("缺少启动命令")
+		}
+	case "remote":
+		if item.URL == "" {
+			return fmt.Errorf("缺少 URL")
+		}
+	default:
+		return fmt.Errorf("未知类型 %s", item.Type)
+	}
+
+	params := make(map[string]bool)
+	for _, p := range item.Params {
+		params[p.Name] = true
+	}
+	for _, env := range item.EnvVars {
+		if !params[env] {
+			item.Params = append(item.Params, MCPMarketParam{Name: env, Type: "string", Target: "env", Required: true})
+			params[env] = true
+		}
+	}
+	item.EnvVars = nil
+	if item.Params == nil {
+		item.Params = []MCPMarketParam{}
+	}
+
+	args := make(map[string]bool)
+	for i := range item.Params {
+		p := &item.Params[i]
+		if p.Type == "" {
+			p.Type = "string"
+		}
+		if p.Target == "" {
+			p.Target = "arg"
+		}
+		if p.Label == "" {
+			p.Label = p.Name
+		}
+		switch p.Type {
+		case "string", "number", "boolean", "enum", "path", "url", "secret":
+		default:
+			return fmt.Errorf("参数 %s 的类型未知: %s", p.Name, p.Type)
+		}
+		switch p.Target {
+		case "arg":
+			args[p.Name] = true
+		case "env", "header":
+		default:
+			return fmt.Errorf("参数 %s 的目标未知: %s", p.Name, p.Target)
+		}
+		if p.Type == "enum" && len(p.Options) == 0 {
+			return fmt.Errorf("参数 %s 缺少可选值", p.Name)
+		}
+		if p.Pattern != "" {
+			if _, err := regexp.Compile(p.Pattern); err != nil {
+				return fmt.Errorf("参数 %s 的格式无效: %v", p.Name, err)
+			}
+		}
+	}
+	for _, s := range append(append([]string{}, item.Command...), item.URL) {
+		for _, m := range mcpParamRefRe.FindAllStringSubmatch(s, -1) {
+			if !args[m[1]] {
+				return fmt.Errorf("占位符 {{%s}} 没有对应的 arg 参数", m[1])
+			}
+		}
+	}
+	return nil
+}
+
+// validateMCPMarketValue 按参数类型校验填写的值，返回错误信息
+func validateMCPMarketValue(p MCPMarketParam, value string) string {
+	if value == "" {
+		if p.Required {
+			return "必填"
+		}
+		return ""
+	}
+	switch p.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "应为数字"
+		}
+	case "boolean":
+		if value != "true" && value != "false" {
+			return "应为 true 或 false"
+		}
+	case "enum":
+		found := false
+		for _, o := range p.Options {
+			if o == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("取值必须是 %s 之一", strings.Join(p.Options, ", "))
+		}
+	case "url":
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "应为 http(s) 地址"
+		}
+	case "path":
+		if !filepath.IsAbs(value) && !strings.HasPrefix(value, "~") {
+			return "应为绝对路径"
+		}
+	}
+	if p.Pattern != "" {
+		if re, err := regexp.Compile(p.Pattern); err == nil && !re.MatchString(value) {
+			return fmt.Sprintf("不匹配格式 %s", p.Pattern)
+		}
+	}
+	return ""
+}
+
+// buildMCPMarketServer 用填写的参数生成服务器配置
+// secret 参数（包括命令参数）不会写入配置，而是返回给调用方加密保存，配置中只保留 {secret:NAME} 引用
+func buildMCPMarketServer(item MCPMarketItem, values map[string]string) (MCPServer, map[string]string, []MCPValidationError) {
+	errs := []MCPValidationError{}
+	secrets := make(map[string]string)
+	args := make(map[string]string)
+	server := MCPServer{Type: item.Type, URL: item.URL, Enabled: true}
+
+	for _, p := range item.Params {
+		value, ok := values[p.Name]
+		if !ok {
+			value = p.Default
+		}
+		value = strings.TrimSpace(value)
+		if msg := validateMCPMarketValue(p, value); msg != "" {
+			errs = append(errs, MCPValidationError{Path: p.Name, Message: msg})
+			continue
+		}
+		if p.Type == "path" && strings.HasPrefix(value, "~") {
+			if home, err := os.UserHomeDir(); err == nil {
+				value = filepath.Join(home, strings.TrimPrefix(value, "~"))
+			}
+		}
+		if value == "" && p.Target != "arg" {
+			continue
+		}
+		if p.Type == "secret" && value != "" {
+			name := mcpSecretInvalidRe.ReplaceAllString(item.Name+"_"+p.Name, "_")
+			secrets[name] = value
+			value = "{secret:" + name + "}"
+		}
+		switch p.Target {
+		case "arg":
+			args[p.Name] = value
+		case "env":
+			if server.Environment == nil {
+				server.Environment = make(map[string]string)
+			}
+			server.Environment[p.Name] = value
+		case "header":
+			if server.Headers == nil {
+				server.Headers = make(map[string]string)
+			}
+			server.Headers[p.Name] = value
+		}
+	}
+	if len(errs) > 0 {
+		return server, nil, errs
+	}
+
+	expand := func(s string) string {
+		return mcpParamRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+			return args[mcpParamRefRe.FindStringSubmatch(ref)[1]]
+		})
+	}
+	for _, arg := range item.Command {
+		// 可选参数留空时去掉整个命令参数
+		if expanded := expand(arg); expanded != "" || arg == "" {
+			server.Command = append(server.Command, expanded)
+		}
+	}
+	server.URL = expand(server.URL)
+	return server, secrets, errs
+}
+
+// mcpRegistryIsRemote 索引地址是否为 http(s) URL
+func mcpRegistryIsRemote(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// loadMCPRegistry 加载索引：远程索引优先使用未过期的缓存，下载失败时回退到缓存，
+// 都不可用时回退到内置索引并在状态中记录错误
+func loadMCPRegistry(dir, location string, client *http.Client, refresh bool) ([]MCPMarketItem, MCPMarketStatus) {
+	builtin := func(status MCPMarketStatus, err error) ([]MCPMarketItem, MCPMarketStatus) {
+		items, _ := parseMCPRegistry(builtinMCPRegistry)
+		status.Source = "builtin"
+		status.Count = len(items)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		return items, status
+	}
+
+	if location == "" {
+		return builtin(MCPMarketStatus{}, nil)
+	}
+	status := MCPMarketStatus{Location: location}
+
+	if !mcpRegistryIsRemote(location) {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return builtin(status, fmt.Errorf("读取 MCP 索引失败: %v", err))
+		}
+		items, err := parseMCPRegistry(data)
+		if err != nil {
+			return builtin(status, err)
+		}
+		status.Source = "file"
+		status.Count = len(items)
+		return items, status
+	}
+
+	status.Source = "remote"
+	cachePath := filepath.Join(dir, "registry-cache.json")
+	var cache mcpRegistryCache
+	if data, err := os.ReadFile(cachePath); err == nil {
+		if json.Unmarshal(data, &cache) != nil || cache.URL != location {
+			cache = mcpRegistryCache{}
+		}
+	}
+	var cached []MCPMarketItem
+	if len(cache.Index) > 0 {
+		cached, _ = parseMCPRegistry(cache.Index)
+	}
+	if cached != nil && !refresh && time.Since(cache.FetchedAt) < mcpRegistryMaxAge {
+		status.Count = len(cached)
+		status.FetchedAt = cache.FetchedAt
+		return cached, status
+	}
+
+	items, data, err := fetchMCPRegistry(client, location)
+	if err == nil {
+		cache = mcpRegistryCache{URL: location, FetchedAt: time.Now(), Index: data}
+		if raw, err := json.MarshalIndent(cache, "", "  "); err == nil {
+			os.MkdirAll(dir, 0755)
+			writeFileAtomic(cachePath, raw, 0644)
+		}
+		status.Count = len(items)
+		status.FetchedAt = cache.FetchedAt
+		return items, status
+	}
+	if cached != nil {
+		status.Count = len(cached)
+		status.FetchedAt = cache.FetchedAt
+		status.FromCache = true
+		status.Error = err.Error()
+		return cached, status
+	}
+	return builtin(status, err)
+}
+
+// fetchMCPRegistry 下载并解析远程索引
+func fetchMCPRegistry(client *http.Client, location string) ([]MCPMarketItem, []byte, error) {
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, nil, fmt.Errorf("下载 MCP 索引失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("下载 MCP 索引失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, nil, fmt.Errorf("下载 MCP 索引失败: %v", err)
+	}
+	items, err := parseMCPRegistry(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, data, nil
+}
+
+// loadMCPMarketSettings 读取市场设置
+func loadMCPMarketSettings(dir string) MCPMarketSettings {
+	var settings MCPMarketSettings
+	if data, err := os.ReadFile(filepath.Join(dir, "market.json")); err == nil {
+		json.Unmarshal(data, &settings)
+	}
+	return settings
+}
+
+// mcpMarket 按当前设置加载市场
+func (a *App) mcpMarket(refresh bool) ([]MCPMarketItem, MCPMarketStatus) {
+	dir, err := a.getDataDir("mcp")
+	if err != nil {
+		return loadMCPRegistry("", "", nil, false)
+	}
+	settings := loadMCPMarketSettings(dir)
+	return loadMCPRegistry(dir, settings.RegistryURL, &http.Client{Timeout: 15 * time.Second}, refresh)
+}
+
+// findMCPMarketItem 按名称查找市场中的服务器
+func (a *App) findMCPMarketItem(name string) (MCPMarketItem, error) {
+	items, _ := a.mcpMarket(false)
+	for _, item := range items {
+		if item.Name == name {
+			return item, nil
+		}
+	}
+	return MCPMarketItem{}, fmt.Errorf("市场中没有该服务器: %s", name)
+}
+
+// --- App API ---
+
+// GetMCPMarket 获取 MCP 市场列表（来自配置的索引，离线时使用缓存或内置索引）
+func (a *App) GetMCPMarket() []MCPMarketItem {
+	items, status := a.mcpMarket(false)
+	if status.Error != "" {
+		a.emitEvent("output-log", fmt.Sprintf("加载 MCP 市场索引失败: %s", status.Error))
+	}
+	return items
+}
+
+// RefreshMCPMarket 重新下载市场索引并返回索引状态
+func (a *App) RefreshMCPMarket() MCPMarketStatus {
+	_, status := a.mcpMarket(true)
+	return status
+}
+
+// GetMCPMarketStatus 返回当前使用的市场索引状态
+func (a *App) GetMCPMarketStatus() MCPMarketStatus {
+	_, status := a.mcpMarket(false)
+	return status
+}
+
+// GetMCPMarketSettings 读取 MCP 市场设置
+func (a *App) GetMCPMarketSettings() MCPMarketSettings {
+	dir, err := a.getDataDir("mcp")
+	if err != nil {
+		return MCPMarketSettings{}
+	}
+	return loadMCPMarketSettings(dir)
+}
+
+// SaveMCPMarketSettings 保存 MCP 市场设置
+func (a *App) SaveMCPMarketSettings(settings MCPMarketSettings) error {
+	settings.RegistryURL = strings.TrimSpace(settings.RegistryURL)
+	if loc := settings.RegistryURL; loc != "" && !mcpRegistryIsRemote(loc) && !filepath.IsAbs(loc) {
+		return fmt.Errorf("索引地址应为 http(s) URL 或本地文件的绝对路径")
+	}
+	dir, err := a.getDataDir("mcp")
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, "market.json"), data, 0644)
+}
+
+// ValidateMCPMarketValues 校验安装参数，不执行安装
+func (a *App) ValidateMCPMarketValues(name string, values map[string]string) ([]MCPValidationError, error) {
+	item, err := a.findMCPMarketItem(name)
+	if err != nil {
+		return nil, err
+	}
+	_, _, errs := buildMCPMarketServer(item, values)
+	return errs, nil
+}
+
+// InstallMCPMarketItem 校验参数后从市场安装服务器，secret 参数加密保存
+func (a *App) InstallMCPMarketItem(name string, values map[string]string, layer string) (map[string]MCPServerStatus, error) {
+	item, err := a.findMCPMarketItem(name)
+	if err != nil {
+		return nil, err
+	}
+	server, secrets, errs := buildMCPMarketServer(item, values)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("参数 %s %s", errs[0].Path, errs[0].Message)
+	}
+	for secret, value := range secrets {
+		if err := a.mcpSecrets.Set(secret, value); err != nil {
+			return nil, err
+		}
+	}
+	return a.AddMCPServer(name, server, layer)
+}
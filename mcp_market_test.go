@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBuiltinMCPRegistry tests that the embedded index is valid and has no placeholder paths left
+func TestBuiltinMCPRegistry(t *testing.T) {
+	items, err := parseMCPRegistry(builtinMCPRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) == 0 {
+		t.Fatal("empty builtin registry")
+	}
+	for _, item := range items {
+		for _, arg := range item.Command {
+			if strings.Contains(arg, "/path/to") {
+				t.Errorf("%s has placeholder arg %q", item.Name, arg)
+			}
+		}
+	}
+}
+
+// TestParseMCPRegistry tests index validation and the envVars shorthand
+func TestParseMCPRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   string
+		wantErr string
+	}{
+		{"valid", `{"version":1,"servers":[{"name":"a","command":["a","{{dir}}"],"params":[{"name":"dir","type":"path"}]}]}`, ""},
+		{"bad json", `{`, "解析"},
+		{"future version", `{"version":2,"servers":[]}`, "版本"},
+		{"missing name", `{"servers":[{"command":["a"]}]}`, "缺少名称"},
+		{"duplicate", `{"servers":[{"name":"a","command":["a"]},{"name":"a","command":["b"]}]}`, "重复"},
+		{"no command", `{"servers":[{"name":"a"}]}`, "启动命令"},
+		{"remote without url", `{"servers":[{"name":"a","type":"remote"}]}`, "URL"},
+		{"undeclared placeholder", `{"servers":[{"name":"a","command":["a","{{dir}}"]}]}`, "{{dir}}"},
+		{"env param as placeholder", `{"servers":[{"name":"a","command":["{{K}}"],"params":[{"name":"K","target":"env"}]}]}`, "{{K}}"},
+		{"enum without options", `{"servers":[{"name":"a","command":["a"],"params":[{"name":"k","type":"enum","target":"env"}]}]}`, "可选值"},
+		{"bad pattern", `{"servers":[{"name":"a","command":["a"],"params":[{"name":"k","target":"env","pattern":"("}]}]}`, "格式"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMCPRegistry([]byte(tt.index))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	items, err := parseMCPRegistry([]byte(`{"servers":[{"name":"a","command":["a"],"envVars":["TOKEN"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MCPMarketParam{{Name: "TOKEN", Label: "TOKEN", Type: "string", Target: "env", Required: true}}
+	if !reflect.DeepEqual(items[0].Params, want) || items[0].EnvVars != nil || items[0].Type != "local" {
+		t.Errorf("item = %+v", items[0])
+	}
+}
+
+// TestBuildMCPMarketServer tests value validation, placeholder expansion and secret references
+func TestBuildMCPMarketServer(t *testing.T) {
+	item := MCPMarketItem{
+		Name:    "db",
+		Type:    "local",
+		Command: []string{"db-server", "--root", "{{dir}}", "{{flag}}", "{{conn}}"},
+		Params: []MCPMarketParam{
+			{Name: "dir", Type: "path", Target: "arg", Required: true},
+			{Name: "flag", Type: "enum", Target: "arg", Options: []string{"--ro", "--rw"}},
+			{Name: "conn", Type: "secret", Target: "arg"},
+			{Name: "PORT", Type: "number", Target: "env", Default: "5432"},
+			{Name: "TOKEN", Type: "secret", Target: "env", Required: true, Pattern: "^tk_"},
+			{Name: "DEBUG", Type: "boolean", Target: "env"},
+		},
+	}
+
+	server, secrets, errs := buildMCPMarketServer(item, map[string]string{"dir": "/data", "TOKEN": "tk_123"})
+	if len(errs) != 0 {
+		t.Fatalf("errs = %+v", errs)
+	}
+	if !reflect.DeepEqual(server.Command, []string{"db-server", "--root", "/data"}) {
+		t.Errorf("command = %v", server.Command)
+	}
+	if !reflect.DeepEqual(server.Environment, map[string]string{"PORT": "5432", "TOKEN": "{secret:db_TOKEN}"}) {
+		t.Errorf("environment = %v", server.Environment)
+	}
+	if !reflect.DeepEqual(secrets, map[string]string{"db_TOKEN": "tk_123"}) || !server.Enabled {
+		t.Errorf("secrets = %v enabled = %v", secrets, server.Enabled)
+	}
+
+	// secret args are stored as references
+	withConn, connSecrets, _ := buildMCPMarketServer(item, map[string]string{"dir": "/data", "TOKEN": "tk_123", "conn": "postgresql://u:p@h/db"})
+	if !reflect.DeepEqual(withConn.Command, []string{"db-server", "--root", "/data", "{secret:db_conn}"}) || connSecrets["db_conn"] != "postgresql://u:p@h/db" {
+		t.Errorf("command = %v secrets = %v", withConn.Command, connSecrets)
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		path   string
+	}{
+		{"missing required", map[string]string{"TOKEN": "tk_1"}, "dir"},
+		{"relative path", map[string]string{"dir": "data", "TOKEN": "tk_1"}, "dir"},
+		{"bad enum", map[string]string{"dir": "/d", "flag": "--x", "TOKEN": "tk_1"}, "flag"},
+		{"bad number", map[string]string{"dir": "/d", "PORT": "abc", "TOKEN": "tk_1"}, "PORT"},
+		{"bad pattern", map[string]string{"dir": "/d", "TOKEN": "nope"}, "TOKEN"},
+		{"bad boolean", map[string]string{"dir": "/d", "TOKEN": "tk_1", "DEBUG": "yes"}, "DEBUG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, secrets, errs := buildMCPMarketServer(item, tt.values)
+			if len(errs) != 1 || errs[0].Path != tt.path || secrets != nil {
+				t.Errorf("errs = %+v secrets = %v", errs, secrets)
+			}
+		})
+	}
+}
+
+// TestLoadMCPRegistry tests remote fetching, disk caching, offline fallback and local files
+func TestLoadMCPRegistry(t *testing.T) {
+	dir := t.TempDir()
+	index := `{"version":1,"servers":[{"name":"remote-one","command":["one"]}]}`
+	online := true
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if !online {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, index)
+	}))
+	defer srv.Close()
+
+	items, status := loadMCPRegistry(dir, srv.URL, srv.Client(), false)
+	if status.Source != "remote" || status.Error != "" || len(items) != 1 || items[0].Name != "remote-one" {
+		t.Fatalf("items = %+v status = %+v", items, status)
+	}
+
+	// fresh cache is used without a request
+	loadMCPRegistry(dir, srv.URL, srv.Client(), false)
+	if hits != 1 {
+		t.Errorf("hits = %d, want cached result", hits)
+	}
+
+	// offline refresh falls back to the cache
+	online = false
+	items, status = loadMCPRegistry(dir, srv.URL, srv.Client(), true)
+	if !status.FromCache || status.Error == "" || len(items) != 1 {
+		t.Errorf("offline items = %+v status = %+v", items, status)
+	}
+
+	// a different URL does not reuse the cache and falls back to the builtin index
+	items, status = loadMCPRegistry(dir, srv.URL+"/other", srv.Client(), false)
+	if status.Source != "builtin" || status.Error == "" || len(items) < 2 {
+		t.Errorf("builtin fallback status = %+v", status)
+	}
+
+	// stale cache triggers a refetch
+	online = true
+	cachePath := filepath.Join(dir, "registry-cache.json")
+	var cache mcpRegistryCache
+	data, _ := os.ReadFile(cachePath)
+	json.Unmarshal(data, &cache)
+	cache.FetchedAt = time.Now().Add(-2 * mcpRegistryMaxAge)
+	data, _ = json.Marshal(cache)
+	os.WriteFile(cachePath, data, 0644)
+	before := hits
+	loadMCPRegistry(dir, srv.URL, srv.Client(), false)
+	if hits != before+1 {
+		t.Errorf("stale cache was not refreshed")
+	}
+
+	file := filepath.Join(dir, "index.json")
+	os.WriteFile(file, []byte(`{"servers":[{"name":"local-one","command":["x"]}]}`), 0644)
+	items, status = loadMCPRegistry(dir, file, nil, false)
+	if status.Source != "file" || len(items) != 1 || items[0].Name != "local-one" {
+		t.Errorf("file items = %+v status = %+v", items, status)
+	}
+}
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// mcpOAuthCallbackPath 远程 MCP 授权回调路径（挂在 AuthService 的本地回调服务上）
+const mcpOAuthCallbackPath = "/mcp/oauth/callback"
+
+// mcpOAuthPendingTTL 授权请求的有效期
+const mcpOAuthPendingTTL = 10 * time.Minute
+
+// MCPOAuthOptions 授权选项，未提供 ClientID 时使用动态客户端注册
+type MCPOAuthOptions struct {
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// MCPOAuthStatus 远程服务器的授权状态
+type MCPOAuthStatus struct {
+	Server      string    `json:"server"`
+	Authorized  bool      `json:"authorized"`
+	ExpiresAt   time.Time `json:"expiresAt,omitempty"`
+	Scope       string    `json:"scope,omitempty"`
+	Refreshable bool      `json:"refreshable"`
+}
+
+// mcpOAuthCredentials 加密保存的 OAuth 客户端和令牌
+type mcpOAuthCredentials struct {
+	ServerURL     string    `json:"serverUrl"`
+	ClientID      string    `json:"clientId"`
+	ClientSecret  string    `json:"clientSecret,omitempty"`
+	TokenEndpoint string    `json:"tokenEndpoint"`
+	AccessToken   string    `json:"accessToken"`
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	TokenType     string    `json:"tokenType,omitempty"`
+	Scope         string    `json:"scope,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt,omitempty"`
+}
+
+// mcpOAuthPending 等待回调的授权请求
+type mcpOAuthPending struct {
+	Server        string
+	ServerURL     string
+	Verifier      string
+	RedirectURI   string
+	ClientID      string
+	ClientSecret  string
+	TokenEndpoint string
+	CreatedAt     time.Time
+}
+
+// mcpAuthServerMetadata 授权服务器元数据（RFC 8414）
+type mcpAuthServerMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+}
+
+// mcpTokenResponse 令牌端点响应
+type mcpTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+var resourceMetadataRe = regexp.MustCompile(`resource_metadata="([^"]+)"`)
+
+// getJSON 请求并解析 JSON，非 2xx 返回错误
+func getJSON(ctx context.Context, client *http.Client, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s 返回 %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// discoverMCPAuthServer 发现远程 MCP 服务器的授权服务器（MCP 授权规范：RFC 9728 + RFC 8414）
+func discoverMCPAuthServer(ctx context.Context, client *http.Client, serverURL string) (*mcpAuthServerMetadata, []string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, nil, fmt.Errorf("服务器 URL 无效: %s", serverURL)
+	}
+	origin := u.Scheme + "://" + u.Host
+
+	// 1. 从 401 响应的 WWW-Authenticate 或约定路径获取受保护资源元数据
+	prmURLs := []string{}
+	if req, err := http.NewRequestWithContext(ctx, "POST", serverURL, strings.NewReader(`{"jsonrpc":"2.0","id":0,"method":"ping"}`)); err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+			if m := resourceMetadataRe.FindStringSubmatch(resp.Header.Get("WWW-Authenticate")); m != nil {
+				prmURLs = append(prmURLs, m[1])
+			}
+		}
+	}
+	if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+		prmURLs = append(prmURLs, origin+"/.well-known/oauth-protected-resource"+path)
+	}
+	prmURLs = append(prmURLs, origin+"/.well-known/oauth-protected-resource")
+
+	issuer := origin
+	var scopes []string
+	for _, prmURL := range prmURLs {
+		var prm struct {
+			AuthorizationServers []string `json:"authorization_servers"`
+			ScopesSupported      []string `json:"scopes_supported"`
+		}
+		if getJSON(ctx, client, prmURL, &prm) == nil && len(prm.AuthorizationServers) > 0 {
+			issuer, scopes = strings.TrimSuffix(prm.AuthorizationServers[0], "/"), prm.ScopesSupported
+			break
+		}
+	}
+
+	// 2. 获取授权服务器元数据，依次尝试 OAuth 和 OpenID 的约定路径
+	iu, err := url.Parse(issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("授权服务器地址无效: %s", issuer)
+	}
+	issuerOrigin := iu.Scheme + "://" + iu.Host
+	issuerPath := strings.TrimSuffix(iu.Path, "/")
+	candidates := []string{issuerOrigin + "/.well-known/oauth-authorization-server" + issuerPath}
+	if issuerPath != "" {
+		candidates = append(candidates, issuerOrigin+"/.well-known/openid-configuration"+issuerPath)
+	}
+	candidates = append(candidates, issuer+"/.well-known/openid-configuration")
+	for _, c := range candidates {
+		var meta mcpAuthServerMetadata
+		if getJSON(ctx, client, c, &meta) == nil && meta.AuthorizationEndpoint != "" && meta.TokenEndpoint != "" {
+			if len(scopes) == 0 {
+				scopes = meta.ScopesSupported
+			}
+			return &meta, scopes, nil
+		}
+	}
+
+	// 3. 没有元数据时使用规范约定的默认端点
+	return &mcpAuthServerMetadata{
+		Issuer:                issuer,
+		AuthorizationEndpoint: issuerOrigin + "/authorize",
+		TokenEndpoint:         issuerOrigin + "/token",
+		RegistrationEndpoint:  issuerOrigin + "/register",
+	}, scopes, nil
+}
+
+// registerMCPClient 动态注册公共客户端（RFC 7591）
+func registerMCPClient(ctx context.Context, client *http.Client, endpoint, redirectURI string) (string, string, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"client_name":                "OpenCode Desktop",
+		"redirect_uris":              []string{redirectURI},
+		"grant_types":                []string{"authorization_code", "refresh_token"},
+		"response_types":             []string{"code"},
+		"token_endpoint_auth_method": "none",
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("注册客户端失败: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return "", "", fmt.Errorf("注册客户端失败 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var result struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || result.ClientID == "" {
+		return "", "", fmt.Errorf("注册客户端失败: 响应中没有 client_id")
+	}
+	return result.ClientID, result.ClientSecret, nil
+}
+
+// requestMCPToken 调用令牌端点
+func requestMCPToken(ctx context.Context, client *http.Client, endpoint string, form url.Values) (*mcpTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求令牌失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var token mcpTokenResponse
+	data, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("请求令牌失败 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("请求令牌失败: %s %s", token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode/100 != 2 || token.AccessToken == "" {
+		return nil, fmt.Errorf("请求令牌失败 %d: 响应中没有 access_token", resp.StatusCode)
+	}
+	return &token, nil
+}
+
+// apply 用令牌响应更新凭据（刷新时服务器可能不返回新的 refresh_token）
+func (c *mcpOAuthCredentials) apply(token *mcpTokenResponse) {
+	c.AccessToken = token.AccessToken
+	c.TokenType = token.TokenType
+	if token.RefreshToken != "" {
+		c.RefreshToken = token.RefreshToken
+	}
+	if token.Scope != "" {
+		c.Scope = token.Scope
+	}
+	c.ExpiresAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+}
+
+// credentials 解密读取服务器的 OAuth 凭据，不存在时返回 nil
+func (sm *MCPSecretManager) credentials(server string) (*mcpOAuthCredentials, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if err := sm.loadLocked(); err != nil {
+		return nil, err
+	}
+	encrypted, ok := sm.data.OAuth[server]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := sm.crypto.DecryptString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("解密 OAuth 凭据失败: %v", err)
+	}
+	var creds mcpOAuthCredentials
+	if err := json.Unmarshal([]byte(raw), &creds); err != nil {
+		return nil, fmt.Errorf("解析 OAuth 凭据失败: %v", err)
+	}
+	return &creds, nil
+}
+
+// saveCredentials 加密保存 OAuth 凭据，creds 为 nil 时删除
+func (sm *MCPSecretManager) saveCredentials(server string, creds *mcpOAuthCredentials) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if err := sm.loadLocked(); err != nil {
+		return err
+	}
+	if creds == nil {
+		if t, ok := sm.timers[server]; ok {
+			t.timer.Stop()
+			delete(sm.timers, server)
+		}
+		delete(sm.data.OAuth, server)
+		return sm.saveLocked()
+	}
+	raw, _ := json.Marshal(creds)
+	encrypted, err := sm.crypto.EncryptString(string(raw))
+	if err != nil {
+		return fmt.Errorf("加密 OAuth 凭据失败: %v", err)
+	}
+	sm.data.OAuth[server] = encrypted
+	return sm.saveLocked()
+}
+
+// accessToken 返回服务器的访问令牌，即将过期时用 refresh_token 刷新；未授权时返回空
+func (sm *MCPSecretManager) accessToken(server string) (string, error) {
+	creds, err := sm.credentials(server)
+	if err != nil || creds == nil {
+		return "", err
+	}
+	if !creds.expiring() {
+		sm.scheduleRefresh(server, creds)
+		return creds.AccessToken, nil
+	}
+
+	// 同一服务器同时只刷新一次，等待的调用直接使用刷新后的令牌
+	lock := sm.refreshLock(server)
+	lock.Lock()
+	defer lock.Unlock()
+	if creds, err = sm.credentials(server); err != nil || creds == nil {
+		return "", err
+	}
+	if !creds.expiring() {
+		return creds.AccessToken, nil
+	}
+	if creds.RefreshToken == "" {
+		return "", fmt.Errorf("%w: 访问令牌已过期", errMCPNeedsAuth)
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {creds.RefreshToken},
+		"client_id":     {creds.ClientID},
+		"resource":      {creds.ServerURL},
+	}
+	if creds.ClientSecret != "" {
+		form.Set("client_secret", creds.ClientSecret)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	token, err := requestMCPToken(ctx, sm.client, creds.TokenEndpoint, form)
+	if err != nil {
+		return "", fmt.Errorf("%w: 刷新令牌失败: %v", errMCPNeedsAuth, err)
+	}
+	creds.apply(token)
+	if err := sm.saveCredentials(server, creds); err != nil {
+		return "", err
+	}
+	sm.scheduleRefresh(server, creds)
+	if sm.onRefresh != nil {
+		go sm.onRefresh(server)
+	}
+	return creds.AccessToken, nil
+}
+
+// expiring 访问令牌是否已过期或将在一分钟内过期
+func (c *mcpOAuthCredentials) expiring() bool {
+	return !c.ExpiresAt.IsZero() && time.Until(c.ExpiresAt) <= time.Minute
+}
+
+// refreshLock 返回服务器的刷新锁
+func (sm *MCPSecretManager) refreshLock(server string) *sync.Mutex {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	lock, ok := sm.refreshing[server]
+	if !ok {
+		lock = &sync.Mutex{}
+		sm.refreshing[server] = lock
+	}
+	return lock
+}
+
+// scheduleRefresh 在令牌过期前主动刷新，刷新后 onRefresh 会把新令牌同步给 OpenCode
+func (sm *MCPSecretManager) scheduleRefresh(server string, creds *mcpOAuthCredentials) {
+	if sm.onRefresh == nil || creds.ExpiresAt.IsZero() || creds.RefreshToken == "" {
+		return
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if t, ok := sm.timers[server]; ok {
+		if t.expiresAt.Equal(creds.ExpiresAt) {
+			return
+		}
+		t.timer.Stop()
+	}
+	// 在 expiring 判定的一分钟窗口内触发，确保会真正刷新
+	delay := time.Until(creds.ExpiresAt) - 30*time.Second
+	sm.timers[server] = &mcpRefreshTimer{
+		expiresAt: creds.ExpiresAt,
+		timer: time.AfterFunc(delay, func() {
+			if _, err := sm.accessToken(server); err != nil {
+				sm.app.emitEvent("output-log", fmt.Sprintf("刷新 MCP 服务器 %s 的令牌失败: %v", server, err))
+			}
+		}),
+	}
+}
+
+// redirectURI 在本地回调服务上注册 MCP 授权回调并返回地址
+func (sm *MCPSecretManager) redirectURI() (string, error) {
+	sm.once.Do(func() {
+		if sm.app.accountMgr == nil || sm.app.accountMgr.authService == nil {
+			sm.redirErr = fmt.Errorf("OAuth 回调服务未启动")
+			return
+		}
+		sm.redirect, sm.redirErr = sm.app.accountMgr.authService.HandleCallbackPath(mcpOAuthCallbackPath, sm.handleCallback)
+	})
+	return sm.redirect, sm.redirErr
+}
+
+// startOAuth 发现授权服务器、注册客户端并返回授权地址
+func (sm *MCPSecretManager) startOAuth(ctx context.Context, name, serverURL, redirectURI string, opts MCPOAuthOptions) (string, error) {
+	meta, scopes, err := discoverMCPAuthServer(ctx, sm.client, serverURL)
+	if err != nil {
+		return "", err
+	}
+	if len(meta.CodeChallengeMethodsSupported) > 0 && !containsString(meta.CodeChallengeMethodsSupported, "S256") {
+		return "", fmt.Errorf("授权服务器不支持 PKCE S256")
+	}
+
+	clientID, clientSecret := opts.ClientID, opts.ClientSecret
+	if clientID == "" {
+		// 复用之前为同一服务器注册的客户端
+		if prev, _ := sm.credentials(name); prev != nil && prev.ServerURL == serverURL && prev.TokenEndpoint == meta.TokenEndpoint {
+			clientID, clientSecret = prev.ClientID, prev.ClientSecret
+		}
+	}
+	if clientID == "" {
+		if meta.RegistrationEndpoint == "" {
+			return "", fmt.Errorf("授权服务器不支持动态注册，请提供 Client ID")
+		}
+		if clientID, clientSecret, err = registerMCPClient(ctx, sm.client, meta.RegistrationEndpoint, redirectURI); err != nil {
+			return "", err
+		}
+	}
+
+	verifier, err := GenerateCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+	state := generateToken()
+	scope := opts.Scope
+	if scope == "" {
+		scope = strings.Join(scopes, " ")
+	}
+
+	authURL, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("授权地址无效: %v", err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("code_challenge", GenerateCodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	q.Set("state", state)
+	q.Set("resource", serverURL)
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	authURL.RawQuery = q.Encode()
+
+	sm.mu.Lock()
+	for s, p := range sm.pending {
+		if time.Since(p.CreatedAt) > mcpOAuthPendingTTL {
+			delete(sm.pending, s)
+		}
+	}
+	sm.pending[state] = &mcpOAuthPending{
+		Server:        name,
+		ServerURL:     serverURL,
+		Verifier:      verifier,
+		RedirectURI:   redirectURI,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		TokenEndpoint: meta.TokenEndpoint,
+		CreatedAt:     time.Now(),
+	}
+	sm.mu.Unlock()
+	return authURL.String(), nil
+}
+
+// completeOAuth 用授权码换取令牌并保存，返回服务器名
+func (sm *MCPSecretManager) completeOAuth(ctx context.Context, state, code string) (string, error) {
+	sm.mu.Lock()
+	pending, ok := sm.pending[state]
+	delete(sm.pending, state)
+	sm.mu.Unlock()
+	if !ok || time.Since(pending.CreatedAt) > mcpOAuthPendingTTL {
+		return "", fmt.Errorf("授权请求不存在或已过期")
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {pending.RedirectURI},
+		"client_id":     {pending.ClientID},
+		"code_verifier": {pending.Verifier},
+		"resource":      {pending.ServerURL},
+	}
+	if pending.ClientSecret != "" {
+		form.Set("client_secret", pending.ClientSecret)
+	}
+	token, err := requestMCPToken(ctx, sm.client, pending.TokenEndpoint, form)
+	if err != nil {
+		return pending.Server, err
+	}
+	creds := &mcpOAuthCredentials{
+		ServerURL:     pending.ServerURL,
+		ClientID:      pending.ClientID,
+		ClientSecret:  pending.ClientSecret,
+		TokenEndpoint: pending.TokenEndpoint,
+	}
+	creds.apply(token)
+	return pending.Server, sm.saveCredentials(pending.Server, creds)
+}
+
+// handleCallback 处理浏览器授权后的回调
+func (sm *MCPSecretManager) handleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	as := sm.app.accountMgr.authService
+	if errStr := query.Get("error"); errStr != "" {
+		sm.app.emitEvent("mcp-oauth-complete", map[string]interface{}{"success": false, "error": errStr})
+		as.writeAuthResponse(w, false, fmt.Sprintf("Authorization failed: %s", errStr))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	server, err := sm.completeOAuth(ctx, query.Get("state"), query.Get("code"))
+	if err != nil {
+		sm.app.emitEvent("mcp-oauth-complete", map[string]interface{}{"server": server, "success": false, "error": err.Error()})
+		as.writeAuthResponse(w, false, "Authorization failed")
+		return
+	}
+
+	sm.app.mcpMgr.reset()
+	go sm.app.syncSecretMCPServers()
+	sm.app.emitEvent("mcp-oauth-complete", map[string]interface{}{"server": server, "success": true})
+	as.writeAuthResponse(w, true, "MCP server authorized! You can close this window.")
+}
+
+// status 返回授权状态
+func (sm *MCPSecretManager) status(server string) (*MCPOAuthStatus, error) {
+	creds, err := sm.credentials(server)
+	if err != nil {
+		return nil, err
+	}
+	status := &MCPOAuthStatus{Server: server}
+	if creds != nil {
+		status.Authorized = creds.AccessToken != "" && (creds.ExpiresAt.IsZero() || time.Now().Before(creds.ExpiresAt) || creds.RefreshToken != "")
+		status.ExpiresAt = creds.ExpiresAt
+		status.Scope = creds.Scope
+		status.Refreshable = creds.RefreshToken != ""
+	}
+	return status, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// --- App API ---
+
+// StartMCPOAuth 为远程 MCP 服务器发起 OAuth 2.1 授权码 + PKCE 授权，在浏览器中打开授权页面
+func (a *App) StartMCPOAuth(name string, opts MCPOAuthOptions) (string, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return "", err
+	}
+	server, ok := config.MCP[name]
+	if !ok {
+		return "", fmt.Errorf("MCP 服务器不存在: %s", name)
+	}
+	if server.Type != "remote" || server.URL == "" {
+		return "", fmt.Errorf("只有远程 MCP 服务器支持 OAuth 授权")
+	}
+	redirectURI, err := a.mcpSecrets.redirectURI()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	authURL, err := a.mcpSecrets.startOAuth(ctx, name, server.URL, redirectURI, opts)
+	if err != nil {
+		return "", err
+	}
+	if a.ctx != nil {
+		runtime.BrowserOpenURL(a.ctx, authURL)
+	}
+	return authURL, nil
+}
+
+// GetMCPOAuthStatus 获取远程 MCP 服务器的授权状态
+func (a *App) GetMCPOAuthStatus(name string) (*MCPOAuthStatus, error) {
+	return a.mcpSecrets.status(name)
+}
+
+// LogoutMCPOAuth 删除远程 MCP 服务器保存的令牌
+func (a *App) LogoutMCPOAuth(name string) error {
+	if err := a.mcpSecrets.saveCredentials(name, nil); err != nil {
+		return err
+	}
+	a.mcpMgr.reset()
+	return nil
+}
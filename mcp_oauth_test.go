@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is an MCP resource server plus authorization server for OAuth tests
+type fakeAuthServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	forms     []url.Values
+	expiresIn int
+	failToken bool
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	t.Helper()
+	f := &fakeAuthServer{expiresIn: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="`+f.URL+`/meta/prm"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/meta/prm", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"resource": f.URL + "/mcp", "authorization_servers": []string{f.URL + "/auth"}, "scopes_supported": []string{"read", "write"}})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server/auth", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                           f.URL + "/auth",
+			"authorization_endpoint":           f.URL + "/auth/authorize",
+			"token_endpoint":                   f.URL + "/auth/token",
+			"registration_endpoint":            f.URL + "/auth/register",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+	mux.HandleFunc("/auth/register", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"client_id": "client-1"})
+	})
+	mux.HandleFunc("/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f.mu.Lock()
+		f.forms = append(f.forms, r.PostForm)
+		fail, expiresIn := f.failToken, f.expiresIn
+		f.mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		resp := map[string]interface{}{"access_token": "at-" + r.PostForm.Get("grant_type"), "token_type": "Bearer", "expires_in": expiresIn}
+		if r.PostForm.Get("grant_type") == "authorization_code" {
+			resp["refresh_token"] = "rt-1"
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// TestDiscoverMCPAuthServer tests protected resource and authorization server metadata discovery
+func TestDiscoverMCPAuthServer(t *testing.T) {
+	f := newFakeAuthServer(t)
+	meta, scopes, err := discoverMCPAuthServer(context.Background(), f.Client(), f.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("discoverMCPAuthServer() error = %v", err)
+	}
+	if meta.TokenEndpoint != f.URL+"/auth/token" || meta.RegistrationEndpoint != f.URL+"/auth/register" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if strings.Join(scopes, " ") != "read write" {
+		t.Errorf("scopes = %v", scopes)
+	}
+
+	// without any metadata the default endpoints on the server origin are used
+	bare := httptest.NewServer(http.NotFoundHandler())
+	defer bare.Close()
+	meta, _, err = discoverMCPAuthServer(context.Background(), bare.Client(), bare.URL+"/mcp")
+	if err != nil || meta.AuthorizationEndpoint != bare.URL+"/authorize" {
+		t.Errorf("fallback meta = %+v, %v", meta, err)
+	}
+}
+
+// TestMCPOAuthFlow tests the authorization code + PKCE flow, token storage, refresh and logout
+func TestMCPOAuthFlow(t *testing.T) {
+	f := newFakeAuthServer(t)
+	app := newTestSecretApp(t)
+	sm := app.mcpSecrets
+	serverURL := f.URL + "/mcp"
+	redirect := "http://127.0.0.1:54321/mcp/oauth/callback"
+
+	authURL, err := sm.startOAuth(context.Background(), "remote", serverURL, redirect, MCPOAuthOptions{})
+	if err != nil {
+		t.Fatalf("startOAuth() error = %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if u.Path != "/auth/authorize" || q.Get("client_id") != "client-1" || q.Get("code_challenge_method") != "S256" ||
+		q.Get("resource") != serverURL || q.Get("scope") != "read write" || q.Get("redirect_uri") != redirect {
+		t.Fatalf("authURL = %s", authURL)
+	}
+
+	if _, err := sm.completeOAuth(context.Background(), "wrong-state", "code"); err == nil {
+		t.Error("completeOAuth() with unknown state should fail")
+	}
+	server, err := sm.completeOAuth(context.Background(), q.Get("state"), "code-1")
+	if err != nil || server != "remote" {
+		t.Fatalf("completeOAuth() = %q, %v", server, err)
+	}
+	form := f.forms[len(f.forms)-1]
+	if GenerateCodeChallenge(form.Get("code_verifier")) != q.Get("code_challenge") || form.Get("code") != "code-1" {
+		t.Errorf("token request = %v", form)
+	}
+
+	resolved, err := app.resolveMCPServer("remote", MCPServer{Type: "remote", URL: serverURL})
+	if err != nil || resolved.Headers["Authorization"] != "Bearer at-authorization_code" {
+		t.Errorf("resolved headers = %v, %v", resolved.Headers, err)
+	}
+	if status, _ := sm.status("remote"); !status.Authorized || !status.Refreshable {
+		t.Errorf("status = %+v", status)
+	}
+
+	// expire the token so the next use refreshes it; concurrent callers share one refresh
+	refreshed := make(chan string, 10)
+	sm.onRefresh = func(server string) { refreshed <- server }
+	creds, _ := sm.credentials("remote")
+	creds.ExpiresAt = time.Now().Add(-time.Minute)
+	sm.saveCredentials("remote", creds)
+	f.mu.Lock()
+	formsBefore := len(f.forms)
+	f.mu.Unlock()
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = sm.accessToken("remote")
+		}(i)
+	}
+	wg.Wait()
+	for _, token := range tokens {
+		if token != "at-refresh_token" {
+			t.Errorf("accessToken() after expiry = %q", token)
+		}
+	}
+	f.mu.Lock()
+	if n := len(f.forms) - formsBefore; n != 1 {
+		t.Errorf("Expected 1 refresh request, got %d", n)
+	}
+	f.mu.Unlock()
+	select {
+	case server := <-refreshed:
+		if server != "remote" {
+			t.Errorf("onRefresh() server = %q", server)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected onRefresh to be called after a refresh")
+	}
+	if form := f.forms[len(f.forms)-1]; form.Get("refresh_token") != "rt-1" || form.Get("client_id") != "client-1" {
+		t.Errorf("refresh request = %v", form)
+	}
+	if creds, _ := sm.credentials("remote"); creds.RefreshToken != "rt-1" {
+		t.Error("refresh token not kept when the server does not rotate it")
+	}
+
+	// a failed refresh surfaces as needs_auth
+	creds, _ = sm.credentials("remote")
+	creds.ExpiresAt = time.Now().Add(-time.Minute)
+	sm.saveCredentials("remote", creds)
+	f.mu.Lock()
+	f.failToken = true
+	f.mu.Unlock()
+	if _, err := sm.accessToken("remote"); err == nil || !errors.Is(err, errMCPNeedsAuth) {
+		t.Errorf("accessToken() with failing refresh error = %v", err)
+	}
+
+	// re-authorizing reuses the registered client
+	if authURL, err := sm.startOAuth(context.Background(), "remote", serverURL, redirect, MCPOAuthOptions{Scope: "read"}); err != nil || !strings.Contains(authURL, "client_id=client-1") || !strings.Contains(authURL, "scope=read&") {
+		t.Errorf("second startOAuth() = %s, %v", authURL, err)
+	}
+
+	if err := sm.saveCredentials("remote", nil); err != nil {
+		t.Fatalf("saveCredentials(nil) error = %v", err)
+	}
+	if token, err := sm.accessToken("remote"); token != "" || err != nil {
+		t.Errorf("accessToken() after logout = %q, %v", token, err)
+	}
+}
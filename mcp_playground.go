@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCPTrafficEntry 一条 JSON-RPC 往来记录
+type MCPTrafficEntry struct {
+	Direction string          `json:"direction"` // send 或 recv
+	Method    string          `json:"method"`
+	Message   json.RawMessage `json:"message,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Time      time.Time       `json:"time"`
+}
+
+// MCPValidationError 参数校验错误
+type MCPValidationError struct {
+	Path    string `json:"path"` // 如 $.options.limit
+	Message string `json:"message"`
+}
+
+// MCPContent 工具返回的内容块
+type MCPContent struct {
+	Type     string          `json:"type"` // text, image, audio, resource, resource_link
+	Text     string          `json:"text,omitempty"`
+	Data     string          `json:"data,omitempty"` // base64 编码的图片或音频
+	MimeType string          `json:"mimeType,omitempty"`
+	URI      string          `json:"uri,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Resource *MCPResourceRef `json:"resource,omitempty"`
+}
+
+// MCPResourceRef 内嵌资源内容
+type MCPResourceRef struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// MCPToolCallResult 调用工具的结果
+type MCPToolCallResult struct {
+	Server            string               `json:"server"`
+	Tool              string               `json:"tool"`
+	Called            bool                 `json:"called"` // 参数校验失败时不会调用
+	IsError           bool                 `json:"isError"`
+	Error             string               `json:"error,omitempty"`
+	ValidationErrors  []MCPValidationError `json:"validationErrors"`
+	Content           []MCPContent         `json:"content"`
+	StructuredContent interface{}          `json:"structuredContent,omitempty"`
+	ConnectMs         int64                `json:"connectMs"`
+	CallMs            int64                `json:"callMs"`
+	Traffic           []MCPTrafficEntry    `json:"traffic"`
+}
+
+// mcpRecorder 记录经过传输层的请求和响应
+type mcpRecorder struct {
+	inner   mcpTransport
+	entries []MCPTrafficEntry
+	mu      sync.Mutex
+}
+
+func (r *mcpRecorder) record(entry MCPTrafficEntry) {
+	entry.Time = time.Now()
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+func (r *mcpRecorder) send(ctx context.Context, req jsonRPCRequest) (*jsonRPCMessage, error) {
+	data, _ := json.Marshal(req)
+	r.record(MCPTrafficEntry{Direction: "send", Method: req.Method, Message: data})
+	resp, err := r.inner.send(ctx, req)
+	switch {
+	case err != nil:
+		r.record(MCPTrafficEntry{Direction: "recv", Method: req.Method, Error: err.Error()})
+	case resp != nil:
+		data, _ := json.Marshal(resp)
+		r.record(MCPTrafficEntry{Direction: "recv", Method: req.Method, Message: data})
+	}
+	return resp, err
+}
+
+func (r *mcpRecorder) setProtocolVersion(version string) {
+	if vt, ok := r.inner.(mcpVersionedTransport); ok {
+		vt.setProtocolVersion(version)
+	}
+}
+
+func (r *mcpRecorder) close() error {
+	return r.inner.close()
+}
+
+func (r *mcpRecorder) traffic() []MCPTrafficEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MCPTrafficEntry{}, r.entries...)
+}
+
+// callMCPTool 连接服务器，校验参数后调用工具（服务器无需已启用）
+func callMCPTool(ctx context.Context, name string, server MCPServer, dir, tool string, args map[string]interface{}) *MCPToolCallResult {
+	result := &MCPToolCallResult{
+		Server:           name,
+		Tool:             tool,
+		ValidationErrors: []MCPValidationError{},
+		Content:          []MCPContent{},
+	}
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout(server))
+	defer cancel()
+
+	transport, err := newMCPTransport(server, dir)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	rec := &mcpRecorder{inner: transport}
+	defer func() { result.Traffic = rec.traffic() }()
+
+	start := time.Now()
+	client, err := initializeMCP(ctx, rec)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer client.close()
+
+	list, err := client.list(ctx, "tools/list")
+	result.ConnectMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	var info *MCPToolInfo
+	for i := range list.Tools {
+		if list.Tools[i].Name == tool {
+			info = &list.Tools[i]
+			break
+		}
+	}
+	if info == nil {
+		result.Error = fmt.Sprintf("服务器没有提供工具: %s", tool)
+		return result
+	}
+
+	if len(info.InputSchema) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(info.InputSchema, &schema); err == nil {
+			result.ValidationErrors = validateJSONSchema(schema, args, "$")
+		}
+	}
+	if len(result.ValidationErrors) > 0 {
+		return result
+	}
+
+	var call struct {
+		Content           []MCPContent `json:"content"`
+		StructuredContent interface{}  `json:"structuredContent"`
+		IsError           bool         `json:"isError"`
+	}
+	start = time.Now()
+	err = client.call(ctx, "tools/call", map[string]interface{}{"name": tool, "arguments": args}, &call)
+	result.CallMs = time.Since(start).Milliseconds()
+	result.Called = true
+	if err != nil {
+		result.IsError = true
+		result.Error = err.Error()
+		return result
+	}
+	if call.Content != nil {
+		result.Content = call.Content
+	}
+	result.StructuredContent = call.StructuredContent
+	result.IsError = call.IsError
+	return result
+}
+
+// validateJSONSchema 按 JSON Schema 的常用子集校验参数
+// 支持 type、required、properties、additionalProperties、items、enum、const、
+// minimum/maximum、minLength/maxLength、minItems/maxItems 和 pattern
+func validateJSONSchema(schema map[string]interface{}, value interface{}, path string) []MCPValidationError {
+	errs := []MCPValidationError{}
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, MCPValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		actual := jsonTypeOf(value)
+		ok := false
+		for _, t := range types {
+			if t == actual || (t == "number" && actual == "integer") {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			fail("类型应为 %s，实际为 %s", strings.Join(types, " | "), actual)
+			return errs
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("取值必须是 %s 之一", jsonString(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		fail("取值必须为 %s", jsonString(c))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				if key, ok := r.(string); ok {
+					if _, present := v[key]; !present {
+						errs = append(errs, MCPValidationError{Path: path + "." + key, Message: "缺少必填参数"})
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if sub, ok := props[key].(map[string]interface{}); ok {
+				errs = append(errs, validateJSONSchema(sub, v[key], path+"."+key)...)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					errs = append(errs, MCPValidationError{Path: path + "." + key, Message: "不允许的参数"})
+				}
+			case map[string]interface{}:
+				errs = append(errs, validateJSONSchema(extra, v[key], path+"."+key)...)
+			}
+		}
+	case []interface{}:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < n {
+			fail("至少需要 %v 项", n)
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > n {
+			fail("最多允许 %v 项", n)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateJSONSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if n, ok := schemaNumber(schema, "minLength"); ok && length < n {
+			fail("长度不能少于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && length > n {
+			fail("长度不能超过 %v", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("不匹配格式 %s", pattern)
+			}
+		}
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			fail("不能小于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			fail("不能大于 %v", n)
+		}
+	}
+	return errs
+}
+
+// schemaTypes 读取 type 字段（字符串或字符串数组）
+func schemaTypes(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+// jsonTypeOf 返回 JSON 值的 Schema 类型名
+func jsonTypeOf(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonString(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	return jsonString(a) == jsonString(b)
+}
+
+// --- App API ---
+
+// CallMCPTool 在调试面板中调用 MCP 工具，返回内容、耗时和原始 JSON-RPC 往来
+func (a *App) CallMCPTool(server, tool string, args map[string]interface{}) (*MCPToolCallResult, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := config.MCP[server]
+	if !ok {
+		return nil, fmt.Errorf("MCP 服务器不存在: %s", server)
+	}
+	if cfg, err = a.resolveMCPServer(server, cfg); err != nil {
+		return nil, err
+	}
+	return callMCPTool(context.Background(), server, cfg, a.mcpMgr.workDir(), tool, args), nil
+}
+
+// ValidateMCPToolArgs 只校验参数，不调用工具
+func (a *App) ValidateMCPToolArgs(schema map[string]interface{}, args map[string]interface{}) []MCPValidationError {
+	return validateJSONSchema(schema, args, "$")
+}
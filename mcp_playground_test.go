@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCallMCPTool tests invoking a tool through the fake stdio server and recording traffic
+func TestCallMCPTool(t *testing.T) {
+	result := callMCPTool(context.Background(), "fake", helperServer("1"), "", "read_file", map[string]interface{}{"path": "a.txt"})
+	if result.Error != "" || !result.Called || result.IsError {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(result.Content) != 3 || result.Content[0].Text != "hello" || result.Content[1].Data != "aGk=" || result.Content[2].Resource.URI != "file:///a.txt" {
+		t.Errorf("content = %+v", result.Content)
+	}
+	if got := jsonString(result.StructuredContent); got != `{"size":5}` {
+		t.Errorf("structuredContent = %s", got)
+	}
+
+	var methods []string
+	for _, e := range result.Traffic {
+		methods = append(methods, e.Direction+":"+e.Method)
+	}
+	want := "send:initialize,recv:initialize,send:notifications/initialized,send:tools/list,recv:tools/list,send:tools/list,recv:tools/list,send:tools/call,recv:tools/call"
+	if strings.Join(methods, ",") != want {
+		t.Errorf("traffic = %v", methods)
+	}
+}
+
+// TestCallMCPToolErrors tests validation failures, unknown tools and tool-reported errors
+func TestCallMCPToolErrors(t *testing.T) {
+	result := callMCPTool(context.Background(), "fake", helperServer("1"), "", "read_file", map[string]interface{}{"path": 1})
+	if result.Called || len(result.ValidationErrors) != 1 || result.ValidationErrors[0].Path != "$.path" {
+		t.Errorf("invalid args result = %+v", result)
+	}
+
+	result = callMCPTool(context.Background(), "fake", helperServer("1"), "", "nope", nil)
+	if result.Called || !strings.Contains(result.Error, "nope") {
+		t.Errorf("unknown tool result = %+v", result)
+	}
+
+	result = callMCPTool(context.Background(), "fake", helperServer("1"), "", "read_file", map[string]interface{}{"path": "missing"})
+	if !result.Called || !result.IsError || result.Content[0].Text != "not found" {
+		t.Errorf("tool error result = %+v", result)
+	}
+}
+
+// TestValidateJSONSchema tests the supported JSON Schema keywords
+func TestValidateJSONSchema(t *testing.T) {
+	schema := map[string]interface{}{}
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["query"],
+		"additionalProperties": false,
+		"properties": {
+			"query": {"type": "string", "minLength": 1, "pattern": "^[a-z ]*$"},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+			"mode": {"enum": ["fast", "full"]},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+			"score": {"type": ["number", "null"]}
+		}
+	}`), &schema)
+
+	tests := []struct {
+		name string
+		args string
+		want []string // "path: message fragment"
+	}{
+		{"valid", `{"query": "hello", "limit": 10, "mode": "fast", "tags": ["a"], "score": 0.5}`, nil},
+		{"null union", `{"query": "hello", "score": null}`, nil},
+		{"missing required", `{}`, []string{"$.query: 缺少必填参数"}},
+		{"wrong types", `{"query": 1, "limit": 1.5}`, []string{"$.limit: 类型应为 integer", "$.query: 类型应为 string"}},
+		{"bounds", `{"query": "", "limit": 0}`, []string{"$.limit: 不能小于", "$.query: 长度不能少于"}},
+		{"pattern", `{"query": "ABC"}`, []string{"$.query: 不匹配格式"}},
+		{"enum", `{"query": "a", "mode": "slow"}`, []string{"$.mode: 取值必须是"}},
+		{"items", `{"query": "a", "tags": ["a", 2, "c"]}`, []string{"$.tags: 最多允许", "$.tags[1]: 类型应为 string"}},
+		{"additional", `{"query": "a", "extra": true}`, []string{"$.extra: 不允许的参数"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args map[string]interface{}
+			json.Unmarshal([]byte(tt.args), &args)
+			errs := validateJSONSchema(schema, args, "$")
+			if len(errs) != len(tt.want) {
+				t.Fatalf("errors = %+v, want %v", errs, tt.want)
+			}
+			for i, e := range errs {
+				if got := e.Path + ": " + e.Message; !strings.HasPrefix(got, tt.want[i]) {
+					t.Errorf("error[%d] = %q, want prefix %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MCPProbeResult 直接连接 MCP 服务器得到的健康检查结果
+type MCPProbeResult struct {
+	Name            string            `json:"name"`
+	Status          string            `json:"status"` // connected, disabled, failed, needs_auth
+	Error           string            `json:"error,omitempty"`
+	Warnings        []string          `json:"warnings,omitempty"` // 列表请求失败等非致命问题
+	ServerInfo      MCPServerInfo     `json:"serverInfo"`
+	ProtocolVersion string            `json:"protocolVersion,omitempty"`
+	InitLatencyMs   int64             `json:"initLatencyMs"` // 启动/连接并完成 initialize 的耗时
+	LatencyMs       int64             `json:"latencyMs"`     // 整个探测耗时
+	Tools           []MCPToolInfo     `json:"tools"`
+	Resources       []MCPResourceInfo `json:"resources"`
+	Prompts         []MCPPromptInfo   `json:"prompts"`
+	CheckedAt       time.Time         `json:"checkedAt"`
+}
+
+// mcpProbeMaxAge 状态和工具列表复用探测结果的最长时间（设置面板会定时刷新状态）
+const mcpProbeMaxAge = 60 * time.Second
+
+// MCPManager 直接与 MCP 服务器通信，缓存最近的探测结果
+type MCPManager struct {
+	app      *App
+	results  map[string]*MCPProbeResult
+	inflight map[string]*mcpProbeCall // 同一服务器的并发探测只执行一次
+	mu       sync.Mutex
+}
+
+// mcpProbeCall 进行中的探测
+type mcpProbeCall struct {
+	done   chan struct{}
+	result *MCPProbeResult
+}
+
+// NewMCPManager 创建 MCP 管理器
+func NewMCPManager(app *App) *MCPManager {
+	return &MCPManager{
+		app:      app,
+		results:  make(map[string]*MCPProbeResult),
+		inflight: make(map[string]*mcpProbeCall),
+	}
+}
+
+// probeTimeout 返回服务器的探测超时（Timeout 单位为毫秒）
+func probeTimeout(server MCPServer) time.Duration {
+	if server.Timeout > 0 {
+		return time.Duration(server.Timeout) * time.Millisecond
+	}
+	return mcpDefaultTimeout
+}
+
+// probeMCPServer 连接服务器，完成握手并列出工具、资源和提示模板
+func probeMCPServer(ctx context.Context, name string, server MCPServer, dir string) *MCPProbeResult {
+	result := &MCPProbeResult{
+		Name:      name,
+		Tools:     []MCPToolInfo{},
+		Resources: []MCPResourceInfo{},
+		Prompts:   []MCPPromptInfo{},
+		CheckedAt: time.Now(),
+	}
+	start := time.Now()
+	defer func() { result.LatencyMs = time.Since(start).Milliseconds() }()
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout(server))
+	defer cancel()
+
+	client, err := connectMCP(ctx, server, dir)
+	result.InitLatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = "failed"
+		if errors.Is(err, errMCPNeedsAuth) {
+			result.Status = "needs_auth"
+		}
+		result.Error = err.Error()
+		return result
+	}
+	defer client.close()
+
+	result.Status = "connected"
+	result.ServerInfo = client.serverInfo
+	result.ProtocolVersion = client.protocolVersion
+
+	// 只请求服务器声明支持的列表
+	for _, kind := range []string{"tools", "resources", "prompts"} {
+		if !client.hasCapability(kind) {
+			continue
+		}
+		list, err := client.list(ctx, kind+"/list")
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s/list: %v", kind, err))
+			continue
+		}
+		switch kind {
+		case "tools":
+			result.Tools = append(result.Tools, list.Tools...)
+		case "resources":
+			result.Resources = append(result.Resources, list.Resources...)
+		case "prompts":
+			result.Prompts = append(result.Prompts, list.Prompts...)
+		}
+	}
+	return result
+}
+
+// workDir 返回启动本地服务器使用的工作目录
+func (mm *MCPManager) workDir() string {
+	if mm.app.openCode != nil {
+		return mm.app.openCode.GetWorkDir()
+	}
+	return ""
+}
+
+// probe 探测单个服务器并缓存结果；同一服务器已在探测时等待其结果
+func (mm *MCPManager) probe(name string, server MCPServer) *MCPProbeResult {
+	mm.mu.Lock()
+	if call, ok := mm.inflight[name]; ok {
+		mm.mu.Unlock()
+		<-call.done
+		return call.result
+	}
+	call := &mcpProbeCall{done: make(chan struct{})}
+	mm.inflight[name] = call
+	mm.mu.Unlock()
+
+	result := mm.resolveAndProbe(name, server)
+
+	mm.mu.Lock()
+	mm.results[name] = result
+	delete(mm.inflight, name)
+	mm.mu.Unlock()
+	call.result = result
+	close(call.done)
+
+	mm.app.emitEvent("mcp-probe-result", result)
+	return result
+}
+
+// resolveAndProbe 解析密钥引用和 OAuth 令牌后探测服务器
+func (mm *MCPManager) resolveAndProbe(name string, server MCPServer) *MCPProbeResult {
+	resolved, err := mm.app.resolveMCPServer(name, server)
+	if err != nil {
+		status := "failed"
+		if errors.Is(err, errMCPNeedsAuth) {
+			status = "needs_auth"
+		}
+		return &MCPProbeResult{
+			Name:      name,
+			Status:    status,
+			Error:     err.Error(),
+			Tools:     []MCPToolInfo{},
+			Resources: []MCPResourceInfo{},
+			Prompts:   []MCPPromptInfo{},
+			CheckedAt: time.Now(),
+		}
+	}
+	return probeMCPServer(context.Background(), name, resolved, mm.workDir())
+}
+
+// checkAll 并发探测所有已配置的服务器，禁用的服务器不启动
+func (mm *MCPManager) checkAll(config *MCPConfig, maxAge time.Duration) map[string]*MCPProbeResult {
+	results := make(map[string]*MCPProbeResult)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	set := func(name string, result *MCPProbeResult) {
+		mu.Lock()
+		results[name] = result
+		mu.Unlock()
+	}
+	for name, server := range config.MCP {
+		if !server.Enabled {
+			set(name, &MCPProbeResult{Name: name, Status: "disabled", CheckedAt: time.Now()})
+			continue
+		}
+		if cached := mm.cached(name, maxAge); cached != nil {
+			set(name, cached)
+			continue
+		}
+		wg.Add(1)
+		go func(name string, server MCPServer) {
+			defer wg.Done()
+			set(name, mm.probe(name, server))
+		}(name, server)
+	}
+	wg.Wait()
+	return results
+}
+
+// cachedAll 只返回已缓存的探测结果（不论是否过期），不等待探测；
+// 缺失或过期的服务器在后台重新探测，供下次使用
+func (mm *MCPManager) cachedAll(config *MCPConfig, maxAge time.Duration) map[string]*MCPProbeResult {
+	results := make(map[string]*MCPProbeResult)
+	for name, server := range config.MCP {
+		if !server.Enabled {
+			continue
+		}
+		mm.mu.Lock()
+		r, ok := mm.results[name]
+		_, running := mm.inflight[name]
+		mm.mu.Unlock()
+		if ok {
+			results[name] = r
+		}
+		if !running && (!ok || time.Since(r.CheckedAt) >= maxAge) {
+			go mm.probe(name, server)
+		}
+	}
+	return results
+}
+
+// cached 返回未过期的探测结果
+func (mm *MCPManager) cached(name string, maxAge time.Duration) *MCPProbeResult {
+	if maxAge <= 0 {
+		return nil
+	}
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	if r, ok := mm.results[name]; ok && time.Since(r.CheckedAt) < maxAge {
+		return r
+	}
+	return nil
+}
+
+// reset 清除缓存的探测结果（配置变更后调用）
+func (mm *MCPManager) reset() {
+	if mm == nil {
+		return
+	}
+	mm.mu.Lock()
+	mm.results = make(map[string]*MCPProbeResult)
+	mm.mu.Unlock()
+}
+
+var mcpToolNameRe = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// mcpToolsFromProbes 将探测到的工具转换为带服务器前缀的工具列表
+func mcpToolsFromProbes(results map[string]*MCPProbeResult) []MCPTool {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := []MCPTool{}
+	for _, name := range names {
+		for _, t := range results[name].Tools {
+			var params interface{}
+			if len(t.InputSchema) > 0 {
+				json.Unmarshal(t.InputSchema, &params)
+			}
+			tools = append(tools, MCPTool{
+				ID:          fmt.Sprintf("mcp_%s_%s", name, mcpToolNameRe.ReplaceAllString(t.Name, "_")),
+				Server:      name,
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  params,
+			})
+		}
+	}
+	return tools
+}
+
+// --- App API ---
+
+// ProbeMCPServer 直接连接已配置的 MCP 服务器并返回健康检查结果
+func (a *App) ProbeMCPServer(name string) (*MCPProbeResult, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	server, ok := config.MCP[name]
+	if !ok {
+		return nil, fmt.Errorf("MCP 服务器不存在: %s", name)
+	}
+	return a.mcpMgr.probe(name, server), nil
+}
+
+// TestMCPServer 在保存前测试一个 MCP 服务器配置（结果不缓存）
+func (a *App) TestMCPServer(name string, server MCPServer) *MCPProbeResult {
+	return a.mcpMgr.resolveAndProbe(name, server)
+}
+
+// CheckMCPHealth 探测所有已配置的 MCP 服务器
+func (a *App) CheckMCPHealth() (map[string]*MCPProbeResult, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	results := a.mcpMgr.checkAll(config, 0)
+	a.emitEvent("mcp-health", results)
+	return results, nil
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MCP 配置中的密钥引用格式：{secret:NAME}，与 OpenCode 的 {env:VAR}、{file:path} 写法一致
+var mcpSecretRefRe = regexp.MustCompile(`\{secret:([A-Za-z0-9_.-]+)\}`)
+
+var (
+	mcpSecretNameRe    = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	mcpSecretInvalidRe = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// MCPSecretInfo 密钥信息（不包含值）
+type MCPSecretInfo struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UsedBy    []string  `json:"usedBy"` // 引用该密钥的服务器
+}
+
+// mcpStoredSecret 加密保存的密钥
+type mcpStoredSecret struct {
+	Value     string    `json:"value"` // CryptoService 加密后的 base64
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// mcpSecretFile 密钥文件内容
+type mcpSecretFile struct {
+	Secrets map[string]mcpStoredSecret `json:"secrets"`
+	OAuth   map[string]string          `json:"oauth"` // 服务器名 -> 加密的 OAuth 凭据 JSON
+}
+
+// MCPSecretManager 管理 MCP 密钥和远程服务器的 OAuth 凭据，均加密保存在数据目录中
+type MCPSecretManager struct {
+	app      *App
+	dir      string // 密钥文件保存目录
+	crypto   *CryptoService
+	data     *mcpSecretFile
+	pending  map[string]*mcpOAuthPending // state -> 进行中的授权
+	client   *http.Client
+	redirect string // OAuth 回调地址
+	redirErr error
+	once     sync.Once
+	mu       sync.Mutex
+
+	refreshing map[string]*sync.Mutex      // 按服务器串行化令牌刷新
+	timers     map[string]*mcpRefreshTimer // 令牌过期前的自动刷新
+	onRefresh  func(server string)         // 令牌刷新后调用（重新同步到 OpenCode）
+}
+
+// mcpRefreshTimer 令牌自动刷新定时器
+type mcpRefreshTimer struct {
+	timer     *time.Timer
+	expiresAt time.Time
+}
+
+// NewMCPSecretManager 创建 MCP 密钥管理器
+func NewMCPSecretManager(app *App) *MCPSecretManager {
+	return &MCPSecretManager{
+		app:     app,
+		crypto:  NewCryptoService(defaultMasterKey),
+		pending: make(map[string]*mcpOAuthPending),
+		client:  &http.Client{Timeout: 30 * time.Second},
+
+		refreshing: make(map[string]*sync.Mutex),
+		timers:     make(map[string]*mcpRefreshTimer),
+	}
+}
+
+// loadLocked 首次使用时读取密钥文件（调用方需持有锁）
+func (sm *MCPSecretManager) loadLocked() error {
+	if sm.data != nil {
+		return nil
+	}
+	if sm.dir == "" {
+		dir, err := sm.app.getDataDir("mcp")
+		if err != nil {
+			return err
+		}
+		sm.dir = dir
+	}
+	data := &mcpSecretFile{}
+	if raw, err := os.ReadFile(filepath.Join(sm.dir, "secrets.json")); err == nil {
+		if err := json.Unmarshal(raw, data); err != nil {
+			return fmt.Errorf("解析密钥文件失败: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	if data.Secrets == nil {
+		data.Secrets = make(map[string]mcpStoredSecret)
+	}
+	if data.OAuth == nil {
+		data.OAuth = make(map[string]string)
+	}
+	sm.data = data
+	return nil
+}
+
+// saveLocked 保存密钥文件（调用方需持有锁）
+func (sm *MCPSecretManager) saveLocked() error {
+	raw, err := json.MarshalIndent(sm.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(sm.dir, 0700); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	return writeFileAtomic(filepath.Join(sm.dir, "secrets.json"), raw, 0600)
+}
+
+// Set 保存或更新密钥
+func (sm *MCPSecretManager) Set(name, value string) error {
+	if !mcpSecretNameRe.MatchString(name) {
+		return fmt.Errorf("密钥名称无效: %s", name)
+	}
+	if value == "" {
+		return fmt.Errorf("密钥值不能为空")
+	}
+	encrypted, err := sm.crypto.EncryptString(value)
+	if err != nil {
+		return fmt.Errorf("加密密钥失败: %v", err)
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if err := sm.loadLocked(); err != nil {
+		return err
+	}
+	sm.data.Secrets[name] = mcpStoredSecret{Value: encrypted, UpdatedAt: time.Now()}
+	return sm.saveLocked()
+}
+
+// Delete 删除密钥
+func (sm *MCPSecretManager) Delete(name string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if err := sm.loadLocked(); err != nil {
+		return err
+	}
+	if _, ok := sm.data.Secrets[name]; !ok {
+		return fmt.Errorf("密钥不存在: %s", name)
+	}
+	delete(sm.data.Secrets, name)
+	return sm.saveLocked()
+}
+
+// get 解密读取密钥
+func (sm *MCPSecretManager) get(name string) (string, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if err := sm.loadLocked(); err != nil {
+		return "", err
+	}
+	stored, ok := sm.data.Secrets[name]
+	if !ok {
+		return "", fmt.Errorf("密钥不存在: %s", name)
+	}
+	value, err := sm.crypto.DecryptString(stored.Value)
+	if err != nil {
+		return "", fmt.Errorf("解密密钥 %s 失败: %v", name, err)
+	}
+	return value, nil
+}
+
+// List 列出密钥及引用它们的服务器
+func (sm *MCPSecretManager) List(config *MCPConfig) ([]MCPSecretInfo, error) {
+	sm.mu.Lock()
+	if err := sm.loadLocked(); err != nil {
+		sm.mu.Unlock()
+		return nil, err
+	}
+	infos := make([]MCPSecretInfo, 0, len(sm.data.Secrets))
+	for name, stored := range sm.data.Secrets {
+		infos = append(infos, MCPSecretInfo{Name: name, UpdatedAt: stored.UpdatedAt, UsedBy: []string{}})
+	}
+	sm.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	if config != nil {
+		for i := range infos {
+			for server, cfg := range config.MCP {
+				if mcpServerRefs(cfg)[infos[i].Name] {
+					infos[i].UsedBy = append(infos[i].UsedBy, server)
+				}
+			}
+			sort.Strings(infos[i].UsedBy)
+		}
+	}
+	return infos, nil
+}
+
+// mcpServerRefs 返回服务器配置引用的密钥名称
+func mcpServerRefs(server MCPServer) map[string]bool {
+	refs := make(map[string]bool)
+	add := func(v string) {
+		for _, m := range mcpSecretRefRe.FindAllStringSubmatch(v, -1) {
+			refs[m[1]] = true
+		}
+	}
+	for _, values := range []map[string]string{server.Environment, server.Headers} {
+		for _, v := range values {
+			add(v)
+		}
+	}
+	for _, arg := range server.Command {
+		add(arg)
+	}
+	return refs
+}
+
+// resolveString 替换字符串中的密钥引用
+func (sm *MCPSecretManager) resolveString(value string) (string, error) {
+	var firstErr error
+	resolved := mcpSecretRefRe.ReplaceAllStringFunc(value, func(ref string) string {
+		secret, err := sm.get(mcpSecretRefRe.FindStringSubmatch(ref)[1])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return secret
+	})
+	return resolved, firstErr
+}
+
+// resolveMap 返回替换了密钥引用的副本
+func (sm *MCPSecretManager) resolveMap(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(values))
+	for k, v := range values {
+		r, err := sm.resolveString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", k, err)
+		}
+		resolved[k] = r
+	}
+	return resolved, nil
+}
+
+// resolveMCPServer 返回可直接使用的服务器配置：替换密钥引用，并为远程服务器附加 OAuth 令牌
+// 配置文件中始终只保存引用，解析后的值只用于启动服务器和同步到 OpenCode
+func (a *App) resolveMCPServer(name string, server MCPServer) (MCPServer, error) {
+	sm := a.mcpSecrets
+	if sm == nil {
+		return server, nil
+	}
+	var err error
+	if server.Environment, err = sm.resolveMap(server.Environment); err != nil {
+		return server, err
+	}
+	if server.Headers, err = sm.resolveMap(server.Headers); err != nil {
+		return server, err
+	}
+	if len(server.Command) > 0 {
+		command := make([]string, len(server.Command))
+		for i, arg := range server.Command {
+			if command[i], err = sm.resolveString(arg); err != nil {
+				return server, fmt.Errorf("command: %v", err)
+			}
+		}
+		server.Command = command
+	}
+	if server.Type == "remote" {
+		token, err := sm.accessToken(name)
+		if err != nil {
+			return server, err
+		}
+		if token != "" && server.Headers["Authorization"] == "" {
+			if server.Headers == nil {
+				server.Headers = make(map[string]string)
+			}
+			server.Headers["Authorization"] = "Bearer " + token
+		}
+	}
+	return server, nil
+}
+
+// hasSecrets 服务器配置是否依赖桌面端保存的密钥或 OAuth 令牌
+func (sm *MCPSecretManager) hasSecrets(name string, server MCPServer) bool {
+	if len(mcpServerRefs(server)) > 0 {
+		return true
+	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.loadLocked() != nil {
+		return false
+	}
+	_, ok := sm.data.OAuth[name]
+	return ok
+}
+
+// syncSecretMCPServers 将依赖密钥或来自非项目配置层的服务器同步到 OpenCode
+// OpenCode 从配置文件中只能读到 {secret:...} 引用，也不会读取全局层和本机层，需要由桌面端解析合并后推送
+func (a *App) syncSecretMCPServers() {
+	if a.mcpSecrets == nil {
+		return
+	}
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return
+	}
+	for name, server := range config.MCP {
+		if server.Enabled && (a.mcpSecrets.hasSecrets(name, server) || !projectOnlyMCPLayers(config.Layers[name])) {
+			if err := a.syncMCPToOpenCode(name, server); err != nil {
+				a.emitEvent("output-log", fmt.Sprintf("同步 MCP 服务器 %s 失败: %v", name, err))
+			}
+		}
+	}
+}
+
+// resyncMCPServer OAuth 令牌刷新后重新同步服务器，替换 OpenCode 中过期的 Authorization 头
+func (a *App) resyncMCPServer(name string) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return
+	}
+	server, ok := config.MCP[name]
+	if !ok || !server.Enabled || server.Type != "remote" {
+		return
+	}
+	if err := a.syncMCPToOpenCode(name, server); err != nil {
+		a.emitEvent("output-log", fmt.Sprintf("同步 MCP 服务器 %s 失败: %v", name, err))
+	}
+}
+
+// --- App API ---
+
+// SetMCPSecret 加密保存 MCP 密钥，配置中使用 {secret:NAME} 引用
+func (a *App) SetMCPSecret(name, value string) error {
+	if err := a.mcpSecrets.Set(name, value); err != nil {
+		return err
+	}
+	a.mcpMgr.reset()
+	return nil
+}
+
+// DeleteMCPSecret 删除 MCP 密钥
+func (a *App) DeleteMCPSecret(name string) error {
+	return a.mcpSecrets.Delete(name)
+}
+
+// GetMCPSecrets 列出已保存的 MCP 密钥（不返回值）
+func (a *App) GetMCPSecrets() ([]MCPSecretInfo, error) {
+	config, _ := a.GetMCPConfig()
+	return a.mcpSecrets.List(config)
+}
+
+// moveMCPValuesToSecrets 将服务器中明文的环境变量和请求头加密保存，并就地替换为 {secret:NAME} 引用，返回被替换的键
+func (sm *MCPSecretManager) moveMCPValuesToSecrets(server string, cfg MCPServer) ([]string, error) {
+	moved := []string{}
+	move := func(values map[string]string, prefix string) error {
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := values[k]
+			if v == "" || mcpSecretRefRe.MatchString(v) || strings.HasPrefix(v, "{env:") || strings.HasPrefix(v, "{file:") {
+				continue
+			}
+			name := mcpSecretInvalidRe.ReplaceAllString(server+"_"+prefix+k, "_")
+			if err := sm.Set(name, v); err != nil {
+				return err
+			}
+			values[k] = "{secret:" + name + "}"
+			moved = append(moved, prefix+k)
+		}
+		return nil
+	}
+	if err := move(cfg.Environment, ""); err != nil {
+		return nil, err
+	}
+	if err := move(cfg.Headers, "header_"); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// MoveMCPEnvToSecrets 将服务器配置中明文的环境变量和请求头移入加密存储，返回被替换的键
+func (a *App) MoveMCPEnvToSecrets(server string) ([]string, error) {
+	config, err := a.GetMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := config.MCP[server]
+	if !ok {
+		return nil, fmt.Errorf("MCP 服务器不存在: %s", server)
+	}
+
+	moved, err := a.mcpSecrets.moveMCPValuesToSecrets(server, cfg)
+	if err != nil || len(moved) == 0 {
+		return moved, err
+	}
+	config.MCP[server] = cfg
+	if err := a.SaveMCPConfig(*config); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
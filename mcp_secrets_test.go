@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestSecretApp returns an App whose MCP secret store lives in a temp dir
+func newTestSecretApp(t *testing.T) *App {
+	t.Helper()
+	app := &App{}
+	app.mcpSecrets = NewMCPSecretManager(app)
+	app.mcpSecrets.dir = t.TempDir()
+	return app
+}
+
+// TestMCPSecretStore tests saving, listing and deleting encrypted secrets
+func TestMCPSecretStore(t *testing.T) {
+	app := newTestSecretApp(t)
+	sm := app.mcpSecrets
+
+	if err := sm.Set("GITHUB_TOKEN", "ghp_plaintext"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := sm.Set("bad name", "x"); err == nil {
+		t.Error("Set() with invalid name should fail")
+	}
+	if err := sm.Set("EMPTY", ""); err == nil {
+		t.Error("Set() with empty value should fail")
+	}
+
+	raw, err := os.ReadFile(filepath.Join(sm.dir, "secrets.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(raw), "ghp_plaintext") {
+		t.Error("secret stored in plaintext")
+	}
+
+	// a fresh manager reads the same file
+	reloaded := NewMCPSecretManager(app)
+	reloaded.dir = sm.dir
+	if got, err := reloaded.get("GITHUB_TOKEN"); err != nil || got != "ghp_plaintext" {
+		t.Errorf("get() = %q, %v", got, err)
+	}
+
+	config := &MCPConfig{MCP: map[string]MCPServer{
+		"github": {Environment: map[string]string{"GITHUB_TOKEN": "{secret:GITHUB_TOKEN}"}},
+		"other":  {Environment: map[string]string{"X": "plain"}},
+	}}
+	infos, err := sm.List(config)
+	if err != nil || len(infos) != 1 || strings.Join(infos[0].UsedBy, ",") != "github" {
+		t.Errorf("List() = %+v, %v", infos, err)
+	}
+
+	if err := sm.Delete("GITHUB_TOKEN"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := sm.get("GITHUB_TOKEN"); err == nil {
+		t.Error("get() after Delete() should fail")
+	}
+}
+
+// TestResolveMCPServer tests substituting secret references without touching the original config
+func TestResolveMCPServer(t *testing.T) {
+	app := newTestSecretApp(t)
+	app.mcpSecrets.Set("TOKEN", "s3cret")
+
+	server := MCPServer{
+		Type:        "remote",
+		URL:         "https://example.com/mcp",
+		Environment: map[string]string{"A": "prefix-{secret:TOKEN}", "B": "{env:HOME}"},
+		Headers:     map[string]string{"X-Api-Key": "{secret:TOKEN}"},
+	}
+	resolved, err := app.resolveMCPServer("srv", server)
+	if err != nil {
+		t.Fatalf("resolveMCPServer() error = %v", err)
+	}
+	if resolved.Environment["A"] != "prefix-s3cret" || resolved.Environment["B"] != "{env:HOME}" || resolved.Headers["X-Api-Key"] != "s3cret" {
+		t.Errorf("resolved = %+v", resolved)
+	}
+	if server.Environment["A"] != "prefix-{secret:TOKEN}" {
+		t.Error("original config was modified")
+	}
+	if _, ok := resolved.Headers["Authorization"]; ok {
+		t.Error("Authorization added without OAuth credentials")
+	}
+
+	server.Environment["C"] = "{secret:MISSING}"
+	if _, err := app.resolveMCPServer("srv", server); err == nil || !strings.Contains(err.Error(), "MISSING") {
+		t.Errorf("resolveMCPServer() missing secret error = %v", err)
+	}
+
+	if got, _ := (&App{}).resolveMCPServer("srv", server); got.Environment["A"] != "prefix-{secret:TOKEN}" {
+		t.Error("resolveMCPServer() without a secret store should return the config unchanged")
+	}
+
+	local := MCPServer{Type: "local", Command: []string{"srv", "--token", "{secret:TOKEN}"}}
+	if resolved, err := app.resolveMCPServer("srv", local); err != nil || resolved.Command[2] != "s3cret" || local.Command[2] != "{secret:TOKEN}" {
+		t.Errorf("resolved command = %v, original = %v, err = %v", resolved.Command, local.Command, err)
+	}
+	if refs := mcpServerRefs(local); !refs["TOKEN"] {
+		t.Errorf("mcpServerRefs() = %v", refs)
+	}
+}
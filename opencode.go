@@ -220,7 +220,8 @@ func (m *OpenCodeManager) StartForDir(dir string) error {
 
 	cmd := exec.Command(path, "serve", "--port", fmt.Sprintf("%d", port), "--print-logs")
 	cmd.Dir = dir
-	cmd.Env = os.Environ()
+	// 注入技能开关，本机层的规则 OpenCode 不会自己读取
+	cmd.Env = NewSkillsManager(dir).openCodeEnv(os.Environ())
 	stdout, _ := cmd.StdoutPipe()
 	stderr, _ := cmd.StderrPipe()
 	m.setupHiddenProcess(cmd)
@@ -358,6 +359,8 @@ func (m *OpenCodeManager) cleanupPortProcesses(port int) {
 }
 
 func (m *OpenCodeManager) Stop() {
+	// 服务器停止后 worktree 的事件订阅失效，重新订阅时再建立
+	m.app.worktreeMgr.unsubscribeAll()
 	dir := m.GetWorkDir()
 	m.StopForDir(dir)
 	// 确保实例被清理
@@ -9,6 +9,11 @@ import (
 
 // setupHiddenProcess 设置进程为后台运行（Unix/macOS）
 func (m *OpenCodeManager) setupHiddenProcess(cmd *exec.Cmd) {
+	hideProcessWindow(cmd)
+}
+
+// hideProcessWindow 让子进程在后台运行，不弹出终端窗口
+func hideProcessWindow(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setsid: true, // 创建新会话，脱离终端
 	}
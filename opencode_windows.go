@@ -9,6 +9,11 @@ import (
 
 // setupHiddenProcess 设置进程为隐藏运行（Windows）
 func (m *OpenCodeManager) setupHiddenProcess(cmd *exec.Cmd) {
+	hideProcessWindow(cmd)
+}
+
+// hideProcessWindow 让子进程在后台运行，不弹出终端窗口
+func hideProcessWindow(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		HideWindow:    true,
 		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
@@ -0,0 +1,611 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplaceOptions 替换选项
+type ReplaceOptions struct {
+	Search  SearchOptions `json:"search"`
+	Replace string        `json:"replace"` // 正则模式下支持 $1、${name} 捕获组
+}
+
+// ReplaceChange 单行替换
+type ReplaceChange struct {
+	Line   int    `json:"line"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+	Count  int    `json:"count"`
+}
+
+// ReplaceFilePreview 单个文件的替换预览
+type ReplaceFilePreview struct {
+	Path    string          `json:"path"`
+	RelPath string          `json:"relPath"`
+	Changes []ReplaceChange `json:"changes"`
+	Count   int             `json:"count"`
+	Diff    string          `json:"diff"`
+}
+
+// ReplacePreview 替换预览
+type ReplacePreview struct {
+	ID           string               `json:"id"`
+	Files        []ReplaceFilePreview `json:"files"`
+	FileCount    int                  `json:"fileCount"`
+	ReplaceCount int                  `json:"replaceCount"`
+	Truncated    bool                 `json:"truncated"`
+}
+
+// ReplaceSelection 要应用的文件及行（Lines 为空表示该文件全部行）
+type ReplaceSelection struct {
+	Path  string `json:"path"`
+	Lines []int  `json:"lines,omitempty"`
+}
+
+// ReplaceJournalFile 撤销日志中的文件记录
+type ReplaceJournalFile struct {
+	Path         string `json:"path"`
+	OriginalHash string `json:"originalHash"`
+	NewHash      string `json:"newHash"`
+	Backup       string `json:"backup"` // 原始内容在日志目录中的文件名
+	Mode         uint32 `json:"mode"`
+	Count        int    `json:"count"`
+}
+
+// ReplaceOperation 一次已应用的替换操作（撤销日志）
+type ReplaceOperation struct {
+	ID        string               `json:"id"`
+	Query     string               `json:"query"`
+	Replace   string               `json:"replace"`
+	Files     []ReplaceJournalFile `json:"files"`
+	AppliedAt time.Time            `json:"appliedAt"`
+	Undone    bool                 `json:"undone"`
+	UndoneAt  *time.Time           `json:"undoneAt,omitempty"`
+}
+
+// pendingReplaceFile 预览中记录的文件原始状态
+type pendingReplaceFile struct {
+	preview ReplaceFilePreview
+	hash    string
+}
+
+// pendingReplace 尚未应用的预览
+type pendingReplace struct {
+	opts  ReplaceOptions
+	re    *regexp.Regexp
+	files map[string]*pendingReplaceFile
+}
+
+const (
+	maxReplacePreviews   = 10
+	maxReplaceOperations = 20
+)
+
+// ReplaceManager 两阶段替换管理器
+type ReplaceManager struct {
+	app        *App
+	journalDir string
+	previews   map[string]*pendingReplace
+	order      []string
+	nextID     int
+	mu         sync.Mutex
+}
+
+// NewReplaceManager 创建替换管理器
+func NewReplaceManager(app *App) *ReplaceManager {
+	return &ReplaceManager{
+		app:      app,
+		previews: make(map[string]*pendingReplace),
+	}
+}
+
+// getJournalDir 返回撤销日志目录
+func (rm *ReplaceManager) getJournalDir() (string, error) {
+	if rm.journalDir != "" {
+		return rm.journalDir, os.MkdirAll(rm.journalDir, 0755)
+	}
+	return rm.app.getDataDir("replace-journal")
+}
+
+// Preview 计算所有替换，但不修改文件
+func (rm *ReplaceManager) Preview(opts ReplaceOptions) (*ReplacePreview, error) {
+	re, err := compileSearchPattern(opts.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var matched []SearchFileResult
+	summary, err := runSearch(context.Background(), opts.Search, nil, func(f SearchFileResult) {
+		mu.Lock()
+		matched = append(matched, f)
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].RelPath < matched[j].RelPath })
+
+	pending := &pendingReplace{opts: opts, re: re, files: make(map[string]*pendingReplaceFile)}
+	preview := &ReplacePreview{Files: []ReplaceFilePreview{}, Truncated: summary.Truncated}
+
+	for _, f := range matched {
+		content, err := os.ReadFile(f.Path)
+		if err != nil {
+			continue
+		}
+		newContent, changes := applyLineReplacements(string(content), re, opts.Replace, opts.Search.UseRegex, nil)
+		if len(changes) == 0 {
+			continue
+		}
+
+		fp := ReplaceFilePreview{
+			Path:    f.Path,
+			RelPath: f.RelPath,
+			Changes: changes,
+			Diff:    UnifiedDiff("a/"+f.RelPath, "b/"+f.RelPath, string(content), newContent, 3),
+		}
+		for _, c := range changes {
+			fp.Count += c.Count
+		}
+		pending.files[f.Path] = &pendingReplaceFile{preview: fp, hash: hashBytes(content)}
+		preview.Files = append(preview.Files, fp)
+		preview.FileCount++
+		preview.ReplaceCount += fp.Count
+	}
+
+	rm.mu.Lock()
+	rm.nextID++
+	preview.ID = fmt.Sprintf("replace-preview-%d", rm.nextID)
+	rm.previews[preview.ID] = pending
+	rm.order = append(rm.order, preview.ID)
+	for len(rm.order) > maxReplacePreviews {
+		delete(rm.previews, rm.order[0])
+		rm.order = rm.order[1:]
+	}
+	rm.mu.Unlock()
+
+	return preview, nil
+}
+
+// Discard 丢弃预览
+func (rm *ReplaceManager) Discard(previewID string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	delete(rm.previews, previewID)
+}
+
+// Apply 原子地应用预览中选中的替换；任一文件在预览后被修改则整体拒绝
+func (rm *ReplaceManager) Apply(previewID string, selections []ReplaceSelection) (*ReplaceOperation, error) {
+	rm.mu.Lock()
+	pending, ok := rm.previews[previewID]
+	rm.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("替换预览不存在或已过期: %s", previewID)
+	}
+
+	if len(selections) == 0 {
+		for path := range pending.files {
+			selections = append(selections, ReplaceSelection{Path: path})
+		}
+		sort.Slice(selections, func(i, j int) bool { return selections[i].Path < selections[j].Path })
+	}
+
+	type target struct {
+		path     string
+		original []byte
+		updated  []byte
+		mode     os.FileMode
+		count    int
+	}
+	var targets []target
+	var conflicts []string
+
+	for _, sel := range selections {
+		pf, ok := pending.files[sel.Path]
+		if !ok {
+			return nil, fmt.Errorf("文件不在预览中: %s", sel.Path)
+		}
+		info, err := os.Stat(sel.Path)
+		if err != nil {
+			conflicts = append(conflicts, pf.preview.RelPath)
+			continue
+		}
+		content, err := os.ReadFile(sel.Path)
+		if err != nil || hashBytes(content) != pf.hash {
+			conflicts = append(conflicts, pf.preview.RelPath)
+			continue
+		}
+
+		var lines map[int]bool
+		if len(sel.Lines) > 0 {
+			lines = make(map[int]bool, len(sel.Lines))
+			for _, l := range sel.Lines {
+				lines[l] = true
+			}
+		}
+		updated, changes := applyLineReplacements(string(content), pending.re, pending.opts.Replace, pending.opts.Search.UseRegex, lines)
+		if len(changes) == 0 {
+			continue
+		}
+		count := 0
+		for _, c := range changes {
+			count += c.Count
+		}
+		targets = append(targets, target{path: sel.Path, original: content, updated: []byte(updated), mode: info.Mode().Perm(), count: count})
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("以下文件在预览后已被修改，请重新预览: %s", strings.Join(conflicts, ", "))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("没有需要替换的内容")
+	}
+
+	// 1. 写撤销日志（原始内容）
+	journalDir, err := rm.getJournalDir()
+	if err != nil {
+		return nil, err
+	}
+	op := &ReplaceOperation{
+		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
+		Query:     pending.opts.Search.Query,
+		Replace:   pending.opts.Replace,
+		AppliedAt: time.Now(),
+	}
+	opDir := filepath.Join(journalDir, op.ID)
+	if err := os.MkdirAll(opDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建撤销日志失败: %v", err)
+	}
+	for i, t := range targets {
+		backup := fmt.Sprintf("%d.orig", i)
+		if err := os.WriteFile(filepath.Join(opDir, backup), t.original, 0600); err != nil {
+			os.RemoveAll(opDir)
+			return nil, fmt.Errorf("写入撤销日志失败: %v", err)
+		}
+		op.Files = append(op.Files, ReplaceJournalFile{
+			Path:         t.path,
+			OriginalHash: hashBytes(t.original),
+			NewHash:      hashBytes(t.updated),
+			Backup:       backup,
+			Mode:         uint32(t.mode),
+			Count:        t.count,
+		})
+	}
+
+	// 2. 两阶段写入
+	writes := make([]pendingWrite, len(targets))
+	for i, t := range targets {
+		writes[i] = pendingWrite{path: t.path, data: t.updated, original: t.original, mode: t.mode}
+	}
+	for _, w := range writes {
+		rm.app.snapshotFile(w.path, "replace")
+	}
+	if err := commitWrites(writes); err != nil {
+		os.RemoveAll(opDir)
+		return nil, err
+	}
+	for _, w := range writes {
+		rm.app.snapshotFile(w.path, "replace")
+	}
+
+	if err := saveReplaceOperation(opDir, op); err != nil {
+		return nil, err
+	}
+	pruneReplaceJournal(journalDir)
+
+	rm.mu.Lock()
+	delete(rm.previews, previewID)
+	rm.mu.Unlock()
+
+	return op, nil
+}
+
+// Undo 撤销一次替换操作；文件在替换后又被修改则拒绝
+func (rm *ReplaceManager) Undo(operationID string) error {
+	journalDir, err := rm.getJournalDir()
+	if err != nil {
+		return err
+	}
+	opDir := filepath.Join(journalDir, filepath.Base(operationID))
+	op, err := loadReplaceOperation(opDir)
+	if err != nil {
+		return err
+	}
+	if op.Undone {
+		return fmt.Errorf("该替换操作已撤销")
+	}
+
+	var writes []pendingWrite
+	var conflicts []string
+	for _, f := range op.Files {
+		current, err := os.ReadFile(f.Path)
+		if err != nil || hashBytes(current) != f.NewHash {
+			conflicts = append(conflicts, f.Path)
+			continue
+		}
+		original, err := os.ReadFile(filepath.Join(opDir, f.Backup))
+		if err != nil {
+			return fmt.Errorf("读取撤销日志失败: %v", err)
+		}
+		writes = append(writes, pendingWrite{path: f.Path, data: original, original: current, mode: os.FileMode(f.Mode)})
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("以下文件在替换后已被修改，无法撤销: %s", strings.Join(conflicts, ", "))
+	}
+
+	for _, w := range writes {
+		rm.app.snapshotFile(w.path, "replace-undo")
+	}
+	if err := commitWrites(writes); err != nil {
+		return err
+	}
+	for _, w := range writes {
+		rm.app.snapshotFile(w.path, "replace-undo")
+	}
+
+	now := time.Now()
+	op.Undone = true
+	op.UndoneAt = &now
+	return saveReplaceOperation(opDir, op)
+}
+
+// ListOperations 按时间倒序列出替换操作
+func (rm *ReplaceManager) ListOperations() ([]ReplaceOperation, error) {
+	journalDir, err := rm.getJournalDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(journalDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []ReplaceOperation{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		op, err := loadReplaceOperation(filepath.Join(journalDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].AppliedAt.After(ops[j].AppliedAt) })
+	return ops, nil
+}
+
+// pendingWrite 待提交的文件写入
+type pendingWrite struct {
+	path     string
+	data     []byte
+	original []byte // 回滚时恢复的内容
+	mode     os.FileMode
+}
+
+// commitWrites 先全部写入临时文件，再逐个重命名；任一步失败则回滚已替换的文件
+func commitWrites(writes []pendingWrite) error {
+	temps := make([]string, len(writes))
+	cleanup := func() {
+		for _, t := range temps {
+			if t != "" {
+				os.Remove(t)
+			}
+		}
+	}
+
+	for i, w := range writes {
+		tmp, err := os.CreateTemp(filepath.Dir(w.path), "."+filepath.Base(w.path)+".replace-*")
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("写入临时文件失败: %v", err)
+		}
+		temps[i] = tmp.Name()
+		_, err = tmp.Write(w.data)
+		if err == nil {
+			err = tmp.Sync()
+		}
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(temps[i], w.mode)
+		}
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("写入临时文件失败: %v", err)
+		}
+	}
+
+	for i, w := range writes {
+		if err := os.Rename(temps[i], w.path); err != nil {
+			// 回滚已经替换的文件
+			for j := 0; j < i; j++ {
+				writeFileAtomic(writes[j].path, writes[j].original, writes[j].mode)
+			}
+			temps = temps[i:]
+			cleanup()
+			return fmt.Errorf("替换文件失败 %s: %v", w.path, err)
+		}
+	}
+	return nil
+}
+
+// applyLineReplacements 逐行替换（保留原换行符），lines 为 nil 时替换所有行
+func applyLineReplacements(content string, re *regexp.Regexp, replacement string, useRegex bool, lines map[int]bool) (string, []ReplaceChange) {
+	if useRegex {
+		// 兼容 JS 风格的 $& 整体匹配引用
+		replacement = strings.ReplaceAll(replacement, "$&", "${0}")
+	}
+
+	segments := strings.SplitAfter(content, "\n")
+	var changes []ReplaceChange
+	var sb strings.Builder
+	sb.Grow(len(content))
+
+	for i, seg := range segments {
+		lineNo := i + 1
+		body := seg
+		ending := ""
+		if strings.HasSuffix(body, "\r\n") {
+			body, ending = body[:len(body)-2], "\r\n"
+		} else if strings.HasSuffix(body, "\n") {
+			body, ending = body[:len(body)-1], "\n"
+		}
+
+		if (lines != nil && !lines[lineNo]) || !re.MatchString(body) {
+			sb.WriteString(seg)
+			continue
+		}
+
+		count := 0
+		for _, loc := range re.FindAllStringIndex(body, -1) {
+			if loc[0] != loc[1] {
+				count++
+			}
+		}
+		var replaced string
+		if useRegex {
+			replaced = re.ReplaceAllString(body, replacement)
+		} else {
+			replaced = re.ReplaceAllLiteralString(body, replacement)
+		}
+		if replaced == body || count == 0 {
+			sb.WriteString(seg)
+			continue
+		}
+
+		changes = append(changes, ReplaceChange{Line: lineNo, Before: body, After: replaced, Count: count})
+		sb.WriteString(replaced)
+		sb.WriteString(ending)
+	}
+	return sb.String(), changes
+}
+
+// hashBytes 计算内容的 SHA-256
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveReplaceOperation 写入撤销日志清单
+func saveReplaceOperation(opDir string, op *ReplaceOperation) error {
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(opDir, "manifest.json"), data, 0600); err != nil {
+		return fmt.Errorf("写入撤销日志失败: %v", err)
+	}
+	return nil
+}
+
+// loadReplaceOperation 读取撤销日志清单
+func loadReplaceOperation(opDir string) (*ReplaceOperation, error) {
+	data, err := os.ReadFile(filepath.Join(opDir, "manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("替换操作不存在: %s", filepath.Base(opDir))
+	}
+	var op ReplaceOperation
+	if err := json.Unmarshal(data, &op); err != nil {
+		return nil, fmt.Errorf("解析撤销日志失败: %v", err)
+	}
+	return &op, nil
+}
+
+// pruneReplaceJournal 只保留最近的替换操作
+func pruneReplaceJournal(journalDir string) {
+	entries, err := os.ReadDir(journalDir)
+	if err != nil {
+		return
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	// 目录名为纳秒时间戳，长度相同时按字符串排序即按时间排序
+	sort.Slice(dirs, func(i, j int) bool {
+		if len(dirs[i]) != len(dirs[j]) {
+			return len(dirs[i]) < len(dirs[j])
+		}
+		return dirs[i] < dirs[j]
+	})
+	for len(dirs) > maxReplaceOperations {
+		os.RemoveAll(filepath.Join(journalDir, dirs[0]))
+		dirs = dirs[1:]
+	}
+}
+
+// --- App API ---
+
+// ReplaceInFiles 在文件中替换文本（直接应用全部替换，可通过 UndoReplace 撤销）
+func (a *App) ReplaceInFiles(dir, searchText, replaceText string, caseSensitive bool) (int, error) {
+	if dir == "" || searchText == "" {
+		return 0, fmt.Errorf("搜索文本不能为空")
+	}
+
+	preview, err := a.replaceMgr.Preview(ReplaceOptions{
+		Search:  SearchOptions{Dir: dir, Query: searchText, CaseSensitive: caseSensitive},
+		Replace: replaceText,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if preview.FileCount == 0 {
+		a.replaceMgr.Discard(preview.ID)
+		return 0, nil
+	}
+	if preview.Truncated {
+		// 预览只包含部分匹配，应用后会遗漏其余匹配
+		a.replaceMgr.Discard(preview.ID)
+		return 0, fmt.Errorf("匹配过多，无法一次全部替换，请缩小搜索范围")
+	}
+
+	op, err := a.replaceMgr.Apply(preview.ID, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(op.Files), nil
+}
+
+// PreviewReplace 计算替换预览（按文件给出逐行变更和 diff）
+func (a *App) PreviewReplace(opts ReplaceOptions) (*ReplacePreview, error) {
+	if opts.Search.Dir == "" {
+		opts.Search.Dir = a.fileMgr.GetRootDir()
+	}
+	return a.replaceMgr.Preview(opts)
+}
+
+// ApplyReplace 应用预览中选中的替换，selections 为空时应用全部
+func (a *App) ApplyReplace(previewID string, selections []ReplaceSelection) (*ReplaceOperation, error) {
+	return a.replaceMgr.Apply(previewID, selections)
+}
+
+// DiscardReplacePreview 丢弃替换预览
+func (a *App) DiscardReplacePreview(previewID string) {
+	a.replaceMgr.Discard(previewID)
+}
+
+// UndoReplace 撤销一次替换操作
+func (a *App) UndoReplace(operationID string) error {
+	return a.replaceMgr.Undo(operationID)
+}
+
+// GetReplaceOperations 获取替换操作历史
+func (a *App) GetReplaceOperations() ([]ReplaceOperation, error) {
+	return a.replaceMgr.ListOperations()
+}
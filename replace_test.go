@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestReplaceManager creates a replace manager with a temp journal dir
+func newTestReplaceManager(t *testing.T) *ReplaceManager {
+	t.Helper()
+	rm := NewReplaceManager(&App{})
+	rm.journalDir = t.TempDir()
+	return rm
+}
+
+// readTestFile reads a file relative to dir
+func readTestFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", name, err)
+	}
+	return string(data)
+}
+
+// TestReplacePreviewAndApply tests preview, partial apply and undo
+func TestReplacePreviewAndApply(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "foo one\r\nbar\r\nfoo two\r\n")
+	writeTestFile(t, dir, "b.txt", "foo\n")
+	rm := newTestReplaceManager(t)
+
+	preview, err := rm.Preview(ReplaceOptions{Search: SearchOptions{Dir: dir, Query: "foo"}, Replace: "baz"})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.FileCount != 2 || preview.ReplaceCount != 3 {
+		t.Fatalf("Unexpected preview: %+v", preview)
+	}
+	if readTestFile(t, dir, "a.txt") != "foo one\r\nbar\r\nfoo two\r\n" {
+		t.Fatal("Preview must not modify files")
+	}
+	if !strings.Contains(preview.Files[0].Diff, "+baz one") {
+		t.Errorf("Expected diff to contain replacement, got %q", preview.Files[0].Diff)
+	}
+
+	op, err := rm.Apply(preview.ID, []ReplaceSelection{{Path: filepath.Join(dir, "a.txt"), Lines: []int{3}}})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "foo one\r\nbar\r\nbaz two\r\n" {
+		t.Errorf("Unexpected content after apply: %q", got)
+	}
+	if readTestFile(t, dir, "b.txt") != "foo\n" {
+		t.Error("Unselected file should not change")
+	}
+
+	if err := rm.Undo(op.ID); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "foo one\r\nbar\r\nfoo two\r\n" {
+		t.Errorf("Unexpected content after undo: %q", got)
+	}
+	if err := rm.Undo(op.ID); err == nil {
+		t.Error("Expected error when undoing twice")
+	}
+
+	ops, err := rm.ListOperations()
+	if err != nil || len(ops) != 1 || !ops[0].Undone {
+		t.Errorf("Unexpected operations: %+v, %v", ops, err)
+	}
+}
+
+// TestReplaceApplyConflict tests that files changed after preview are rejected
+func TestReplaceApplyConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "foo\n")
+	writeTestFile(t, dir, "b.txt", "foo\n")
+	rm := newTestReplaceManager(t)
+
+	preview, err := rm.Preview(ReplaceOptions{Search: SearchOptions{Dir: dir, Query: "foo"}, Replace: "bar"})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	writeTestFile(t, dir, "b.txt", "foo changed\n")
+
+	if _, err := rm.Apply(preview.ID, nil); err == nil {
+		t.Fatal("Expected conflict error")
+	}
+	if readTestFile(t, dir, "a.txt") != "foo\n" {
+		t.Error("No file should be modified when a conflict is detected")
+	}
+}
+
+// TestReplaceUndoConflict tests that undo refuses to overwrite later edits
+func TestReplaceUndoConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "foo\n")
+	rm := newTestReplaceManager(t)
+
+	preview, _ := rm.Preview(ReplaceOptions{Search: SearchOptions{Dir: dir, Query: "foo"}, Replace: "bar"})
+	op, err := rm.Apply(preview.ID, nil)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	writeTestFile(t, dir, "a.txt", "edited\n")
+	if err := rm.Undo(op.ID); err == nil {
+		t.Error("Expected undo conflict error")
+	}
+}
+
+// TestApplyLineReplacements tests regex capture groups and literal replacement
+func TestApplyLineReplacements(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		replace  string
+		input    string
+		expected string
+	}{
+		{"capture groups", SearchOptions{Query: `(\w+)@(\w+)`, UseRegex: true}, "$2 at $1", "a@b x c@d\n", "b at a x d at c\n"},
+		{"whole match", SearchOptions{Query: `\d+`, UseRegex: true}, "<$&>", "v1 v22\n", "v<1> v<22>\n"},
+		{"literal dollar", SearchOptions{Query: "x"}, "$1", "x\n", "$1\n"},
+		{"case insensitive", SearchOptions{Query: "Foo"}, "bar", "FOO foo\n", "bar bar\n"},
+	}
+	for _, tt := range tests {
+		re, err := compileSearchPattern(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: compileSearchPattern() error = %v", tt.name, err)
+		}
+		got, _ := applyLineReplacements(tt.input, re, tt.replace, tt.opts.UseRegex, nil)
+		if got != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+// TestReplaceInFilesRefusesTruncatedPreview tests that the replace-all API does not apply a partial preview
+func TestReplaceInFilesRefusesTruncatedPreview(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", strings.Repeat("x\n", defaultSearchMaxResults+1))
+
+	a := &App{}
+	a.replaceMgr = newTestReplaceManager(t)
+	if _, err := a.ReplaceInFiles(dir, "x", "y", true); err == nil {
+		t.Error("Expected error for truncated preview")
+	}
+	if content := readTestFile(t, dir, "a.txt"); strings.Contains(content, "y") {
+		t.Error("File should not be modified")
+	}
+}
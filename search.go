@@ -1,167 +1,722 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
 	goruntime "runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
-// SearchResult 搜索结果
+// SearchResult 搜索结果（按行，兼容旧接口）
 type SearchResult struct {
 	Path    string `json:"path"`
 	Line    int    `json:"line"`
+	Column  int    `json:"column"`
 	Content string `json:"content"`
 	Match   string `json:"match"`
 }
 
-// SearchInFiles 在文件中搜索
-func (a *App) SearchInFiles(dir, query string, caseSensitive, useRegex bool) ([]SearchResult, error) {
-	if dir == "" || query == "" {
-		return []SearchResult{}, nil
+// SearchOptions 搜索选项
+type SearchOptions struct {
+	Dir            string   `json:"dir"` // 为空时使用工作区根目录
+	Query          string   `json:"query"`
+	CaseSensitive  bool     `json:"caseSensitive"`
+	UseRegex       bool     `json:"useRegex"`
+	WholeWord      bool     `json:"wholeWord"`
+	Includes       []string `json:"includes,omitempty"` // glob，如 "src/**/*.ts"
+	Excludes       []string `json:"excludes,omitempty"`
+	IncludeIgnored bool     `json:"includeIgnored"` // 为 true 时不读取 .gitignore
+	ContextLines   int      `json:"contextLines"`
+	MaxResults     int      `json:"maxResults"` // 最多匹配行数，0 使用默认值
+	UseIndex       bool     `json:"useIndex"`   // 使用三元组索引跳过不可能匹配的文件
+}
+
+// SearchMatch 行内的一处匹配，列号和长度以 UTF-16 码元计（与 JS 字符串下标一致）
+type SearchMatch struct {
+	Column int    `json:"column"`
+	Length int    `json:"length"`
+	Text   string `json:"text"`
+}
+
+// SearchLine 匹配行及其上下文
+type SearchLine struct {
+	Line    int           `json:"line"`
+	Content string        `json:"content"`
+	Matches []SearchMatch `json:"matches"`
+	Before  []string      `json:"before,omitempty"`
+	After   []string      `json:"after,omitempty"`
+}
+
+// SearchFileResult 单个文件的搜索结果
+type SearchFileResult struct {
+	Path    string       `json:"path"`
+	RelPath string       `json:"relPath"`
+	Lines   []SearchLine `json:"lines"`
+}
+
+// SearchSummary 搜索汇总
+type SearchSummary struct {
+	ID           string             `json:"id,omitempty"`
+	Files        []SearchFileResult `json:"files,omitempty"`
+	FileCount    int                `json:"fileCount"`
+	MatchCount   int                `json:"matchCount"`
+	ScannedFiles int                `json:"scannedFiles"`
+	Truncated    bool               `json:"truncated"`
+	Cancelled    bool               `json:"cancelled"`
+	DurationMs   int64              `json:"durationMs"`
+	Error        string             `json:"error,omitempty"`
+}
+
+const (
+	defaultSearchMaxResults = 20000
+	maxSearchFileSize       = 10 * 1024 * 1024
+	maxSearchContextLines   = 10
+)
+
+// defaultSkippedDirs 未包含忽略文件时也跳过的依赖目录
+var defaultSkippedDirs = map[string]bool{
+	"node_modules": true,
+	"__pycache__":  true,
+}
+
+// SearchManager 工作区搜索管理器
+type SearchManager struct {
+	app      *App
+	searches map[string]context.CancelFunc
+	index    *SearchIndex
+	nextID   int
+	mu       sync.Mutex
+}
+
+// NewSearchManager 创建搜索管理器
+func NewSearchManager(app *App) *SearchManager {
+	return &SearchManager{
+		app:      app,
+		searches: make(map[string]context.CancelFunc),
 	}
+}
+
+// Search 同步搜索并返回全部结果（按路径排序）
+func (sm *SearchManager) Search(ctx context.Context, opts SearchOptions) (*SearchSummary, error) {
+	var mu sync.Mutex
+	var files []SearchFileResult
+	summary, err := runSearch(ctx, opts, sm.indexFor(opts), func(f SearchFileResult) {
+		mu.Lock()
+		files = append(files, f)
+		mu.Unlock()
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].RelPath < files[j].RelPath })
+	summary.Files = files
+	return summary, nil
+}
 
-	var results []SearchResult
-	var cmd *exec.Cmd
+// Start 在后台搜索，结果通过 search-result 事件逐个文件推送，结束时推送 search-done
+func (sm *SearchManager) Start(opts SearchOptions) (string, error) {
+	if _, err := compileSearchPattern(opts); err != nil {
+		return "", err
+	}
 
-	if useRegex {
-		// 使用 grep 进行正则搜索
-		args := []string{"-rn", "-E"}
-		if !caseSensitive {
-			args = append(args, "-i")
+	ctx, cancel := context.WithCancel(context.Background())
+	sm.mu.Lock()
+	sm.nextID++
+	id := fmt.Sprintf("search-%d", sm.nextID)
+	sm.searches[id] = cancel
+	sm.mu.Unlock()
+
+	go func() {
+		defer func() {
+			sm.mu.Lock()
+			delete(sm.searches, id)
+			sm.mu.Unlock()
+			cancel()
+		}()
+
+		summary, err := runSearch(ctx, opts, sm.indexFor(opts), func(f SearchFileResult) {
+			sm.app.emitEvent("search-result", map[string]interface{}{"searchId": id, "file": f})
+		})
+		if err != nil {
+			summary = &SearchSummary{Error: err.Error()}
 		}
-		args = append(args, query, dir)
+		summary.ID = id
+		sm.app.emitEvent("search-done", summary)
+	}()
 
-		if goruntime.GOOS == "windows" {
-			cmd = exec.Command("findstr", "/S", "/N", query, dir+"\\*")
-		} else {
-			cmd = exec.Command("grep", args...)
+	return id, nil
+}
+
+// Cancel 取消后台搜索
+func (sm *SearchManager) Cancel(id string) {
+	sm.mu.Lock()
+	cancel, ok := sm.searches[id]
+	sm.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// EnableIndex 为目录构建三元组索引（后台执行），dir 为空时关闭索引
+func (sm *SearchManager) EnableIndex(dir string) {
+	sm.mu.Lock()
+	if dir == "" {
+		sm.index = nil
+		sm.mu.Unlock()
+		return
+	}
+	index := NewSearchIndex(dir)
+	sm.index = index
+	sm.mu.Unlock()
+
+	go func() {
+		start := time.Now()
+		count := index.Build(context.Background())
+		sm.app.emitEvent("search-index-ready", map[string]interface{}{
+			"dir":        dir,
+			"files":      count,
+			"durationMs": time.Since(start).Milliseconds(),
+		})
+	}()
+}
+
+// applyFileChanges 根据文件监听的变化更新索引（目录中的新文件在下次搜索时补充索引）
+func (sm *SearchManager) applyFileChanges(root string, changes []FileChangeEvent) {
+	if sm == nil {
+		return
+	}
+	sm.mu.Lock()
+	index := sm.index
+	sm.mu.Unlock()
+	if index == nil || index.root != filepath.Clean(root) {
+		return
+	}
+	for _, c := range changes {
+		if c.Type == "rename" && c.OldPath != "" {
+			if rel, err := filepath.Rel(index.root, c.OldPath); err == nil {
+				index.Remove(rel)
+			}
 		}
-	} else {
-		// 简单文本搜索
-		args := []string{"-rn", "-F"}
-		if !caseSensitive {
-			args = append(args, "-i")
+		switch {
+		case c.Type == "delete":
+			index.Remove(c.RelPath)
+		case c.IsDir:
+			index.Remove(c.RelPath)
+		default:
+			index.Refresh(c.RelPath)
 		}
-		args = append(args, query, dir)
+	}
+}
 
-		if goruntime.GOOS == "windows" {
-			cmd = exec.Command("findstr", "/S", "/N", query, dir+"\\*")
-		} else {
-			cmd = exec.Command("grep", args...)
-		}
+// indexFor 返回适用于本次搜索的索引
+func (sm *SearchManager) indexFor(opts SearchOptions) *SearchIndex {
+	if !opts.UseIndex {
+		return nil
 	}
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.index != nil && sm.index.root == filepath.Clean(opts.Dir) {
+		return sm.index
+	}
+	return nil
+}
 
-	output, err := cmd.CombinedOutput()
+// compileSearchPattern 根据选项编译匹配正则
+func compileSearchPattern(opts SearchOptions) (*regexp.Regexp, error) {
+	if opts.Query == "" {
+		return nil, fmt.Errorf("搜索文本不能为空")
+	}
+	pattern := opts.Query
+	if !opts.UseRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.WholeWord {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
 	if err != nil {
-		// grep 没找到结果会返回错误，这是正常的
-		if len(output) == 0 {
-			return []SearchResult{}, nil
-		}
+		return nil, fmt.Errorf("无效的正则表达式: %v", err)
 	}
+	return re, nil
+}
 
-	// 解析输出
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if line == "" {
+// compileGlobs 编译一组 glob
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
 			continue
 		}
-
-		// 格式: path:line:content
-		parts := strings.SplitN(line, ":", 3)
-		if len(parts) < 3 {
-			continue
+		re, err := compileGlob(p)
+		if err != nil {
+			return nil, fmt.Errorf("无效的 glob %q: %v", p, err)
 		}
+		res = append(res, re)
+	}
+	return res, nil
+}
 
-		lineNum := 0
-		fmt.Sscanf(parts[1], "%d", &lineNum)
-
-		results = append(results, SearchResult{
-			Path:    parts[0],
-			Line:    lineNum,
-			Content: strings.TrimSpace(parts[2]),
-			Match:   query,
-		})
-
-		// 限制结果数量
-		if len(results) >= 500 {
-			break
+// matchAnyGlob 判断路径或其任一祖先目录是否匹配
+func matchAnyGlob(globs []*regexp.Regexp, rel string) bool {
+	for {
+		for _, g := range globs {
+			if g.MatchString(rel) {
+				return true
+			}
+		}
+		idx := strings.LastIndexByte(rel, '/')
+		if idx < 0 {
+			return false
 		}
+		rel = rel[:idx]
 	}
+}
 
-	return results, nil
+// workspaceFile 遍历得到的文件
+type workspaceFile struct {
+	path string
+	rel  string
+	info fs.FileInfo
 }
 
-// ReplaceInFiles 在文件中替换文本
-func (a *App) ReplaceInFiles(dir, searchText, replaceText string, caseSensitive bool) (int, error) {
-	if dir == "" || searchText == "" {
-		return 0, fmt.Errorf("搜索文本不能为空")
+// walkWorkspace 遍历目录，按忽略规则和 include/exclude 过滤文件
+func walkWorkspace(ctx context.Context, root string, includeIgnored bool, includes, excludes []*regexp.Regexp, fn func(workspaceFile)) error {
+	var ignore *IgnoreMatcher
+	if !includeIgnored {
+		ignore = NewIgnoreMatcher(root)
 	}
 
-	count := 0
-
-	// 遍历目录下所有文件
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // 跳过错误的文件
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		if err != nil || path == root {
+			return nil
 		}
 
-		// 跳过目录和隐藏文件/目录
-		if info.IsDir() {
-			if strings.HasPrefix(info.Name(), ".") {
+		rel := filepath.ToSlash(strings.TrimPrefix(path, root+string(filepath.Separator)))
+		name := d.Name()
+		if d.IsDir() {
+			if alwaysIgnoredDirs[name] {
+				return filepath.SkipDir
+			}
+			if ignore != nil && (defaultSkippedDirs[name] || ignore.MatchEntry(rel, true)) {
+				return filepath.SkipDir
+			}
+			if len(excludes) > 0 && matchAnyGlob(excludes, rel) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// 跳过二进制文件和大文件
-		if info.Size() > 10*1024*1024 { // 10MB
+		if !d.Type().IsRegular() {
 			return nil
 		}
-
-		// 读取文件内容
-		content, err := os.ReadFile(path)
-		if err != nil {
+		if ignore != nil && ignore.MatchEntry(rel, false) {
+			return nil
+		}
+		if len(excludes) > 0 && matchAnyGlob(excludes, rel) {
+			return nil
+		}
+		if len(includes) > 0 && !matchAnyGlob(includes, rel) {
 			return nil
 		}
 
-		// 检查是否是文本文件（简单检测）
-		if !isTextFile(content) {
+		info, err := d.Info()
+		if err != nil {
 			return nil
 		}
+		fn(workspaceFile{path: path, rel: rel, info: info})
+		return nil
+	})
+}
 
-		contentStr := string(content)
-		var newContent string
-		var replaced bool
+// runSearch 执行搜索，每个有匹配的文件调用一次 onFile（可能并发调用）
+func runSearch(ctx context.Context, opts SearchOptions, index *SearchIndex, onFile func(SearchFileResult)) (*SearchSummary, error) {
+	start := time.Now()
+	re, err := compileSearchPattern(opts)
+	if err != nil {
+		return nil, err
+	}
+	includes, err := compileGlobs(opts.Includes)
+	if err != nil {
+		return nil, err
+	}
+	excludes, err := compileGlobs(opts.Excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Clean(opts.Dir)
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("目录不存在: %s", opts.Dir)
+	}
+
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSearchMaxResults
+	}
+	contextLines := opts.ContextLines
+	if contextLines > maxSearchContextLines {
+		contextLines = maxSearchContextLines
+	}
+
+	var trigrams []uint32
+	if index != nil && !opts.UseRegex {
+		trigrams = queryTrigrams(opts.Query)
+	}
 
-		if caseSensitive {
-			if strings.Contains(contentStr, searchText) {
-				newContent = strings.ReplaceAll(contentStr, searchText, replaceText)
-				replaced = true
+	searchCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var scanned, fileCount, matchCount int64
+	var truncated atomic.Bool
+
+	jobs := make(chan workspaceFile, 256)
+	var wg sync.WaitGroup
+	workers := goruntime.NumCPU()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				if searchCtx.Err() != nil {
+					continue
+				}
+				if f.info.Size() > maxSearchFileSize {
+					continue
+				}
+				if index != nil && len(trigrams) > 0 {
+					if known, may := index.mayContain(f.rel, f.info, trigrams); known && !may {
+						continue
+					}
+				}
+
+				content, err := os.ReadFile(f.path)
+				if err != nil {
+					continue
+				}
+				atomic.AddInt64(&scanned, 1)
+				if !isTextFile(content) {
+					continue
+				}
+				if index != nil {
+					index.update(f.rel, f.info, content)
+				}
+				// 字面量可先整体判断；正则含 ^/$ 时逐行语义不同，直接逐行匹配
+				if !opts.UseRegex && !re.Match(content) {
+					continue
+				}
+
+				lines := searchContent(content, re, contextLines)
+				if len(lines) == 0 {
+					continue
+				}
+				total := atomic.AddInt64(&matchCount, int64(len(lines)))
+				if over := total - int64(maxResults); over > 0 {
+					truncated.Store(true)
+					stop()
+					keep := len(lines) - int(over)
+					if keep <= 0 {
+						continue
+					}
+					lines = lines[:keep]
+				}
+				atomic.AddInt64(&fileCount, 1)
+				onFile(SearchFileResult{Path: f.path, RelPath: f.rel, Lines: lines})
 			}
-		} else {
-			// 大小写不敏感替换
-			lower := strings.ToLower(contentStr)
-			searchLower := strings.ToLower(searchText)
-			if strings.Contains(lower, searchLower) {
-				newContent = replaceInsensitive(contentStr, searchText, replaceText)
-				replaced = true
+		}()
+	}
+
+	walkErr := walkWorkspace(searchCtx, root, opts.IncludeIgnored, includes, excludes, func(f workspaceFile) {
+		select {
+		case jobs <- f:
+		case <-searchCtx.Done():
+		}
+	})
+	close(jobs)
+	wg.Wait()
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	count := int(matchCount)
+	if count > maxResults {
+		count = maxResults
+	}
+	return &SearchSummary{
+		FileCount:    int(fileCount),
+		MatchCount:   count,
+		ScannedFiles: int(scanned),
+		Truncated:    truncated.Load(),
+		Cancelled:    ctx.Err() != nil,
+		DurationMs:   time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// searchContent 在文件内容中逐行查找所有匹配
+func searchContent(content []byte, re *regexp.Regexp, contextLines int) []SearchLine {
+	rawLines := bytes.Split(content, []byte("\n"))
+	lines := make([]string, len(rawLines))
+	for i, l := range rawLines {
+		lines[i] = string(bytes.TrimSuffix(l, []byte("\r")))
+	}
+
+	var result []SearchLine
+	for i, line := range lines {
+		locs := re.FindAllStringIndex(line, -1)
+		if len(locs) == 0 {
+			continue
+		}
+
+		var matches []SearchMatch
+		for _, loc := range locs {
+			if loc[0] == loc[1] {
+				continue
 			}
+			matches = append(matches, SearchMatch{
+				Column: utf16Len(line[:loc[0]]),
+				Length: utf16Len(line[loc[0]:loc[1]]),
+				Text:   line[loc[0]:loc[1]],
+			})
+		}
+		if len(matches) == 0 {
+			continue
 		}
 
-		if replaced {
-			if err := os.WriteFile(path, []byte(newContent), info.Mode()); err != nil {
-				return nil
+		sl := SearchLine{Line: i + 1, Content: line, Matches: matches}
+		if contextLines > 0 {
+			from := i - contextLines
+			if from < 0 {
+				from = 0
+			}
+			to := i + contextLines + 1
+			if to > len(lines) {
+				to = len(lines)
 			}
-			count++
+			sl.Before = append([]string{}, lines[from:i]...)
+			sl.After = append([]string{}, lines[i+1:to]...)
 		}
+		result = append(result, sl)
+	}
+	return result
+}
 
-		return nil
+// utf16Len 计算字符串的 UTF-16 码元数
+func utf16Len(s string) int {
+	n := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+		s = s[size:]
+	}
+	return n
+}
+
+// --- 三元组索引 ---
+
+// indexedFile 索引中的文件条目
+type indexedFile struct {
+	size     int64
+	modTime  time.Time
+	trigrams []uint32 // 已排序的小写三元组
+}
+
+// SearchIndex 工作区三元组索引，用于在读取文件前排除不可能匹配的文件
+type SearchIndex struct {
+	root  string
+	files map[string]indexedFile
+	mu    sync.RWMutex
+}
+
+// NewSearchIndex 创建索引
+func NewSearchIndex(root string) *SearchIndex {
+	return &SearchIndex{
+		root:  filepath.Clean(root),
+		files: make(map[string]indexedFile),
+	}
+}
+
+// Build 遍历目录构建索引，返回索引的文件数
+func (idx *SearchIndex) Build(ctx context.Context) int {
+	count := 0
+	walkWorkspace(ctx, idx.root, false, nil, nil, func(f workspaceFile) {
+		if f.info.Size() > maxSearchFileSize {
+			return
+		}
+		content, err := os.ReadFile(f.path)
+		if err != nil || !isTextFile(content) {
+			return
+		}
+		idx.update(f.rel, f.info, content)
+		count++
+	})
+	return count
+}
+
+// update 更新单个文件的索引
+func (idx *SearchIndex) update(rel string, info fs.FileInfo, content []byte) {
+	entry := indexedFile{
+		size:     info.Size(),
+		modTime:  info.ModTime(),
+		trigrams: contentTrigrams(content),
+	}
+	idx.mu.Lock()
+	idx.files[rel] = entry
+	idx.mu.Unlock()
+}
+
+// Remove 从索引删除文件或目录下的全部文件
+func (idx *SearchIndex) Remove(rel string) {
+	rel = filepath.ToSlash(rel)
+	idx.mu.Lock()
+	delete(idx.files, rel)
+	for name := range idx.files {
+		if strings.HasPrefix(name, rel+"/") {
+			delete(idx.files, name)
+		}
+	}
+	idx.mu.Unlock()
+}
+
+// Refresh 重新索引单个文件，文件不存在或不再是可索引的文本时移除
+func (idx *SearchIndex) Refresh(rel string) {
+	rel = filepath.ToSlash(rel)
+	path := filepath.Join(idx.root, filepath.FromSlash(rel))
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxSearchFileSize {
+		idx.Remove(rel)
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !isTextFile(content) {
+		idx.Remove(rel)
+		return
+	}
+	idx.update(rel, info, content)
+}
+
+// mayContain 判断文件是否可能包含全部三元组；known 为 false 表示索引中没有最新条目
+func (idx *SearchIndex) mayContain(rel string, info fs.FileInfo, trigrams []uint32) (known bool, may bool) {
+	idx.mu.RLock()
+	entry, ok := idx.files[rel]
+	idx.mu.RUnlock()
+	if !ok || entry.size != info.Size() || !entry.modTime.Equal(info.ModTime()) {
+		return false, true
+	}
+	for _, t := range trigrams {
+		i := sort.Search(len(entry.trigrams), func(i int) bool { return entry.trigrams[i] >= t })
+		if i >= len(entry.trigrams) || entry.trigrams[i] != t {
+			return true, false
+		}
+	}
+	return true, true
+}
+
+// contentTrigrams 计算内容的去重、排序后的小写三元组
+func contentTrigrams(content []byte) []uint32 {
+	lower := bytes.ToLower(content)
+	seen := make(map[uint32]struct{})
+	for i := 0; i+3 <= len(lower); i++ {
+		seen[uint32(lower[i])<<16|uint32(lower[i+1])<<8|uint32(lower[i+2])] = struct{}{}
+	}
+	result := make([]uint32, 0, len(seen))
+	for t := range seen {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// queryTrigrams 计算字面量查询的小写三元组
+func queryTrigrams(query string) []uint32 {
+	q := []byte(strings.ToLower(query))
+	var result []uint32
+	for i := 0; i+3 <= len(q); i++ {
+		result = append(result, uint32(q[i])<<16|uint32(q[i+1])<<8|uint32(q[i+2]))
+	}
+	return result
+}
+
+// --- App API ---
+
+// SearchInFiles 在文件中搜索（按行返回，兼容旧接口）
+func (a *App) SearchInFiles(dir, query string, caseSensitive, useRegex bool) ([]SearchResult, error) {
+	if dir == "" || query == "" {
+		return []SearchResult{}, nil
+	}
+
+	summary, err := a.searchMgr.Search(context.Background(), SearchOptions{
+		Dir:           dir,
+		Query:         query,
+		CaseSensitive: caseSensitive,
+		UseRegex:      useRegex,
+		UseIndex:      true,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []SearchResult{}
+	for _, f := range summary.Files {
+		for _, l := range f.Lines {
+			results = append(results, SearchResult{
+				Path:    f.Path,
+				Line:    l.Line,
+				Column:  l.Matches[0].Column,
+				Content: strings.TrimSpace(l.Content),
+				Match:   l.Matches[0].Text,
+			})
+		}
+	}
+	return results, nil
+}
 
-	return count, err
+// SearchWorkspace 按选项同步搜索，返回按文件分组的结构化结果
+func (a *App) SearchWorkspace(opts SearchOptions) (*SearchSummary, error) {
+	if opts.Dir == "" {
+		opts.Dir = a.fileMgr.GetRootDir()
+	}
+	return a.searchMgr.Search(context.Background(), opts)
+}
+
+// StartSearch 启动后台搜索，返回搜索 ID；结果通过 search-result / search-done 事件推送
+func (a *App) StartSearch(opts SearchOptions) (string, error) {
+	if opts.Dir == "" {
+		opts.Dir = a.fileMgr.GetRootDir()
+	}
+	return a.searchMgr.Start(opts)
+}
+
+// CancelSearch 取消后台搜索
+func (a *App) CancelSearch(searchID string) {
+	a.searchMgr.Cancel(searchID)
+}
+
+// EnableSearchIndex 为当前工作区构建三元组索引（大仓库使用）
+func (a *App) EnableSearchIndex(enabled bool) {
+	if enabled {
+		a.searchMgr.EnableIndex(a.fileMgr.GetRootDir())
+	} else {
+		a.searchMgr.EnableIndex("")
+	}
 }
 
 // isTextFile 简单判断是否是文本文件
@@ -186,28 +741,3 @@ func isTextFile(content []byte) bool {
 
 	return float64(nonPrintable)/float64(len(sample)) < 0.3
 }
-
-// replaceInsensitive 大小写不敏感替换
-func replaceInsensitive(s, old, new string) string {
-	if old == "" {
-		return s
-	}
-
-	oldLower := strings.ToLower(old)
-	result := ""
-	remaining := s
-
-	for {
-		remainingLower := strings.ToLower(remaining)
-		idx := strings.Index(remainingLower, oldLower)
-		if idx == -1 {
-			result += remaining
-			break
-		}
-
-		result += remaining[:idx] + new
-		remaining = remaining[idx+len(old):]
-	}
-
-	return result
-}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupSearchWorkspace creates a small workspace for search tests
+func setupSearchWorkspace(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".gitignore", "build/\n*.log\n!keep.log\n")
+	writeTestFile(t, dir, "src/main.go", "package main\n\nfunc main() {\n\tfoo(); foo()\n}\n")
+	writeTestFile(t, dir, "src/util.ts", "// 中文 foo\nexport const x = 1\n")
+	writeTestFile(t, dir, "src/sub/.gitignore", "generated.go\n")
+	writeTestFile(t, dir, "src/sub/generated.go", "foo\n")
+	writeTestFile(t, dir, "build/out.go", "foo\n")
+	writeTestFile(t, dir, "debug.log", "foo\n")
+	writeTestFile(t, dir, "keep.log", "foo\n")
+	writeTestFile(t, dir, "node_modules/pkg/index.js", "foo\n")
+	writeTestFile(t, dir, ".git/config", "foo\n")
+	return dir
+}
+
+// searchFiles runs a search and returns matched relative paths
+func searchFiles(t *testing.T, opts SearchOptions) map[string]SearchFileResult {
+	t.Helper()
+	sm := NewSearchManager(&App{})
+	summary, err := sm.Search(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	files := make(map[string]SearchFileResult)
+	for _, f := range summary.Files {
+		files[f.RelPath] = f
+	}
+	return files
+}
+
+// TestSearchHonorsIgnoreFiles tests .gitignore handling including nested files and negation
+func TestSearchHonorsIgnoreFiles(t *testing.T) {
+	dir := setupSearchWorkspace(t)
+	files := searchFiles(t, SearchOptions{Dir: dir, Query: "foo"})
+
+	for _, rel := range []string{"src/main.go", "src/util.ts", "keep.log"} {
+		if _, ok := files[rel]; !ok {
+			t.Errorf("Expected match in %s", rel)
+		}
+	}
+	for _, rel := range []string{"build/out.go", "debug.log", "src/sub/generated.go", "node_modules/pkg/index.js", ".git/config"} {
+		if _, ok := files[rel]; ok {
+			t.Errorf("Expected %s to be ignored", rel)
+		}
+	}
+
+	all := searchFiles(t, SearchOptions{Dir: dir, Query: "foo", IncludeIgnored: true})
+	if _, ok := all["build/out.go"]; !ok {
+		t.Error("Expected ignored file to be searched with IncludeIgnored")
+	}
+	if _, ok := all[".git/config"]; ok {
+		t.Error(".git should always be skipped")
+	}
+}
+
+// TestSearchIncludeExclude tests include/exclude globs
+func TestSearchIncludeExclude(t *testing.T) {
+	dir := setupSearchWorkspace(t)
+
+	files := searchFiles(t, SearchOptions{Dir: dir, Query: "foo", Includes: []string{"src/**/*.{go,ts}"}})
+	if len(files) != 2 {
+		t.Errorf("Expected 2 files, got %d: %v", len(files), files)
+	}
+
+	files = searchFiles(t, SearchOptions{Dir: dir, Query: "foo", Excludes: []string{"*.ts", "keep.log"}})
+	if _, ok := files["src/util.ts"]; ok {
+		t.Error("Expected *.ts to be excluded")
+	}
+	if _, ok := files["src/main.go"]; !ok {
+		t.Error("Expected src/main.go to be searched")
+	}
+}
+
+// TestSearchMatchPositions tests multiple matches per line, UTF-16 columns and context lines
+func TestSearchMatchPositions(t *testing.T) {
+	dir := setupSearchWorkspace(t)
+	files := searchFiles(t, SearchOptions{Dir: dir, Query: "FOO", ContextLines: 1})
+
+	main := files["src/main.go"]
+	if len(main.Lines) != 1 {
+		t.Fatalf("Expected 1 line, got %d", len(main.Lines))
+	}
+	line := main.Lines[0]
+	if line.Line != 4 || len(line.Matches) != 2 {
+		t.Fatalf("Unexpected line result: %+v", line)
+	}
+	if line.Matches[0].Column != 1 || line.Matches[1].Column != 8 || line.Matches[0].Length != 3 {
+		t.Errorf("Unexpected match columns: %+v", line.Matches)
+	}
+	if len(line.Before) != 1 || line.Before[0] != "func main() {" || len(line.After) != 1 || line.After[0] != "}" {
+		t.Errorf("Unexpected context: before=%v after=%v", line.Before, line.After)
+	}
+
+	util := files["src/util.ts"]
+	if util.Lines[0].Matches[0].Column != 6 {
+		t.Errorf("Expected UTF-16 column 6, got %d", util.Lines[0].Matches[0].Column)
+	}
+
+	if got := searchFiles(t, SearchOptions{Dir: dir, Query: "FOO", CaseSensitive: true}); len(got) != 0 {
+		t.Errorf("Expected no case-sensitive matches, got %d", len(got))
+	}
+}
+
+// TestSearchRegexAndWholeWord tests regex anchors and whole-word matching
+func TestSearchRegexAndWholeWord(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "first\r\nfoobar\r\nfoo bar\r\n")
+
+	files := searchFiles(t, SearchOptions{Dir: dir, Query: "^foo$|^foo ", UseRegex: true})
+	if len(files["a.txt"].Lines) != 1 || files["a.txt"].Lines[0].Line != 3 {
+		t.Errorf("Unexpected regex result: %+v", files["a.txt"])
+	}
+
+	files = searchFiles(t, SearchOptions{Dir: dir, Query: "foo", WholeWord: true})
+	if len(files["a.txt"].Lines) != 1 || files["a.txt"].Lines[0].Line != 3 {
+		t.Errorf("Unexpected whole word result: %+v", files["a.txt"])
+	}
+
+	sm := NewSearchManager(&App{})
+	if _, err := sm.Search(context.Background(), SearchOptions{Dir: dir, Query: "(", UseRegex: true}); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
+// TestSearchIndexSkipsFiles tests trigram pruning
+func TestSearchIndexSkipsFiles(t *testing.T) {
+	dir := setupSearchWorkspace(t)
+	index := NewSearchIndex(dir)
+	if count := index.Build(context.Background()); count == 0 {
+		t.Fatal("Expected files to be indexed")
+	}
+
+	summary, err := runSearch(context.Background(), SearchOptions{Dir: dir, Query: "export const"}, index, func(SearchFileResult) {})
+	if err != nil {
+		t.Fatalf("runSearch() error = %v", err)
+	}
+	if summary.FileCount != 1 {
+		t.Errorf("Expected 1 matching file, got %d", summary.FileCount)
+	}
+	if summary.ScannedFiles != 1 {
+		t.Errorf("Expected index to prune non-matching files, scanned %d", summary.ScannedFiles)
+	}
+}
+
+// TestSearchIndexAppliesFileChanges tests that watcher changes update and prune the index
+func TestSearchIndexAppliesFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "alpha\n")
+	writeTestFile(t, dir, "sub/b.txt", "beta\n")
+
+	sm := NewSearchManager(&App{})
+	sm.index = NewSearchIndex(dir)
+	sm.index.Build(context.Background())
+
+	writeTestFile(t, dir, "a.txt", "gamma\n")
+	os.RemoveAll(filepath.Join(dir, "sub"))
+	sm.applyFileChanges(dir, []FileChangeEvent{
+		{Type: "modify", Path: filepath.Join(dir, "a.txt"), RelPath: "a.txt"},
+		{Type: "delete", Path: filepath.Join(dir, "sub"), RelPath: "sub", IsDir: true},
+	})
+
+	if _, ok := sm.index.files["sub/b.txt"]; ok {
+		t.Error("Expected files under a deleted directory to be removed from the index")
+	}
+	info, _ := os.Stat(filepath.Join(dir, "a.txt"))
+	if known, may := sm.index.mayContain("a.txt", info, queryTrigrams("gamma")); !known || !may {
+		t.Errorf("Expected refreshed entry to contain new content, known=%v may=%v", known, may)
+	}
+}
+
+// TestSearchMaxResults tests result truncation
+func TestSearchMaxResults(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", "x\nx\nx\nx\nx\n")
+
+	sm := NewSearchManager(&App{})
+	summary, err := sm.Search(context.Background(), SearchOptions{Dir: dir, Query: "x", MaxResults: 3})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !summary.Truncated || summary.MatchCount != 3 || len(summary.Files[0].Lines) != 3 {
+		t.Errorf("Unexpected truncation result: %+v", summary)
+	}
+}
+
+// TestIgnoreMatcherPatterns tests gitignore pattern semantics
+func TestIgnoreMatcherPatterns(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".gitignore", "/dist\n**/tmp/**\ndocs/*.pdf\n*.o\n!important.o\n")
+
+	m := NewIgnoreMatcher(dir)
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"dist", true, true},
+		{"src/dist", true, false},
+		{"dist/app.js", false, true},
+		{"a/tmp/b.txt", false, true},
+		{"docs/a.pdf", false, true},
+		{"docs/sub/a.pdf", false, false},
+		{"x/y.o", false, true},
+		{"important.o", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.expected {
+			t.Errorf("Match(%s, %v) = %v, want %v", tt.path, tt.isDir, got, tt.expected)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -83,6 +84,8 @@ func (a *App) CreateSession() (*Session, error) {
 
 // SendMessage 发送消息（异步，不等待响应）
 func (a *App) SendMessage(sessionID, content string) error {
+	a.beginSessionTurn(sessionID, content)
+
 	payload := map[string]interface{}{
 		"parts": []map[string]interface{}{
 			{"type": "text", "text": content},
@@ -91,7 +94,7 @@ func (a *App) SendMessage(sessionID, content string) error {
 	body, _ := json.Marshal(payload)
 
 	// 使用异步接口，立即返回
-	url := fmt.Sprintf("%s/session/%s/prompt_async", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/prompt_async")
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %v", err)
@@ -172,6 +175,8 @@ func (a *App) SaveImageToWorkDir(img ImageData) (string, error) {
 
 // SendMessageWithModel 发送消息并指定模型（支持图片）
 func (a *App) SendMessageWithModel(sessionID, content, model string, images []ImageData) error {
+	a.beginSessionTurn(sessionID, content)
+
 	// 构建消息内容
 	messageText := content
 
@@ -216,7 +221,7 @@ func (a *App) SendMessageWithModel(sessionID, content, model string, images []Im
 	}
 	body, _ := json.Marshal(payload)
 
-	url := fmt.Sprintf("%s/session/%s/prompt_async", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/prompt_async")
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %v", err)
@@ -238,7 +243,7 @@ func (a *App) SendMessageWithModel(sessionID, content, model string, images []Im
 
 // CancelSession 取消会话中正在进行的请求
 func (a *App) CancelSession(sessionID string) error {
-	url := fmt.Sprintf("%s/session/%s/cancel", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/cancel")
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %v", err)
@@ -272,7 +277,7 @@ func (a *App) SetActiveFile(sessionID, filePath string) error {
 	}
 	body, _ := json.Marshal(payload)
 
-	url := fmt.Sprintf("%s/session/%s/prompt", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/prompt")
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %v", err)
@@ -290,7 +295,7 @@ func (a *App) SetActiveFile(sessionID, filePath string) error {
 
 // GetSessionMessages 获取会话的历史消息
 func (a *App) GetSessionMessages(sessionID string) ([]Message, error) {
-	url := fmt.Sprintf("%s/session/%s/message", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/message")
 	runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("获取历史消息: %s", url))
 
 	resp, err := a.httpClient.Get(url)
@@ -355,7 +360,7 @@ Completion:`, language, filename, code)
 	body, _ := json.Marshal(payload)
 
 	// 使用同步 prompt 接口，添加 Accept header
-	url := fmt.Sprintf("%s/session/%s/prompt", a.serverURL, sessionID)
+	url := a.sessionURL(sessionID, "/prompt")
 	runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("请求代码补全: %s", url))
 
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
@@ -441,85 +446,90 @@ func (a *App) SubscribeEvents() error {
 	// 保存当前的 serverURL，避免在循环中被修改
 	serverURL := a.serverURL
 
-	go func() {
+	go a.streamEvents(ctx, serverURL+"/event")
+	a.worktreeMgr.subscribeAll(ctx, serverURL)
+	go a.syncSecretMCPServers()
+	return nil
+}
+
+// streamEvents 持续读取 SSE 事件流并转发为 server-event，断开后自动重连
+func (a *App) streamEvents(ctx context.Context, url string) {
+	for {
+		select {
+		case <-ctx.Done():
+			runtime.EventsEmit(a.ctx, "output-log", "事件订阅已取消")
+			return
+		default:
+		}
+
+		runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("订阅事件: %s", url))
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("创建请求失败: %v", err))
+			time.Sleep(3 * time.Second)
+			continue
+		}
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return // 上下文已取消
+			}
+			runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("订阅失败: %v", err))
+			runtime.EventsEmit(a.ctx, "connection-error", err.Error())
+			time.Sleep(3 * time.Second)
+			continue
+		}
+
+		runtime.EventsEmit(a.ctx, "output-log", "事件订阅成功，等待事件...")
+		reader := bufio.NewReader(resp.Body)
+		var dataBuffer bytes.Buffer
+
 		for {
 			select {
 			case <-ctx.Done():
-				runtime.EventsEmit(a.ctx, "output-log", "事件订阅已取消")
+				resp.Body.Close()
 				return
 			default:
 			}
 
-			url := serverURL + "/event"
-			runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("订阅事件: %s", url))
-
-			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			line, err := reader.ReadString('\n')
 			if err != nil {
-				runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("创建请求失败: %v", err))
-				time.Sleep(3 * time.Second)
-				continue
+				resp.Body.Close()
+				runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("读取事件流中断: %v", err))
+				break
 			}
 
-			resp, err := a.httpClient.Do(req)
-			if err != nil {
-				if ctx.Err() != nil {
-					return // 上下文已取消
+			// 处理 SSE 协议
+			// 1. 空行表示事件结束，发送累积的数据
+			if strings.TrimSpace(line) == "" {
+				if dataBuffer.Len() > 0 {
+					data := dataBuffer.String()
+					runtime.EventsEmit(a.ctx, "server-event", data)
+					a.turnMgr.handleServerEvent(data)
+					dataBuffer.Reset()
 				}
-				runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("订阅失败: %v", err))
-				runtime.EventsEmit(a.ctx, "connection-error", err.Error())
-				time.Sleep(3 * time.Second)
 				continue
 			}
 
-			runtime.EventsEmit(a.ctx, "output-log", "事件订阅成功，等待事件...")
-			reader := bufio.NewReader(resp.Body)
-			var dataBuffer bytes.Buffer
-
-			for {
-				select {
-				case <-ctx.Done():
-					resp.Body.Close()
-					return
-				default:
-				}
-
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					resp.Body.Close()
-					runtime.EventsEmit(a.ctx, "output-log", fmt.Sprintf("读取事件流中断: %v", err))
-					break
-				}
-
-				// 处理 SSE 协议
-				// 1. 空行表示事件结束，发送累积的数据
-				if strings.TrimSpace(line) == "" {
-					if dataBuffer.Len() > 0 {
-						data := dataBuffer.String()
-						runtime.EventsEmit(a.ctx, "server-event", data)
-						dataBuffer.Reset()
-					}
-					continue
-				}
-
-				// 2. data 行累积数据
-				if strings.HasPrefix(line, "data:") {
-					data := strings.TrimPrefix(line, "data:")
-					// 如果有多行 data，用换行符连接
-					if dataBuffer.Len() > 0 {
-						dataBuffer.WriteString("\n")
-					}
-					dataBuffer.WriteString(strings.TrimSpace(data))
+			// 2. data 行累积数据
+			if strings.HasPrefix(line, "data:") {
+				data := strings.TrimPrefix(line, "data:")
+				// 如果有多行 data，用换行符连接
+				if dataBuffer.Len() > 0 {
+					dataBuffer.WriteString("\n")
 				}
+				dataBuffer.WriteString(strings.TrimSpace(data))
 			}
+		}
 
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(1 * time.Second):
-			}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(1 * time.Second):
 		}
-	}()
-	return nil
+	}
 }
 
 // CheckConnection 检查连接状态
@@ -539,3 +549,79 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// promptSession 同步发送提示并返回模型回复的文本（model 格式: provider/modelID，为空时使用默认模型）
+func (a *App) promptSession(sessionID, text, model string, timeout time.Duration) (string, error) {
+	payload := map[string]interface{}{
+		"parts": []map[string]interface{}{
+			{"type": "text", "text": text},
+		},
+	}
+	if modelParts := strings.SplitN(model, "/", 2); len(modelParts) == 2 {
+		payload["model"] = map[string]string{
+			"providerID": modelParts[0],
+			"modelID":    modelParts[1],
+		}
+	}
+	body, _ := json.Marshal(payload)
+
+	url := a.sessionURL(sessionID, "/prompt")
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("发送失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("请求失败 %d: %s", resp.StatusCode, string(respBody[:min(200, len(respBody))]))
+	}
+
+	var result struct {
+		Parts []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"parts"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("解析响应失败: %v", err)
+	}
+	var texts []string
+	for _, part := range result.Parts {
+		if part.Type == "text" && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n")), nil
+}
+
+// sessionURL 返回会话接口地址，工作树会话附带 directory 参数以路由到对应的项目
+func (a *App) sessionURL(sessionID, suffix string) string {
+	u := fmt.Sprintf("%s/session/%s%s", a.serverURL, sessionID, suffix)
+	if dir := a.worktreeMgr.sessionDir(sessionID); dir != "" {
+		u += "?directory=" + neturl.QueryEscape(dir)
+	}
+	return u
+}
+
+// deleteSession 删除会话（用于一次性的后台会话）
+func (a *App) deleteSession(sessionID string) error {
+	req, err := http.NewRequest("DELETE", a.sessionURL(sessionID, ""), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("删除会话失败: %v", err)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,711 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionTurn 会话中的一轮对话及其前后的工作区快照
+type SessionTurn struct {
+	ID          string            `json:"id"`
+	SessionID   string            `json:"sessionId"`
+	Index       int               `json:"index"` // 会话中的第几轮（从 1 开始）
+	Root        string            `json:"root"`
+	Prompt      string            `json:"prompt"`
+	BaseCommit  string            `json:"baseCommit"`            // 开始前的快照
+	AfterCommit string            `json:"afterCommit,omitempty"` // 结束后的快照
+	Status      string            `json:"status"`                // running, done, accepted, reverted
+	FileCount   int               `json:"fileCount"`
+	HunkStates  map[string]string `json:"hunkStates,omitempty"` // 区块键 -> accepted/reverted
+	StartedAt   time.Time         `json:"startedAt"`
+	EndedAt     *time.Time        `json:"endedAt,omitempty"`
+}
+
+// TurnHunk 本轮修改的一个区块
+type TurnHunk struct {
+	DiffHunk
+	Key   string `json:"key"`
+	State string `json:"state"` // pending, accepted, reverted
+}
+
+// TurnFileChange 本轮修改的文件
+type TurnFileChange struct {
+	Path    string     `json:"path"`
+	OldPath string     `json:"oldPath,omitempty"`
+	Status  string     `json:"status"`
+	Binary  bool       `json:"binary"`
+	Hunks   []TurnHunk `json:"hunks"`
+}
+
+// TurnChanges 一轮对话的全部修改
+type TurnChanges struct {
+	Turn  SessionTurn      `json:"turn"`
+	Files []TurnFileChange `json:"files"`
+}
+
+// turnRefPrefix 快照提交的引用前缀，防止被 git gc 回收
+const turnRefPrefix = "refs/opencode-desktop/turns/"
+
+// maxSessionTurns 保留的最大轮数
+const maxSessionTurns = 200
+
+// turnPromptPreview 记录的提示长度
+const turnPromptPreview = 200
+
+// SessionChangeTracker 按会话轮次记录 AI 对工作区的修改
+type SessionChangeTracker struct {
+	app    *App
+	dir    string // 索引保存目录
+	turns  []*SessionTurn
+	loaded bool
+	mu     sync.Mutex
+	begin  sync.Mutex // 串行化开始轮次（快照在 mu 之外进行）
+}
+
+// NewSessionChangeTracker 创建会话修改跟踪器
+func NewSessionChangeTracker(app *App) *SessionChangeTracker {
+	return &SessionChangeTracker{app: app}
+}
+
+// turnIndexFile 快照使用的独立索引文件名（位于 git 目录中，跨快照保留以复用文件状态缓存）
+const turnIndexFile = "opencode-desktop-turn-index"
+
+// snapshotMu 串行化快照，避免并发的 git add 争用同一个快照索引
+var snapshotMu sync.Mutex
+
+// snapshotWorktree 将工作区（含未跟踪、不含忽略的文件）写入快照提交，不影响暂存区
+func snapshotWorktree(root string) (string, error) {
+	gitDirOut, err := runGit(root, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("不是 Git 仓库: %s", root)
+	}
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	// 独立索引只需重新读取上次快照后变化的文件；首次快照时复制当前索引以复用其中的缓存
+	index := filepath.Join(strings.TrimSpace(gitDirOut), turnIndexFile)
+	if _, err := os.Stat(index); os.IsNotExist(err) {
+		if err := copyGitIndex(filepath.Join(strings.TrimSpace(gitDirOut), "index"), index); err != nil {
+			return "", err
+		}
+	}
+	env := []string{"GIT_INDEX_FILE=" + index}
+	if _, err := runGitEnv(root, env, "add", "-A", "--", "."); err != nil {
+		// 索引损坏时丢弃重建
+		os.Remove(index)
+		if _, err := runGitEnv(root, env, "add", "-A", "--", "."); err != nil {
+			return "", err
+		}
+	}
+	tree, err := runGitEnv(root, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	commit, err := runGit(root, "-c", "user.name=OpenCode Desktop", "-c", "user.email=desktop@opencode.local",
+		"commit-tree", strings.TrimSpace(tree), "-m", "opencode-desktop turn snapshot")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// copyGitIndex 复制索引文件（源不存在时忽略，由 git 自行创建）
+func copyGitIndex(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("创建快照索引失败: %v", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("复制索引失败: %v", err)
+	}
+	return out.Close()
+}
+
+// snapshot 复制轮次（区块状态单独复制，避免与后续修改共享）
+func (t *SessionTurn) snapshot() SessionTurn {
+	s := *t
+	if t.HunkStates != nil {
+		s.HunkStates = make(map[string]string, len(t.HunkStates))
+		for k, v := range t.HunkStates {
+			s.HunkStates[k] = v
+		}
+	}
+	return s
+}
+
+// indexPath 返回索引文件路径
+func (st *SessionChangeTracker) indexPath() (string, error) {
+	if st.dir == "" {
+		dir, err := st.app.getDataDir("session-changes")
+		if err != nil {
+			return "", err
+		}
+		st.dir = dir
+	}
+	if err := os.MkdirAll(st.dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(st.dir, "turns.json"), nil
+}
+
+// loadLocked 首次使用时读取索引（调用方需持有锁）
+func (st *SessionChangeTracker) loadLocked() {
+	if st.loaded {
+		return
+	}
+	st.loaded = true
+	path, err := st.indexPath()
+	if err != nil {
+		return
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &st.turns)
+	}
+	// 应用退出时未结束的轮次视为已结束
+	for _, t := range st.turns {
+		if t.Status == "running" {
+			t.Status = "done"
+		}
+	}
+}
+
+// saveLocked 保存索引（调用方需持有锁）
+func (st *SessionChangeTracker) saveLocked() error {
+	path, err := st.indexPath()
+	if err != nil {
+		return err
+	}
+	turns := st.turns
+	if turns == nil {
+		turns = []*SessionTurn{}
+	}
+	data, err := json.MarshalIndent(turns, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0600)
+}
+
+// findLocked 按 ID 查找轮次（调用方需持有锁）
+func (st *SessionChangeTracker) findLocked(turnID string) (*SessionTurn, error) {
+	st.loadLocked()
+	for _, t := range st.turns {
+		if t.ID == turnID {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("对话轮次不存在: %s", turnID)
+}
+
+// activeLocked 返回会话中正在进行的轮次（调用方需持有锁）
+func (st *SessionChangeTracker) activeLocked(sessionID string) *SessionTurn {
+	for i := len(st.turns) - 1; i >= 0; i-- {
+		if t := st.turns[i]; t.SessionID == sessionID && t.Status == "running" {
+			return t
+		}
+	}
+	return nil
+}
+
+// BeginTurn 在会话开始新一轮对话前记录工作区快照
+func (st *SessionChangeTracker) BeginTurn(sessionID, root, prompt string) (*SessionTurn, error) {
+	if sessionID == "" || root == "" {
+		return nil, fmt.Errorf("会话或工作目录为空")
+	}
+	repoRoot, err := gitTopLevel(root)
+	if err != nil {
+		return nil, err
+	}
+	st.begin.Lock()
+	defer st.begin.Unlock()
+
+	// 同一会话上一轮尚未结束时先结束它
+	st.mu.Lock()
+	st.loadLocked()
+	prev := st.activeLocked(sessionID)
+	st.mu.Unlock()
+	if prev != nil {
+		st.EndTurn(sessionID)
+	}
+
+	base, err := snapshotWorktree(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len([]rune(prompt)) > turnPromptPreview {
+		prompt = string([]rune(prompt)[:turnPromptPreview]) + "…"
+	}
+
+	st.mu.Lock()
+	index := 1
+	for _, t := range st.turns {
+		if t.SessionID == sessionID && t.Index >= index {
+			index = t.Index + 1
+		}
+	}
+	turn := &SessionTurn{
+		ID:         uuid.New().String(),
+		SessionID:  sessionID,
+		Index:      index,
+		Root:       repoRoot,
+		Prompt:     prompt,
+		BaseCommit: base,
+		Status:     "running",
+		StartedAt:  time.Now(),
+	}
+	runGit(repoRoot, "update-ref", turnRefPrefix+turn.ID+"/base", base)
+	st.turns = append(st.turns, turn)
+	st.pruneLocked()
+	err = st.saveLocked()
+	snapshot := turn.snapshot()
+	st.mu.Unlock()
+
+	st.app.emitEvent("session-turn-started", snapshot)
+	return &snapshot, err
+}
+
+// EndTurn 会话空闲时记录结束快照；没有任何修改的轮次直接丢弃
+func (st *SessionChangeTracker) EndTurn(sessionID string) (*SessionTurn, error) {
+	st.mu.Lock()
+	st.loadLocked()
+	turn := st.activeLocked(sessionID)
+	if turn == nil {
+		st.mu.Unlock()
+		return nil, nil
+	}
+	root, base := turn.Root, turn.BaseCommit
+	st.mu.Unlock()
+
+	after, err := snapshotWorktree(root)
+	if err != nil {
+		return nil, err
+	}
+	files := 0
+	if out, err := runGit(root, "diff", "--name-only", "-z", base, after); err == nil {
+		files = len(strings.Split(strings.TrimRight(out, "\x00"), "\x00"))
+		if strings.TrimSpace(out) == "" {
+			files = 0
+		}
+	}
+
+	st.mu.Lock()
+	if turn.Status != "running" {
+		st.mu.Unlock()
+		return nil, nil
+	}
+	now := time.Now()
+	turn.EndedAt = &now
+	turn.AfterCommit = after
+	turn.FileCount = files
+	turn.Status = "done"
+	if files == 0 {
+		st.removeLocked(turn.ID)
+	} else {
+		runGit(root, "update-ref", turnRefPrefix+turn.ID+"/after", after)
+	}
+	err = st.saveLocked()
+	snapshot := turn.snapshot()
+	st.mu.Unlock()
+
+	st.app.emitEvent("session-turn-finished", snapshot)
+	return &snapshot, err
+}
+
+// removeLocked 删除轮次及其快照引用（调用方需持有锁）
+func (st *SessionChangeTracker) removeLocked(turnID string) {
+	for i, t := range st.turns {
+		if t.ID == turnID {
+			runGit(t.Root, "update-ref", "-d", turnRefPrefix+t.ID+"/base")
+			if t.AfterCommit != "" {
+				runGit(t.Root, "update-ref", "-d", turnRefPrefix+t.ID+"/after")
+			}
+			st.turns = append(st.turns[:i], st.turns[i+1:]...)
+			return
+		}
+	}
+}
+
+// pruneLocked 删除超出上限的最早的已结束轮次（调用方需持有锁）
+func (st *SessionChangeTracker) pruneLocked() {
+	for len(st.turns) > maxSessionTurns {
+		oldest := st.turns[0]
+		if oldest.Status == "running" {
+			return
+		}
+		st.removeLocked(oldest.ID)
+	}
+}
+
+// Turns 列出轮次（最新的在前），sessionID 为空时列出全部
+func (st *SessionChangeTracker) Turns(sessionID string) []SessionTurn {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.loadLocked()
+
+	turns := []SessionTurn{}
+	for i := len(st.turns) - 1; i >= 0; i-- {
+		if t := st.turns[i]; sessionID == "" || t.SessionID == sessionID {
+			turns = append(turns, t.snapshot())
+		}
+	}
+	return turns
+}
+
+// turnDiff 读取轮次的修改（进行中的轮次与当前工作区比较）
+func (st *SessionChangeTracker) turnDiff(turnID string) (SessionTurn, []GitFileDiff, error) {
+	st.mu.Lock()
+	turn, err := st.findLocked(turnID)
+	if err != nil {
+		st.mu.Unlock()
+		return SessionTurn{}, nil, err
+	}
+	snapshot := turn.snapshot()
+	st.mu.Unlock()
+
+	after := snapshot.AfterCommit
+	if after == "" {
+		if after, err = snapshotWorktree(snapshot.Root); err != nil {
+			return snapshot, nil, err
+		}
+	}
+	out, err := runGit(snapshot.Root, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "-M", "-U3", snapshot.BaseCommit, after)
+	if err != nil {
+		return snapshot, nil, err
+	}
+	return snapshot, parseGitDiff(out), nil
+}
+
+// turnHunkKey 区块的稳定标识
+func turnHunkKey(path string, h DiffHunk) string {
+	return path + "#" + hashBytes([]byte(h.Header() + "\n" + strings.Join(h.Lines, "\n")))[:16]
+}
+
+// Changes 获取轮次修改的文件和区块
+func (st *SessionChangeTracker) Changes(turnID string) (*TurnChanges, error) {
+	turn, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return nil, err
+	}
+	changes := &TurnChanges{Turn: turn, Files: []TurnFileChange{}}
+	for _, d := range diffs {
+		f := TurnFileChange{Path: d.Path, OldPath: d.OldPath, Status: d.Status, Binary: d.Binary, Hunks: []TurnHunk{}}
+		for _, h := range d.Hunks {
+			key := turnHunkKey(d.Path, h)
+			state := turn.HunkStates[key]
+			if state == "" {
+				state = "pending"
+			}
+			f.Hunks = append(f.Hunks, TurnHunk{DiffHunk: h, Key: key, State: state})
+		}
+		changes.Files = append(changes.Files, f)
+	}
+	return changes, nil
+}
+
+// findTurnFile 在轮次修改中查找文件
+func findTurnFile(diffs []GitFileDiff, path string) (*GitFileDiff, error) {
+	for i := range diffs {
+		if diffs[i].Path == path || (diffs[i].OldPath != "" && diffs[i].OldPath == path) {
+			return &diffs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("本轮没有修改该文件: %s", path)
+}
+
+// setHunkStates 记录区块状态，所有区块都已处理时更新轮次状态
+func (st *SessionChangeTracker) setHunkStates(turnID string, states map[string]string, turnStatus string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	turn, err := st.findLocked(turnID)
+	if err != nil {
+		return err
+	}
+	if turn.HunkStates == nil {
+		turn.HunkStates = make(map[string]string)
+	}
+	for k, v := range states {
+		turn.HunkStates[k] = v
+	}
+	if turnStatus != "" && turn.Status != "running" {
+		turn.Status = turnStatus
+	}
+	return st.saveLocked()
+}
+
+// AcceptHunk 接受单个区块
+func (st *SessionChangeTracker) AcceptHunk(turnID, path string, index int) error {
+	_, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return err
+	}
+	d, err := findTurnFile(diffs, path)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(d.Hunks) {
+		return fmt.Errorf("区块不存在: %d", index)
+	}
+	return st.setHunkStates(turnID, map[string]string{turnHunkKey(d.Path, d.Hunks[index]): "accepted"}, "")
+}
+
+// Accept 接受整轮修改
+func (st *SessionChangeTracker) Accept(turnID string) error {
+	_, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return err
+	}
+	states := make(map[string]string)
+	for _, d := range diffs {
+		for _, h := range d.Hunks {
+			states[turnHunkKey(d.Path, h)] = "accepted"
+		}
+	}
+	return st.setHunkStates(turnID, states, "accepted")
+}
+
+// RevertHunk 撤销单个区块（将其反向应用到工作区）
+func (st *SessionChangeTracker) RevertHunk(turnID, path string, index int) error {
+	turn, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return err
+	}
+	d, err := findTurnFile(diffs, path)
+	if err != nil {
+		return err
+	}
+	patch, err := d.patch(index)
+	if err != nil {
+		return err
+	}
+	st.app.snapshotFile(filepath.Join(turn.Root, filepath.FromSlash(d.Path)), "turn-revert")
+	if _, err := runGitInput(turn.Root, patch, "apply", "-R", "--whitespace=nowarn", "-"); err != nil {
+		return fmt.Errorf("撤销区块失败，文件可能已被修改: %v", err)
+	}
+	return st.setHunkStates(turnID, map[string]string{turnHunkKey(d.Path, d.Hunks[index]): "reverted"}, "")
+}
+
+// revertFile 将单个文件恢复到轮次开始前的状态；反向应用本轮的修改（跳过已撤销的区块），
+// 文件在本轮之后又被修改而无法应用时拒绝撤销，不覆盖之后的修改
+func (st *SessionChangeTracker) revertFile(turn SessionTurn, d *GitFileDiff) error {
+	st.app.snapshotFile(filepath.Join(turn.Root, filepath.FromSlash(d.Path)), "turn-revert")
+	if d.Binary {
+		return revertBinaryTurnFile(turn, d)
+	}
+
+	var sb strings.Builder
+	for _, l := range d.header {
+		sb.WriteString(l + "\n")
+	}
+	pending := 0
+	for _, h := range d.Hunks {
+		if turn.HunkStates[turnHunkKey(d.Path, h)] == "reverted" {
+			continue
+		}
+		pending++
+		sb.WriteString(h.Header() + "\n")
+		for _, l := range h.Lines {
+			sb.WriteString(l + "\n")
+		}
+	}
+	if len(d.Hunks) > 0 && pending == 0 {
+		return nil
+	}
+	if _, err := runGitInput(turn.Root, sb.String(), "apply", "-R", "--whitespace=nowarn", "-"); err != nil {
+		return fmt.Errorf("撤销文件失败，文件可能已被修改: %v", err)
+	}
+	return nil
+}
+
+// revertBinaryTurnFile 恢复二进制文件（无法反向应用补丁，先确认文件仍是本轮结束时的内容）
+func revertBinaryTurnFile(turn SessionTurn, d *GitFileDiff) error {
+	abs := filepath.Join(turn.Root, filepath.FromSlash(d.Path))
+	if turn.AfterCommit != "" {
+		// 进行中的轮次的修改刚从当前工作区计算得出，无需比较
+		if d.Status == "D" {
+			if _, err := os.Lstat(abs); err == nil {
+				return fmt.Errorf("文件在本轮之后已被重新创建: %s", d.Path)
+			}
+		} else {
+			want, err := runGit(turn.Root, "rev-parse", turn.AfterCommit+":"+d.Path)
+			if err != nil {
+				return err
+			}
+			got, err := runGit(turn.Root, "hash-object", "--", d.Path)
+			if err != nil || strings.TrimSpace(got) != strings.TrimSpace(want) {
+				return fmt.Errorf("文件在本轮之后已被修改: %s", d.Path)
+			}
+		}
+	}
+	switch d.Status {
+	case "A":
+		if err := os.Remove(abs); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除文件失败: %v", err)
+		}
+		return nil
+	case "R":
+		if err := os.Remove(abs); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除文件失败: %v", err)
+		}
+		_, err := runGit(turn.Root, "restore", "--source="+turn.BaseCommit, "--worktree", "--", d.OldPath)
+		return err
+	}
+	_, err := runGit(turn.Root, "restore", "--source="+turn.BaseCommit, "--worktree", "--", d.Path)
+	return err
+}
+
+// RevertFile 将文件恢复到轮次开始前的状态
+func (st *SessionChangeTracker) RevertFile(turnID, path string) error {
+	turn, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return err
+	}
+	d, err := findTurnFile(diffs, path)
+	if err != nil {
+		return err
+	}
+	if err := st.revertFile(turn, d); err != nil {
+		return err
+	}
+	states := make(map[string]string)
+	for _, h := range d.Hunks {
+		states[turnHunkKey(d.Path, h)] = "reverted"
+	}
+	return st.setHunkStates(turnID, states, "")
+}
+
+// Revert 撤销整轮修改（已接受的区块所在文件也会恢复）
+func (st *SessionChangeTracker) Revert(turnID string) error {
+	turn, diffs, err := st.turnDiff(turnID)
+	if err != nil {
+		return err
+	}
+	states := make(map[string]string)
+	var failed []string
+	for i := range diffs {
+		if err := st.revertFile(turn, &diffs[i]); err != nil {
+			failed = append(failed, diffs[i].Path)
+			continue
+		}
+		for _, h := range diffs[i].Hunks {
+			states[turnHunkKey(diffs[i].Path, h)] = "reverted"
+		}
+	}
+	status := "reverted"
+	if len(failed) > 0 {
+		status = ""
+	}
+	if err := st.setHunkStates(turnID, states, status); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("部分文件撤销失败: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// handleServerEvent 根据 OpenCode 事件结束会话的当前轮次
+func (st *SessionChangeTracker) handleServerEvent(data string) {
+	var event struct {
+		Type       string `json:"type"`
+		Properties struct {
+			SessionID string          `json:"sessionID"`
+			Status    json.RawMessage `json:"status"`
+		} `json:"properties"`
+	}
+	if json.Unmarshal([]byte(data), &event) != nil || event.Properties.SessionID == "" {
+		return
+	}
+	idle := event.Type == "session.idle"
+	if event.Type == "session.status" {
+		var status struct {
+			Type string `json:"type"`
+		}
+		var plain string
+		if json.Unmarshal(event.Properties.Status, &status) == nil {
+			idle = status.Type == "idle"
+		} else if json.Unmarshal(event.Properties.Status, &plain) == nil {
+			idle = plain == "idle"
+		}
+	}
+	if idle {
+		go st.EndTurn(event.Properties.SessionID)
+	}
+}
+
+// beginSessionTurn 发送消息前记录开始快照（非 Git 工作区时忽略）；
+// 必须在请求发出前完成，否则 agent 的修改可能混入开始快照，空闲事件也可能先于轮次到达
+func (a *App) beginSessionTurn(sessionID, prompt string) {
+	root := a.worktreeMgr.sessionDir(sessionID)
+	if root == "" {
+		root = a.openCode.GetWorkDir()
+	}
+	if root == "" && a.fileMgr != nil {
+		root = a.fileMgr.GetRootDir()
+	}
+	if root == "" {
+		return
+	}
+	if _, err := gitTopLevel(root); err != nil {
+		return
+	}
+	if _, err := a.turnMgr.BeginTurn(sessionID, root, prompt); err != nil {
+		a.emitEvent("output-log", fmt.Sprintf("记录会话快照失败: %v", err))
+	}
+}
+
+// --- App API ---
+
+// GetSessionTurns 列出会话的对话轮次（sessionID 为空时列出全部）
+func (a *App) GetSessionTurns(sessionID string) []SessionTurn {
+	return a.turnMgr.Turns(sessionID)
+}
+
+// GetTurnChanges 获取一轮对话修改的文件和区块
+func (a *App) GetTurnChanges(turnID string) (*TurnChanges, error) {
+	return a.turnMgr.Changes(turnID)
+}
+
+// EndSessionTurn 手动结束会话当前轮次（未订阅事件时使用）
+func (a *App) EndSessionTurn(sessionID string) (*SessionTurn, error) {
+	return a.turnMgr.EndTurn(sessionID)
+}
+
+// AcceptTurn 接受一轮对话的全部修改
+func (a *App) AcceptTurn(turnID string) error {
+	return a.turnMgr.Accept(turnID)
+}
+
+// AcceptTurnHunk 接受一轮对话中的单个区块
+func (a *App) AcceptTurnHunk(turnID, path string, index int) error {
+	return a.turnMgr.AcceptHunk(turnID, path, index)
+}
+
+// RevertTurn 撤销一轮对话的全部修改
+func (a *App) RevertTurn(turnID string) error {
+	return a.turnMgr.Revert(turnID)
+}
+
+// RevertTurnFile 撤销一轮对话对单个文件的修改
+func (a *App) RevertTurnFile(turnID, path string) error {
+	return a.turnMgr.RevertFile(turnID, path)
+}
+
+// RevertTurnHunk 撤销一轮对话中的单个区块
+func (a *App) RevertTurnHunk(turnID, path string, index int) error {
+	return a.turnMgr.RevertHunk(turnID, path, index)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSessionTurnTracking tests baseline snapshots, per-turn changes and hunk/turn revert
+func TestSessionTurnTracking(t *testing.T) {
+	dir := initTestRepo(t)
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, "line")
+	}
+	writeTestFile(t, dir, "a.txt", strings.Join(lines, "\n")+"\n")
+	writeTestFile(t, dir, "b.txt", "b\n")
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "init")
+	writeTestFile(t, dir, "b.txt", "b staged\n")
+	mustGit(t, dir, "add", "b.txt")
+	writeTestFile(t, dir, "untracked.txt", "keep me\n")
+	stagedBefore := mustGit(t, dir, "diff", "--cached")
+
+	st := NewSessionChangeTracker(&App{})
+	st.dir = t.TempDir()
+	turn, err := st.BeginTurn("ses_1", dir, "refactor things")
+	if err != nil {
+		t.Fatalf("BeginTurn() error = %v", err)
+	}
+	if turn.Index != 1 || turn.Status != "running" || turn.BaseCommit == "" {
+		t.Fatalf("turn = %+v", turn)
+	}
+
+	// The agent edits two hunks, adds a file and deletes an untracked file
+	lines[0], lines[19] = "agent first", "agent last"
+	writeTestFile(t, dir, "a.txt", strings.Join(lines, "\n")+"\n")
+	writeTestFile(t, dir, "new.txt", "new\n")
+	os.Remove(filepath.Join(dir, "untracked.txt"))
+
+	ended, err := st.EndTurn("ses_1")
+	if err != nil || ended == nil {
+		t.Fatalf("EndTurn() = %+v, %v", ended, err)
+	}
+	if ended.Status != "done" || ended.FileCount != 3 {
+		t.Errorf("ended turn = %+v", ended)
+	}
+	if staged := mustGit(t, dir, "diff", "--cached"); staged != stagedBefore {
+		t.Errorf("snapshot changed the index: %q", staged)
+	}
+
+	changes, err := st.Changes(turn.ID)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	byPath := make(map[string]TurnFileChange)
+	for _, f := range changes.Files {
+		byPath[f.Path] = f
+	}
+	if len(byPath) != 3 || byPath["a.txt"].Status != "M" || len(byPath["a.txt"].Hunks) != 2 || byPath["new.txt"].Status != "A" || byPath["untracked.txt"].Status != "D" {
+		t.Fatalf("changes = %+v", changes.Files)
+	}
+
+	if err := st.RevertHunk(turn.ID, "a.txt", 0); err != nil {
+		t.Fatalf("RevertHunk() error = %v", err)
+	}
+	if err := st.AcceptHunk(turn.ID, "a.txt", 1); err != nil {
+		t.Fatalf("AcceptHunk() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.txt"))
+	if strings.Contains(string(data), "agent first") || !strings.Contains(string(data), "agent last") {
+		t.Errorf("a.txt after hunk revert = %q", data)
+	}
+	changes, _ = st.Changes(turn.ID)
+	for _, f := range changes.Files {
+		if f.Path == "a.txt" && (f.Hunks[0].State != "reverted" || f.Hunks[1].State != "accepted") {
+			t.Errorf("hunk states = %+v", f.Hunks)
+		}
+	}
+
+	if err := st.Revert(turn.ID); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if fileExists(filepath.Join(dir, "new.txt")) {
+		t.Error("new.txt still exists after revert")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "untracked.txt")); string(data) != "keep me\n" {
+		t.Errorf("untracked.txt = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); strings.Contains(string(data), "agent") {
+		t.Errorf("a.txt after revert = %q", data)
+	}
+	if turns := st.Turns("ses_1"); len(turns) != 1 || turns[0].Status != "reverted" {
+		t.Errorf("Turns() = %+v", turns)
+	}
+
+	// Reload the index from disk
+	reloaded := NewSessionChangeTracker(&App{})
+	reloaded.dir = st.dir
+	if turns := reloaded.Turns(""); len(turns) != 1 || turns[0].ID != turn.ID {
+		t.Errorf("reloaded Turns() = %+v", turns)
+	}
+}
+
+// TestSessionTurnEndedByServerEvent tests that idle events end the turn and empty turns are dropped
+func TestSessionTurnEndedByServerEvent(t *testing.T) {
+	dir := initTestRepo(t)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "init")
+
+	st := NewSessionChangeTracker(&App{})
+	st.dir = t.TempDir()
+	if _, err := st.BeginTurn("ses_2", dir, "just a question"); err != nil {
+		t.Fatalf("BeginTurn() error = %v", err)
+	}
+	st.handleServerEvent(`{"type":"session.status","properties":{"sessionID":"ses_2","status":{"type":"idle"}}}`)
+
+	for i := 0; i < 100 && len(st.Turns("ses_2")) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if turns := st.Turns("ses_2"); len(turns) != 0 {
+		t.Errorf("turn without changes was kept: %+v", turns)
+	}
+	if refs := mustGit(t, dir, "for-each-ref", turnRefPrefix); refs != "" {
+		t.Errorf("snapshot refs left behind: %q", refs)
+	}
+}
+
+// TestSnapshotWorktreeWithoutIndex tests snapshots of a fresh repository that has no index yet
+func TestSnapshotWorktreeWithoutIndex(t *testing.T) {
+	dir := initTestRepo(t)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	if _, err := os.Stat(filepath.Join(dir, ".git", "index")); !os.IsNotExist(err) {
+		t.Fatalf("Expected no index in fresh repo, stat error = %v", err)
+	}
+
+	commit, err := snapshotWorktree(dir)
+	if err != nil {
+		t.Fatalf("snapshotWorktree() error = %v", err)
+	}
+	if got := mustGit(t, dir, "show", commit+":a.txt"); got != "a\n" {
+		t.Errorf("Snapshot content = %q", got)
+	}
+}
+
+// TestSessionTurnRevertRefusesLaterEdits tests that reverting a file keeps edits made after the turn
+func TestSessionTurnRevertRefusesLaterEdits(t *testing.T) {
+	dir := initTestRepo(t)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	mustGit(t, dir, "add", ".")
+	mustGit(t, dir, "commit", "-q", "-m", "init")
+
+	st := NewSessionChangeTracker(&App{})
+	st.dir = t.TempDir()
+	turn, err := st.BeginTurn("ses_3", dir, "edit a")
+	if err != nil {
+		t.Fatalf("BeginTurn() error = %v", err)
+	}
+	writeTestFile(t, dir, "a.txt", "agent\n")
+	if _, err := st.EndTurn("ses_3"); err != nil {
+		t.Fatalf("EndTurn() error = %v", err)
+	}
+
+	writeTestFile(t, dir, "a.txt", "user\n")
+	if err := st.RevertFile(turn.ID, "a.txt"); err == nil {
+		t.Error("Expected RevertFile() to refuse a file edited after the turn")
+	}
+	if err := st.Revert(turn.ID); err == nil {
+		t.Error("Expected Revert() to report the edited file")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "user\n" {
+		t.Errorf("a.txt = %q", data)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// 技能的启用状态保存为 OpenCode 原生的 permission.skill 规则，"deny" 会让技能对 Agent 隐藏。
+// 规则与 MCP 共用配置层，按 全局 -> 项目 -> 本机 合并：项目层随仓库提交作为团队默认值，
+// 本机层只在本机生效。OpenCode 不读取本机层，启动时通过 OPENCODE_CONFIG_CONTENT 注入合并结果。
+
+const (
+	skillPermissionAllow = "allow"
+	skillPermissionDeny  = "deny"
+)
+
+// skillRule 合并后的规则及其来源层
+type skillRule struct {
+	Layer  string
+	Action string
+}
+
+// activationLayers 返回保存技能开关的配置层
+func (sm *SkillsManager) activationLayers() []mcpLayer {
+	homeDir, _ := os.UserHomeDir()
+	return mcpLayerPaths(homeDir, sm.workDir)
+}
+
+// readSkillPermissions 读取配置中的 permission.skill，字符串形式等同于 {"*": 值}
+func readSkillPermissions(file map[string]interface{}) map[string]string {
+	rules := make(map[string]string)
+	perm, _ := file["permission"].(map[string]interface{})
+	switch v := perm["skill"].(type) {
+	case string:
+		rules["*"] = v
+	case map[string]interface{}:
+		for pattern, action := range v {
+			if s, ok := action.(string); ok {
+				rules[pattern] = s
+			}
+		}
+	}
+	return rules
+}
+
+// mergeSkillRules 按优先级合并各层规则，同一模式由高层覆盖
+func mergeSkillRules(layers []mcpLayer, perLayer []map[string]string) map[string]skillRule {
+	merged := make(map[string]skillRule)
+	for i, rules := range perLayer {
+		for pattern, action := range rules {
+			merged[pattern] = skillRule{Layer: layers[i].Name, Action: action}
+		}
+	}
+	return merged
+}
+
+// resolveSkillRule 查找对技能生效的规则，与 OpenCode 一致取匹配的最长模式
+func resolveSkillRule(rules map[string]skillRule, name string) (skillRule, bool) {
+	best, found := "", false
+	for pattern := range rules {
+		if ok, _ := path.Match(pattern, name); !ok {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern > best) {
+			best, found = pattern, true
+		}
+	}
+	if !found {
+		return skillRule{}, false
+	}
+	return rules[best], true
+}
+
+// skillEnabled 没有规则时技能默认启用，"ask" 也视为启用
+func skillEnabled(rules map[string]skillRule, name string) bool {
+	rule, ok := resolveSkillRule(rules, name)
+	return !ok || rule.Action != skillPermissionDeny
+}
+
+// loadSkillRules 读取各层的配置文件和其中的规则
+func loadSkillRules(layers []mcpLayer) ([]map[string]interface{}, []map[string]string, error) {
+	files := make([]map[string]interface{}, len(layers))
+	perLayer := make([]map[string]string, len(layers))
+	for i, layer := range layers {
+		file, _, err := readMCPLayer(layer.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[i] = file
+		perLayer[i] = readSkillPermissions(file)
+	}
+	return files, perLayer, nil
+}
+
+// skillRules 返回当前工作目录合并后的规则，配置无法读取时按全部启用处理
+func (sm *SkillsManager) skillRules() map[string]skillRule {
+	layers := sm.activationLayers()
+	_, perLayer, err := loadSkillRules(layers)
+	if err != nil {
+		return map[string]skillRule{}
+	}
+	return mergeSkillRules(layers, perLayer)
+}
+
+// defaultSkillActivationLayer 未指定层时写入本机层，没有打开项目时写入全局层
+func defaultSkillActivationLayer(layers []mcpLayer) string {
+	if len(layers) > 1 {
+		return MCPLayerLocal
+	}
+	return MCPLayerGlobal
+}
+
+// SetSkillEnabled 在指定配置层启用或禁用技能，layer 为空时使用本机层，返回实际写入的层
+func (sm *SkillsManager) SetSkillEnabled(name string, enabled bool, layer string) (string, error) {
+	if _, err := sm.GetSkill(name); err != nil {
+		return "", err
+	}
+	layers := sm.activationLayers()
+	if layer == "" {
+		layer = defaultSkillActivationLayer(layers)
+	}
+	idx := -1
+	for i, l := range layers {
+		if l.Name == layer {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return "", fmt.Errorf("activation layer not available: %s", layer)
+	}
+
+	files, perLayer, err := loadSkillRules(layers)
+	if err != nil {
+		return "", err
+	}
+	file := files[idx]
+	perm, ok := file["permission"].(map[string]interface{})
+	if !ok {
+		if file["permission"] != nil {
+			return "", fmt.Errorf("permission in %s is not an object", layers[idx].Path)
+		}
+		perm = make(map[string]interface{})
+	}
+
+	// 先去掉本层对该技能的规则，其他规则已得到期望结果时不再写入
+	rules := perLayer[idx]
+	delete(rules, name)
+	if skillEnabled(mergeSkillRules(layers, perLayer), name) != enabled {
+		rules[name] = skillPermissionDeny
+		if enabled {
+			rules[name] = skillPermissionAllow
+		}
+	}
+	if merged := mergeSkillRules(layers, perLayer); skillEnabled(merged, name) != enabled {
+		rule, _ := resolveSkillRule(merged, name)
+		return "", fmt.Errorf("skill %s is overridden by the %s layer", name, rule.Layer)
+	}
+
+	if len(rules) == 0 {
+		delete(perm, "skill")
+	} else {
+		skill := make(map[string]interface{}, len(rules))
+		for pattern, action := range rules {
+			skill[pattern] = action
+		}
+		perm["skill"] = skill
+	}
+	if len(perm) == 0 {
+		delete(file, "permission")
+	} else {
+		file["permission"] = perm
+	}
+	if err := writeConfigLayer(layers[idx], file); err != nil {
+		return "", err
+	}
+	return layer, nil
+}
+
+// openCodeEnv 将合并后的技能规则加入 OPENCODE_CONFIG_CONTENT，环境中已有的规则优先
+func (sm *SkillsManager) openCodeEnv(env []string) []string {
+	rules := sm.skillRules()
+	if len(rules) == 0 {
+		return env
+	}
+	const key = "OPENCODE_CONFIG_CONTENT="
+	content := make(map[string]interface{})
+	pos := -1
+	for i, kv := range env {
+		if strings.HasPrefix(kv, key) {
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(kv, key)), &content); err != nil || content == nil {
+				return env // 无法解析的用户配置保持原样
+			}
+			pos = i
+		}
+	}
+
+	skill := make(map[string]interface{}, len(rules))
+	for pattern, rule := range rules {
+		skill[pattern] = rule.Action
+	}
+	perm, ok := content["permission"].(map[string]interface{})
+	if !ok {
+		if content["permission"] != nil {
+			return env
+		}
+		perm = make(map[string]interface{})
+	}
+	for pattern, action := range readSkillPermissions(content) {
+		skill[pattern] = action
+	}
+	perm["skill"] = skill
+	content["permission"] = perm
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return env
+	}
+	out := append([]string{}, env...)
+	if pos >= 0 {
+		out[pos] = key + string(data)
+	} else {
+		out = append(out, key+string(data))
+	}
+	return out
+}
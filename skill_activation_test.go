@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestResolveSkillRule tests exact names, wildcard patterns and layer overrides
+func TestResolveSkillRule(t *testing.T) {
+	layers := []mcpLayer{{MCPLayerGlobal, ""}, {MCPLayerProject, ""}, {MCPLayerLocal, ""}}
+	perLayer := []map[string]string{
+		{"*": "allow", "internal-*": "deny"},
+		{"internal-docs": "allow", "beta": "deny"},
+		{"beta": "allow"},
+	}
+	rules := mergeSkillRules(layers, perLayer)
+	tests := []struct {
+		name    string
+		enabled bool
+		layer   string
+	}{
+		{"review", true, MCPLayerGlobal},
+		{"internal-tools", false, MCPLayerGlobal},
+		{"internal-docs", true, MCPLayerProject},
+		{"beta", true, MCPLayerLocal},
+	}
+	for _, tt := range tests {
+		rule, _ := resolveSkillRule(rules, tt.name)
+		if skillEnabled(rules, tt.name) != tt.enabled || rule.Layer != tt.layer {
+			t.Errorf("%s: rule = %+v, want enabled=%v layer=%s", tt.name, rule, tt.enabled, tt.layer)
+		}
+	}
+	if !skillEnabled(map[string]skillRule{}, "any") {
+		t.Error("skills are enabled by default")
+	}
+	if got := readSkillPermissions(map[string]interface{}{"permission": map[string]interface{}{"skill": "deny"}}); !reflect.DeepEqual(got, map[string]string{"*": "deny"}) {
+		t.Errorf("string form = %v", got)
+	}
+}
+
+// TestSetSkillEnabled tests per-workspace opt-out of a project skill and the files it writes
+func TestSetSkillEnabled(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	if err := sm.CreateSkill("shared", "Team skill", "body", false); err != nil {
+		t.Fatal(err)
+	}
+	projectConfig := filepath.Join(sm.workDir, ".opencode", "config.json")
+	os.WriteFile(projectConfig, []byte(`{"mcp":{},"permission":{"bash":"ask"}}`), 0644)
+
+	layer, err := sm.SetSkillEnabled("shared", false, "")
+	if err != nil || layer != MCPLayerLocal {
+		t.Fatalf("layer = %s err = %v", layer, err)
+	}
+	skill, _ := sm.GetSkill("shared")
+	if skill.Enabled || skill.Activation != MCPLayerLocal {
+		t.Errorf("skill = %+v", skill)
+	}
+	gitignore, _ := os.ReadFile(filepath.Join(sm.workDir, ".opencode", ".gitignore"))
+	if !strings.Contains(string(gitignore), mcpLocalConfigName) {
+		t.Errorf(".gitignore = %q", gitignore)
+	}
+
+	// 团队在项目层默认关闭，本机层重新开启
+	if _, err := sm.SetSkillEnabled("shared", true, ""); err != nil {
+		t.Fatal(err)
+	}
+	local, _ := os.ReadFile(filepath.Join(sm.workDir, ".opencode", mcpLocalConfigName))
+	if strings.Contains(string(local), "shared") {
+		t.Errorf("enabling without other rules should remove the entry: %s", local)
+	}
+	if _, err := sm.SetSkillEnabled("shared", false, MCPLayerProject); err != nil {
+		t.Fatal(err)
+	}
+	var project map[string]interface{}
+	data, _ := os.ReadFile(projectConfig)
+	json.Unmarshal(data, &project)
+	perm := project["permission"].(map[string]interface{})
+	if perm["bash"] != "ask" || !reflect.DeepEqual(perm["skill"], map[string]interface{}{"shared": "deny"}) {
+		t.Errorf("project config = %s", data)
+	}
+	if _, err := sm.SetSkillEnabled("shared", true, MCPLayerLocal); err != nil {
+		t.Fatal(err)
+	}
+	skill, _ = sm.GetSkill("shared")
+	if !skill.Enabled || skill.Activation != MCPLayerLocal {
+		t.Errorf("local override: %+v", skill)
+	}
+
+	// 低层无法覆盖高层的规则
+	if _, err := sm.SetSkillEnabled("shared", false, MCPLayerGlobal); err == nil || !strings.Contains(err.Error(), "local") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := sm.SetSkillEnabled("missing", false, ""); err == nil {
+		t.Error("unknown skill should fail")
+	}
+	if _, err := sm.SetSkillEnabled("shared", false, "team"); err == nil {
+		t.Error("unknown layer should fail")
+	}
+}
+
+// TestSkillOpenCodeEnv tests merging skill rules into OPENCODE_CONFIG_CONTENT
+func TestSkillOpenCodeEnv(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	env := []string{"PATH=/bin"}
+	if got := sm.openCodeEnv(env); !reflect.DeepEqual(got, env) {
+		t.Errorf("no rules should leave env unchanged: %v", got)
+	}
+
+	os.MkdirAll(filepath.Join(sm.workDir, ".opencode"), 0755)
+	os.WriteFile(filepath.Join(sm.workDir, ".opencode", mcpLocalConfigName), []byte(`{"permission":{"skill":{"a":"deny","b":"deny"}}}`), 0644)
+
+	got := sm.openCodeEnv(append(env, `OPENCODE_CONFIG_CONTENT={"model":"x","permission":{"skill":{"b":"allow"}}}`))
+	var content map[string]interface{}
+	json.Unmarshal([]byte(strings.TrimPrefix(got[1], "OPENCODE_CONFIG_CONTENT=")), &content)
+	want := map[string]interface{}{"a": "deny", "b": "allow"}
+	if len(got) != 2 || content["model"] != "x" || !reflect.DeepEqual(content["permission"].(map[string]interface{})["skill"], want) {
+		t.Errorf("env = %v", got)
+	}
+
+	bad := append(env, "OPENCODE_CONFIG_CONTENT={")
+	if got := sm.openCodeEnv(bad); !reflect.DeepEqual(got, bad) {
+		t.Errorf("invalid content should be left alone: %v", got)
+	}
+}
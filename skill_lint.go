@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SkillIssue 技能检查发现的问题
+type SkillIssue struct {
+	Severity string `json:"severity"` // "error" | "warning"
+	Field    string `json:"field,omitempty"`
+	Line     int    `json:"line,omitempty"` // SKILL.md 中的行号
+	Message  string `json:"message"`
+}
+
+// skillFrontmatterFields OpenCode 识别的 frontmatter 字段，以及本应用使用的 version/author
+var skillFrontmatterFields = map[string]bool{
+	"name":          true,
+	"description":   true,
+	"license":       true,
+	"compatibility": true,
+	"metadata":      true,
+	"version":       true,
+	"author":        true,
+	"allowed-tools": true, // Claude 兼容
+}
+
+// maxSkillDescSize description 的最大字符数
+const maxSkillDescSize = 1024
+
+var (
+	skillVersionRe  = regexp.MustCompile(`^v?\d+(\.\d+){0,2}([-+][0-9A-Za-z.-]+)?$`)
+	yamlErrorLineRe = regexp.MustCompile(`line (\d+)`)
+)
+
+// splitFrontmatter 拆分 SKILL.md，分隔符必须独占一行
+func splitFrontmatter(content string) (front, body string, ok bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimRight(lines[0], " \t") != "---" {
+		return "", content, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == "---" {
+			front = strings.Join(lines[1:i], "\n")
+			body = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			return front, body, true
+		}
+	}
+	return "", content, false
+}
+
+// hasSkillErrors 是否存在会导致 OpenCode 忽略技能的问题
+func hasSkillErrors(issues []SkillIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// lintSkill 按 OpenCode 的要求检查 SKILL.md，dirName 为技能所在目录名
+func lintSkill(dirName string, content []byte) []SkillIssue {
+	issues := []SkillIssue{}
+	add := func(severity, field string, line int, format string, args ...interface{}) {
+		issues = append(issues, SkillIssue{Severity: severity, Field: field, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	text := string(content)
+	if strings.TrimRight(strings.SplitN(text, "\n", 2)[0], " \t\r") != "---" {
+		add("error", "", 1, "缺少 frontmatter，SKILL.md 必须以 --- 开头")
+		return issues
+	}
+	front, body, ok := splitFrontmatter(text)
+	if !ok {
+		add("error", "", 1, "frontmatter 没有结束的 ---")
+		return issues
+	}
+
+	// frontmatter 从第 2 行开始
+	const offset = 1
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(front), &doc); err != nil {
+		line := 0
+		if m := yamlErrorLineRe.FindStringSubmatch(err.Error()); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			line = n + offset
+		}
+		add("error", "", line, "frontmatter 不是有效的 YAML: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+		return issues
+	}
+	if len(doc.Content) == 0 {
+		add("error", "", 2, "frontmatter 为空")
+		return issues
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		add("error", "", root.Line+offset, "frontmatter 必须是键值映射")
+		return issues
+	}
+
+	fields := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		line := key.Line + offset
+		if _, dup := fields[key.Value]; dup {
+			add("error", key.Value, line, "字段 %s 重复定义", key.Value)
+			continue
+		}
+		fields[key.Value] = value
+		if !skillFrontmatterFields[key.Value] {
+			add("warning", key.Value, line, "未知字段 %s，OpenCode 会忽略", key.Value)
+			continue
+		}
+		switch key.Value {
+		case "metadata":
+			if value.Kind != yaml.MappingNode {
+				add("error", key.Value, line, "metadata 必须是键值映射")
+				break
+			}
+			for j := 1; j < len(value.Content); j += 2 {
+				if value.Content[j].Kind != yaml.ScalarNode {
+					add("error", key.Value, value.Content[j].Line+offset, "metadata.%s 必须是字符串", value.Content[j-1].Value)
+				}
+			}
+		case "allowed-tools":
+			if value.Kind != yaml.ScalarNode && value.Kind != yaml.SequenceNode {
+				add("error", key.Value, line, "allowed-tools 必须是字符串或列表")
+			}
+		default:
+			if value.Kind != yaml.ScalarNode {
+				add("error", key.Value, line, "%s 必须是字符串", key.Value)
+				delete(fields, key.Value)
+			}
+		}
+	}
+
+	scalar := func(field string) (string, int, bool) {
+		node, ok := fields[field]
+		if !ok || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return "", 0, false
+		}
+		return node.Value, node.Line + offset, true
+	}
+
+	if name, line, ok := scalar("name"); !ok || name == "" {
+		add("error", "name", 0, "缺少 name 字段")
+	} else if !isValidSkillName(name) {
+		add("error", "name", line, "name %q 不合法：只能使用小写字母、数字和单个连字符，1-64 字符", name)
+	} else if dirName != "" && name != dirName {
+		add("error", "name", line, "name %q 与目录名 %q 不一致，OpenCode 不会加载该技能", name, dirName)
+	}
+
+	if desc, line, ok := scalar("description"); !ok || strings.TrimSpace(desc) == "" {
+		add("error", "description", 0, "缺少 description 字段")
+	} else if n := utf8.RuneCountInString(desc); n > maxSkillDescSize {
+		add("error", "description", line, "description 过长（%d 字符，最多 %d）", n, maxSkillDescSize)
+	}
+
+	if version, line, ok := scalar("version"); ok && !skillVersionRe.MatchString(version) {
+		add("warning", "version", line, "version %q 不是 x.y.z 格式", version)
+	}
+
+	if body == "" {
+		add("warning", "", 0, "SKILL.md 没有正文指令")
+	}
+	return issues
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLintSkill tests frontmatter parsing and schema checks
+func TestLintSkill(t *testing.T) {
+	long := strings.Repeat("长", maxSkillDescSize+1)
+	tests := []struct {
+		name     string
+		content  string
+		severity string // 期望的第一个问题，为空表示没有问题
+		field    string
+		line     int
+		message  string
+	}{
+		{"valid", "---\nname: demo\ndescription: Demo skill\nversion: 1.0.0\nmetadata:\n  audience: dev\n---\n\nDo it.\n", "", "", 0, ""},
+		{"crlf", "---\r\nname: demo\r\ndescription: d\r\n---\r\n\r\nbody\r\n", "", "", 0, ""},
+		{"no frontmatter", "# demo\n", "error", "", 1, "缺少 frontmatter"},
+		{"unclosed", "---\nname: demo\n", "error", "", 1, "没有结束"},
+		{"bad yaml", "---\nname: demo\ndescription: a: b\n---\nbody", "error", "", 3, "YAML"},
+		{"empty", "---\n---\nbody", "error", "", 2, "为空"},
+		{"not a mapping", "---\n- a\n---\nbody", "error", "", 2, "键值映射"},
+		{"duplicate key", "---\nname: demo\ndescription: d\nname: demo\n---\nbody", "error", "name", 4, "重复"},
+		{"unknown field", "---\nname: demo\ndescription: d\ntags: [a]\n---\nbody", "warning", "tags", 4, "未知字段"},
+		{"missing name", "---\ndescription: d\n---\nbody", "error", "name", 0, "缺少 name"},
+		{"invalid name", "---\nname: de--mo\ndescription: d\n---\nbody", "error", "name", 2, "不合法"},
+		{"name mismatch", "---\nname: other\ndescription: d\n---\nbody", "error", "name", 2, "目录名"},
+		{"empty description", "---\nname: demo\ndescription:\n---\nbody", "error", "description", 0, "缺少 description"},
+		{"long description", "---\nname: demo\ndescription: " + long + "\n---\nbody", "error", "description", 3, "过长"},
+		{"description list", "---\nname: demo\ndescription:\n  - a\n---\nbody", "error", "description", 3, "必须是字符串"},
+		{"metadata value", "---\nname: demo\ndescription: d\nmetadata:\n  a: [1]\n---\nbody", "error", "metadata", 5, "metadata.a"},
+		{"bad version", "---\nname: demo\ndescription: d\nversion: latest\n---\nbody", "warning", "version", 4, "x.y.z"},
+		{"empty body", "---\nname: demo\ndescription: d\n---\n", "warning", "", 0, "正文"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := lintSkill("demo", []byte(tt.content))
+			if tt.severity == "" {
+				if len(issues) != 0 {
+					t.Errorf("issues = %+v", issues)
+				}
+				return
+			}
+			if len(issues) == 0 {
+				t.Fatal("no issues")
+			}
+			got := issues[0]
+			if got.Severity != tt.severity || got.Field != tt.field || got.Line != tt.line || !strings.Contains(got.Message, tt.message) {
+				t.Errorf("issue = %+v, want %s %s line %d %q", got, tt.severity, tt.field, tt.line, tt.message)
+			}
+		})
+	}
+}
+
+// TestParseFrontmatterDelimiter tests that --- inside a value does not end the frontmatter
+func TestParseFrontmatterDelimiter(t *testing.T) {
+	fm, body := parseFrontmatter("---\nname: demo\ndescription: before---after\n---\nbody --- text\n")
+	if fm.Description != "before---after" || body != "body --- text" {
+		t.Errorf("fm = %+v body = %q", fm, body)
+	}
+}
+
+// TestListSkillsDuplicates tests that shadowed and duplicate skills are reported
+func TestListSkillsDuplicates(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	write := func(dir, content string) {
+		os.MkdirAll(dir, 0755)
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	home := os.Getenv("HOME")
+	write(filepath.Join(home, ".config", "opencode", "skills", "review"), "---\nname: review\ndescription: global\n---\nbody")
+	write(filepath.Join(sm.workDir, ".opencode", "skills", "review"), "---\nname: review\ndescription: project\n---\nbody")
+	write(filepath.Join(sm.workDir, ".claude", "skills", "review-copy"), "---\nname: review\ndescription: copy\n---\nbody")
+
+	skills, err := sm.ListSkills()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skills) != 2 {
+		t.Fatalf("skills = %+v", skills)
+	}
+	review := skills[0]
+	if review.Source != "project" || review.Description != "project" {
+		t.Errorf("project skill should win: %+v", review)
+	}
+	var shadowed, duplicate bool
+	for _, issue := range review.Issues {
+		shadowed = shadowed || (issue.Severity == "warning" && strings.Contains(issue.Message, "覆盖"))
+		duplicate = duplicate || (issue.Field == "name" && strings.Contains(issue.Message, "review-copy"))
+	}
+	if !shadowed || !duplicate {
+		t.Errorf("issues = %+v", review.Issues)
+	}
+	if !hasSkillErrors(skills[1].Issues) {
+		t.Errorf("copy should report name mismatch: %+v", skills[1].Issues)
+	}
+}
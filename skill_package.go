@@ -0,0 +1,662 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// skillManifestName 技能包中的清单文件，位于 zip 根目录
+const skillManifestName = "manifest.json"
+
+// 技能包大小限制，防止解压炸弹
+const (
+	maxSkillFiles       = 500
+	maxSkillPackageSize = 20 << 20 // 解压后的总大小
+)
+
+// SkillManifest 技能包清单
+type SkillManifest struct {
+	Name        string              `json:"name"`
+	Version     string              `json:"version"`
+	Author      string              `json:"author,omitempty"`
+	Description string              `json:"description"`
+	Files       []SkillManifestFile `json:"files"`
+}
+
+// SkillManifestFile 清单中的文件
+type SkillManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// SkillFileDiff 更新技能时单个文件的变化
+type SkillFileDiff struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // added, removed, modified
+	Diff   string `json:"diff,omitempty"`
+	Binary bool   `json:"binary"`
+}
+
+// SkillImportPreview 导入技能包前的预览
+type SkillImportPreview struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Author      string          `json:"author"`
+	Description string          `json:"description"`
+	Files       []string        `json:"files"`
+	HasManifest bool            `json:"hasManifest"`
+	Warnings    []string        `json:"warnings"`
+	Installed   *SkillInfo      `json:"installed,omitempty"` // 目标位置已安装的同名技能
+	Downgrade   bool            `json:"downgrade"`           // 包的版本低于已安装版本
+	Changes     []SkillFileDiff `json:"changes"`
+}
+
+// skillPackage 读入内存的技能包
+type skillPackage struct {
+	frontmatter SkillFrontmatter
+	files       map[string][]byte // 相对路径 -> 内容，包含 SKILL.md
+	hasManifest bool
+	warnings    []string
+}
+
+// renderSkillFile 生成 SKILL.md 内容
+func renderSkillFile(fm SkillFrontmatter, body string) (string, error) {
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return fmt.Sprintf("---\n%s---\n\n%s\n", header, strings.TrimSpace(body)), nil
+}
+
+// cleanSkillPath 校验技能内的相对路径，拒绝绝对路径和越界路径
+func cleanSkillPath(p string) (string, error) {
+	p = strings.ReplaceAll(p, "\\", "/")
+	if p == "" || strings.HasPrefix(p, "/") || filepath.IsAbs(p) || strings.Contains(p, ":") {
+		return "", fmt.Errorf("invalid skill file path: %s", p)
+	}
+	clean := path.Clean(p)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid skill file path: %s", p)
+	}
+	return clean, nil
+}
+
+// isSkillResource 是否为随技能分发的文件（跳过隐藏文件和清单）
+func isSkillResource(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return rel != skillManifestName
+}
+
+// readSkillDir 读取技能目录下的全部文件
+func readSkillDir(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isSkillResource(rel) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skill directory: %w", err)
+	}
+	return files, nil
+}
+
+// skillResourceList 返回除 SKILL.md 外的文件列表
+func skillResourceList(files map[string][]byte) []string {
+	list := []string{}
+	for rel := range files {
+		if rel != "SKILL.md" {
+			list = append(list, rel)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// buildSkillManifest 根据文件生成清单
+func buildSkillManifest(files map[string][]byte) (SkillManifest, error) {
+	skillFile, ok := files["SKILL.md"]
+	if !ok {
+		return SkillManifest{}, fmt.Errorf("SKILL.md not found")
+	}
+	fm, _ := parseFrontmatter(string(skillFile))
+	manifest := SkillManifest{Name: fm.Name, Version: fm.Version, Author: fm.Author, Description: fm.Description}
+	paths := make([]string, 0, len(files))
+	for rel := range files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		sum := sha256.Sum256(files[rel])
+		manifest.Files = append(manifest.Files, SkillManifestFile{Path: rel, Size: int64(len(files[rel])), SHA256: hex.EncodeToString(sum[:])})
+	}
+	return manifest, nil
+}
+
+// writeSkillZip 将技能文件和清单写入 zip
+func writeSkillZip(w io.Writer, files map[string][]byte) error {
+	manifest, err := buildSkillManifest(files)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	write := func(name string, content []byte, mode os.FileMode) error {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(mode)
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(content)
+		return err
+	}
+	if err := write(skillManifestName, data, 0644); err != nil {
+		return err
+	}
+	for _, f := range manifest.Files {
+		if err := write(f.Path, files[f.Path], skillFileMode(f.Path, files[f.Path])); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// skillFileMode scripts 目录下的文件和带 shebang 的文件需要可执行权限
+func skillFileMode(rel string, content []byte) os.FileMode {
+	if strings.HasPrefix(rel, "scripts/") || bytes.HasPrefix(content, []byte("#!")) {
+		return 0755
+	}
+	return 0644
+}
+
+// readSkillZip 读取并校验技能包
+// 兼容直接压缩技能目录产生的单层顶级目录；有清单时文件列表和校验和必须一致
+func readSkillZip(zipPath string) (*skillPackage, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open skill package: %w", err)
+	}
+	defer zr.Close()
+
+	var entries []*zip.File
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			entries = append(entries, f)
+		}
+	}
+	if len(entries) > maxSkillFiles {
+		return nil, fmt.Errorf("skill package has too many files (max %d)", maxSkillFiles)
+	}
+
+	// 没有根目录的 SKILL.md 时，去掉唯一的顶级目录
+	prefix := ""
+	hasRoot := false
+	tops := make(map[string]bool)
+	for _, f := range entries {
+		name := strings.ReplaceAll(f.Name, "\\", "/")
+		if name == "SKILL.md" {
+			hasRoot = true
+		}
+		tops[strings.SplitN(name, "/", 2)[0]] = true
+	}
+	if !hasRoot && len(tops) == 1 {
+		for top := range tops {
+			prefix = top + "/"
+		}
+	}
+
+	pkg := &skillPackage{files: make(map[string][]byte)}
+	var manifestData []byte
+	var total int64
+	for _, f := range entries {
+		name := strings.TrimPrefix(strings.ReplaceAll(f.Name, "\\", "/"), prefix)
+		rel, err := cleanSkillPath(name)
+		if err != nil {
+			return nil, err
+		}
+		if !isSkillResource(rel) && rel != skillManifestName {
+			continue
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			return nil, fmt.Errorf("symlinks are not allowed in skill packages: %s", rel)
+		}
+		total += int64(f.UncompressedSize64)
+		if total > maxSkillPackageSize {
+			return nil, fmt.Errorf("skill package is too large (max %d MB)", maxSkillPackageSize>>20)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxSkillPackageSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		if int64(len(data)) > int64(f.UncompressedSize64) {
+			return nil, fmt.Errorf("size mismatch for %s", rel)
+		}
+		if rel == skillManifestName {
+			manifestData = data
+			continue
+		}
+		pkg.files[rel] = data
+	}
+
+	skillFile, ok := pkg.files["SKILL.md"]
+	if !ok {
+		return nil, fmt.Errorf("SKILL.md not found in skill package")
+	}
+	pkg.frontmatter, _ = parseFrontmatter(string(skillFile))
+	if !isValidSkillName(pkg.frontmatter.Name) {
+		return nil, fmt.Errorf("invalid skill name in SKILL.md: %q", pkg.frontmatter.Name)
+	}
+	// 包内的 SKILL.md 不能有会导致 OpenCode 忽略的问题
+	for _, issue := range lintSkill(pkg.frontmatter.Name, skillFile) {
+		if issue.Severity == "error" {
+			return nil, fmt.Errorf("invalid SKILL.md: %s", issue.Message)
+		}
+		pkg.warnings = append(pkg.warnings, issue.Message)
+	}
+
+	if manifestData == nil {
+		pkg.warnings = append(pkg.warnings, "技能包没有 manifest.json，无法校验文件完整性")
+		if pkg.frontmatter.Version == "" {
+			pkg.warnings = append(pkg.warnings, "SKILL.md 没有 version 字段")
+		}
+		return pkg, nil
+	}
+	pkg.hasManifest = true
+	if err := verifySkillManifest(manifestData, pkg); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// verifySkillManifest 校验清单与 SKILL.md 和实际文件一致
+func verifySkillManifest(data []byte, pkg *skillPackage) error {
+	var manifest SkillManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest.json: %w", err)
+	}
+	fm := pkg.frontmatter
+	if manifest.Name != fm.Name {
+		return fmt.Errorf("manifest name %q does not match SKILL.md name %q", manifest.Name, fm.Name)
+	}
+	if manifest.Version != fm.Version {
+		return fmt.Errorf("manifest version %q does not match SKILL.md version %q", manifest.Version, fm.Version)
+	}
+	listed := make(map[string]bool)
+	for _, f := range manifest.Files {
+		rel, err := cleanSkillPath(f.Path)
+		if err != nil {
+			return err
+		}
+		data, ok := pkg.files[rel]
+		if !ok {
+			return fmt.Errorf("file listed in manifest is missing: %s", rel)
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), f.SHA256) {
+			return fmt.Errorf("checksum mismatch: %s", rel)
+		}
+		listed[rel] = true
+	}
+	for rel := range pkg.files {
+		if !listed[rel] {
+			return fmt.Errorf("file not listed in manifest: %s", rel)
+		}
+	}
+	return nil
+}
+
+// diffSkillFiles 比较已安装文件和新文件
+func diffSkillFiles(oldFiles, newFiles map[string][]byte) []SkillFileDiff {
+	paths := make(map[string]bool)
+	for rel := range oldFiles {
+		paths[rel] = true
+	}
+	for rel := range newFiles {
+		paths[rel] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for rel := range paths {
+		sorted = append(sorted, rel)
+	}
+	sort.Strings(sorted)
+
+	changes := []SkillFileDiff{}
+	for _, rel := range sorted {
+		oldData, hadOld := oldFiles[rel]
+		newData, hasNew := newFiles[rel]
+		change := SkillFileDiff{Path: rel}
+		switch {
+		case !hadOld:
+			change.Status = "added"
+		case !hasNew:
+			change.Status = "removed"
+		case bytes.Equal(oldData, newData):
+			continue
+		default:
+			change.Status = "modified"
+		}
+		if isBinarySkillFile(oldData) || isBinarySkillFile(newData) {
+			change.Binary = true
+		} else {
+			change.Diff = UnifiedDiff("a/"+rel, "b/"+rel, string(oldData), string(newData), 3)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func isBinarySkillFile(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data)
+}
+
+// compareSkillVersions 比较 x.y.z 形式的版本号，忽略前缀 v 和预发布后缀
+func compareSkillVersions(a, b string) int {
+	parse := func(v string) []int {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+		if i := strings.IndexAny(v, "-+"); i >= 0 {
+			v = v[:i]
+		}
+		var nums []int
+		for _, part := range strings.Split(v, ".") {
+			n, _ := strconv.Atoi(part)
+			nums = append(nums, n)
+		}
+		return nums
+	}
+	pa, pb := parse(a), parse(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// skillTargetDir 返回新技能在全局或项目目录中的位置
+func (sm *SkillsManager) skillTargetDir(name string, global bool) (string, error) {
+	if global {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot get home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".config", "opencode", "skills", name), nil
+	}
+	if sm.workDir == "" {
+		return "", fmt.Errorf("work directory not set")
+	}
+	return filepath.Join(sm.workDir, ".opencode", "skills", name), nil
+}
+
+// ExportSkill 将技能及其附带文件打包为 zip
+func (sm *SkillsManager) ExportSkill(name, zipPath string) error {
+	skill, err := sm.GetSkill(name)
+	if err != nil {
+		return err
+	}
+	files, err := readSkillDir(skill.Path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := writeSkillZip(&buf, files); err != nil {
+		return fmt.Errorf("failed to create skill package: %w", err)
+	}
+	if err := writeFileAtomic(zipPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write skill package: %w", err)
+	}
+	return nil
+}
+
+// PreviewSkillImport 校验技能包，已安装同名技能时列出将要发生的文件变化
+func (sm *SkillsManager) PreviewSkillImport(zipPath string, global bool) (*SkillImportPreview, error) {
+	pkg, err := readSkillZip(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	fm := pkg.frontmatter
+	preview := &SkillImportPreview{
+		Name:        fm.Name,
+		Version:     fm.Version,
+		Author:      fm.Author,
+		Description: fm.Description,
+		Files:       skillResourceList(pkg.files),
+		HasManifest: pkg.hasManifest,
+		Warnings:    append([]string{}, pkg.warnings...),
+		Changes:     []SkillFileDiff{},
+	}
+	target, err := sm.skillTargetDir(fm.Name, global)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(target, "SKILL.md")); err == nil {
+		installed, err := readSkillDir(target)
+		if err != nil {
+			return nil, err
+		}
+		info := skillInfoFromDir(target, fm.Name, skillSource(global), installed)
+		preview.Installed = &info
+		preview.Downgrade = info.Version != "" && compareSkillVersions(fm.Version, info.Version) < 0
+		preview.Changes = diffSkillFiles(installed, pkg.files)
+	}
+	return preview, nil
+}
+
+// ImportSkill 安装技能包；已安装同名技能时需要 replace 为 true，替换前会完整写好新目录再切换
+func (sm *SkillsManager) ImportSkill(zipPath string, global, replace bool) (*SkillInfo, error) {
+	pkg, err := readSkillZip(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	target, err := sm.skillTargetDir(pkg.frontmatter.Name, global)
+	if err != nil {
+		return nil, err
+	}
+	exists := false
+	if _, err := os.Stat(target); err == nil {
+		if !replace {
+			return nil, fmt.Errorf("skill already exists: %s", pkg.frontmatter.Name)
+		}
+		exists = true
+	}
+
+	parent := filepath.Dir(target)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create skill directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(parent, "."+pkg.frontmatter.Name+".import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+	for rel, data := range pkg.files {
+		dest := filepath.Join(staging, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create skill directory: %w", err)
+		}
+		if err := os.WriteFile(dest, data, skillFileMode(rel, data)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+
+	if exists {
+		backup := staging + ".old"
+		if err := os.Rename(target, backup); err != nil {
+			return nil, fmt.Errorf("failed to replace skill: %w", err)
+		}
+		if err := os.Rename(staging, target); err != nil {
+			os.Rename(backup, target)
+			return nil, fmt.Errorf("failed to replace skill: %w", err)
+		}
+		os.RemoveAll(backup)
+	} else if err := os.Rename(staging, target); err != nil {
+		return nil, fmt.Errorf("failed to install skill: %w", err)
+	}
+
+	info := skillInfoFromDir(target, pkg.frontmatter.Name, skillSource(global), pkg.files)
+	return &info, nil
+}
+
+// skillSource 全局或项目来源
+func skillSource(global bool) string {
+	if global {
+		return "global"
+	}
+	return "project"
+}
+
+// skillInfoFromDir 根据读入的文件构造技能信息
+func skillInfoFromDir(dir, name, source string, files map[string][]byte) SkillInfo {
+	fm, body := parseFrontmatter(string(files["SKILL.md"]))
+	info := SkillInfo{
+		Name:        fm.Name,
+		Description: fm.Description,
+		Version:     fm.Version,
+		Author:      fm.Author,
+		Path:        dir,
+		Source:      source,
+		Content:     body,
+		Files:       skillResourceList(files),
+		Enabled:     true,
+		Issues:      lintSkill(filepath.Base(dir), files["SKILL.md"]),
+	}
+	if info.Name == "" {
+		info.Name = name
+	}
+	return info
+}
+
+// skillResourcePath 返回技能内资源文件的绝对路径，SKILL.md 和清单需通过专门的方法修改
+func (sm *SkillsManager) skillResourcePath(name, rel string) (string, string, error) {
+	skill, err := sm.GetSkill(name)
+	if err != nil {
+		return "", "", err
+	}
+	clean, err := cleanSkillPath(rel)
+	if err != nil {
+		return "", "", err
+	}
+	if clean == "SKILL.md" || !isSkillResource(clean) {
+		return "", "", fmt.Errorf("reserved skill file: %s", clean)
+	}
+	return filepath.Join(skill.Path, filepath.FromSlash(clean)), clean, nil
+}
+
+// ReadSkillFile 读取技能附带的文件
+func (sm *SkillsManager) ReadSkillFile(name, rel string) (string, error) {
+	p, _, err := sm.skillResourcePath(name, rel)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to read skill file: %w", err)
+	}
+	return string(data), nil
+}
+
+// WriteSkillFile 添加或修改技能附带的脚本、参考文档等文件
+func (sm *SkillsManager) WriteSkillFile(name, rel, content string) error {
+	p, clean, err := sm.skillResourcePath(name, rel)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(p, []byte(content), skillFileMode(clean, []byte(content))); err != nil {
+		return fmt.Errorf("failed to write skill file: %w", err)
+	}
+	return nil
+}
+
+// DeleteSkillFile 删除技能附带的文件
+func (sm *SkillsManager) DeleteSkillFile(name, rel string) error {
+	p, _, err := sm.skillResourcePath(name, rel)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		return fmt.Errorf("failed to delete skill file: %w", err)
+	}
+	return nil
+}
+
+// SetSkillMetadata 修改技能的版本和作者
+func (sm *SkillsManager) SetSkillMetadata(name, version, author string) error {
+	skill, err := sm.GetSkill(name)
+	if err != nil {
+		return err
+	}
+	skillFile := filepath.Join(skill.Path, "SKILL.md")
+	data, err := os.ReadFile(skillFile)
+	if err != nil {
+		return fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	fm, body := parseFrontmatter(string(data))
+	fm.Version = strings.TrimSpace(version)
+	fm.Author = strings.TrimSpace(author)
+	content, err := renderSkillFile(fm, body)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(skillFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to update SKILL.md: %w", err)
+	}
+	return nil
+}
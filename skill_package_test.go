@@ -0,0 +1,254 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestZip 按给定的文件内容生成 zip
+func writeTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "skill.zip")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	f.Close()
+	return p
+}
+
+func newTestSkillsManager(t *testing.T) *SkillsManager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	return NewSkillsManager(t.TempDir())
+}
+
+// TestRenderSkillFile tests that frontmatter survives values that need quoting
+func TestRenderSkillFile(t *testing.T) {
+	fm := SkillFrontmatter{Name: "a", Description: "usage: run it # now", Version: "1.2.0", Author: "dev"}
+	content, err := renderSkillFile(fm, "\nbody\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, body := parseFrontmatter(content)
+	if got != fm || body != "body" {
+		t.Errorf("got %+v %q from %q", got, body, content)
+	}
+	content, _ = renderSkillFile(SkillFrontmatter{Name: "a", Description: "d"}, "b")
+	if strings.Contains(content, "version") || strings.Contains(content, "author") {
+		t.Errorf("empty fields should be omitted: %q", content)
+	}
+}
+
+// TestSkillExportImport tests exporting a skill with bundled files and importing it elsewhere
+func TestSkillExportImport(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	if err := sm.CreateSkill("deploy", "Deploy the app", "Run scripts/deploy.sh", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.WriteSkillFile("deploy", "scripts/deploy.sh", "#!/bin/sh\necho hi\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.WriteSkillFile("deploy", "reference/notes.md", "notes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetSkillMetadata("deploy", "1.0.0", "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.UpdateSkill("deploy", "Deploy the app safely", "Run scripts/deploy.sh"); err != nil {
+		t.Fatal(err)
+	}
+
+	skill, err := sm.GetSkill("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skill.Version != "1.0.0" || skill.Author != "ops" {
+		t.Errorf("UpdateSkill lost metadata: %+v", skill)
+	}
+	if !reflect.DeepEqual(skill.Files, []string{"reference/notes.md", "scripts/deploy.sh"}) {
+		t.Errorf("files = %v", skill.Files)
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "deploy.zip")
+	if err := sm.ExportSkill("deploy", zipPath); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := readSkillZip(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pkg.hasManifest || len(pkg.warnings) != 0 || len(pkg.files) != 3 {
+		t.Errorf("pkg = %+v", pkg)
+	}
+
+	preview, err := sm.PreviewSkillImport(zipPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Installed != nil || preview.Version != "1.0.0" || len(preview.Files) != 2 {
+		t.Errorf("preview = %+v", preview)
+	}
+	imported, err := sm.ImportSkill(zipPath, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Source != "global" || imported.Author != "ops" {
+		t.Errorf("imported = %+v", imported)
+	}
+	info, err := os.Stat(filepath.Join(imported.Path, "scripts", "deploy.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 && os.PathSeparator == '/' {
+		t.Errorf("script is not executable: %v", info.Mode())
+	}
+	if _, err := sm.ImportSkill(zipPath, true, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("duplicate import err = %v", err)
+	}
+}
+
+// TestSkillImportUpdate tests the diff preview and replacement of an installed skill
+func TestSkillImportUpdate(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	if err := sm.CreateSkill("lint", "Lint code", "old body", false); err != nil {
+		t.Fatal(err)
+	}
+	sm.SetSkillMetadata("lint", "2.0.0", "")
+	sm.WriteSkillFile("lint", "reference/old.md", "old")
+	sm.WriteSkillFile("lint", "scripts/run.sh", "same")
+
+	zipPath := writeTestZip(t, map[string]string{
+		"lint/SKILL.md":         "---\nname: lint\ndescription: Lint code\nversion: 1.5.0\n---\n\nnew body\n",
+		"lint/scripts/run.sh":   "same",
+		"lint/reference/new.md": "new",
+	})
+	preview, err := sm.PreviewSkillImport(zipPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Installed == nil || !preview.Downgrade || preview.HasManifest || len(preview.Warnings) != 1 {
+		t.Fatalf("preview = %+v", preview)
+	}
+	status := map[string]string{}
+	for _, c := range preview.Changes {
+		status[c.Path] = c.Status
+	}
+	want := map[string]string{"SKILL.md": "modified", "reference/old.md": "removed", "reference/new.md": "added"}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("changes = %v", status)
+	}
+	if preview.Changes[0].Path != "SKILL.md" || !strings.Contains(preview.Changes[0].Diff, "+new body") {
+		t.Errorf("SKILL.md diff = %+v", preview.Changes[0])
+	}
+
+	if _, err := sm.ImportSkill(zipPath, false, true); err != nil {
+		t.Fatal(err)
+	}
+	skill, _ := sm.GetSkill("lint")
+	if skill.Version != "1.5.0" || skill.Content != "new body" || !reflect.DeepEqual(skill.Files, []string{"reference/new.md", "scripts/run.sh"}) {
+		t.Errorf("skill = %+v", skill)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(skill.Path))
+	if len(entries) != 1 {
+		t.Errorf("leftover staging directories: %v", entries)
+	}
+}
+
+// TestReadSkillZipValidation tests manifest checks and unsafe archives
+func TestReadSkillZipValidation(t *testing.T) {
+	skillMD := "---\nname: demo\ndescription: Demo\nversion: 1.0.0\n---\n\nbody\n"
+	manifest := func(m SkillManifest) string {
+		data, _ := json.Marshal(m)
+		return string(data)
+	}
+	valid, _ := buildSkillManifest(map[string][]byte{"SKILL.md": []byte(skillMD)})
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"no skill file", map[string]string{"README.md": "x"}, "SKILL.md not found"},
+		{"traversal", map[string]string{"SKILL.md": skillMD, "../evil.sh": "x"}, "invalid skill file path"},
+		{"bad name", map[string]string{"SKILL.md": "---\nname: Bad Name\ndescription: d\n---\n"}, "invalid skill name"},
+		{"version mismatch", map[string]string{"SKILL.md": skillMD, "manifest.json": manifest(SkillManifest{Name: "demo", Version: "2.0.0", Files: valid.Files})}, "version"},
+		{"checksum", map[string]string{"SKILL.md": skillMD, "manifest.json": manifest(SkillManifest{Name: "demo", Version: "1.0.0", Files: []SkillManifestFile{{Path: "SKILL.md", SHA256: "00"}}})}, "checksum"},
+		{"unlisted file", map[string]string{"SKILL.md": skillMD, "extra.md": "x", "manifest.json": manifest(valid)}, "not listed"},
+		{"missing file", map[string]string{"SKILL.md": skillMD, "manifest.json": manifest(SkillManifest{Name: "demo", Version: "1.0.0", Files: append(valid.Files, SkillManifestFile{Path: "gone.md"})})}, "missing"},
+		{"bad manifest", map[string]string{"SKILL.md": skillMD, "manifest.json": "{"}, "invalid manifest.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readSkillZip(writeTestZip(t, tt.files))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	pkg, err := readSkillZip(writeTestZip(t, map[string]string{"SKILL.md": skillMD, "manifest.json": manifest(valid), ".DS_Store": "x"}))
+	if err != nil || !pkg.hasManifest || len(pkg.files) != 1 {
+		t.Errorf("pkg = %+v err = %v", pkg, err)
+	}
+}
+
+// TestSkillResourceFiles tests that resource file helpers stay inside the skill directory
+func TestSkillResourceFiles(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	if err := sm.CreateSkill("docs", "Docs", "body", false); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"../x", "/etc/passwd", "SKILL.md", "manifest.json", ".hidden", "a/../../x"} {
+		if err := sm.WriteSkillFile("docs", p, "x"); err == nil {
+			t.Errorf("WriteSkillFile(%q) succeeded", p)
+		}
+	}
+	if err := sm.WriteSkillFile("docs", "reference/a.md", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := sm.ReadSkillFile("docs", "reference/a.md"); got != "hello" {
+		t.Errorf("ReadSkillFile = %q", got)
+	}
+	if err := sm.DeleteSkillFile("docs", "reference/a.md"); err != nil {
+		t.Fatal(err)
+	}
+	if skill, _ := sm.GetSkill("docs"); len(skill.Files) != 0 {
+		t.Errorf("files = %v", skill.Files)
+	}
+}
+
+// TestCompareSkillVersions tests semantic version ordering
+func TestCompareSkillVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"0.9.9", "1.0.0", -1},
+		{"2.0.0-beta", "1.9.9", 1},
+	}
+	for _, tt := range tests {
+		if got := compareSkillVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareSkillVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 用户模板保存在模板目录下，每个模板一个子目录，目录名即模板 ID：
+//
+//	<模板目录>/<id>/TEMPLATE.md   frontmatter 为 name/description/category/variables，正文为技能内容
+//	<模板目录>/<id>/scripts/...   随模板复制到新技能的附带文件
+//
+// 使用 TEMPLATE.md 而不是 SKILL.md，避免 OpenCode 把模板当作技能加载。
+// 描述、正文和文本附带文件中的 {{变量}} 在创建技能时替换，{{name}} 始终为新技能名称。
+// 写成 \{{变量}} 时按原样保留为 {{变量}}（从技能保存模板时会自动转义已有的占位符）。
+
+// skillTemplateFile 模板目录中的主文件
+const skillTemplateFile = "TEMPLATE.md"
+
+// skillTemplateVarRe 模板占位符，以反斜杠开头的为转义的字面量
+var skillTemplateVarRe = regexp.MustCompile(`\\?\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// SkillTemplateVariable 模板变量
+type SkillTemplateVariable struct {
+	Name        string `json:"name" yaml:"name"`
+	Label       string `json:"label" yaml:"label,omitempty"`
+	Description string `json:"description" yaml:"description,omitempty"`
+	Default     string `json:"default" yaml:"default,omitempty"`
+	Required    bool   `json:"required" yaml:"required,omitempty"`
+}
+
+// skillTemplateFrontmatter TEMPLATE.md 的 frontmatter
+type skillTemplateFrontmatter struct {
+	Name        string                  `yaml:"name"`
+	Description string                  `yaml:"description"`
+	Category    string                  `yaml:"category,omitempty"`
+	Variables   []SkillTemplateVariable `yaml:"variables,omitempty"`
+}
+
+// GetSkillTemplateDirectories 获取模板目录，项目目录优先
+func (sm *SkillsManager) GetSkillTemplateDirectories() map[string]string {
+	dirs := make(map[string]string)
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		dirs["global"] = filepath.Join(homeDir, ".config", "opencode", "skill-templates")
+	}
+	if sm.workDir != "" {
+		dirs["project"] = filepath.Join(sm.workDir, ".opencode", "skill-templates")
+	}
+	return dirs
+}
+
+// templateVariableNames 按出现顺序返回文本中的占位符
+func templateVariableNames(texts ...string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, m := range skillTemplateVarRe.FindAllStringSubmatch(text, -1) {
+			if strings.HasPrefix(m[0], `\`) {
+				continue
+			}
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// withImplicitVariables 未声明的占位符按必填变量处理，{{name}} 由新技能名称填充
+func withImplicitVariables(declared []SkillTemplateVariable, texts ...string) []SkillTemplateVariable {
+	vars := []SkillTemplateVariable{}
+	seen := map[string]bool{"name": true}
+	for _, v := range declared {
+		if v.Name == "" || seen[v.Name] {
+			continue
+		}
+		seen[v.Name] = true
+		if v.Label == "" {
+			v.Label = v.Name
+		}
+		vars = append(vars, v)
+	}
+	for _, name := range templateVariableNames(texts...) {
+		if !seen[name] {
+			seen[name] = true
+			vars = append(vars, SkillTemplateVariable{Name: name, Label: name, Required: true})
+		}
+	}
+	return vars
+}
+
+// loadSkillTemplate 读取模板目录
+func loadSkillTemplate(dir, source string) SkillTemplate {
+	id := filepath.Base(dir)
+	tpl := SkillTemplate{ID: id, Name: id, Source: source, Path: dir, Category: "custom", Variables: []SkillTemplateVariable{}, Files: []string{}}
+	files, err := readSkillDir(dir)
+	if err != nil {
+		tpl.Error = err.Error()
+		return tpl
+	}
+	data, ok := files[skillTemplateFile]
+	if !ok {
+		tpl.Error = skillTemplateFile + " not found"
+		return tpl
+	}
+	front, body, ok := splitFrontmatter(string(data))
+	if !ok {
+		tpl.Error = "missing frontmatter in " + skillTemplateFile
+		return tpl
+	}
+	var fm skillTemplateFrontmatter
+	if err := yaml.Unmarshal([]byte(front), &fm); err != nil {
+		tpl.Error = fmt.Sprintf("invalid frontmatter: %v", err)
+		return tpl
+	}
+	if fm.Name != "" {
+		tpl.Name = fm.Name
+	}
+	if fm.Category != "" {
+		tpl.Category = fm.Category
+	}
+	tpl.Description = fm.Description
+	tpl.Content = body
+
+	texts := []string{fm.Description, body}
+	for rel, content := range files {
+		if rel == skillTemplateFile {
+			continue
+		}
+		tpl.Files = append(tpl.Files, rel)
+		if !isBinarySkillFile(content) {
+			texts = append(texts, string(content))
+		}
+	}
+	sort.Strings(tpl.Files)
+	tpl.Variables = withImplicitVariables(fm.Variables, texts...)
+	return tpl
+}
+
+// GetSkillTemplates 获取技能模板列表，同 ID 时项目模板覆盖全局模板，全局模板覆盖内置模板
+func (sm *SkillsManager) GetSkillTemplates() []SkillTemplate {
+	templates := []SkillTemplate{}
+	index := make(map[string]int)
+	add := func(tpl SkillTemplate) {
+		if i, ok := index[tpl.ID]; ok {
+			templates[i] = tpl
+			return
+		}
+		index[tpl.ID] = len(templates)
+		templates = append(templates, tpl)
+	}
+
+	for _, tpl := range builtinSkillTemplates() {
+		tpl.Source = "builtin"
+		tpl.Variables = withImplicitVariables(nil, tpl.Description, tpl.Content)
+		tpl.Files = []string{}
+		add(tpl)
+	}
+	dirs := sm.GetSkillTemplateDirectories()
+	for _, source := range []string{"global", "project"} {
+		dir, ok := dirs[source]
+		if !ok {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && isValidSkillName(entry.Name()) {
+				add(loadSkillTemplate(filepath.Join(dir, entry.Name()), source))
+			}
+		}
+	}
+	return templates
+}
+
+// resolveTemplateValues 补全默认值并检查必填变量
+func resolveTemplateValues(vars []SkillTemplateVariable, values map[string]string, name string) (map[string]string, error) {
+	resolved := map[string]string{"name": name}
+	for _, v := range vars {
+		value := strings.TrimSpace(values[v.Name])
+		if value == "" {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			return nil, fmt.Errorf("missing value for template variable: %s", v.Name)
+		}
+		resolved[v.Name] = value
+	}
+	return resolved, nil
+}
+
+// renderSkillTemplate 替换占位符，转义的占位符去掉反斜杠后原样保留
+func renderSkillTemplate(text string, values map[string]string) string {
+	return skillTemplateVarRe.ReplaceAllStringFunc(text, func(m string) string {
+		if strings.HasPrefix(m, `\`) {
+			return m[1:]
+		}
+		return values[skillTemplateVarRe.FindStringSubmatch(m)[1]]
+	})
+}
+
+// escapeSkillTemplate 转义文本中已有的占位符，使其在创建技能时原样保留
+func escapeSkillTemplate(text string) string {
+	return skillTemplateVarRe.ReplaceAllStringFunc(text, func(m string) string {
+		return `\` + m
+	})
+}
+
+// CreateSkillFromTemplate 从模板创建技能，values 为模板变量的取值
+func (sm *SkillsManager) CreateSkillFromTemplate(templateID, customName string, global bool, values map[string]string) error {
+	var template *SkillTemplate
+	for _, t := range sm.GetSkillTemplates() {
+		if t.ID == templateID {
+			template = &t
+			break
+		}
+	}
+	if template == nil {
+		return fmt.Errorf("template not found: %s", templateID)
+	}
+	if template.Error != "" {
+		return fmt.Errorf("invalid template %s: %s", templateID, template.Error)
+	}
+
+	name := customName
+	if name == "" {
+		name = template.ID
+	}
+	resolved, err := resolveTemplateValues(template.Variables, values, name)
+	if err != nil {
+		return err
+	}
+	description := renderSkillTemplate(template.Description, resolved)
+	if err := sm.CreateSkill(name, description, renderSkillTemplate(template.Content, resolved), global); err != nil {
+		return err
+	}
+	if len(template.Files) == 0 {
+		return nil
+	}
+
+	// 复制附带文件，失败时删除已创建的技能
+	targetDir, err := sm.skillTargetDir(name, global)
+	if err != nil {
+		return err
+	}
+	files, err := readSkillDir(template.Path)
+	if err == nil {
+		for _, rel := range template.Files {
+			content := files[rel]
+			if !isBinarySkillFile(content) {
+				content = []byte(renderSkillTemplate(string(content), resolved))
+			}
+			dest := filepath.Join(targetDir, filepath.FromSlash(rel))
+			if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				break
+			}
+			if err = os.WriteFile(dest, content, skillFileMode(rel, content)); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		os.RemoveAll(targetDir)
+		return fmt.Errorf("failed to copy template files: %w", err)
+	}
+	return nil
+}
+
+// SaveSkillAsTemplate 将已有技能及其附带文件保存为模板
+func (sm *SkillsManager) SaveSkillAsTemplate(skillName, templateID, category string, global bool) error {
+	skill, err := sm.GetSkill(skillName)
+	if err != nil {
+		return err
+	}
+	if templateID == "" {
+		templateID = skill.Name
+	}
+	if !isValidSkillName(templateID) {
+		return fmt.Errorf("invalid template id: must be lowercase alphanumeric with hyphens, 1-64 chars")
+	}
+	source := "project"
+	if global {
+		source = "global"
+	}
+	dir, ok := sm.GetSkillTemplateDirectories()[source]
+	if !ok {
+		return fmt.Errorf("work directory not set")
+	}
+	targetDir := filepath.Join(dir, templateID)
+	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
+		return fmt.Errorf("template already exists: %s", templateID)
+	}
+
+	files, err := readSkillDir(skill.Path)
+	if err != nil {
+		return err
+	}
+	fm, body := parseFrontmatter(string(files["SKILL.md"]))
+	delete(files, "SKILL.md")
+	// 技能内容是成品，其中的 {{...}} 不是模板变量
+	for rel, content := range files {
+		if !isBinarySkillFile(content) {
+			files[rel] = []byte(escapeSkillTemplate(string(content)))
+		}
+	}
+	header, err := yaml.Marshal(skillTemplateFrontmatter{Name: skill.Name, Description: escapeSkillTemplate(fm.Description), Category: category})
+	if err != nil {
+		return fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	files[skillTemplateFile] = []byte(fmt.Sprintf("---\n%s---\n\n%s\n", header, escapeSkillTemplate(body)))
+
+	for rel, content := range files {
+		dest := filepath.Join(targetDir, filepath.FromSlash(rel))
+		if err = os.MkdirAll(filepath.Dir(dest), 0755); err == nil {
+			err = os.WriteFile(dest, content, skillFileMode(rel, content))
+		}
+		if err != nil {
+			os.RemoveAll(targetDir)
+			return fmt.Errorf("failed to write template: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteSkillTemplate 删除用户模板，内置模板不能删除
+func (sm *SkillsManager) DeleteSkillTemplate(templateID string) error {
+	for _, t := range sm.GetSkillTemplates() {
+		if t.ID != templateID {
+			continue
+		}
+		if t.Source == "builtin" {
+			return fmt.Errorf("builtin templates cannot be deleted: %s", templateID)
+		}
+		if err := os.RemoveAll(t.Path); err != nil {
+			return fmt.Errorf("failed to delete template: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("template not found: %s", templateID)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestTemplate 在模板目录下创建模板
+func writeTestTemplate(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestGetSkillTemplates tests loading templates from directories and override order
+func TestGetSkillTemplates(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	dirs := sm.GetSkillTemplateDirectories()
+	writeTestTemplate(t, filepath.Join(dirs["global"], "code-review"), map[string]string{
+		"TEMPLATE.md": "---\nname: Team review\ndescription: Review {{language}} code\n---\n\nbody",
+	})
+	writeTestTemplate(t, filepath.Join(dirs["global"], "migration"), map[string]string{
+		"TEMPLATE.md":    "---\nname: Migration\ndescription: Migrate {{name}}\ncategory: database\nvariables:\n  - name: db\n    label: Database\n    default: postgres\n---\n\nUse {{db}} and {{tool}}.",
+		"scripts/run.sh": "#!/bin/sh\n{{tool}} {{db}}\n",
+	})
+	writeTestTemplate(t, filepath.Join(dirs["project"], "migration"), map[string]string{
+		"TEMPLATE.md": "---\nname: Project migration\ndescription: d\nvariables:\n  - name: db\n---\n\nUse {{db}}.",
+	})
+	writeTestTemplate(t, filepath.Join(dirs["project"], "broken"), map[string]string{
+		"TEMPLATE.md": "no frontmatter",
+	})
+
+	byID := make(map[string]SkillTemplate)
+	for _, tpl := range sm.GetSkillTemplates() {
+		byID[tpl.ID] = tpl
+	}
+	if len(byID) != len(builtinSkillTemplates())+2 {
+		t.Errorf("templates = %d", len(byID))
+	}
+	review := byID["code-review"]
+	if review.Source != "global" || review.Name != "Team review" || len(review.Variables) != 1 || !review.Variables[0].Required {
+		t.Errorf("code-review = %+v", review)
+	}
+	migration := byID["migration"]
+	if migration.Source != "project" || migration.Name != "Project migration" || migration.Category != "custom" {
+		t.Errorf("migration = %+v", migration)
+	}
+	if byID["broken"].Error == "" || byID["bug-fix"].Source != "builtin" {
+		t.Errorf("broken = %+v bug-fix = %+v", byID["broken"], byID["bug-fix"])
+	}
+}
+
+// TestCreateSkillFromTemplate tests variable substitution, defaults and bundled files
+func TestCreateSkillFromTemplate(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	dir := filepath.Join(sm.GetSkillTemplateDirectories()["global"], "migration")
+	writeTestTemplate(t, dir, map[string]string{
+		"TEMPLATE.md":    "---\nname: Migration\ndescription: Migrate {{name}} with {{ tool }}\nvariables:\n  - name: db\n    default: postgres\n  - name: owner\n---\n\nUse {{db}} and {{tool}}.{{owner}}",
+		"scripts/run.sh": "#!/bin/sh\n{{tool}} {{db}}\n",
+	})
+
+	if err := sm.CreateSkillFromTemplate("migration", "db-migrate", false, nil); err == nil || !strings.Contains(err.Error(), "tool") {
+		t.Fatalf("missing required value err = %v", err)
+	}
+	if _, err := sm.GetSkill("db-migrate"); err == nil {
+		t.Fatal("failed creation should not leave a skill")
+	}
+
+	if err := sm.CreateSkillFromTemplate("migration", "db-migrate", false, map[string]string{"tool": "goose"}); err != nil {
+		t.Fatal(err)
+	}
+	skill, err := sm.GetSkill("db-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skill.Description != "Migrate db-migrate with goose" || skill.Content != "Use postgres and goose." {
+		t.Errorf("skill = %+v", skill)
+	}
+	script, _ := os.ReadFile(filepath.Join(skill.Path, "scripts", "run.sh"))
+	if string(script) != "#!/bin/sh\ngoose postgres\n" || !reflect.DeepEqual(skill.Files, []string{"scripts/run.sh"}) {
+		t.Errorf("script = %q files = %v", script, skill.Files)
+	}
+
+	if err := sm.CreateSkillFromTemplate("missing", "", false, nil); err == nil {
+		t.Error("unknown template should fail")
+	}
+	if err := sm.CreateSkillFromTemplate("bug-fix", "", true, nil); err != nil {
+		t.Errorf("builtin template: %v", err)
+	}
+}
+
+// TestSaveSkillAsTemplate tests round-tripping a skill through a user template
+func TestSaveSkillAsTemplate(t *testing.T) {
+	sm := newTestSkillsManager(t)
+	if err := sm.CreateSkill("release", "Release {{product}}", "Ship {{product}}", false); err != nil {
+		t.Fatal(err)
+	}
+	sm.WriteSkillFile("release", "reference/checklist.md", "- {{product}}")
+
+	if err := sm.SaveSkillAsTemplate("release", "", "ops", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SaveSkillAsTemplate("release", "", "ops", false); err == nil {
+		t.Error("duplicate template should fail")
+	}
+	var tpl SkillTemplate
+	for _, t := range sm.GetSkillTemplates() {
+		if t.ID == "release" {
+			tpl = t
+		}
+	}
+	// Placeholders already in the skill are literal text, not template variables
+	if tpl.Source != "project" || tpl.Category != "ops" || len(tpl.Variables) != 0 || !reflect.DeepEqual(tpl.Files, []string{"reference/checklist.md"}) {
+		t.Fatalf("template = %+v", tpl)
+	}
+
+	if err := sm.CreateSkillFromTemplate("release", "release-app", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := sm.ReadSkillFile("release-app", "reference/checklist.md"); text != "- {{product}}" {
+		t.Errorf("checklist = %q", text)
+	}
+	if skill, err := sm.GetSkill("release-app"); err != nil || !strings.Contains(skill.Content, "Ship {{product}}") || skill.Description != "Release {{product}}" {
+		t.Errorf("GetSkill() = %+v, %v", skill, err)
+	}
+
+	if err := sm.DeleteSkillTemplate("release"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.DeleteSkillTemplate("code-review"); err == nil {
+		t.Error("builtin template should not be deletable")
+	}
+}
+
+// TestRenderSkillTemplateEscapes tests that escaped placeholders are kept literally and survive another escape round
+func TestRenderSkillTemplateEscapes(t *testing.T) {
+	values := map[string]string{"name": "demo", "x": "1"}
+	if got := renderSkillTemplate(`{{name}} \{{x}} {{ x }}`, values); got != "demo {{x}} 1" {
+		t.Errorf("renderSkillTemplate() = %q", got)
+	}
+	if names := templateVariableNames(`\{{x}} {{y}}`); !reflect.DeepEqual(names, []string{"y"}) {
+		t.Errorf("templateVariableNames() = %v", names)
+	}
+	for _, text := range []string{"{{name}} and {{ x }}", `already \{{x}}`} {
+		if got := renderSkillTemplate(escapeSkillTemplate(text), values); got != text {
+			t.Errorf("render(escape(%q)) = %q", text, got)
+		}
+	}
+}
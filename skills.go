@@ -12,27 +12,39 @@ import (
 
 // SkillInfo 技能信息
 type SkillInfo struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Path        string `json:"path"`
-	Source      string `json:"source"` // "project" | "global"
-	Content     string `json:"content,omitempty"`
-	Enabled     bool   `json:"enabled"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Version     string       `json:"version,omitempty"`
+	Author      string       `json:"author,omitempty"`
+	Path        string       `json:"path"`
+	Source      string       `json:"source"` // "project" | "global"
+	Content     string       `json:"content,omitempty"`
+	Files       []string     `json:"files"` // SKILL.md 以外的附带文件
+	Enabled     bool         `json:"enabled"`
+	Activation  string       `json:"activation,omitempty"` // 决定启用状态的配置层，为空表示默认启用
+	Issues      []SkillIssue `json:"issues"`               // 检查发现的问题
 }
 
 // SkillFrontmatter SKILL.md 的 frontmatter
 type SkillFrontmatter struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
+	Version     string `yaml:"version,omitempty"`
+	Author      string `yaml:"author,omitempty"`
 }
 
 // SkillTemplate 技能模板
 type SkillTemplate struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Content     string `json:"content"`
-	Category    string `json:"category"`
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Content     string                  `json:"content"`
+	Category    string                  `json:"category"`
+	Source      string                  `json:"source"` // "builtin" | "global" | "project"
+	Path        string                  `json:"path,omitempty"`
+	Variables   []SkillTemplateVariable `json:"variables"`
+	Files       []string                `json:"files"`           // TEMPLATE.md 以外的附带文件
+	Error       string                  `json:"error,omitempty"` // 模板无法解析时的错误
 }
 
 // SkillsManager 技能管理器
@@ -56,6 +68,14 @@ func (sm *SkillsManager) SetWorkDir(dir string) {
 func (sm *SkillsManager) GetSkillsDirectories() []string {
 	dirs := []string{}
 
+	// 项目目录，优先级高于全局目录
+	if sm.workDir != "" {
+		// OpenCode 项目技能目录
+		dirs = append(dirs, filepath.Join(sm.workDir, ".opencode", "skills"))
+		// Claude 兼容目录
+		dirs = append(dirs, filepath.Join(sm.workDir, ".claude", "skills"))
+	}
+
 	// 全局目录
 	homeDir, _ := os.UserHomeDir()
 	if homeDir != "" {
@@ -65,21 +85,13 @@ func (sm *SkillsManager) GetSkillsDirectories() []string {
 		dirs = append(dirs, filepath.Join(homeDir, ".claude", "skills"))
 	}
 
-	// 项目目录
-	if sm.workDir != "" {
-		// OpenCode 项目技能目录
-		dirs = append(dirs, filepath.Join(sm.workDir, ".opencode", "skills"))
-		// Claude 兼容目录
-		dirs = append(dirs, filepath.Join(sm.workDir, ".claude", "skills"))
-	}
-
 	return dirs
 }
 
 // ListSkills 列出所有技能
 func (sm *SkillsManager) ListSkills() ([]SkillInfo, error) {
 	skills := []SkillInfo{}
-	seen := make(map[string]bool)
+	seen := make(map[string]int) // 目录名 -> skills 下标
 
 	dirs := sm.GetSkillsDirectories()
 
@@ -114,36 +126,55 @@ func (sm *SkillsManager) ListSkills() ([]SkillInfo, error) {
 				continue
 			}
 
-			// 避免重复（项目级覆盖全局级）
-			if seen[skillName] {
+			// 避免重复（项目级覆盖全局级），被覆盖的副本记录到生效的技能上
+			if i, ok := seen[skillName]; ok {
+				skills[i].Issues = append(skills[i].Issues, SkillIssue{
+					Severity: "warning",
+					Message:  fmt.Sprintf("%s 中的同名技能被此技能覆盖", skillPath),
+				})
 				continue
 			}
-			seen[skillName] = true
+			seen[skillName] = len(skills)
 
-			// 读取技能内容
-			content, err := os.ReadFile(skillFile)
+			// 读取技能内容及附带文件
+			files, err := readSkillDir(skillPath)
 			if err != nil {
 				continue
 			}
 
-			// 解析 frontmatter
-			frontmatter, body := parseFrontmatter(string(content))
+			// 解析 frontmatter，没有 name 时使用目录名
+			skills = append(skills, skillInfoFromDir(skillPath, skillName, source, files))
+		}
+	}
 
-			skill := SkillInfo{
-				Name:        frontmatter.Name,
-				Description: frontmatter.Description,
-				Path:        skillPath,
-				Source:      source,
-				Content:     body,
-				Enabled:     true, // 默认启用
-			}
+	// 按配置层中的 permission.skill 规则确定启用状态
+	rules := sm.skillRules()
+	for i := range skills {
+		if rule, ok := resolveSkillRule(rules, skills[i].Name); ok {
+			skills[i].Enabled = rule.Action != skillPermissionDeny
+			skills[i].Activation = rule.Layer
+		}
+	}
 
-			// 如果 frontmatter 中没有 name，使用目录名
-			if skill.Name == "" {
-				skill.Name = skillName
+	// 不同目录的技能声明了相同的 name
+	byName := make(map[string][]int)
+	for i, skill := range skills {
+		byName[skill.Name] = append(byName[skill.Name], i)
+	}
+	for _, idx := range byName {
+		if len(idx) < 2 {
+			continue
+		}
+		for _, i := range idx {
+			for _, j := range idx {
+				if i != j {
+					skills[i].Issues = append(skills[i].Issues, SkillIssue{
+						Severity: "error",
+						Field:    "name",
+						Message:  fmt.Sprintf("name 与 %s 重复", skills[j].Path),
+					})
+				}
 			}
-
-			skills = append(skills, skill)
 		}
 	}
 
@@ -179,18 +210,9 @@ func (sm *SkillsManager) CreateSkill(name, description, content string, global b
 	}
 
 	// 确定目标目录
-	var targetDir string
-	if global {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("cannot get home directory: %w", err)
-		}
-		targetDir = filepath.Join(homeDir, ".config", "opencode", "skills", name)
-	} else {
-		if sm.workDir == "" {
-			return fmt.Errorf("work directory not set")
-		}
-		targetDir = filepath.Join(sm.workDir, ".opencode", "skills", name)
+	targetDir, err := sm.skillTargetDir(name, global)
+	if err != nil {
+		return err
 	}
 
 	// 检查是否已存在
@@ -204,13 +226,10 @@ func (sm *SkillsManager) CreateSkill(name, description, content string, global b
 	}
 
 	// 生成 SKILL.md 内容
-	skillContent := fmt.Sprintf(`---
-name: %s
-description: %s
----
-
-%s
-`, name, description, content)
+	skillContent, err := renderSkillFile(SkillFrontmatter{Name: name, Description: description, Version: "0.1.0"}, content)
+	if err != nil {
+		return err
+	}
 
 	// 写入文件
 	skillFile := filepath.Join(targetDir, "SKILL.md")
@@ -235,14 +254,11 @@ func (sm *SkillsManager) UpdateSkill(name, description, content string) error {
 		return fmt.Errorf("description must be 1-1024 characters")
 	}
 
-	// 生成新的 SKILL.md 内容
-	skillContent := fmt.Sprintf(`---
-name: %s
-description: %s
----
-
-%s
-`, name, description, content)
+	// 生成新的 SKILL.md 内容，保留版本和作者
+	skillContent, err := renderSkillFile(SkillFrontmatter{Name: name, Description: description, Version: skill.Version, Author: skill.Author}, content)
+	if err != nil {
+		return err
+	}
 
 	// 写入文件
 	skillFile := filepath.Join(skill.Path, "SKILL.md")
@@ -268,8 +284,8 @@ func (sm *SkillsManager) DeleteSkill(name string) error {
 	return nil
 }
 
-// GetSkillTemplates 获取技能模板列表
-func (sm *SkillsManager) GetSkillTemplates() []SkillTemplate {
+// builtinSkillTemplates 内置技能模板
+func builtinSkillTemplates() []SkillTemplate {
 	return []SkillTemplate{
 		{
 			ID:          "code-review",
@@ -459,50 +475,18 @@ func (sm *SkillsManager) GetSkillTemplates() []SkillTemplate {
 	}
 }
 
-// CreateSkillFromTemplate 从模板创建技能
-func (sm *SkillsManager) CreateSkillFromTemplate(templateID, customName string, global bool) error {
-	templates := sm.GetSkillTemplates()
-
-	var template *SkillTemplate
-	for _, t := range templates {
-		if t.ID == templateID {
-			template = &t
-			break
-		}
-	}
-
-	if template == nil {
-		return fmt.Errorf("template not found: %s", templateID)
-	}
-
-	name := customName
-	if name == "" {
-		name = template.ID
-	}
-
-	return sm.CreateSkill(name, template.Description, template.Content, global)
-}
-
 // parseFrontmatter 解析 SKILL.md 的 frontmatter
 func parseFrontmatter(content string) (SkillFrontmatter, string) {
 	var fm SkillFrontmatter
 
-	// 检查是否以 --- 开头
-	if !strings.HasPrefix(content, "---") {
-		return fm, content
-	}
-
-	// 查找结束的 ---
-	parts := strings.SplitN(content[3:], "---", 2)
-	if len(parts) != 2 {
+	// 拆分 frontmatter 和正文，格式问题由 lintSkill 报告
+	front, body, ok := splitFrontmatter(content)
+	if !ok {
 		return fm, content
 	}
 
 	// 解析 YAML
-	yaml.Unmarshal([]byte(parts[0]), &fm)
-
-	// 返回 body 部分
-	body := strings.TrimSpace(parts[1])
+	yaml.Unmarshal([]byte(front), &fm)
 	return fm, body
 }
 
@@ -512,7 +496,7 @@ func isValidSkillName(name string) bool {
 		return false
 	}
 
-	// 必须是小写字母、数字、连字符
-	matched, _ := regexp.MatchString(`^[a-z0-9][a-z0-9-]*[a-z0-9]$|^[a-z0-9]$`, name)
+	// 必须是小写字母、数字，以单个连字符分隔
+	matched, _ := regexp.MatchString(`^[a-z0-9]+(-[a-z0-9]+)*$`, name)
 	return matched
 }
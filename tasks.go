@@ -0,0 +1,850 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Task 可执行任务（自动检测或用户定义）
+type Task struct {
+	ID             string            `json:"id"`
+	Label          string            `json:"label"`
+	Source         string            `json:"source"` // npm, make, go, cargo, maven, gradle, file, user
+	Command        string            `json:"command"`
+	Args           []string          `json:"args,omitempty"`
+	Cwd            string            `json:"cwd,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	ProblemMatcher string            `json:"problemMatcher,omitempty"` // go, tsc, gcc, rustc, javac, maven, python
+	CommandLine    string            `json:"commandLine"`              // 已正确转义的完整命令行，用于展示或粘贴到终端
+}
+
+// TaskDiagnostic 从任务输出中解析出的诊断信息
+type TaskDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"` // error, warning, info
+	Message  string `json:"message"`
+	Code     string `json:"code,omitempty"`
+}
+
+// TaskRun 一次任务执行记录
+type TaskRun struct {
+	ID          string           `json:"id"`
+	Task        Task             `json:"task"`
+	Status      string           `json:"status"` // running, success, failed, cancelled
+	ExitCode    int              `json:"exitCode"`
+	Output      string           `json:"output"`
+	Diagnostics []TaskDiagnostic `json:"diagnostics"`
+	Error       string           `json:"error,omitempty"`
+	StartedAt   time.Time        `json:"startedAt"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+
+	output []byte // 累积的输出，快照时写入 Output
+	cancel context.CancelFunc
+}
+
+// userTasksFile 工作区自定义任务文件 (.opencode/tasks.json)
+type userTasksFile struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// maxTaskOutput 单次任务保留的最大输出字节数
+const maxTaskOutput = 2 * 1024 * 1024
+
+// maxTaskRuns 保留的历史执行记录数
+const maxTaskRuns = 50
+
+// TaskManager 任务管理器
+type TaskManager struct {
+	app    *App
+	runs   map[string]*TaskRun
+	order  []string
+	nextID int
+	mu     sync.Mutex
+}
+
+// NewTaskManager 创建任务管理器
+func NewTaskManager(app *App) *TaskManager {
+	return &TaskManager{
+		app:  app,
+		runs: make(map[string]*TaskRun),
+	}
+}
+
+// DetectTasks 检测工作区中的所有任务
+func (tm *TaskManager) DetectTasks(dir string) ([]Task, error) {
+	if dir == "" {
+		return []Task{}, nil
+	}
+
+	tasks := []Task{}
+	tasks = append(tasks, detectNpmTasks(dir)...)
+	tasks = append(tasks, detectMakeTasks(dir)...)
+	tasks = append(tasks, detectGoTasks(dir)...)
+	tasks = append(tasks, detectCargoTasks(dir)...)
+	tasks = append(tasks, detectMavenTasks(dir)...)
+	tasks = append(tasks, detectGradleTasks(dir)...)
+
+	userTasks, err := loadUserTasks(dir)
+	if err != nil {
+		return tasks, err
+	}
+	tasks = append(tasks, userTasks...)
+
+	for i := range tasks {
+		finalizeTask(&tasks[i], dir)
+	}
+	return tasks, nil
+}
+
+// FindTask 按 ID 查找任务
+func (tm *TaskManager) FindTask(dir, id string) (*Task, error) {
+	tasks, err := tm.DetectTasks(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if t.ID == id {
+			task := t
+			return &task, nil
+		}
+	}
+	return nil, fmt.Errorf("任务不存在: %s", id)
+}
+
+// Run 在后台执行任务，立即返回执行记录 ID
+func (tm *TaskManager) Run(task Task) (string, error) {
+	if task.Command == "" {
+		return "", fmt.Errorf("任务命令为空")
+	}
+
+	tm.mu.Lock()
+	tm.nextID++
+	runID := fmt.Sprintf("run-%d", tm.nextID)
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &TaskRun{
+		ID:          runID,
+		Task:        task,
+		Status:      "running",
+		Diagnostics: []TaskDiagnostic{},
+		StartedAt:   time.Now(),
+		cancel:      cancel,
+	}
+	tm.runs[runID] = run
+	tm.order = append(tm.order, runID)
+	tm.pruneLocked()
+	tm.mu.Unlock()
+
+	cmd := exec.CommandContext(ctx, task.Command, task.Args...)
+	cmd.Dir = task.Cwd
+	cmd.WaitDelay = 2 * time.Second
+	setupTaskProcess(cmd)
+	cmd.Env = os.Environ()
+	for k, v := range task.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		pw.Close()
+		tm.finish(run, -1, "failed", fmt.Sprintf("启动任务失败: %v", err))
+		return runID, nil
+	}
+
+	tm.app.emitEvent("task-started", run.snapshot())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			tm.mu.Lock()
+			if len(run.output) < maxTaskOutput {
+				run.output = append(append(run.output, line...), '\n')
+			}
+			tm.mu.Unlock()
+			tm.app.emitEvent("task-output", map[string]interface{}{"runId": runID, "line": line})
+		}
+		io.Copy(io.Discard, pr)
+	}()
+
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		<-done
+
+		exitCode := 0
+		status := "success"
+		errMsg := ""
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			} else {
+				exitCode = -1
+				errMsg = err.Error()
+			}
+			status = "failed"
+		}
+		if ctx.Err() != nil {
+			status = "cancelled"
+		}
+		tm.finish(run, exitCode, status, errMsg)
+	}()
+
+	return runID, nil
+}
+
+// finish 记录任务结束状态并解析诊断
+func (tm *TaskManager) finish(run *TaskRun, exitCode int, status, errMsg string) {
+	tm.mu.Lock()
+	now := time.Now()
+	run.ExitCode = exitCode
+	run.Status = status
+	run.Error = errMsg
+	run.FinishedAt = &now
+	run.Diagnostics = ParseProblems(run.Task.ProblemMatcher, string(run.output), run.Task.Cwd)
+	if run.cancel != nil {
+		run.cancel()
+	}
+	snapshot := run.snapshot()
+	tm.mu.Unlock()
+
+	tm.app.emitEvent("task-finished", snapshot)
+}
+
+// Stop 取消正在运行的任务
+func (tm *TaskManager) Stop(runID string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	run, ok := tm.runs[runID]
+	if !ok {
+		return fmt.Errorf("任务执行记录不存在: %s", runID)
+	}
+	if run.Status == "running" && run.cancel != nil {
+		run.cancel()
+	}
+	return nil
+}
+
+// StopAll 取消所有正在运行的任务
+func (tm *TaskManager) StopAll() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for _, run := range tm.runs {
+		if run.Status == "running" && run.cancel != nil {
+			run.cancel()
+		}
+	}
+}
+
+// GetRun 获取单次执行记录
+func (tm *TaskManager) GetRun(runID string) (*TaskRun, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	run, ok := tm.runs[runID]
+	if !ok {
+		return nil, fmt.Errorf("任务执行记录不存在: %s", runID)
+	}
+	snapshot := run.snapshot()
+	return &snapshot, nil
+}
+
+// ListRuns 按时间倒序列出执行记录（不含输出）
+func (tm *TaskManager) ListRuns() []TaskRun {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	runs := make([]TaskRun, 0, len(tm.order))
+	for i := len(tm.order) - 1; i >= 0; i-- {
+		runs = append(runs, tm.runs[tm.order[i]].summary())
+	}
+	return runs
+}
+
+// pruneLocked 删除超出上限的已结束记录（调用方需持有锁）
+func (tm *TaskManager) pruneLocked() {
+	for len(tm.order) > maxTaskRuns {
+		oldest := tm.order[0]
+		if tm.runs[oldest].Status == "running" {
+			break
+		}
+		delete(tm.runs, oldest)
+		tm.order = tm.order[1:]
+	}
+}
+
+// snapshot 复制执行记录（不含取消函数）
+func (r *TaskRun) snapshot() TaskRun {
+	s := r.summary()
+	s.Output = string(r.output)
+	return s
+}
+
+// summary 复制执行记录，不含输出
+func (r *TaskRun) summary() TaskRun {
+	s := *r
+	s.output = nil
+	s.cancel = nil
+	s.Diagnostics = append([]TaskDiagnostic{}, r.Diagnostics...)
+	return s
+}
+
+// --- 任务检测 ---
+
+// finalizeTask 补全任务的工作目录、ID 和命令行
+func finalizeTask(t *Task, dir string) {
+	if t.Cwd == "" {
+		t.Cwd = dir
+	} else if !filepath.IsAbs(t.Cwd) {
+		t.Cwd = filepath.Join(dir, t.Cwd)
+	}
+	if t.ID == "" {
+		t.ID = t.Source + ":" + t.Label
+	}
+	t.CommandLine = quoteCommandLine(append([]string{t.Command}, t.Args...))
+}
+
+// detectNpmTasks 从 package.json 的 scripts 检测任务
+func detectNpmTasks(dir string) []Task {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+
+	runner := "npm"
+	if fileExists(filepath.Join(dir, "pnpm-lock.yaml")) {
+		runner = "pnpm"
+	} else if fileExists(filepath.Join(dir, "yarn.lock")) {
+		runner = "yarn"
+	} else if fileExists(filepath.Join(dir, "bun.lockb")) {
+		runner = "bun"
+	}
+
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tasks := make([]Task, 0, len(names))
+	for _, name := range names {
+		tasks = append(tasks, Task{
+			Label:          name,
+			Source:         "npm",
+			Command:        runner,
+			Args:           []string{"run", name},
+			ProblemMatcher: guessScriptMatcher(pkg.Scripts[name]),
+		})
+	}
+	return tasks
+}
+
+// guessScriptMatcher 根据脚本内容推测问题匹配器，无法识别时不解析诊断
+func guessScriptMatcher(script string) string {
+	switch {
+	case strings.Contains(script, "tsc"), strings.Contains(script, "vue-tsc"):
+		return "tsc"
+	case strings.Contains(script, "go "):
+		return "go"
+	default:
+		return ""
+	}
+}
+
+// makeTargetRegex 匹配 Makefile 目标行
+var makeTargetRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.\-/]*)\s*:([^=]|$)`)
+
+// detectMakeTasks 从 Makefile 检测目标
+func detectMakeTasks(dir string) []Task {
+	var content []byte
+	for _, name := range []string{"GNUmakefile", "makefile", "Makefile"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			content = data
+			break
+		}
+	}
+	if content == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var tasks []Task
+	for _, line := range strings.Split(string(content), "\n") {
+		m := makeTargetRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		target := m[1]
+		if seen[target] || strings.Contains(target, "%") {
+			continue
+		}
+		seen[target] = true
+		tasks = append(tasks, Task{
+			Label:          target,
+			Source:         "make",
+			Command:        "make",
+			Args:           []string{target},
+			ProblemMatcher: "gcc",
+		})
+	}
+	return tasks
+}
+
+// detectGoTasks 根据 go.mod 生成 Go 任务
+func detectGoTasks(dir string) []Task {
+	if !fileExists(filepath.Join(dir, "go.mod")) {
+		return nil
+	}
+	return []Task{
+		{Label: "build", Source: "go", Command: "go", Args: []string{"build", "./..."}, ProblemMatcher: "go"},
+		{Label: "test", Source: "go", Command: "go", Args: []string{"test", "./..."}, ProblemMatcher: "go"},
+		{Label: "vet", Source: "go", Command: "go", Args: []string{"vet", "./..."}, ProblemMatcher: "go"},
+		{Label: "run", Source: "go", Command: "go", Args: []string{"run", "."}, ProblemMatcher: "go"},
+	}
+}
+
+// detectCargoTasks 根据 Cargo.toml 生成 Rust 任务
+func detectCargoTasks(dir string) []Task {
+	if !fileExists(filepath.Join(dir, "Cargo.toml")) {
+		return nil
+	}
+	return []Task{
+		{Label: "build", Source: "cargo", Command: "cargo", Args: []string{"build"}, ProblemMatcher: "rustc"},
+		{Label: "test", Source: "cargo", Command: "cargo", Args: []string{"test"}, ProblemMatcher: "rustc"},
+		{Label: "run", Source: "cargo", Command: "cargo", Args: []string{"run"}, ProblemMatcher: "rustc"},
+		{Label: "check", Source: "cargo", Command: "cargo", Args: []string{"check"}, ProblemMatcher: "rustc"},
+	}
+}
+
+// detectMavenTasks 根据 pom.xml 生成 Maven 任务
+func detectMavenTasks(dir string) []Task {
+	if !fileExists(filepath.Join(dir, "pom.xml")) {
+		return nil
+	}
+	mvn := "mvn"
+	if wrapper := findWrapper(dir, "mvnw"); wrapper != "" {
+		mvn = wrapper
+	}
+	return []Task{
+		{Label: "compile", Source: "maven", Command: mvn, Args: []string{"compile"}, ProblemMatcher: "maven"},
+		{Label: "test", Source: "maven", Command: mvn, Args: []string{"test"}, ProblemMatcher: "maven"},
+		{Label: "package", Source: "maven", Command: mvn, Args: []string{"package"}, ProblemMatcher: "maven"},
+	}
+}
+
+// detectGradleTasks 根据 build.gradle 生成 Gradle 任务
+func detectGradleTasks(dir string) []Task {
+	if !fileExists(filepath.Join(dir, "build.gradle")) && !fileExists(filepath.Join(dir, "build.gradle.kts")) {
+		return nil
+	}
+	gradle := "gradle"
+	if wrapper := findWrapper(dir, "gradlew"); wrapper != "" {
+		gradle = wrapper
+	}
+	return []Task{
+		{Label: "build", Source: "gradle", Command: gradle, Args: []string{"build"}, ProblemMatcher: "javac"},
+		{Label: "test", Source: "gradle", Command: gradle, Args: []string{"test"}, ProblemMatcher: "javac"},
+		{Label: "run", Source: "gradle", Command: gradle, Args: []string{"run"}, ProblemMatcher: "javac"},
+	}
+}
+
+// findWrapper 查找项目自带的构建脚本（mvnw / gradlew）
+func findWrapper(dir, name string) string {
+	if goruntime.GOOS == "windows" {
+		name += ".cmd"
+		if name == "gradlew.cmd" {
+			name = "gradlew.bat"
+		}
+	}
+	path := filepath.Join(dir, name)
+	if fileExists(path) {
+		return path
+	}
+	return ""
+}
+
+// loadUserTasks 读取 .opencode/tasks.json 中的自定义任务
+func loadUserTasks(dir string) ([]Task, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".opencode", "tasks.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取任务配置失败: %v", err)
+	}
+
+	var file userTasksFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析任务配置失败: %v", err)
+	}
+
+	tasks := make([]Task, 0, len(file.Tasks))
+	for _, t := range file.Tasks {
+		if t.Label == "" || t.Command == "" {
+			continue
+		}
+		t.Source = "user"
+		t.ID = ""
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// FileTask 根据文件类型生成运行任务
+func FileTask(filePath string) (*Task, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	dir := filepath.Dir(filePath)
+	fileName := filepath.Base(filePath)
+
+	task := &Task{
+		ID:     "file:" + filePath,
+		Label:  fileName,
+		Source: "file",
+		Cwd:    dir,
+	}
+
+	switch ext {
+	case ".py":
+		task.Command, task.Args, task.ProblemMatcher = pythonCommand(), []string{filePath}, "python"
+	case ".go":
+		task.Command, task.Args, task.ProblemMatcher = "go", []string{"run", filePath}, "go"
+	case ".js", ".mjs", ".cjs":
+		task.Command, task.Args = "node", []string{filePath}
+	case ".ts":
+		task.Command, task.Args, task.ProblemMatcher = "npx", []string{"ts-node", filePath}, "tsc"
+	case ".java":
+		if pom := findUpwards(dir, "pom.xml"); pom != "" {
+			task.Command = "mvn"
+			task.Args = []string{"-f", pom, "compile", "exec:java", "-Dexec.mainClass=" + javaMainClass(filePath)}
+			task.ProblemMatcher = "maven"
+		} else if gradleDir := findGradleDir(dir); gradleDir != "" {
+			task.Command, task.Args, task.ProblemMatcher = "gradle", []string{"-p", gradleDir, "run"}, "javac"
+		} else {
+			// JDK 11+ 支持直接运行单个源文件
+			task.Command, task.Args, task.ProblemMatcher = "java", []string{fileName}, "javac"
+		}
+	case ".rs":
+		task.Command = "cargo"
+		task.Args = []string{"run"}
+		if manifest := findUpwards(dir, "Cargo.toml"); manifest != "" {
+			task.Args = append(task.Args, "--manifest-path", manifest)
+		}
+		task.ProblemMatcher = "rustc"
+	case ".rb":
+		task.Command, task.Args = "ruby", []string{filePath}
+	case ".php":
+		task.Command, task.Args = "php", []string{filePath}
+	case ".sh":
+		task.Command, task.Args = "bash", []string{filePath}
+	default:
+		return nil, fmt.Errorf("不支持运行 %s 文件", ext)
+	}
+
+	task.CommandLine = quoteCommandLine(append([]string{task.Command}, task.Args...))
+	return task, nil
+}
+
+// pythonCommand 返回当前平台的 Python 解释器名
+func pythonCommand() string {
+	if goruntime.GOOS == "windows" {
+		return "python"
+	}
+	return "python3"
+}
+
+// findUpwards 向上查找指定文件，返回完整路径
+func findUpwards(dir, name string) string {
+	for {
+		path := filepath.Join(dir, name)
+		if fileExists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findGradleDir 向上查找 Gradle 项目目录
+func findGradleDir(dir string) string {
+	if p := findUpwards(dir, "build.gradle"); p != "" {
+		return filepath.Dir(p)
+	}
+	if p := findUpwards(dir, "build.gradle.kts"); p != "" {
+		return filepath.Dir(p)
+	}
+	return ""
+}
+
+// javaMainClass 获取 Java 主类名 (package.ClassName)
+func javaMainClass(filePath string) string {
+	className := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return className
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "package ") && strings.HasSuffix(line, ";") {
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "package "), ";")) + "." + className
+		}
+	}
+	return className
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// --- 参数转义 ---
+
+// quoteCommandLine 按当前平台 shell 规则拼接命令行
+func quoteCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if goruntime.GOOS == "windows" {
+			quoted[i] = quoteWindowsArg(arg)
+		} else {
+			quoted[i] = quotePosixArg(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// safeArgRegex 无需转义的参数字符
+var safeArgRegex = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./\-]+$`)
+
+// quotePosixArg 使用单引号转义 POSIX shell 参数
+func quotePosixArg(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if safeArgRegex.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// quoteWindowsArg 按 CommandLineToArgvW 规则转义参数
+func quoteWindowsArg(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(arg, " \t\"&|<>^") {
+		return arg
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	backslashes := 0
+	for _, c := range arg {
+		switch c {
+		case '\\':
+			backslashes++
+		case '"':
+			sb.WriteString(strings.Repeat(`\`, backslashes*2+1))
+			sb.WriteRune(c)
+			backslashes = 0
+		default:
+			sb.WriteString(strings.Repeat(`\`, backslashes))
+			sb.WriteRune(c)
+			backslashes = 0
+		}
+	}
+	sb.WriteString(strings.Repeat(`\`, backslashes*2))
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// --- 问题匹配 ---
+
+var (
+	// main.go:12:5: undefined: foo / src/a.c:3:1: error: xxx
+	gccProblemRegex = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)?\s*(?:(fatal error|error|warning|note|info):\s*)?(.+)$`)
+	// src/a.ts(12,5): error TS2304: Cannot find name 'x'.
+	tscProblemRegex = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\):\s*(error|warning)\s+(TS\d+):\s*(.+)$`)
+	// error[E0308]: mismatched types
+	rustHeaderRegex = regexp.MustCompile(`^(error|warning)(?:\[(E\d+)\])?:\s*(.+)$`)
+	//   --> src/main.rs:4:5
+	rustLocationRegex = regexp.MustCompile(`^\s*-->\s*(.+?):(\d+):(\d+)$`)
+	// [ERROR] /path/App.java:[12,5] cannot find symbol
+	mavenProblemRegex = regexp.MustCompile(`^\[(ERROR|WARNING)\]\s+(.+?):\[(\d+),(\d+)\]\s*(.+)$`)
+	//   File "app.py", line 12, in <module>
+	pythonLocationRegex = regexp.MustCompile(`^\s*File "(.+?)", line (\d+)`)
+	// NameError: name 'x' is not defined
+	pythonErrorRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.]*(?:Error|Exception|Warning)):\s*(.*)$`)
+)
+
+// ParseProblems 使用指定的问题匹配器从输出中解析诊断
+func ParseProblems(matcher, output, cwd string) []TaskDiagnostic {
+	diagnostics := []TaskDiagnostic{}
+	if matcher == "" {
+		return diagnostics
+	}
+
+	lines := strings.Split(output, "\n")
+	switch matcher {
+	case "rustc":
+		diagnostics = parseRustProblems(lines)
+	case "python":
+		diagnostics = parsePythonProblems(lines)
+	default:
+		for _, line := range lines {
+			line = strings.TrimRight(line, "\r")
+			if d, ok := parseProblemLine(matcher, line); ok {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+
+	for i := range diagnostics {
+		if cwd != "" && !filepath.IsAbs(diagnostics[i].File) {
+			diagnostics[i].File = filepath.Join(cwd, diagnostics[i].File)
+		}
+	}
+	return diagnostics
+}
+
+// parseProblemLine 解析单行格式的诊断（go / gcc / javac / tsc / maven）
+func parseProblemLine(matcher, line string) (TaskDiagnostic, bool) {
+	switch matcher {
+	case "tsc":
+		if m := tscProblemRegex.FindStringSubmatch(line); m != nil {
+			return TaskDiagnostic{
+				File:     m[1],
+				Line:     atoiOrZero(m[2]),
+				Column:   atoiOrZero(m[3]),
+				Severity: m[4],
+				Code:     m[5],
+				Message:  m[6],
+			}, true
+		}
+	case "maven":
+		if m := mavenProblemRegex.FindStringSubmatch(line); m != nil {
+			return TaskDiagnostic{
+				File:     m[2],
+				Line:     atoiOrZero(m[3]),
+				Column:   atoiOrZero(m[4]),
+				Severity: strings.ToLower(m[1]),
+				Message:  m[5],
+			}, true
+		}
+	}
+
+	if m := gccProblemRegex.FindStringSubmatch(line); m != nil {
+		file := m[1]
+		// 跳过 URL、时间戳等误匹配
+		if strings.Contains(file, "://") || strings.ContainsAny(file, "[]") || strings.HasPrefix(file, " ") {
+			return TaskDiagnostic{}, false
+		}
+		if matcher == "go" && !strings.HasSuffix(file, ".go") {
+			return TaskDiagnostic{}, false
+		}
+		severity := m[4]
+		switch severity {
+		case "", "fatal error":
+			severity = "error"
+		case "note":
+			severity = "info"
+		}
+		return TaskDiagnostic{
+			File:     strings.TrimPrefix(file, "./"),
+			Line:     atoiOrZero(m[2]),
+			Column:   atoiOrZero(m[3]),
+			Severity: severity,
+			Message:  strings.TrimSpace(m[5]),
+		}, true
+	}
+	return TaskDiagnostic{}, false
+}
+
+// parseRustProblems 解析 rustc 的多行诊断
+func parseRustProblems(lines []string) []TaskDiagnostic {
+	diagnostics := []TaskDiagnostic{}
+	var pending *TaskDiagnostic
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if m := rustHeaderRegex.FindStringSubmatch(line); m != nil {
+			pending = &TaskDiagnostic{Severity: m[1], Code: m[2], Message: m[3]}
+			continue
+		}
+		if pending == nil {
+			continue
+		}
+		if m := rustLocationRegex.FindStringSubmatch(line); m != nil {
+			pending.File = m[1]
+			pending.Line = atoiOrZero(m[2])
+			pending.Column = atoiOrZero(m[3])
+			diagnostics = append(diagnostics, *pending)
+			pending = nil
+		}
+	}
+	return diagnostics
+}
+
+// parsePythonProblems 从 Python traceback 中提取最后一帧和异常信息
+func parsePythonProblems(lines []string) []TaskDiagnostic {
+	diagnostics := []TaskDiagnostic{}
+	var last *TaskDiagnostic
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if m := pythonLocationRegex.FindStringSubmatch(line); m != nil {
+			last = &TaskDiagnostic{File: m[1], Line: atoiOrZero(m[2]), Severity: "error"}
+			continue
+		}
+		if last == nil {
+			continue
+		}
+		if m := pythonErrorRegex.FindStringSubmatch(line); m != nil {
+			last.Code = m[1]
+			last.Message = m[2]
+			if strings.HasSuffix(m[1], "Warning") {
+				last.Severity = "warning"
+			}
+			diagnostics = append(diagnostics, *last)
+			last = nil
+		}
+	}
+	return diagnostics
+}
+
+// atoiOrZero 解析整数，失败返回 0
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
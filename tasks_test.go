@@ -0,0 +1,260 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// writeTestFile writes a file under dir, creating parent directories
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// TestDetectTasks tests task detection from project manifests
+func TestDetectTasks(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "package.json", `{"scripts": {"build": "vue-tsc && vite build", "dev": "vite"}}`)
+	writeTestFile(t, dir, "yarn.lock", "")
+	writeTestFile(t, dir, "Makefile", "VAR := 1\n.PHONY: all\nall: build\nbuild:\n\tgo build\n%.o: %.c\n")
+	writeTestFile(t, dir, "go.mod", "module example\n")
+	writeTestFile(t, dir, ".opencode/tasks.json", `{"tasks": [{"label": "lint", "command": "golangci-lint", "args": ["run"], "problemMatcher": "go"}]}`)
+
+	tm := NewTaskManager(&App{})
+	tasks, err := tm.DetectTasks(dir)
+	if err != nil {
+		t.Fatalf("DetectTasks() error = %v", err)
+	}
+
+	byID := make(map[string]Task)
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	expected := []string{"npm:build", "npm:dev", "make:all", "make:build", "go:build", "go:test", "user:lint"}
+	for _, id := range expected {
+		if _, ok := byID[id]; !ok {
+			t.Errorf("Expected task %s to be detected", id)
+		}
+	}
+	if _, ok := byID["make:VAR"]; ok {
+		t.Error("Variable assignment should not be detected as make target")
+	}
+
+	build := byID["npm:build"]
+	if build.Command != "yarn" {
+		t.Errorf("Expected yarn runner, got %s", build.Command)
+	}
+	if build.ProblemMatcher != "tsc" {
+		t.Errorf("Expected tsc matcher, got %s", build.ProblemMatcher)
+	}
+	if dev := byID["npm:dev"]; dev.ProblemMatcher != "" {
+		t.Errorf("Expected no matcher for unrecognized script, got %s", dev.ProblemMatcher)
+	}
+	if build.Cwd != dir {
+		t.Errorf("Expected cwd %s, got %s", dir, build.Cwd)
+	}
+	if build.CommandLine != "yarn run build" {
+		t.Errorf("Unexpected command line: %s", build.CommandLine)
+	}
+}
+
+// TestQuotePosixArg tests POSIX shell quoting
+func TestQuotePosixArg(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{"simple", "simple"},
+		{"/path/to/file.go", "/path/to/file.go"},
+		{"", "''"},
+		{"with space", "'with space'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+	}
+
+	for _, tt := range tests {
+		if got := quotePosixArg(tt.arg); got != tt.expected {
+			t.Errorf("quotePosixArg(%q) = %q, want %q", tt.arg, got, tt.expected)
+		}
+	}
+}
+
+// TestQuoteWindowsArg tests Windows command line quoting
+func TestQuoteWindowsArg(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{`C:\dir\file.py`, `C:\dir\file.py`},
+		{`C:\My Files\a.py`, `"C:\My Files\a.py"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`trailing dir\`, `"trailing dir\\"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteWindowsArg(tt.arg); got != tt.expected {
+			t.Errorf("quoteWindowsArg(%q) = %q, want %q", tt.arg, got, tt.expected)
+		}
+	}
+}
+
+// TestParseProblems tests problem matcher parsing
+func TestParseProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		matcher  string
+		output   string
+		expected []TaskDiagnostic
+	}{
+		{
+			name:    "go compiler",
+			matcher: "go",
+			output:  "# example\n./main.go:12:5: undefined: foo\nok  \texample/pkg\t0.01s\n",
+			expected: []TaskDiagnostic{
+				{File: "/work/main.go", Line: 12, Column: 5, Severity: "error", Message: "undefined: foo"},
+			},
+		},
+		{
+			name:    "gcc warning",
+			matcher: "gcc",
+			output:  "src/a.c:3:1: warning: unused variable 'x'\n",
+			expected: []TaskDiagnostic{
+				{File: "/work/src/a.c", Line: 3, Column: 1, Severity: "warning", Message: "unused variable 'x'"},
+			},
+		},
+		{
+			name:    "tsc",
+			matcher: "tsc",
+			output:  "src/app.ts(7,10): error TS2304: Cannot find name 'x'.\n",
+			expected: []TaskDiagnostic{
+				{File: "/work/src/app.ts", Line: 7, Column: 10, Severity: "error", Code: "TS2304", Message: "Cannot find name 'x'."},
+			},
+		},
+		{
+			name:    "rustc",
+			matcher: "rustc",
+			output:  "error[E0308]: mismatched types\n --> src/main.rs:4:18\n  |\n",
+			expected: []TaskDiagnostic{
+				{File: "/work/src/main.rs", Line: 4, Column: 18, Severity: "error", Code: "E0308", Message: "mismatched types"},
+			},
+		},
+		{
+			name:    "maven",
+			matcher: "maven",
+			output:  "[INFO] Compiling\n[ERROR] /src/App.java:[12,5] cannot find symbol\n",
+			expected: []TaskDiagnostic{
+				{File: "/src/App.java", Line: 12, Column: 5, Severity: "error", Message: "cannot find symbol"},
+			},
+		},
+		{
+			name:    "python traceback",
+			matcher: "python",
+			output:  "Traceback (most recent call last):\n  File \"/work/app.py\", line 3, in <module>\n    foo()\nNameError: name 'foo' is not defined\n",
+			expected: []TaskDiagnostic{
+				{File: "/work/app.py", Line: 3, Severity: "error", Code: "NameError", Message: "name 'foo' is not defined"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseProblems(tt.matcher, tt.output, "/work")
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseProblems() returned %d diagnostics, want %d: %+v", len(got), len(tt.expected), got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("diagnostic %d = %+v, want %+v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestTaskRunCapturesExitCode tests that task execution records exit status and output
+func TestTaskRunCapturesExitCode(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("/bin/sh not available")
+	}
+
+	tm := NewTaskManager(&App{})
+	task := Task{Label: "fail", Command: "/bin/sh", Args: []string{"-c", "echo 'main.go:1:2: boom'; exit 3"}, Cwd: t.TempDir(), ProblemMatcher: "go"}
+	runID, err := tm.Run(task)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var run *TaskRun
+	for i := 0; i < 200; i++ {
+		run, _ = tm.GetRun(runID)
+		if run.Status != "running" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if run.Status != "failed" {
+		t.Fatalf("Expected failed status, got %s", run.Status)
+	}
+	if run.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", run.ExitCode)
+	}
+	if len(run.Diagnostics) != 1 || run.Diagnostics[0].Message != "boom" {
+		t.Errorf("Unexpected diagnostics: %+v", run.Diagnostics)
+	}
+}
+
+// TestTaskStopKillsProcessGroup tests that stopping a task also ends background children holding the output pipe
+func TestTaskStopKillsProcessGroup(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("/bin/sh not available")
+	}
+
+	tm := NewTaskManager(&App{})
+	task := Task{Label: "watch", Command: "/bin/sh", Args: []string{"-c", "sleep 30 & sleep 30; wait"}, Cwd: t.TempDir()}
+	runID, err := tm.Run(task)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := tm.Stop(runID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	start := time.Now()
+	var run *TaskRun
+	for i := 0; i < 300; i++ {
+		run, _ = tm.GetRun(runID)
+		if run.Status != "running" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if run.Status != "cancelled" {
+		t.Fatalf("Expected cancelled status, got %s", run.Status)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Stop took %v, background child kept the task alive", elapsed)
+	}
+}
+
+// TestTerminalCommandInDir tests the run-file command line for the current platform
+func TestTerminalCommandInDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell depends on the installed terminal")
+	}
+	if got := terminalCommandInDir("/tmp/my dir", "go run main.go"); got != "cd '/tmp/my dir' && go run main.go" {
+		t.Errorf("terminalCommandInDir() = %q", got)
+	}
+}
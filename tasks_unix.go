@@ -0,0 +1,26 @@
+//go:build !windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setupTaskProcess 让任务在独立进程组中运行，取消时结束整个进程组（包括孙进程）
+func setupTaskProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return killProcessTree(cmd)
+	}
+}
+
+// killProcessTree 结束以 cmd 为组长的整个进程组（需以 Setpgid 或 Setsid 启动）
+func killProcessTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// terminalCommandInDir 生成在终端中切换到 dir 后执行命令的命令行
+func terminalCommandInDir(dir, commandLine string) string {
+	return "cd " + quotePosixArg(dir) + " && " + commandLine
+}
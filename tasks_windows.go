@@ -0,0 +1,37 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// setupTaskProcess 隐藏任务窗口，取消时结束整个进程树
+func setupTaskProcess(cmd *exec.Cmd) {
+	hideProcessWindow(cmd)
+	cmd.Cancel = func() error {
+		return killProcessTree(cmd)
+	}
+}
+
+// killProcessTree 用 taskkill /T 结束整个进程树
+func killProcessTree(cmd *exec.Cmd) error {
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	hideProcessWindow(kill)
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// terminalCommandInDir 生成在终端中切换到 dir 后执行命令的命令行
+// PowerShell 5 不支持 &&，cmd.exe 的 cd 需要 /d 才能切换盘符
+func terminalCommandInDir(dir, commandLine string) string {
+	if strings.EqualFold(filepath.Base(windowsTerminalShell()), "powershell.exe") {
+		return "Set-Location -LiteralPath '" + strings.ReplaceAll(dir, "'", "''") + "'; & " + commandLine
+	}
+	return "cd /d " + quoteWindowsArg(dir) + " && " + commandLine
+}
@@ -36,6 +36,17 @@ func NewTerminalManager(app *App) *TerminalManager {
 	}
 }
 
+// windowsTerminalShell 终端使用的 shell，优先使用 PowerShell
+func windowsTerminalShell() string {
+	if _, err := exec.LookPath("powershell.exe"); err == nil {
+		return "powershell.exe"
+	}
+	if shell := os.Getenv("COMSPEC"); shell != "" {
+		return shell
+	}
+	return "cmd.exe"
+}
+
 // CreateTerminal 创建新终端，返回终端ID
 func (tm *TerminalManager) CreateTerminal() (int, error) {
 	tm.mu.Lock()
@@ -43,15 +54,7 @@ func (tm *TerminalManager) CreateTerminal() (int, error) {
 
 	id := int(atomic.AddInt32(&tm.nextID, 1))
 
-	// Windows 使用 PowerShell
-	shell := os.Getenv("COMSPEC")
-	if shell == "" {
-		shell = "cmd.exe"
-	}
-	// 优先使用 PowerShell
-	if _, err := exec.LookPath("powershell.exe"); err == nil {
-		shell = "powershell.exe"
-	}
+	shell := windowsTerminalShell()
 
 	// 使用 ConPTY 创建伪终端
 	cpty, err := conpty.Start(shell, conpty.ConPtyDimensions(120, 30))
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrashSettings 回收站设置
+type TrashSettings struct {
+	UseSystemTrash bool `json:"useSystemTrash"` // 优先使用系统回收站（Linux freedesktop、macOS ~/.Trash）
+	RetentionDays  int  `json:"retentionDays"`  // 超过天数的条目自动永久删除，0 表示不自动清理
+}
+
+// TrashItem 回收站中由本应用删除的条目
+type TrashItem struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	OriginalPath string    `json:"originalPath"`
+	TrashPath    string    `json:"trashPath"`
+	InfoPath     string    `json:"infoPath,omitempty"`
+	DeletedAt    time.Time `json:"deletedAt"`
+	IsDir        bool      `json:"isDir"`
+	Size         int64     `json:"size"`
+	System       bool      `json:"system"` // 是否在系统回收站中
+}
+
+// TrashManager 回收站管理器
+type TrashManager struct {
+	app *App
+	dir string // 应用回收站目录
+	mu  sync.Mutex
+}
+
+// NewTrashManager 创建回收站管理器
+func NewTrashManager(app *App) *TrashManager {
+	return &TrashManager{app: app}
+}
+
+// baseDir 返回应用回收站目录
+func (tm *TrashManager) baseDir() (string, error) {
+	if tm.dir != "" {
+		return tm.dir, os.MkdirAll(tm.dir, 0755)
+	}
+	dir, err := tm.app.getDataDir("trash")
+	if err != nil {
+		return "", err
+	}
+	tm.dir = dir
+	return dir, nil
+}
+
+// GetSettings 读取回收站设置
+func (tm *TrashManager) GetSettings() TrashSettings {
+	settings := TrashSettings{UseSystemTrash: true, RetentionDays: 30}
+	base, err := tm.baseDir()
+	if err != nil {
+		return settings
+	}
+	if data, err := os.ReadFile(filepath.Join(base, "settings.json")); err == nil {
+		json.Unmarshal(data, &settings)
+	}
+	return settings
+}
+
+// SaveSettings 保存回收站设置
+func (tm *TrashManager) SaveSettings(settings TrashSettings) error {
+	if settings.RetentionDays < 0 {
+		return fmt.Errorf("保留天数不能为负数")
+	}
+	base, err := tm.baseDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(base, "settings.json"), data, 0600)
+}
+
+// loadIndexLocked 读取回收站索引（调用方需持有锁）
+func (tm *TrashManager) loadIndexLocked(base string) []TrashItem {
+	var items []TrashItem
+	if data, err := os.ReadFile(filepath.Join(base, "index.json")); err == nil {
+		json.Unmarshal(data, &items)
+	}
+	return items
+}
+
+// saveIndexLocked 保存回收站索引（调用方需持有锁）
+func (tm *TrashManager) saveIndexLocked(base string, items []TrashItem) error {
+	if items == nil {
+		items = []TrashItem{}
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(base, "index.json"), data, 0600)
+}
+
+// Trash 将文件或目录移入回收站
+func (tm *TrashManager) Trash(path string) (*TrashItem, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("文件不存在: %v", err)
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	base, err := tm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	settings := tm.GetSettings()
+
+	now := time.Now()
+	item := TrashItem{
+		ID:           fmt.Sprintf("%d", now.UnixNano()),
+		Name:         filepath.Base(path),
+		OriginalPath: path,
+		DeletedAt:    now,
+		IsDir:        info.IsDir(),
+		Size:         info.Size(),
+	}
+	if item.IsDir {
+		item.Size = dirSize(path)
+	}
+
+	moved := false
+	if settings.UseSystemTrash {
+		if filesDir, infoDir, ok := systemTrashDirs(filepath.Join(base, "info")); ok {
+			if err := moveToTrashDir(path, filesDir, infoDir, now, &item, false); err == nil {
+				item.System = true
+				moved = true
+			}
+		}
+	}
+	if !moved {
+		// 系统回收站不可用或跨设备时使用应用回收站
+		if err := moveToTrashDir(path, filepath.Join(base, "files"), filepath.Join(base, "info"), now, &item, true); err != nil {
+			return nil, fmt.Errorf("移入回收站失败: %v", err)
+		}
+	}
+
+	items := append(tm.loadIndexLocked(base), item)
+	items = tm.pruneLocked(items, settings)
+	if err := tm.saveIndexLocked(base, items); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// moveToTrashDir 按 freedesktop 规范写入 .trashinfo 并移动文件；allowCopy 为 true 时跨设备改为复制后删除
+func moveToTrashDir(path, filesDir, infoDir string, now time.Time, item *TrashItem, allowCopy bool) error {
+	if err := os.MkdirAll(filesDir, 0700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(infoDir, 0700); err != nil {
+		return err
+	}
+
+	// 以独占方式创建 .trashinfo 预留唯一名称
+	name := filepath.Base(path)
+	var infoPath string
+	var infoFile *os.File
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			ext := filepath.Ext(name)
+			candidate = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(name, ext), i, ext)
+		}
+		if _, err := os.Lstat(filepath.Join(filesDir, candidate)); err == nil {
+			continue
+		}
+		f, err := os.OpenFile(filepath.Join(infoDir, candidate+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		name, infoPath, infoFile = candidate, f.Name(), f
+		break
+	}
+
+	_, err := fmt.Fprintf(infoFile, "[Trash Info]\nPath=%s\nDeletionDate=%s\n", escapeTrashPath(path), now.Format("2006-01-02T15:04:05"))
+	infoFile.Close()
+	if err != nil {
+		os.Remove(infoPath)
+		return err
+	}
+
+	dest := filepath.Join(filesDir, name)
+	if err := os.Rename(path, dest); err != nil {
+		if !allowCopy {
+			os.Remove(infoPath)
+			return err
+		}
+		if err := copyPathTo(path, dest); err != nil {
+			os.RemoveAll(dest)
+			os.Remove(infoPath)
+			return err
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	item.TrashPath = dest
+	item.InfoPath = infoPath
+	return nil
+}
+
+// escapeTrashPath 按 URL 规则转义路径（保留 "/"）
+func escapeTrashPath(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// copyPathTo 复制文件或目录（跨设备移动时使用）
+func copyPathTo(src, dest string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	fm := &FileManager{}
+	if info.IsDir() {
+		return fm.copyDir(src, dest)
+	}
+	return fm.copyFile(src, dest)
+}
+
+// movePath 重命名，跨设备时复制后删除
+func movePath(src, dest string) error {
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+	if err := copyPathTo(src, dest); err != nil {
+		os.RemoveAll(dest)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// pruneLocked 移除已不存在的条目并永久删除超过保留期的条目（调用方需持有锁）
+func (tm *TrashManager) pruneLocked(items []TrashItem, settings TrashSettings) []TrashItem {
+	var kept []TrashItem
+	cutoff := time.Now().AddDate(0, 0, -settings.RetentionDays)
+	for _, item := range items {
+		if _, err := os.Lstat(item.TrashPath); err != nil {
+			continue
+		}
+		if settings.RetentionDays > 0 && item.DeletedAt.Before(cutoff) {
+			removeTrashItem(item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// removeTrashItem 永久删除回收站条目
+func removeTrashItem(item TrashItem) error {
+	if err := os.RemoveAll(item.TrashPath); err != nil {
+		return fmt.Errorf("删除失败: %v", err)
+	}
+	if item.InfoPath != "" {
+		os.Remove(item.InfoPath)
+	}
+	return nil
+}
+
+// List 列出由本应用移入回收站的条目（最新的在前）
+func (tm *TrashManager) List() ([]TrashItem, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	base, err := tm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	items := tm.pruneLocked(tm.loadIndexLocked(base), tm.GetSettings())
+	if err := tm.saveIndexLocked(base, items); err != nil {
+		return nil, err
+	}
+
+	result := append([]TrashItem{}, items...)
+	sort.Slice(result, func(i, j int) bool { return result[i].DeletedAt.After(result[j].DeletedAt) })
+	return result, nil
+}
+
+// takeLocked 从索引中取出条目（调用方需持有锁）
+func (tm *TrashManager) takeLocked(base, id string) (TrashItem, []TrashItem, error) {
+	items := tm.loadIndexLocked(base)
+	for i, item := range items {
+		if item.ID == id {
+			rest := append(append([]TrashItem{}, items[:i]...), items[i+1:]...)
+			return item, rest, nil
+		}
+	}
+	return TrashItem{}, items, fmt.Errorf("回收站条目不存在: %s", id)
+}
+
+// Restore 将条目恢复到原位置
+func (tm *TrashManager) Restore(id string) (string, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	base, err := tm.baseDir()
+	if err != nil {
+		return "", err
+	}
+	item, rest, err := tm.takeLocked(base, id)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return "", fmt.Errorf("原位置已存在同名文件: %s", item.OriginalPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return "", fmt.Errorf("创建目录失败: %v", err)
+	}
+	if err := movePath(item.TrashPath, item.OriginalPath); err != nil {
+		return "", fmt.Errorf("恢复失败: %v", err)
+	}
+	if item.InfoPath != "" {
+		os.Remove(item.InfoPath)
+	}
+	return item.OriginalPath, tm.saveIndexLocked(base, rest)
+}
+
+// Delete 永久删除单个条目
+func (tm *TrashManager) Delete(id string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	base, err := tm.baseDir()
+	if err != nil {
+		return err
+	}
+	item, rest, err := tm.takeLocked(base, id)
+	if err != nil {
+		return err
+	}
+	if err := removeTrashItem(item); err != nil {
+		return err
+	}
+	return tm.saveIndexLocked(base, rest)
+}
+
+// Empty 永久删除本应用移入回收站的全部条目
+func (tm *TrashManager) Empty() error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	base, err := tm.baseDir()
+	if err != nil {
+		return err
+	}
+	var failed []TrashItem
+	for _, item := range tm.loadIndexLocked(base) {
+		if removeTrashItem(item) != nil {
+			failed = append(failed, item)
+		}
+	}
+	if err := tm.saveIndexLocked(base, failed); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d 个条目删除失败", len(failed))
+	}
+	return nil
+}
+
+// --- 重命名/移动/复制的撤销 ---
+
+// maxFileOperations 可撤销的操作数
+const maxFileOperations = 20
+
+// FileOperation 可撤销的文件操作
+type FileOperation struct {
+	Kind   string    `json:"kind"` // "rename", "move", "copy"
+	Source string    `json:"source"`
+	Target string    `json:"target"`
+	Time   time.Time `json:"time"`
+}
+
+// recordOperation 记录可撤销的操作
+func (fm *FileManager) recordOperation(kind, source, target string) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.operations = append(fm.operations, FileOperation{Kind: kind, Source: source, Target: target, Time: time.Now()})
+	if len(fm.operations) > maxFileOperations {
+		fm.operations = fm.operations[len(fm.operations)-maxFileOperations:]
+	}
+}
+
+// LastOperation 返回最近一次可撤销的操作
+func (fm *FileManager) LastOperation() *FileOperation {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	if len(fm.operations) == 0 {
+		return nil
+	}
+	op := fm.operations[len(fm.operations)-1]
+	return &op
+}
+
+// UndoLastOperation 撤销最近一次重命名/移动/复制（复制的副本移入回收站）
+func (fm *FileManager) UndoLastOperation() (*FileOperation, error) {
+	fm.mu.Lock()
+	if len(fm.operations) == 0 {
+		fm.mu.Unlock()
+		return nil, fmt.Errorf("没有可撤销的操作")
+	}
+	op := fm.operations[len(fm.operations)-1]
+	fm.mu.Unlock()
+
+	if _, err := os.Lstat(op.Target); err != nil {
+		return nil, fmt.Errorf("目标已不存在: %s", op.Target)
+	}
+
+	switch op.Kind {
+	case "rename", "move":
+		if _, err := os.Lstat(op.Source); err == nil {
+			return nil, fmt.Errorf("原位置已存在同名文件: %s", op.Source)
+		}
+		if err := os.Rename(op.Target, op.Source); err != nil {
+			return nil, fmt.Errorf("撤销失败: %v", err)
+		}
+	case "copy":
+		if err := fm.trashPath(op.Target); err != nil {
+			return nil, fmt.Errorf("撤销失败: %v", err)
+		}
+	}
+
+	fm.mu.Lock()
+	if n := len(fm.operations); n > 0 && fm.operations[n-1] == op {
+		fm.operations = fm.operations[:n-1]
+	}
+	fm.mu.Unlock()
+	return &op, nil
+}
+
+// trashPath 移入回收站，回收站不可用时直接删除
+func (fm *FileManager) trashPath(path string) error {
+	if fm.app != nil && fm.app.trashMgr != nil {
+		_, err := fm.app.trashMgr.Trash(path)
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// --- App API ---
+
+// GetTrashItems 获取回收站条目
+func (a *App) GetTrashItems() ([]TrashItem, error) {
+	return a.trashMgr.List()
+}
+
+// RestoreTrashItem 恢复回收站条目，返回恢复后的路径
+func (a *App) RestoreTrashItem(id string) (string, error) {
+	return a.trashMgr.Restore(id)
+}
+
+// DeleteTrashItem 永久删除回收站条目
+func (a *App) DeleteTrashItem(id string) error {
+	return a.trashMgr.Delete(id)
+}
+
+// EmptyTrash 清空回收站
+func (a *App) EmptyTrash() error {
+	return a.trashMgr.Empty()
+}
+
+// GetTrashSettings 获取回收站设置
+func (a *App) GetTrashSettings() TrashSettings {
+	return a.trashMgr.GetSettings()
+}
+
+// SaveTrashSettings 保存回收站设置
+func (a *App) SaveTrashSettings(settings TrashSettings) error {
+	return a.trashMgr.SaveSettings(settings)
+}
+
+// DeletePathPermanently 永久删除文件或文件夹（不进入回收站）
+func (a *App) DeletePathPermanently(path string) error {
+	return a.fileMgr.DeletePathPermanently(path)
+}
+
+// UndoFileOperation 撤销最近一次重命名/移动/复制
+func (a *App) UndoFileOperation() (*FileOperation, error) {
+	return a.fileMgr.UndoLastOperation()
+}
+
+// GetLastFileOperation 获取最近一次可撤销的文件操作
+func (a *App) GetLastFileOperation() *FileOperation {
+	return a.fileMgr.LastOperation()
+}
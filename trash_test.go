@@ -0,0 +1,163 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestTrashApp creates an app with an app-managed trash in a temp dir
+func newTestTrashApp(t *testing.T, useSystemTrash bool) *App {
+	t.Helper()
+	app := &App{}
+	app.fileMgr = NewFileManager(app)
+	app.trashMgr = NewTrashManager(app)
+	app.trashMgr.dir = t.TempDir()
+	if err := app.trashMgr.SaveSettings(TrashSettings{UseSystemTrash: useSystemTrash, RetentionDays: 30}); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+// TestTrashDeleteAndRestore tests moving to the app trash, listing and restoring
+func TestTrashDeleteAndRestore(t *testing.T) {
+	app := newTestTrashApp(t, false)
+	dir := t.TempDir()
+	app.fileMgr.rootDir = dir
+	path := writeTestFile(t, dir, "src/a.txt", "hello\n")
+	writeTestFile(t, dir, "other/a.txt", "other\n")
+
+	if err := app.fileMgr.DeletePath(path); err != nil {
+		t.Fatalf("DeletePath() error = %v", err)
+	}
+	if err := app.fileMgr.DeletePath(filepath.Join(dir, "other", "a.txt")); err != nil {
+		t.Fatalf("DeletePath() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("Expected file to be moved out of the workspace")
+	}
+
+	items, err := app.trashMgr.List()
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Expected 2 trash items, got %+v, %v", items, err)
+	}
+	if items[0].TrashPath == items[1].TrashPath {
+		t.Error("Expected unique names in trash for files with the same name")
+	}
+	info, _ := os.ReadFile(items[1].InfoPath)
+	if !strings.Contains(string(info), "[Trash Info]") || !strings.Contains(string(info), "Path=") {
+		t.Errorf("Unexpected trashinfo: %s", info)
+	}
+
+	restored, err := app.trashMgr.Restore(items[1].ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(restored); string(data) != "hello\n" {
+		t.Errorf("Unexpected restored content %q at %s", data, restored)
+	}
+
+	if err := app.trashMgr.Empty(); err != nil {
+		t.Fatalf("Empty() error = %v", err)
+	}
+	if items, _ := app.trashMgr.List(); len(items) != 0 {
+		t.Errorf("Expected empty trash, got %d items", len(items))
+	}
+}
+
+// TestTrashFreedesktop tests the freedesktop trash layout on Linux
+func TestTrashFreedesktop(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("freedesktop trash is Linux only")
+	}
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	app := newTestTrashApp(t, true)
+	// 使回收站与文件位于同一文件系统
+	path := writeTestFile(t, dataHome, "work/a b.txt", "x\n")
+
+	item, err := app.trashMgr.Trash(path)
+	if err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+	if !item.System || filepath.Dir(item.TrashPath) != filepath.Join(dataHome, "Trash", "files") {
+		t.Fatalf("Expected item in freedesktop trash, got %+v", item)
+	}
+	info, _ := os.ReadFile(filepath.Join(dataHome, "Trash", "info", "a b.txt.trashinfo"))
+	if !strings.Contains(string(info), "Path="+escapeTrashPath(path)) || !strings.Contains(string(info), "a%20b.txt") {
+		t.Errorf("Unexpected trashinfo: %s", info)
+	}
+}
+
+// TestTrashRetention tests that expired items are removed permanently
+func TestTrashRetention(t *testing.T) {
+	app := newTestTrashApp(t, false)
+	path := writeTestFile(t, t.TempDir(), "old.txt", "x\n")
+	item, err := app.trashMgr.Trash(path)
+	if err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+
+	// 将删除时间改为保留期之前
+	app.trashMgr.mu.Lock()
+	base, _ := app.trashMgr.baseDir()
+	items := app.trashMgr.loadIndexLocked(base)
+	items[0].DeletedAt = time.Now().AddDate(0, 0, -31)
+	app.trashMgr.saveIndexLocked(base, items)
+	app.trashMgr.mu.Unlock()
+
+	if items, _ := app.trashMgr.List(); len(items) != 0 {
+		t.Errorf("Expected expired item to be pruned, got %+v", items)
+	}
+	if _, err := os.Stat(item.TrashPath); !os.IsNotExist(err) {
+		t.Error("Expected expired item to be deleted from disk")
+	}
+}
+
+// TestUndoFileOperations tests undo of rename, move and copy
+func TestUndoFileOperations(t *testing.T) {
+	app := newTestTrashApp(t, false)
+	dir := t.TempDir()
+	app.fileMgr.rootDir = dir
+	src := writeTestFile(t, dir, "a.txt", "x\n")
+	os.MkdirAll(filepath.Join(dir, "dest"), 0755)
+
+	renamed, err := app.fileMgr.RenamePath(src, "b.txt")
+	if err != nil {
+		t.Fatalf("RenamePath() error = %v", err)
+	}
+	moved, err := app.fileMgr.MovePath(renamed, filepath.Join(dir, "dest"))
+	if err != nil {
+		t.Fatalf("MovePath() error = %v", err)
+	}
+	copied, err := app.fileMgr.CopyPath(moved, dir)
+	if err != nil {
+		t.Fatalf("CopyPath() error = %v", err)
+	}
+
+	for _, kind := range []string{"copy", "move", "rename"} {
+		op, err := app.fileMgr.UndoLastOperation()
+		if err != nil {
+			t.Fatalf("UndoLastOperation() error = %v", err)
+		}
+		if op.Kind != kind {
+			t.Errorf("Expected to undo %s, got %s", kind, op.Kind)
+		}
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		t.Error("Expected original file to be back after undo")
+	}
+	if _, err := os.Stat(copied); !os.IsNotExist(err) {
+		t.Error("Expected copy to be removed by undo")
+	}
+	if items, _ := app.trashMgr.List(); len(items) != 1 {
+		t.Error("Expected undone copy to be recoverable from trash")
+	}
+	if _, err := app.fileMgr.UndoLastOperation(); err == nil {
+		t.Error("Expected error when nothing to undo")
+	}
+}
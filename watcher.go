@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// treeEventDelay 合并文件树事件的等待时间
+const treeEventDelay = 150 * time.Millisecond
+
+// FileChangeEvent 文件树变化
+type FileChangeEvent struct {
+	Type    string `json:"type"` // "create", "delete", "rename", "modify"
+	Path    string `json:"path"`
+	OldPath string `json:"oldPath,omitempty"` // rename 时的原路径
+	RelPath string `json:"relPath"`
+	IsDir   bool   `json:"isDir"`
+}
+
+// FileTreeChange 推送给前端的一批文件树变化
+type FileTreeChange struct {
+	Root    string            `json:"root"`
+	Changes []FileChangeEvent `json:"changes"`
+}
+
+// rawFileEvent 未合并的文件系统事件
+type rawFileEvent struct {
+	op    fsnotify.Op
+	path  string
+	isDir bool
+}
+
+// watchTree 递归监听工作区根目录（跳过被忽略的目录），替换之前的根目录
+func (fm *FileManager) watchTree(root string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if fm.watcher == nil {
+		if err := fm.StartWatcher(); err != nil {
+			return err
+		}
+	}
+
+	// 移除旧根目录的监听
+	for dir := range fm.watchedDirs {
+		fm.watcher.Remove(dir)
+	}
+	fm.watchedDirs = make(map[string]bool)
+	fm.treeRoot = root
+	fm.treeIgnore = NewIgnoreMatcher(root)
+	err := fm.addTreeDirLocked(root)
+
+	// 不在新根目录监听范围内的已打开文件需要单独监听
+	for path := range fm.watchedFiles {
+		if !fm.watchedDirs[filepath.Dir(path)] {
+			fm.watcher.Add(path)
+		}
+	}
+	return err
+}
+
+// addTreeDirLocked 递归添加目录监听（调用方需持有锁）
+func (fm *FileManager) addTreeDirLocked(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != fm.treeRoot && fm.isTreeIgnored(path, true) {
+			return filepath.SkipDir
+		}
+		if fm.watchedDirs[path] {
+			return nil
+		}
+		if err := fm.watcher.Add(path); err != nil {
+			// 通常是 inotify 监听数达到上限，跳过该目录但不中断
+			fmt.Printf("监听目录失败 %s: %v\n", path, err)
+			return filepath.SkipDir
+		}
+		fm.watchedDirs[path] = true
+		return nil
+	})
+}
+
+// removeTreeDirLocked 移除目录及其子目录的监听（调用方需持有锁）
+func (fm *FileManager) removeTreeDirLocked(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path := range fm.watchedDirs {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			fm.watcher.Remove(path)
+			delete(fm.watchedDirs, path)
+		}
+	}
+}
+
+// isTreeIgnored 判断路径是否被忽略（.gitignore、版本控制目录和默认跳过的目录）
+func (fm *FileManager) isTreeIgnored(path string, isDir bool) bool {
+	if isDir && defaultSkippedDirs[filepath.Base(path)] {
+		return true
+	}
+	rel, err := filepath.Rel(fm.treeRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return true
+	}
+	return fm.treeIgnore.Match(rel, isDir)
+}
+
+// handleWatchEvent 处理 fsnotify 事件
+func (fm *FileManager) handleWatchEvent(event fsnotify.Event) {
+	fm.mu.Lock()
+	watchedFile := fm.watchedFiles[event.Name]
+	inTree := fm.treeRoot != "" && (event.Name == fm.treeRoot || fm.watchedDirs[filepath.Dir(event.Name)])
+	fm.mu.Unlock()
+
+	// 编辑器打开的文件：写入或被原子替换（重命名覆盖）时通知
+	if watchedFile && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+		fm.app.emitEvent("file-changed", event.Name)
+	}
+	if !inTree || event.Op == fsnotify.Chmod {
+		return
+	}
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if filepath.Base(event.Name) == ".gitignore" {
+		rel, _ := filepath.Rel(fm.treeRoot, filepath.Dir(event.Name))
+		if rel == "." {
+			rel = ""
+		}
+		fm.treeIgnore.Invalidate(rel)
+	}
+
+	raw := rawFileEvent{op: event.Op, path: event.Name}
+	switch {
+	case event.Has(fsnotify.Create):
+		info, err := os.Lstat(event.Name)
+		if err != nil {
+			return
+		}
+		raw.isDir = info.IsDir()
+		if raw.isDir && !fm.isTreeIgnored(event.Name, true) {
+			// 新目录：递归添加监听（其中已存在的内容由前端重新列出）
+			fm.addTreeDirLocked(event.Name)
+		}
+	case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+		raw.isDir = fm.watchedDirs[event.Name]
+		if raw.isDir {
+			fm.removeTreeDirLocked(event.Name)
+		}
+	case event.Has(fsnotify.Write):
+		raw.op = fsnotify.Write
+	default:
+		return
+	}
+
+	fm.pendingEvents = append(fm.pendingEvents, raw)
+	if fm.flushTimer == nil {
+		fm.flushTimer = time.AfterFunc(treeEventDelay, fm.flushTreeEvents)
+	}
+}
+
+// flushTreeEvents 合并并推送积累的文件树事件
+func (fm *FileManager) flushTreeEvents() {
+	fm.mu.Lock()
+	raw := fm.pendingEvents
+	root := fm.treeRoot
+	fm.pendingEvents = nil
+	fm.flushTimer = nil
+	fm.mu.Unlock()
+
+	changes := coalesceFileEvents(root, raw)
+	if len(changes) == 0 {
+		return
+	}
+
+	// 记录外部（如 agent）修改后的内容，自身写入的版本会被去重
+	for _, c := range changes {
+		if !c.IsDir && c.Type != "delete" && !fm.isIgnoredChange(c) {
+			fm.app.snapshotFile(c.Path, "external")
+		}
+	}
+	fm.app.searchMgr.applyFileChanges(root, changes)
+	fm.app.emitEvent("file-tree-changed", FileTreeChange{Root: root, Changes: changes})
+}
+
+// coalesceFileEvents 合并同一路径上的连续事件，并把紧随的 Rename+Create 配对为 rename
+func coalesceFileEvents(root string, raw []rawFileEvent) []FileChangeEvent {
+	var changes []*FileChangeEvent
+	byPath := make(map[string]*FileChangeEvent)
+
+	put := func(ev *FileChangeEvent) {
+		ev.RelPath, _ = filepath.Rel(root, ev.Path)
+		ev.RelPath = filepath.ToSlash(ev.RelPath)
+		changes = append(changes, ev)
+		byPath[ev.Path] = ev
+	}
+
+	for i, r := range raw {
+		kind := "modify"
+		switch {
+		case r.op.Has(fsnotify.Create):
+			kind = "create"
+		case r.op.Has(fsnotify.Remove), r.op.Has(fsnotify.Rename):
+			kind = "delete"
+		}
+
+		// 重命名：旧路径的 Rename 事件后紧跟新路径的 Create
+		if i > 0 && kind == "create" && raw[i-1].op.Has(fsnotify.Rename) {
+			if prev := byPath[raw[i-1].path]; prev != nil && prev.Type == "delete" {
+				delete(byPath, prev.Path)
+				prev.Type, prev.OldPath, prev.Path, prev.IsDir = "rename", prev.Path, r.path, r.isDir
+				prev.RelPath, _ = filepath.Rel(root, r.path)
+				prev.RelPath = filepath.ToSlash(prev.RelPath)
+				if existing := byPath[r.path]; existing != nil {
+					existing.Type = ""
+				}
+				byPath[r.path] = prev
+				continue
+			}
+		}
+
+		existing := byPath[r.path]
+		if existing == nil {
+			put(&FileChangeEvent{Type: kind, Path: r.path, IsDir: r.isDir})
+			continue
+		}
+
+		switch {
+		case existing.Type == "create" && kind == "delete":
+			// 创建后又删除：前端无需感知
+			existing.Type = ""
+			delete(byPath, r.path)
+		case existing.Type == "create" && kind == "modify":
+		case existing.Type == "rename" && kind == "modify":
+		case existing.Type == "rename" && kind == "delete":
+			existing.Type, existing.Path, existing.OldPath = "delete", existing.OldPath, ""
+			existing.RelPath, _ = filepath.Rel(root, existing.Path)
+			existing.RelPath = filepath.ToSlash(existing.RelPath)
+			delete(byPath, r.path)
+		case existing.Type == "delete" && kind == "create":
+			existing.Type, existing.IsDir = "modify", r.isDir
+		default:
+			existing.Type = kind
+			existing.IsDir = existing.IsDir || r.isDir
+		}
+	}
+
+	result := []FileChangeEvent{}
+	for _, ev := range changes {
+		if ev.Type != "" {
+			result = append(result, *ev)
+		}
+	}
+	return result
+}
+
+// isIgnoredChange 判断变化的文件是否被忽略
+func (fm *FileManager) isIgnoredChange(c FileChangeEvent) bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.treeIgnore == nil || fm.isTreeIgnored(c.Path, false)
+}
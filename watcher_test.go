@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TestCoalesceFileEvents tests merging of raw filesystem events
+func TestCoalesceFileEvents(t *testing.T) {
+	root := "/work"
+	tests := []struct {
+		name     string
+		raw      []rawFileEvent
+		expected []FileChangeEvent
+	}{
+		{
+			name:     "create then write",
+			raw:      []rawFileEvent{{op: fsnotify.Create, path: "/work/a.go"}, {op: fsnotify.Write, path: "/work/a.go"}},
+			expected: []FileChangeEvent{{Type: "create", Path: "/work/a.go", RelPath: "a.go"}},
+		},
+		{
+			name:     "create then delete",
+			raw:      []rawFileEvent{{op: fsnotify.Create, path: "/work/tmp"}, {op: fsnotify.Remove, path: "/work/tmp"}},
+			expected: []FileChangeEvent{},
+		},
+		{
+			name:     "delete then create",
+			raw:      []rawFileEvent{{op: fsnotify.Remove, path: "/work/a.go"}, {op: fsnotify.Create, path: "/work/a.go"}},
+			expected: []FileChangeEvent{{Type: "modify", Path: "/work/a.go", RelPath: "a.go"}},
+		},
+		{
+			name: "rename pair",
+			raw: []rawFileEvent{
+				{op: fsnotify.Rename, path: "/work/old", isDir: true},
+				{op: fsnotify.Create, path: "/work/sub/new", isDir: true},
+			},
+			expected: []FileChangeEvent{{Type: "rename", Path: "/work/sub/new", OldPath: "/work/old", RelPath: "sub/new", IsDir: true}},
+		},
+		{
+			name:     "rename away",
+			raw:      []rawFileEvent{{op: fsnotify.Rename, path: "/work/a.go"}, {op: fsnotify.Write, path: "/work/b.go"}},
+			expected: []FileChangeEvent{{Type: "delete", Path: "/work/a.go", RelPath: "a.go"}, {Type: "modify", Path: "/work/b.go", RelPath: "b.go"}},
+		},
+	}
+
+	for _, tt := range tests {
+		got := coalesceFileEvents(root, tt.raw)
+		if len(got) != len(tt.expected) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%s: event %d = %+v, want %+v", tt.name, i, got[i], tt.expected[i])
+			}
+		}
+	}
+}
+
+// TestWatchTreeAddsNewDirs tests recursive watching, ignored dirs and re-watching created dirs
+func TestWatchTreeAddsNewDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, ".gitignore", "dist/\n")
+	writeTestFile(t, dir, "src/main.go", "package main\n")
+	writeTestFile(t, dir, "dist/app.js", "x\n")
+	writeTestFile(t, dir, "node_modules/pkg/index.js", "x\n")
+
+	fm := NewFileManager(&App{})
+	if err := fm.SetRootDir(dir); err != nil {
+		t.Fatalf("SetRootDir() error = %v", err)
+	}
+	defer fm.watcher.Close()
+
+	watched := func(path string) bool {
+		fm.mu.Lock()
+		defer fm.mu.Unlock()
+		return fm.watchedDirs[path]
+	}
+
+	if !watched(filepath.Join(dir, "src")) {
+		t.Error("Expected src to be watched")
+	}
+	if watched(filepath.Join(dir, "dist")) || watched(filepath.Join(dir, "node_modules")) {
+		t.Error("Expected ignored directories not to be watched")
+	}
+
+	nested := filepath.Join(dir, "src", "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !watched(nested) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !watched(nested) {
+		t.Error("Expected newly created nested directory to be watched")
+	}
+
+	os.RemoveAll(filepath.Join(dir, "src", "a"))
+	deadline = time.Now().Add(2 * time.Second)
+	for watched(nested) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if watched(nested) {
+		t.Error("Expected removed directory to be unwatched")
+	}
+}
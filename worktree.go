@@ -0,0 +1,527 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorktreeSession 在独立 git worktree 中运行的会话
+type WorktreeSession struct {
+	SessionID  string    `json:"sessionId"`
+	Title      string    `json:"title"`
+	RepoRoot   string    `json:"repoRoot"`
+	Path       string    `json:"path"` // worktree 目录
+	Branch     string    `json:"branch"`
+	BaseBranch string    `json:"baseBranch"`
+	BaseCommit string    `json:"baseCommit"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WorktreeDiff worktree 相对基础分支的修改
+type WorktreeDiff struct {
+	Session   WorktreeSession `json:"session"`
+	MergeBase string          `json:"mergeBase"`
+	Ahead     int             `json:"ahead"`  // 分支上尚未合并的提交数
+	Behind    int             `json:"behind"` // 基础分支上的新提交数
+	Dirty     bool            `json:"dirty"`  // worktree 中有未提交的修改
+	Files     []GitFileDiff   `json:"files"`
+}
+
+// WorktreeMergeOptions 合并选项
+type WorktreeMergeOptions struct {
+	Message string `json:"message"` // 提交未提交修改及合并提交使用的信息
+	Squash  bool   `json:"squash"`
+	Cleanup bool   `json:"cleanup"` // 合并成功后删除 worktree 和分支
+}
+
+// WorktreeMergeResult 合并结果
+type WorktreeMergeResult struct {
+	Merged    bool     `json:"merged"`
+	Commit    string   `json:"commit,omitempty"`
+	Conflicts []string `json:"conflicts"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// worktreeBranchPrefix 会话分支前缀
+const worktreeBranchPrefix = "opencode/"
+
+// WorktreeManager 管理 worktree 会话（与 OpenCodeManager 共用同一个 OpenCode 服务，通过 directory 参数区分项目）
+type WorktreeManager struct {
+	app      *App
+	dir      string // worktree 及索引保存目录
+	sessions map[string]*WorktreeSession
+	loaded   bool
+	streams  map[string]context.CancelFunc // 每个 worktree 的事件订阅
+	parent   context.Context               // 主事件订阅的上下文，nil 表示未订阅
+	server   string                        // 订阅时的服务器地址
+	mu       sync.Mutex
+}
+
+// NewWorktreeManager 创建 worktree 会话管理器
+func NewWorktreeManager(app *App) *WorktreeManager {
+	return &WorktreeManager{
+		app:      app,
+		sessions: make(map[string]*WorktreeSession),
+		streams:  make(map[string]context.CancelFunc),
+	}
+}
+
+// baseDir 返回 worktree 保存目录
+func (wm *WorktreeManager) baseDir() (string, error) {
+	if wm.dir == "" {
+		dir, err := wm.app.getDataDir("worktrees")
+		if err != nil {
+			return "", err
+		}
+		wm.dir = dir
+	}
+	return wm.dir, os.MkdirAll(wm.dir, 0755)
+}
+
+// loadLocked 首次使用时读取索引（调用方需持有锁）
+func (wm *WorktreeManager) loadLocked() {
+	if wm.loaded {
+		return
+	}
+	wm.loaded = true
+	base, err := wm.baseDir()
+	if err != nil {
+		return
+	}
+	var sessions []*WorktreeSession
+	if data, err := os.ReadFile(filepath.Join(base, "sessions.json")); err == nil {
+		json.Unmarshal(data, &sessions)
+	}
+	for _, s := range sessions {
+		// 目录已被外部删除的会话不再恢复
+		if fileExists(s.Path) {
+			wm.sessions[s.SessionID] = s
+		}
+	}
+}
+
+// saveLocked 保存索引（调用方需持有锁）
+func (wm *WorktreeManager) saveLocked() error {
+	base, err := wm.baseDir()
+	if err != nil {
+		return err
+	}
+	sessions := make([]*WorktreeSession, 0, len(wm.sessions))
+	for _, s := range wm.sessions {
+		sessions = append(sessions, s)
+	}
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(base, "sessions.json"), data, 0600)
+}
+
+// sessionDir 返回会话绑定的 worktree 目录，普通会话返回空
+func (wm *WorktreeManager) sessionDir(sessionID string) string {
+	if wm == nil || sessionID == "" {
+		return ""
+	}
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.loadLocked()
+	if s, ok := wm.sessions[sessionID]; ok {
+		return s.Path
+	}
+	return ""
+}
+
+// get 按会话 ID 查找
+func (wm *WorktreeManager) get(sessionID string) (WorktreeSession, error) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.loadLocked()
+	s, ok := wm.sessions[sessionID]
+	if !ok {
+		return WorktreeSession{}, fmt.Errorf("worktree 会话不存在: %s", sessionID)
+	}
+	return *s, nil
+}
+
+var slugInvalidRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// worktreeSlug 将会话名称转换为分支和目录名
+func worktreeSlug(name string) string {
+	slug := strings.Trim(slugInvalidRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		slug = "session-" + time.Now().Format("20060102-150405")
+	}
+	return slug
+}
+
+// createWorktree 在新分支上创建 worktree（不创建 OpenCode 会话）
+func (wm *WorktreeManager) createWorktree(dir, name, baseBranch string) (*WorktreeSession, error) {
+	root, err := gitTopLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	if baseBranch == "" {
+		out, err := runGit(root, "symbolic-ref", "--short", "-q", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("当前处于分离 HEAD 状态，请指定基础分支")
+		}
+		baseBranch = strings.TrimSpace(out)
+	}
+	baseCommit, err := runGit(root, "rev-parse", "--verify", "--quiet", baseBranch+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("基础分支不存在: %s", baseBranch)
+	}
+
+	base, err := wm.baseDir()
+	if err != nil {
+		return nil, err
+	}
+	slug := worktreeSlug(name)
+	repoDir := filepath.Join(base, filepath.Base(root)+"-"+hashBytes([]byte(root))[:8])
+
+	// 分支或目录已存在时追加序号
+	branch, path := worktreeBranchPrefix+slug, filepath.Join(repoDir, slug)
+	for i := 2; ; i++ {
+		_, refErr := runGit(root, "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+		if refErr != nil && !fileExists(path) {
+			break
+		}
+		branch = fmt.Sprintf("%s%s-%d", worktreeBranchPrefix, slug, i)
+		path = filepath.Join(repoDir, fmt.Sprintf("%s-%d", slug, i))
+	}
+	if _, err := runGit(root, "check-ref-format", "--branch", branch); err != nil {
+		return nil, fmt.Errorf("分支名称无效: %s", branch)
+	}
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %v", err)
+	}
+	if _, err := runGit(root, "worktree", "add", "-b", branch, path, strings.TrimSpace(baseCommit)); err != nil {
+		return nil, err
+	}
+
+	title := name
+	if title == "" {
+		title = slug
+	}
+	return &WorktreeSession{
+		Title:      title,
+		RepoRoot:   root,
+		Path:       path,
+		Branch:     branch,
+		BaseBranch: baseBranch,
+		BaseCommit: strings.TrimSpace(baseCommit),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// removeWorktree 删除 worktree 目录和分支
+func removeWorktree(s WorktreeSession) error {
+	var errs []string
+	if fileExists(s.Path) {
+		if _, err := runGit(s.RepoRoot, "worktree", "remove", "--force", s.Path); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	runGit(s.RepoRoot, "worktree", "prune")
+	if _, err := runGit(s.RepoRoot, "show-ref", "--verify", "--quiet", "refs/heads/"+s.Branch); err == nil {
+		if _, err := runGit(s.RepoRoot, "branch", "-D", s.Branch); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("清理 worktree 失败: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Create 创建 worktree 并在其中创建 OpenCode 会话
+func (wm *WorktreeManager) Create(dir, name, baseBranch string) (*WorktreeSession, error) {
+	ws, err := wm.createWorktree(dir, name, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	session, err := wm.app.createSessionInDir(ws.Path, ws.Title)
+	if err != nil {
+		removeWorktree(*ws)
+		return nil, err
+	}
+	ws.SessionID = session.ID
+
+	wm.mu.Lock()
+	wm.loadLocked()
+	wm.sessions[ws.SessionID] = ws
+	err = wm.saveLocked()
+	wm.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	wm.subscribe(*ws)
+	wm.app.emitEvent("worktree-session-created", *ws)
+	return ws, nil
+}
+
+// List 列出仓库的 worktree 会话（dir 为空时列出全部）
+func (wm *WorktreeManager) List(dir string) []WorktreeSession {
+	root := ""
+	if dir != "" {
+		root = gitRepoDir(dir)
+	}
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.loadLocked()
+
+	sessions := []WorktreeSession{}
+	for _, s := range wm.sessions {
+		if root == "" || s.RepoRoot == root {
+			sessions = append(sessions, *s)
+		}
+	}
+	return sessions
+}
+
+// Diff 比较 worktree（含未提交的修改）与基础分支的分叉点
+func (wm *WorktreeManager) Diff(sessionID string) (*WorktreeDiff, error) {
+	ws, err := wm.get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	mergeBase, err := runGit(ws.Path, "merge-base", ws.BaseBranch, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	mergeBase = strings.TrimSpace(mergeBase)
+
+	result := &WorktreeDiff{Session: ws, MergeBase: mergeBase, Files: []GitFileDiff{}}
+	if out, err := runGit(ws.Path, "rev-list", "--left-right", "--count", ws.BaseBranch+"...HEAD"); err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			result.Behind, result.Ahead = atoiOrZero(fields[0]), atoiOrZero(fields[1])
+		}
+	}
+	if out, err := runGit(ws.Path, "status", "--porcelain"); err == nil {
+		result.Dirty = strings.TrimSpace(out) != ""
+	}
+
+	// 未提交的修改通过快照提交参与比较
+	target := "HEAD"
+	if result.Dirty {
+		if target, err = snapshotWorktree(ws.Path); err != nil {
+			return nil, err
+		}
+	}
+	out, err := runGit(ws.Path, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "-M", "-U3", mergeBase, target)
+	if err != nil {
+		return nil, err
+	}
+	if diffs := parseGitDiff(out); diffs != nil {
+		result.Files = diffs
+	}
+	return result, nil
+}
+
+// Merge 提交 worktree 中的修改并合并回基础分支（基础分支须在主工作区中检出）
+func (wm *WorktreeManager) Merge(sessionID string, opts WorktreeMergeOptions) (*WorktreeMergeResult, error) {
+	ws, err := wm.get(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("Merge OpenCode session: %s", ws.Title)
+	}
+
+	// 先检查基础分支，避免无法合并时仍在 worktree 中留下提交
+	current, err := runGit(ws.RepoRoot, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || strings.TrimSpace(current) != ws.BaseBranch {
+		return nil, fmt.Errorf("请先在主工作区检出基础分支 %s", ws.BaseBranch)
+	}
+
+	if out, err := runGit(ws.Path, "status", "--porcelain"); err == nil && strings.TrimSpace(out) != "" {
+		if _, err := runGit(ws.Path, "add", "-A"); err != nil {
+			return nil, err
+		}
+		if _, err := runGit(ws.Path, "commit", "-q", "-m", message); err != nil {
+			return nil, err
+		}
+	}
+	if out, _ := runGit(ws.RepoRoot, "rev-list", "--count", ws.BaseBranch+".."+ws.Branch); strings.TrimSpace(out) == "0" {
+		return &WorktreeMergeResult{Conflicts: []string{}, Message: "没有需要合并的提交"}, nil
+	}
+
+	result := &WorktreeMergeResult{Conflicts: []string{}}
+	var mergeErr error
+	if opts.Squash {
+		if _, mergeErr = runGit(ws.RepoRoot, "merge", "--squash", ws.Branch); mergeErr == nil {
+			_, mergeErr = runGit(ws.RepoRoot, "commit", "-q", "-m", message)
+		}
+	} else {
+		_, mergeErr = runGit(ws.RepoRoot, "merge", "--no-ff", "-m", message, ws.Branch)
+	}
+	if mergeErr != nil {
+		out, _ := runGit(ws.RepoRoot, "-c", "core.quotePath=false", "diff", "--name-only", "--diff-filter=U")
+		if files := strings.TrimSpace(out); files != "" {
+			// 冲突留在主工作区中，由冲突解决功能处理
+			result.Conflicts = strings.Split(files, "\n")
+			result.Message = "合并存在冲突，请解决后提交"
+			return result, nil
+		}
+		return nil, mergeErr
+	}
+
+	result.Merged = true
+	if head, err := runGit(ws.RepoRoot, "rev-parse", "HEAD"); err == nil {
+		result.Commit = strings.TrimSpace(head)
+	}
+	wm.app.emitEvent("worktree-session-merged", map[string]interface{}{"sessionId": sessionID, "commit": result.Commit})
+	if opts.Cleanup {
+		if err := wm.Discard(sessionID); err != nil {
+			result.Message = err.Error()
+		}
+	}
+	return result, nil
+}
+
+// Discard 删除 worktree、分支和 OpenCode 会话
+func (wm *WorktreeManager) Discard(sessionID string) error {
+	ws, err := wm.get(sessionID)
+	if err != nil {
+		return err
+	}
+	wm.app.deleteSession(sessionID)
+
+	wm.mu.Lock()
+	wm.unsubscribeLocked(ws.Path)
+	delete(wm.sessions, sessionID)
+	saveErr := wm.saveLocked()
+	wm.mu.Unlock()
+
+	if err := removeWorktree(ws); err != nil {
+		return err
+	}
+	wm.app.emitEvent("worktree-session-discarded", map[string]string{"sessionId": sessionID})
+	return saveErr
+}
+
+// subscribe 订阅 worktree 项目的事件流（OpenCode 按 directory 分发事件），已有订阅时重新订阅
+func (wm *WorktreeManager) subscribe(ws WorktreeSession) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.subscribeLocked(ws.Path)
+}
+
+// subscribeLocked 随主事件订阅启动 worktree 订阅，主订阅取消时一并结束（调用方需持有锁）
+func (wm *WorktreeManager) subscribeLocked(path string) {
+	if wm.parent == nil || wm.parent.Err() != nil {
+		return
+	}
+	wm.unsubscribeLocked(path)
+	ctx, cancel := context.WithCancel(wm.parent)
+	wm.streams[path] = cancel
+	go wm.app.streamEvents(ctx, wm.server+"/event?directory="+url.QueryEscape(path))
+}
+
+// unsubscribeLocked 取消 worktree 的事件订阅（调用方需持有锁）
+func (wm *WorktreeManager) unsubscribeLocked(path string) {
+	if cancel, ok := wm.streams[path]; ok {
+		cancel()
+		delete(wm.streams, path)
+	}
+}
+
+// subscribeAll 在主事件订阅建立后重新订阅所有 worktree 会话
+func (wm *WorktreeManager) subscribeAll(parent context.Context, serverURL string) {
+	if wm == nil {
+		return
+	}
+	sessions := wm.List("")
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.parent, wm.server = parent, serverURL
+	for _, ws := range sessions {
+		wm.subscribeLocked(ws.Path)
+	}
+}
+
+// unsubscribeAll 服务器停止时取消所有 worktree 的事件订阅
+func (wm *WorktreeManager) unsubscribeAll() {
+	if wm == nil {
+		return
+	}
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	for path := range wm.streams {
+		wm.unsubscribeLocked(path)
+	}
+	wm.parent = nil
+}
+
+// createSessionInDir 在指定目录对应的项目中创建会话
+func (a *App) createSessionInDir(dir, title string) (*Session, error) {
+	body, _ := json.Marshal(map[string]string{"title": title})
+	resp, err := a.httpClient.Post(a.serverURL+"/session?directory="+url.QueryEscape(dir), "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建会话失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("创建会话失败 %d: %s", resp.StatusCode, string(data))
+	}
+	// 兼容直接返回会话和 {info: 会话} 两种格式
+	var result struct {
+		Session
+		Info Session `json:"info"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("解析会话失败: %v", err)
+	}
+	if result.ID == "" {
+		result.Session = result.Info
+	}
+	if result.ID == "" {
+		return nil, fmt.Errorf("创建会话失败: 响应中没有会话 ID")
+	}
+	return &result.Session, nil
+}
+
+// --- App API ---
+
+// CreateWorktreeSession 在新分支的独立 worktree 中创建会话（baseBranch 为空时基于当前分支）
+func (a *App) CreateWorktreeSession(dir, name, baseBranch string) (*WorktreeSession, error) {
+	return a.worktreeMgr.Create(dir, name, baseBranch)
+}
+
+// GetWorktreeSessions 列出仓库的 worktree 会话
+func (a *App) GetWorktreeSessions(dir string) []WorktreeSession {
+	return a.worktreeMgr.List(dir)
+}
+
+// DiffWorktreeSession 比较 worktree 与基础分支
+func (a *App) DiffWorktreeSession(sessionID string) (*WorktreeDiff, error) {
+	return a.worktreeMgr.Diff(sessionID)
+}
+
+// MergeWorktreeSession 将 worktree 的修改合并回基础分支
+func (a *App) MergeWorktreeSession(sessionID string, opts WorktreeMergeOptions) (*WorktreeMergeResult, error) {
+	return a.worktreeMgr.Merge(sessionID, opts)
+}
+
+// DiscardWorktreeSession 丢弃 worktree 会话并清理分支
+func (a *App) DiscardWorktreeSession(sessionID string) error {
+	return a.worktreeMgr.Discard(sessionID)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestWorktreeSession creates a repository and a worktree session registered without an OpenCode server
+func newTestWorktreeSession(t *testing.T) (*WorktreeManager, *WorktreeSession) {
+	t.Helper()
+	root := initTestRepo(t)
+	writeTestFile(t, root, "a.txt", "one\ntwo\n")
+	mustGit(t, root, "add", "-A")
+	mustGit(t, root, "commit", "-q", "-m", "init")
+
+	// unreachable server so deleteSession fails fast
+	app := &App{serverURL: "http://127.0.0.1:1", httpClient: &http.Client{Timeout: time.Second}}
+	wm := NewWorktreeManager(app)
+	wm.dir = t.TempDir()
+	wm.loaded = true
+
+	ws, err := wm.createWorktree(root, "Fix Login Bug", "")
+	if err != nil {
+		t.Fatalf("createWorktree() error = %v", err)
+	}
+	ws.SessionID = "ses_test"
+	wm.sessions[ws.SessionID] = ws
+	return wm, ws
+}
+
+// TestWorktreeSlug tests branch name normalization
+func TestWorktreeSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Fix Login Bug", "fix-login-bug"},
+		{"  feat: add/remove  ", "feat-add-remove"},
+		{"修复 bug", "bug"},
+	}
+	for _, tt := range tests {
+		if got := worktreeSlug(tt.name); got != tt.want {
+			t.Errorf("worktreeSlug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := worktreeSlug("中文"); !strings.HasPrefix(got, "session-") {
+		t.Errorf("worktreeSlug() = %q, want session- prefix", got)
+	}
+}
+
+// TestCreateWorktree tests branch creation, base detection and unique naming
+func TestCreateWorktree(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	if ws.Branch != "opencode/fix-login-bug" || ws.BaseBranch != "main" {
+		t.Errorf("branch = %q base = %q", ws.Branch, ws.BaseBranch)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Path, "a.txt")); err != nil {
+		t.Errorf("worktree missing checkout: %v", err)
+	}
+	if got := wm.sessionDir("ses_test"); got != ws.Path {
+		t.Errorf("sessionDir() = %q, want %q", got, ws.Path)
+	}
+
+	second, err := wm.createWorktree(ws.RepoRoot, "Fix Login Bug", "main")
+	if err != nil {
+		t.Fatalf("createWorktree() error = %v", err)
+	}
+	if second.Branch != "opencode/fix-login-bug-2" || second.Path == ws.Path {
+		t.Errorf("second branch = %q path = %q", second.Branch, second.Path)
+	}
+
+	if _, err := wm.createWorktree(ws.RepoRoot, "x", "missing"); err == nil {
+		t.Error("createWorktree() with missing base should fail")
+	}
+}
+
+// TestWorktreeDiff tests that committed and uncommitted worktree changes are reported
+func TestWorktreeDiff(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	writeTestFile(t, ws.Path, "a.txt", "one\nTWO\n")
+	mustGit(t, ws.Path, "commit", "-q", "-am", "change a")
+	writeTestFile(t, ws.Path, "b.txt", "new\n")
+
+	diff, err := wm.Diff("ses_test")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff.Ahead != 1 || !diff.Dirty {
+		t.Errorf("ahead = %d dirty = %v", diff.Ahead, diff.Dirty)
+	}
+	var paths []string
+	for _, f := range diff.Files {
+		paths = append(paths, f.Path)
+	}
+	if strings.Join(paths, ",") != "a.txt,b.txt" {
+		t.Errorf("files = %v", paths)
+	}
+}
+
+// TestMergeWorktree tests merging back into the base branch with cleanup
+func TestMergeWorktree(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	writeTestFile(t, ws.Path, "b.txt", "new\n")
+
+	result, err := wm.Merge("ses_test", WorktreeMergeOptions{Squash: true, Message: "add b", Cleanup: true})
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if !result.Merged || len(result.Conflicts) != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(ws.RepoRoot, "b.txt")); err != nil {
+		t.Errorf("merged file missing: %v", err)
+	}
+	if got := strings.TrimSpace(mustGit(t, ws.RepoRoot, "log", "-1", "--format=%s")); got != "add b" {
+		t.Errorf("subject = %q", got)
+	}
+	if fileExists(ws.Path) || wm.sessionDir("ses_test") != "" {
+		t.Error("worktree not cleaned up")
+	}
+	if out, _ := runGit(ws.RepoRoot, "branch", "--list", ws.Branch); strings.TrimSpace(out) != "" {
+		t.Errorf("branch still exists: %q", out)
+	}
+}
+
+// TestMergeWorktreeConflict tests that conflicts are reported and left in the main worktree
+func TestMergeWorktreeConflict(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	writeTestFile(t, ws.Path, "a.txt", "one\nfrom session\n")
+	writeTestFile(t, ws.RepoRoot, "a.txt", "one\nfrom main\n")
+	mustGit(t, ws.RepoRoot, "commit", "-q", "-am", "main change")
+
+	result, err := wm.Merge("ses_test", WorktreeMergeOptions{})
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if result.Merged || strings.Join(result.Conflicts, ",") != "a.txt" {
+		t.Errorf("result = %+v", result)
+	}
+	if wm.sessionDir("ses_test") == "" {
+		t.Error("session should be kept on conflict")
+	}
+}
+
+// TestMergeWorktreeRequiresBaseBranch tests that merging refuses when the base branch is not checked out
+func TestMergeWorktreeRequiresBaseBranch(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	writeTestFile(t, ws.Path, "b.txt", "new\n")
+	mustGit(t, ws.RepoRoot, "checkout", "-q", "-b", "other")
+
+	if _, err := wm.Merge("ses_test", WorktreeMergeOptions{}); err == nil {
+		t.Error("Merge() should fail when base branch is not checked out")
+	}
+	if out := mustGit(t, ws.Path, "status", "--porcelain"); !strings.Contains(out, "b.txt") {
+		t.Errorf("Expected worktree changes to stay uncommitted, status = %q", out)
+	}
+}
+
+// TestDiscardWorktree tests removal of the worktree, branch and index entry
+func TestDiscardWorktree(t *testing.T) {
+	wm, ws := newTestWorktreeSession(t)
+	writeTestFile(t, ws.Path, "b.txt", "new\n")
+
+	if err := wm.Discard("ses_test"); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if fileExists(ws.Path) {
+		t.Error("worktree directory still exists")
+	}
+	if len(wm.List(ws.RepoRoot)) != 0 {
+		t.Error("session still listed")
+	}
+	if err := wm.Discard("ses_test"); err == nil {
+		t.Error("Discard() of unknown session should fail")
+	}
+}